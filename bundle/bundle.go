@@ -0,0 +1,324 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The bundle package defines the format of bundles: YAML documents that
+// describe a set of services, their units and the relations between
+// them, so that they can be deployed together. For example:
+//
+//	services:
+//	  wordpress:
+//	    charm: cs:precise/wordpress
+//	    num_units: 2
+//	    options:
+//	      debug: "yes"
+//	    constraints: mem=2G
+//	    expose: true
+//	    annotations:
+//	      gui-x: "100"
+//	  mysql:
+//	    charm: mysql
+//	    num_units: 1
+//	    to: lxc:wordpress/0
+//	relations:
+//	  - [wordpress:db, mysql:server]
+package bundle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v3"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+)
+
+// Data holds the contents of a bundle.
+type Data struct {
+	// Services holds the services in the bundle, by name.
+	Services map[string]*ServiceSpec `yaml:"services"`
+
+	// Relations holds the relations between the services. Each
+	// relation is given by its two endpoints, in the form
+	// "service" or "service:relation".
+	Relations [][]string `yaml:"relations,omitempty"`
+}
+
+// ServiceSpec describes a service in a bundle.
+type ServiceSpec struct {
+	// Charm is the charm URL of the service, or an unambiguously
+	// condensed form of it, as accepted by "juju deploy".
+	Charm string `yaml:"charm"`
+
+	// NumUnits is the number of units the service has.
+	NumUnits int `yaml:"num_units,omitempty"`
+
+	// Options holds the configuration settings of the service.
+	Options map[string]interface{} `yaml:"options,omitempty"`
+
+	// Constraints holds the constraints of the service, in the
+	// format accepted by "juju deploy --constraints".
+	Constraints string `yaml:"constraints,omitempty"`
+
+	// To holds the placement of the units of the service, in order.
+	// Units without a placement are assigned to machines as by
+	// "juju add-unit". See ParsePlacement for the accepted forms.
+	To []string `yaml:"to,omitempty"`
+
+	// Expose holds whether the service is exposed.
+	Expose bool `yaml:"expose,omitempty"`
+
+	// Annotations holds the annotations set on the service.
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// Placement describes where a unit is placed.
+type Placement struct {
+	// ContainerType is the type of the new container the unit is
+	// placed in, if any.
+	ContainerType instance.ContainerType
+
+	// Machine is the id of the machine, or container, the unit or
+	// its new container is placed on. It is empty when Unit is set.
+	Machine string
+
+	// Unit is the name of the unit whose machine the unit or its new
+	// container is placed on. It is empty when Machine is set.
+	Unit string
+}
+
+// ParsePlacement parses the placement of a unit. A unit may be placed
+// on an existing machine or container ("1", "1/lxc/0"), on the machine
+// of a unit of another service ("mysql/0"), or in a new container on
+// either ("lxc:1", "kvm:mysql/0").
+func ParsePlacement(placement string) (*Placement, error) {
+	p := &Placement{}
+	target := placement
+	if parts := strings.SplitN(placement, ":", 2); len(parts) == 2 {
+		containerType, err := instance.ParseContainerType(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid placement %q: unknown container type %q", placement, parts[0])
+		}
+		p.ContainerType = containerType
+		target = parts[1]
+	}
+	switch {
+	case names.IsValidMachine(target):
+		p.Machine = target
+	case names.IsValidUnit(target):
+		p.Unit = target
+	default:
+		return nil, fmt.Errorf("invalid placement %q", placement)
+	}
+	return p, nil
+}
+
+// String returns the placement in the form parsed by ParsePlacement.
+func (p *Placement) String() string {
+	target := p.Machine
+	if p.Unit != "" {
+		target = p.Unit
+	}
+	if p.ContainerType != "" {
+		return string(p.ContainerType) + ":" + target
+	}
+	return target
+}
+
+// rawServiceSpec is the form of ServiceSpec read from YAML, in which
+// the placement may be given as a single string.
+type rawServiceSpec struct {
+	Charm       string                 `yaml:"charm"`
+	NumUnits    int                    `yaml:"num_units"`
+	Options     map[string]interface{} `yaml:"options"`
+	Constraints string                 `yaml:"constraints"`
+	To          interface{}            `yaml:"to"`
+	Expose      bool                   `yaml:"expose"`
+	Annotations map[string]string      `yaml:"annotations"`
+}
+
+type rawData struct {
+	Services  map[string]*rawServiceSpec `yaml:"services"`
+	Relations [][]string                 `yaml:"relations"`
+}
+
+// Parse parses a bundle from YAML and verifies it.
+func Parse(data []byte) (*Data, error) {
+	var raw rawData
+	if err := goyaml.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Annotate(err, "cannot parse bundle")
+	}
+	bundle := &Data{
+		Services:  make(map[string]*ServiceSpec),
+		Relations: raw.Relations,
+	}
+	for name, rawSpec := range raw.Services {
+		if rawSpec == nil {
+			return nil, fmt.Errorf("invalid bundle: service %q has no charm", name)
+		}
+		spec := &ServiceSpec{
+			Charm:       rawSpec.Charm,
+			NumUnits:    rawSpec.NumUnits,
+			Options:     rawSpec.Options,
+			Constraints: rawSpec.Constraints,
+			Expose:      rawSpec.Expose,
+			Annotations: rawSpec.Annotations,
+		}
+		switch to := rawSpec.To.(type) {
+		case nil:
+		case string:
+			spec.To = []string{to}
+		case []interface{}:
+			for _, placement := range to {
+				s, ok := placement.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid bundle: service %q: expected placement string, got %v", name, placement)
+				}
+				spec.To = append(spec.To, s)
+			}
+		default:
+			return nil, fmt.Errorf("invalid bundle: service %q: expected placement string or list, got %v", name, to)
+		}
+		bundle.Services[name] = spec
+	}
+	if err := bundle.Verify(); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// YAML returns the bundle in the format read by Parse.
+func (d *Data) YAML() ([]byte, error) {
+	return goyaml.Marshal(d)
+}
+
+// Verify returns an error if the bundle is not valid.
+func (d *Data) Verify() error {
+	if len(d.Services) == 0 {
+		return fmt.Errorf("invalid bundle: no services")
+	}
+	for _, name := range d.serviceNames() {
+		if err := d.verifyService(name, d.Services[name]); err != nil {
+			return fmt.Errorf("invalid bundle: service %q: %v", name, err)
+		}
+	}
+	for _, relation := range d.Relations {
+		if err := d.verifyRelation(relation); err != nil {
+			return fmt.Errorf("invalid bundle: relation %q: %v", strings.Join(relation, " "), err)
+		}
+	}
+	if _, err := d.DeployOrder(); err != nil {
+		return fmt.Errorf("invalid bundle: %v", err)
+	}
+	return nil
+}
+
+func (d *Data) verifyService(name string, spec *ServiceSpec) error {
+	if !names.IsValidService(name) {
+		return fmt.Errorf("invalid service name")
+	}
+	if spec.Charm == "" {
+		return fmt.Errorf("no charm")
+	}
+	if _, err := charm.ParseReference(spec.Charm); err != nil {
+		return err
+	}
+	if spec.NumUnits < 0 {
+		return fmt.Errorf("negative number of units")
+	}
+	if _, err := constraints.Parse(spec.Constraints); err != nil {
+		return err
+	}
+	if len(spec.To) > spec.NumUnits {
+		return fmt.Errorf("%d placements given for %d units", len(spec.To), spec.NumUnits)
+	}
+	for _, placement := range spec.To {
+		p, err := ParsePlacement(placement)
+		if err != nil {
+			return err
+		}
+		if p.Unit == "" {
+			continue
+		}
+		service := names.UnitService(p.Unit)
+		if service == name {
+			return fmt.Errorf("placement %q refers to the service itself", placement)
+		}
+		if _, ok := d.Services[service]; !ok {
+			return fmt.Errorf("placement %q refers to unknown service %q", placement, service)
+		}
+	}
+	return nil
+}
+
+func (d *Data) verifyRelation(relation []string) error {
+	if len(relation) != 2 {
+		return fmt.Errorf("expected two endpoints")
+	}
+	for _, endpoint := range relation {
+		service := strings.SplitN(endpoint, ":", 2)[0]
+		if _, ok := d.Services[service]; !ok {
+			return fmt.Errorf("endpoint %q refers to unknown service %q", endpoint, service)
+		}
+	}
+	return nil
+}
+
+// DeployOrder returns the names of the services in the order they
+// should be deployed, so that services are deployed after the services
+// whose units their units are placed with. It returns an error if the
+// placements refer to each other in a cycle.
+func (d *Data) DeployOrder() ([]string, error) {
+	var order []string
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	marks := make(map[string]int)
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visiting:
+			return fmt.Errorf("placement of service %q depends on itself", name)
+		case visited:
+			return nil
+		}
+		marks[name] = visiting
+		for _, placement := range d.Services[name].To {
+			p, err := ParsePlacement(placement)
+			if err != nil || p.Unit == "" {
+				continue
+			}
+			service := names.UnitService(p.Unit)
+			if _, ok := d.Services[service]; !ok {
+				continue
+			}
+			if err := visit(service); err != nil {
+				return err
+			}
+		}
+		marks[name] = visited
+		order = append(order, name)
+		return nil
+	}
+	for _, name := range d.serviceNames() {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// serviceNames returns the names of the services, sorted.
+func (d *Data) serviceNames() []string {
+	serviceNames := make([]string, 0, len(d.Services))
+	for name := range d.Services {
+		serviceNames = append(serviceNames, name)
+	}
+	sort.Strings(serviceNames)
+	return serviceNames
+}
@@ -0,0 +1,183 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundle_test
+
+import (
+	stdtesting "testing"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/bundle"
+	"github.com/juju/juju/instance"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type BundleSuite struct{}
+
+var _ = gc.Suite(&BundleSuite{})
+
+const wordpressBundle = `
+services:
+  wordpress:
+    charm: cs:precise/wordpress
+    num_units: 2
+    options:
+      debug: "yes"
+      port: 8080
+    constraints: mem=2G
+    to: ["1", "lxc:2"]
+    expose: true
+    annotations:
+      gui-x: "100"
+  mysql:
+    charm: mysql
+    num_units: 1
+    to: lxc:wordpress/0
+relations:
+  - [wordpress:db, mysql:server]
+`
+
+func (*BundleSuite) TestParse(c *gc.C) {
+	data, err := bundle.Parse([]byte(wordpressBundle))
+	c.Assert(err, gc.IsNil)
+	c.Assert(data, gc.DeepEquals, &bundle.Data{
+		Services: map[string]*bundle.ServiceSpec{
+			"wordpress": {
+				Charm:       "cs:precise/wordpress",
+				NumUnits:    2,
+				Options:     map[string]interface{}{"debug": "yes", "port": 8080},
+				Constraints: "mem=2G",
+				To:          []string{"1", "lxc:2"},
+				Expose:      true,
+				Annotations: map[string]string{"gui-x": "100"},
+			},
+			"mysql": {
+				Charm:    "mysql",
+				NumUnits: 1,
+				To:       []string{"lxc:wordpress/0"},
+			},
+		},
+		Relations: [][]string{{"wordpress:db", "mysql:server"}},
+	})
+}
+
+func (*BundleSuite) TestYAMLRoundTrip(c *gc.C) {
+	data, err := bundle.Parse([]byte(wordpressBundle))
+	c.Assert(err, gc.IsNil)
+	out, err := data.YAML()
+	c.Assert(err, gc.IsNil)
+	reparsed, err := bundle.Parse(out)
+	c.Assert(err, gc.IsNil)
+	c.Assert(reparsed, gc.DeepEquals, data)
+}
+
+func (*BundleSuite) TestDeployOrder(c *gc.C) {
+	data, err := bundle.Parse([]byte(`
+services:
+  app:
+    charm: app
+    num_units: 1
+    to: logger/0
+  db:
+    charm: mysql
+  logger:
+    charm: logger
+    num_units: 1
+    to: lxc:db/0
+`))
+	c.Assert(err, gc.IsNil)
+	order, err := data.DeployOrder()
+	c.Assert(err, gc.IsNil)
+	c.Assert(order, gc.DeepEquals, []string{"db", "logger", "app"})
+}
+
+var parseErrorTests = []struct {
+	about  string
+	bundle string
+	err    string
+}{{
+	about:  "not YAML",
+	bundle: "services: [",
+	err:    "cannot parse bundle: .*",
+}, {
+	about:  "no services",
+	bundle: "relations: []",
+	err:    "invalid bundle: no services",
+}, {
+	about:  "no charm",
+	bundle: "services: {wordpress: {num_units: 1}}",
+	err:    `invalid bundle: service "wordpress": no charm`,
+}, {
+	about:  "bad service name",
+	bundle: "services: {Word_Press: {charm: wordpress}}",
+	err:    `invalid bundle: service "Word_Press": invalid service name`,
+}, {
+	about:  "negative units",
+	bundle: "services: {wordpress: {charm: wordpress, num_units: -1}}",
+	err:    `invalid bundle: service "wordpress": negative number of units`,
+}, {
+	about:  "bad constraints",
+	bundle: "services: {wordpress: {charm: wordpress, constraints: bad}}",
+	err:    `invalid bundle: service "wordpress": malformed constraint "bad"`,
+}, {
+	about:  "too many placements",
+	bundle: "services: {wordpress: {charm: wordpress, num_units: 1, to: [\"1\", \"2\"]}}",
+	err:    `invalid bundle: service "wordpress": 2 placements given for 1 units`,
+}, {
+	about:  "bad placement",
+	bundle: "services: {wordpress: {charm: wordpress, num_units: 1, to: \"foo:1\"}}",
+	err:    `invalid bundle: service "wordpress": invalid placement "foo:1": unknown container type "foo"`,
+}, {
+	about:  "placement with unknown service",
+	bundle: "services: {wordpress: {charm: wordpress, num_units: 1, to: mysql/0}}",
+	err:    `invalid bundle: service "wordpress": placement "mysql/0" refers to unknown service "mysql"`,
+}, {
+	about:  "placement with the service itself",
+	bundle: "services: {wordpress: {charm: wordpress, num_units: 2, to: [\"1\", wordpress/0]}}",
+	err:    `invalid bundle: service "wordpress": placement "wordpress/0" refers to the service itself`,
+}, {
+	about:  "placement cycle",
+	bundle: "services: {a: {charm: a, num_units: 1, to: b/0}, b: {charm: b, num_units: 1, to: a/0}}",
+	err:    `invalid bundle: placement of service "a" depends on itself`,
+}, {
+	about:  "relation with one endpoint",
+	bundle: "services: {wordpress: {charm: wordpress}}\nrelations: [[wordpress]]",
+	err:    `invalid bundle: relation "wordpress": expected two endpoints`,
+}, {
+	about:  "relation with unknown service",
+	bundle: "services: {wordpress: {charm: wordpress}}\nrelations: [[wordpress:db, mysql:server]]",
+	err:    `invalid bundle: relation "wordpress:db mysql:server": endpoint "mysql:server" refers to unknown service "mysql"`,
+}}
+
+func (*BundleSuite) TestParseErrors(c *gc.C) {
+	for i, test := range parseErrorTests {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := bundle.Parse([]byte(test.bundle))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (*BundleSuite) TestParsePlacement(c *gc.C) {
+	for i, test := range []struct {
+		placement string
+		expect    bundle.Placement
+	}{
+		{"1", bundle.Placement{Machine: "1"}},
+		{"1/lxc/0", bundle.Placement{Machine: "1/lxc/0"}},
+		{"lxc:1", bundle.Placement{ContainerType: instance.LXC, Machine: "1"}},
+		{"mysql/0", bundle.Placement{Unit: "mysql/0"}},
+		{"kvm:mysql/0", bundle.Placement{ContainerType: instance.KVM, Unit: "mysql/0"}},
+	} {
+		c.Logf("test %d: %s", i, test.placement)
+		p, err := bundle.ParsePlacement(test.placement)
+		c.Assert(err, gc.IsNil)
+		c.Check(*p, gc.Equals, test.expect)
+		c.Check(p.String(), gc.Equals, test.placement)
+	}
+	_, err := bundle.ParsePlacement("mysql")
+	c.Assert(err, gc.ErrorMatches, `invalid placement "mysql"`)
+}
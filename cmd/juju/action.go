@@ -0,0 +1,85 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+)
+
+type ActionCommand struct {
+	*cmd.SuperCommand
+}
+
+type ActionCommandBase struct {
+	envcmd.EnvCommandBase
+}
+
+// ActionAPI defines the client API methods used by the action
+// subcommands.
+type ActionAPI interface {
+	EnqueueActions(actions ...params.ActionParams) ([]params.ActionStatusResult, error)
+	ListActions(receiverTags ...string) ([]params.ReceiverActionsResult, error)
+	ActionResults(actionTags ...string) ([]params.ActionStatusResult, error)
+	Close() error
+}
+
+var getActionAPI = func(c *ActionCommandBase) (ActionAPI, error) {
+	return c.NewAPIClient()
+}
+
+const actionCommandDoc = `
+"juju action" is used to queue actions defined by a unit's charm, and to
+inspect the progress and results of those actions.
+`
+
+const actionCommandPurpose = "queue and inspect charm actions"
+
+func NewActionCommand() cmd.Command {
+	actioncmd := &ActionCommand{
+		SuperCommand: cmd.NewSuperCommand(cmd.SuperCommandParams{
+			Name:        "action",
+			Doc:         actionCommandDoc,
+			UsagePrefix: "juju",
+			Purpose:     actionCommandPurpose,
+		}),
+	}
+	// Define each subcommand in a separate "action_FOO.go" source file
+	// (with tests in action_FOO_test.go) and wire in here.
+	actioncmd.Register(envcmd.Wrap(&ActionDoCommand{}))
+	actioncmd.Register(envcmd.Wrap(&ActionFetchCommand{}))
+	actioncmd.Register(envcmd.Wrap(&ActionListCommand{}))
+	return actioncmd
+}
+
+// actionStatusInfo is the formatted representation of an Action and
+// its outcome, used by the action subcommands.
+type actionStatusInfo struct {
+	Id      string                 `yaml:"id" json:"id"`
+	Name    string                 `yaml:"name" json:"name"`
+	Params  map[string]interface{} `yaml:"params,omitempty" json:"params,omitempty"`
+	Status  string                 `yaml:"status" json:"status"`
+	Results map[string]interface{} `yaml:"results,omitempty" json:"results,omitempty"`
+	Output  string                 `yaml:"output,omitempty" json:"output,omitempty"`
+}
+
+// newActionStatusInfo converts an API result into its displayed form,
+// showing the Action by its id rather than by its tag.
+func newActionStatusInfo(result params.ActionStatusResult) actionStatusInfo {
+	id := result.ActionTag
+	if tag, err := names.ParseActionTag(result.ActionTag); err == nil {
+		id = tag.Id()
+	}
+	return actionStatusInfo{
+		Id:      id,
+		Name:    result.Name,
+		Params:  result.Params,
+		Status:  result.Status,
+		Results: result.Results,
+		Output:  result.Output,
+	}
+}
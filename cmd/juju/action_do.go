@@ -0,0 +1,128 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/state/api/params"
+)
+
+const actionDoDoc = `
+Queue an action, defined by the charm, for execution on the given unit,
+or on each unit of the given service. Parameters for the action are
+given as key=value pairs. The id of the queued action is printed, and
+can be passed to "juju action fetch" to retrieve its result.
+
+An action queued on a service is run on all of the service's units at
+once, unless --batch is given, in which case it is run on at most that
+many units at a time. With --first-unit, the action is only run on the
+service's lowest numbered unit. Fetching a service action shows its
+progress on each unit, with the id, results and output of the action
+queued on that unit.
+
+Examples:
+  	# Queue a database dump on the postgresql/0 unit
+  	$ juju action do postgresql/0 dump outfile=/srv/dump.sql
+  	Action queued with id: postgresql/0_a_3
+
+  	# Restart the wordpress units two at a time
+  	$ juju action do wordpress restart --batch 2
+  	Action queued with id: wordpress_a_0
+`
+
+// ActionDoCommand queues an action for execution on a unit or service.
+type ActionDoCommand struct {
+	ActionCommandBase
+	ReceiverTag   string
+	ActionName    string
+	Params        map[string]interface{}
+	FirstUnitOnly bool
+	BatchSize     int
+}
+
+func (c *ActionDoCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "do",
+		Args:    "<unit or service> <action name> [key=value ...]",
+		Purpose: "queue an action for execution on a unit or service",
+		Doc:     actionDoDoc,
+	}
+}
+
+func (c *ActionDoCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.FirstUnitOnly, "first-unit", false, "run a service action on the service's first unit only")
+	f.IntVar(&c.BatchSize, "batch", 0, "run a service action on at most this many units at a time")
+}
+
+func (c *ActionDoCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return fmt.Errorf("no unit or service specified")
+	case 1:
+		return fmt.Errorf("no action specified")
+	}
+	switch {
+	case names.IsValidUnit(args[0]):
+		if c.FirstUnitOnly || c.BatchSize != 0 {
+			return fmt.Errorf("--first-unit and --batch can only be used with a service")
+		}
+		c.ReceiverTag = names.NewUnitTag(args[0]).String()
+	case names.IsValidService(args[0]):
+		if c.BatchSize < 0 {
+			return fmt.Errorf("invalid batch size %d", c.BatchSize)
+		}
+		c.ReceiverTag = names.NewServiceTag(args[0]).String()
+	default:
+		return fmt.Errorf("invalid unit or service name %q", args[0])
+	}
+	c.ActionName = args[1]
+	c.Params = make(map[string]interface{})
+	for i, arg := range args[2:] {
+		bits := strings.SplitN(arg, "=", 2)
+		if len(bits) < 2 || bits[0] == "" {
+			return fmt.Errorf(`expected "key=value", got %q in arg %d`, arg, i+3)
+		}
+		if _, exists := c.Params[bits[0]]; exists {
+			return fmt.Errorf("key %q specified more than once", bits[0])
+		}
+		c.Params[bits[0]] = bits[1]
+	}
+	return nil
+}
+
+func (c *ActionDoCommand) Run(ctx *cmd.Context) error {
+	client, err := getActionAPI(&c.ActionCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	results, err := client.EnqueueActions(params.ActionParams{
+		Receiver:      c.ReceiverTag,
+		Name:          c.ActionName,
+		Params:        c.Params,
+		FirstUnitOnly: c.FirstUnitOnly,
+		BatchSize:     c.BatchSize,
+	})
+	if err != nil {
+		return err
+	}
+	if len(results) != 1 {
+		return fmt.Errorf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Error != nil {
+		if errs := results[0].ValidationErrors; len(errs) > 0 {
+			return fmt.Errorf("cannot queue action %q:\n    %s", c.ActionName, strings.Join(errs, "\n    "))
+		}
+		return results[0].Error
+	}
+	info := newActionStatusInfo(results[0])
+	fmt.Fprintf(ctx.Stdout, "Action queued with id: %s\n", info.Id)
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+type ActionDoCommandSuite struct {
+	ActionCommandSuite
+}
+
+var _ = gc.Suite(&ActionDoCommandSuite{})
+
+func newActionDoCommand() cmd.Command {
+	return envcmd.Wrap(&ActionDoCommand{})
+}
+
+func (s *ActionDoCommandSuite) TestInit(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no unit or service specified"},
+		{[]string{"mysql/0"}, "no action specified"},
+		{[]string{"mysql/x", "dump"}, `invalid unit or service name "mysql/x"`},
+		{[]string{"mysql/0", "dump", "--batch", "2"}, "--first-unit and --batch can only be used with a service"},
+		{[]string{"mysql/0", "dump", "--first-unit"}, "--first-unit and --batch can only be used with a service"},
+		{[]string{"mysql", "dump", "--batch", "-1"}, "invalid batch size -1"},
+		{[]string{"mysql/0", "dump", "outfile"}, `expected "key=value", got "outfile" in arg 3`},
+		{[]string{"mysql/0", "dump", "a=1", "a=2"}, `key "a" specified more than once`},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		err := testing.InitCommand(&ActionDoCommand{}, t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *ActionDoCommandSuite) TestRun(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newActionDoCommand(), "mysql/0", "dump", "outfile=/srv/dump.sql", "tables=all")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "Action queued with id: mysql/0_a_0\n")
+	c.Assert(s.fake.enqueued, jc.DeepEquals, []params.ActionParams{{
+		Receiver: "unit-mysql-0",
+		Name:     "dump",
+		Params: map[string]interface{}{
+			"outfile": "/srv/dump.sql",
+			"tables":  "all",
+		},
+	}})
+}
+
+func (s *ActionDoCommandSuite) TestRunOnService(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newActionDoCommand(), "mysql", "dump", "--batch", "2")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "Action queued with id: mysql_a_0\n")
+	c.Assert(s.fake.enqueued, jc.DeepEquals, []params.ActionParams{{
+		Receiver:  "service-mysql",
+		Name:      "dump",
+		Params:    map[string]interface{}{},
+		BatchSize: 2,
+	}})
+
+	_, err = testing.RunCommand(c, newActionDoCommand(), "mysql", "dump", "--first-unit")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.fake.enqueued[1].FirstUnitOnly, jc.IsTrue)
+}
+
+func (s *ActionDoCommandSuite) TestRunInvalidAction(c *gc.C) {
+	_, err := testing.RunCommand(c, newActionDoCommand(), "mysql/0", "snapshop")
+	c.Assert(err, gc.ErrorMatches, "cannot queue action \"snapshop\":\n    not defined by charm")
+}
+
+func (s *ActionDoCommandSuite) TestRunError(c *gc.C) {
+	_, err := testing.RunCommand(c, newActionDoCommand(), "wordpress/1", "dump")
+	c.Assert(err, gc.ErrorMatches, "unit-wordpress-1 not found")
+}
@@ -0,0 +1,110 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/state/api/params"
+)
+
+const actionFetchDoc = `
+Show the status of a queued action, and its output once it has
+completed or failed. With --wait, keep checking until the action has
+finished or the given time has elapsed.
+
+Examples:
+  	$ juju action fetch postgresql/0_a_3 --wait 5m
+  	id: postgresql/0_a_3
+  	name: dump
+  	params:
+  	  outfile: /srv/dump.sql
+  	status: complete
+  	output: dumped 42 tables
+`
+
+// ActionFetchCommand shows the status and output of an action.
+type ActionFetchCommand struct {
+	ActionCommandBase
+	out      cmd.Output
+	ActionId string
+	Wait     time.Duration
+}
+
+// actionFetchPollDelay is how long "action fetch --wait" waits between
+// checks on the action's status.
+var actionFetchPollDelay = 2 * time.Second
+
+func (c *ActionFetchCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "fetch",
+		Args:    "<action id>",
+		Purpose: "show the status and output of an action",
+		Doc:     actionFetchDoc,
+	}
+}
+
+func (c *ActionFetchCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+	f.DurationVar(&c.Wait, "wait", 0, "wait up to this long for the action to finish")
+}
+
+func (c *ActionFetchCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return fmt.Errorf("no action id specified")
+	case 1:
+		if !names.IsValidAction(args[0]) {
+			return fmt.Errorf("invalid action id %q", args[0])
+		}
+		c.ActionId = args[0]
+		return nil
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *ActionFetchCommand) Run(ctx *cmd.Context) error {
+	client, err := getActionAPI(&c.ActionCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	tag := names.NewActionTag(c.ActionId).String()
+	deadline := time.After(c.Wait)
+	for {
+		result, err := fetchAction(client, tag)
+		if err != nil {
+			return err
+		}
+		if result.Status != params.ActionPending || c.Wait <= 0 {
+			return c.out.Write(ctx, newActionStatusInfo(result))
+		}
+		select {
+		case <-deadline:
+			return c.out.Write(ctx, newActionStatusInfo(result))
+		case <-time.After(actionFetchPollDelay):
+		}
+	}
+}
+
+// fetchAction returns the current status of the action with the given tag.
+func fetchAction(client ActionAPI, tag string) (params.ActionStatusResult, error) {
+	results, err := client.ActionResults(tag)
+	if err != nil {
+		return params.ActionStatusResult{}, err
+	}
+	if len(results) != 1 {
+		return params.ActionStatusResult{}, fmt.Errorf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Error != nil {
+		return params.ActionStatusResult{}, results[0].Error
+	}
+	return results[0], nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+type ActionFetchCommandSuite struct {
+	ActionCommandSuite
+}
+
+var _ = gc.Suite(&ActionFetchCommandSuite{})
+
+func (s *ActionFetchCommandSuite) SetUpTest(c *gc.C) {
+	s.ActionCommandSuite.SetUpTest(c)
+	s.PatchValue(&actionFetchPollDelay, time.Millisecond)
+	s.fake.actions["action-mysql/0_a_0"] = params.ActionStatusResult{
+		ActionTag: "action-mysql/0_a_0",
+		Name:      "dump",
+		Params:    map[string]interface{}{"outfile": "/srv/dump.sql"},
+		Status:    params.ActionCompleted,
+		Output:    "dumped 42 tables",
+	}
+}
+
+func newActionFetchCommand() cmd.Command {
+	return envcmd.Wrap(&ActionFetchCommand{})
+}
+
+func (s *ActionFetchCommandSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&ActionFetchCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no action id specified")
+	err = testing.InitCommand(&ActionFetchCommand{}, []string{"mysql/0"})
+	c.Assert(err, gc.ErrorMatches, `invalid action id "mysql/0"`)
+	err = testing.InitCommand(&ActionFetchCommand{}, []string{"mysql/0_a_0", "extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+const fetchedDump = `
+id: mysql/0_a_0
+name: dump
+params:
+  outfile: /srv/dump.sql
+status: complete
+output: dumped 42 tables
+`
+
+func (s *ActionFetchCommandSuite) TestFetch(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newActionFetchCommand(), "mysql/0_a_0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, fetchedDump[1:])
+}
+
+func (s *ActionFetchCommandSuite) TestFetchPendingWithoutWait(c *gc.C) {
+	s.fake.pendingFetches = 1
+	ctx, err := testing.RunCommand(c, newActionFetchCommand(), "mysql/0_a_0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Matches, "(?s).*status: pending\n")
+}
+
+func (s *ActionFetchCommandSuite) TestFetchWait(c *gc.C) {
+	s.fake.pendingFetches = 3
+	ctx, err := testing.RunCommand(c, newActionFetchCommand(), "mysql/0_a_0", "--wait", "1m")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, fetchedDump[1:])
+	c.Assert(s.fake.pendingFetches, gc.Equals, 0)
+}
+
+func (s *ActionFetchCommandSuite) TestFetchNotFound(c *gc.C) {
+	_, err := testing.RunCommand(c, newActionFetchCommand(), "mysql/0_a_1")
+	c.Assert(err, gc.ErrorMatches, "action-mysql/0_a_1 not found")
+}
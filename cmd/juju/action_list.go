@@ -0,0 +1,79 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+)
+
+const actionListDoc = `
+List the actions queued on a unit or service, followed by those that
+have already completed or failed.
+`
+
+// ActionListCommand lists the actions queued and completed on a unit or
+// service.
+type ActionListCommand struct {
+	ActionCommandBase
+	out         cmd.Output
+	ReceiverTag string
+}
+
+func (c *ActionListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Args:    "<unit or service>",
+		Purpose: "list the actions queued and completed on a unit or service",
+		Doc:     actionListDoc,
+	}
+}
+
+func (c *ActionListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *ActionListCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return fmt.Errorf("no unit or service specified")
+	case 1:
+		switch {
+		case names.IsValidUnit(args[0]):
+			c.ReceiverTag = names.NewUnitTag(args[0]).String()
+		case names.IsValidService(args[0]):
+			c.ReceiverTag = names.NewServiceTag(args[0]).String()
+		default:
+			return fmt.Errorf("invalid unit or service name %q", args[0])
+		}
+		return nil
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *ActionListCommand) Run(ctx *cmd.Context) error {
+	client, err := getActionAPI(&c.ActionCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	results, err := client.ListActions(c.ReceiverTag)
+	if err != nil {
+		return err
+	}
+	if len(results) != 1 {
+		return fmt.Errorf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Error != nil {
+		return results[0].Error
+	}
+	infos := make([]actionStatusInfo, len(results[0].Actions))
+	for i, result := range results[0].Actions {
+		infos[i] = newActionStatusInfo(result)
+	}
+	return c.out.Write(ctx, infos)
+}
@@ -0,0 +1,68 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+type ActionListCommandSuite struct {
+	ActionCommandSuite
+}
+
+var _ = gc.Suite(&ActionListCommandSuite{})
+
+func newActionListCommand() cmd.Command {
+	return envcmd.Wrap(&ActionListCommand{})
+}
+
+func (s *ActionListCommandSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&ActionListCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no unit or service specified")
+	err = testing.InitCommand(&ActionListCommand{}, []string{"mysql/"})
+	c.Assert(err, gc.ErrorMatches, `invalid unit or service name "mysql/"`)
+	err = testing.InitCommand(&ActionListCommand{}, []string{"mysql/0", "mysql/1"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["mysql/1"\]`)
+
+	command := &ActionListCommand{}
+	err = testing.InitCommand(command, []string{"mysql/0"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(command.ReceiverTag, gc.Equals, "unit-mysql-0")
+	command = &ActionListCommand{}
+	err = testing.InitCommand(command, []string{"mysql"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(command.ReceiverTag, gc.Equals, "service-mysql")
+}
+
+func (s *ActionListCommandSuite) TestList(c *gc.C) {
+	s.fake.actions["action-mysql/0_a_1"] = params.ActionStatusResult{
+		ActionTag: "action-mysql/0_a_1",
+		Name:      "backup",
+		Status:    params.ActionFailed,
+		Output:    "disk full",
+	}
+	ctx, err := testing.RunCommand(c, newActionListCommand(), "mysql/0", "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals,
+		`[{"id":"mysql/0_a_1","name":"backup","status":"fail","output":"disk full"}]`+"\n")
+	c.Assert(s.fake.listed, gc.DeepEquals, []string{"unit-mysql-0"})
+}
+
+func (s *ActionListCommandSuite) TestListService(c *gc.C) {
+	s.fake.actions["action-mysql_a_0"] = params.ActionStatusResult{
+		ActionTag: "action-mysql_a_0",
+		Name:      "backup",
+		Status:    params.ActionPending,
+	}
+	ctx, err := testing.RunCommand(c, newActionListCommand(), "mysql", "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals,
+		`[{"id":"mysql_a_0","name":"backup","status":"pending"}]`+"\n")
+	c.Assert(s.fake.listed, gc.DeepEquals, []string{"service-mysql"})
+}
@@ -0,0 +1,112 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+// ActionCommandSuite provides a fake action API shared by the tests for
+// the "action" subcommands.
+type ActionCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeActionAPI
+}
+
+func (s *ActionCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeActionAPI{
+		actions: make(map[string]params.ActionStatusResult),
+	}
+	s.PatchValue(&getActionAPI, func(*ActionCommandBase) (ActionAPI, error) {
+		return s.fake, nil
+	})
+}
+
+type fakeActionAPI struct {
+	enqueued []params.ActionParams
+	listed   []string
+	actions  map[string]params.ActionStatusResult
+	// pendingFetches is the number of ActionResults calls that will
+	// report an action as pending before its recorded result is returned.
+	pendingFetches int
+}
+
+func (*fakeActionAPI) Close() error {
+	return nil
+}
+
+func (f *fakeActionAPI) EnqueueActions(actions ...params.ActionParams) ([]params.ActionStatusResult, error) {
+	var results []params.ActionStatusResult
+	for _, action := range actions {
+		f.enqueued = append(f.enqueued, action)
+		var prefix string
+		switch action.Receiver {
+		case "unit-mysql-0":
+			prefix = "mysql/0"
+		case "service-mysql":
+			prefix = "mysql"
+		default:
+			results = append(results, params.ActionStatusResult{
+				Error: &params.Error{Message: fmt.Sprintf("%s not found", action.Receiver)},
+			})
+			continue
+		}
+		if action.Name == "snapshop" {
+			results = append(results, params.ActionStatusResult{
+				Error: &params.Error{
+					Message: fmt.Sprintf("invalid action %q", action.Name),
+					Code:    params.CodeActionNotValid,
+				},
+				ValidationErrors: []string{"not defined by charm"},
+			})
+			continue
+		}
+		results = append(results, params.ActionStatusResult{
+			ActionTag: fmt.Sprintf("action-%s_a_%d", prefix, len(f.enqueued)-1),
+			Name:      action.Name,
+			Params:    action.Params,
+			Status:    params.ActionPending,
+		})
+	}
+	return results, nil
+}
+
+func (f *fakeActionAPI) ListActions(receiverTags ...string) ([]params.ReceiverActionsResult, error) {
+	var results []params.ReceiverActionsResult
+	for _, tag := range receiverTags {
+		f.listed = append(f.listed, tag)
+		result := params.ReceiverActionsResult{Receiver: tag}
+		for _, action := range f.actions {
+			result.Actions = append(result.Actions, action)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (f *fakeActionAPI) ActionResults(actionTags ...string) ([]params.ActionStatusResult, error) {
+	var results []params.ActionStatusResult
+	for _, tag := range actionTags {
+		action, ok := f.actions[tag]
+		if !ok {
+			results = append(results, params.ActionStatusResult{
+				Error: &params.Error{Message: fmt.Sprintf("%s not found", tag)},
+			})
+			continue
+		}
+		if f.pendingFetches > 0 {
+			f.pendingFetches--
+			action.Status = params.ActionPending
+			action.Output = ""
+		}
+		results = append(results, action)
+	}
+	return results, nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+)
+
+// AuditLogCommand shows the changes made to the environment.
+type AuditLogCommand struct {
+	envcmd.EnvCommandBase
+	out    cmd.Output
+	since  string
+	until  string
+	filter params.AuditLogFilter
+}
+
+const auditLogDoc = `
+Show the changes made to the environment through the API: who made each
+change and when, the API call that made it, the services, units and
+machines it was made to, and the error if the change failed.  Entries may
+be selected by user, by the entity changed, and by the time they were
+recorded.  Times are given either in RFC3339 format, such as
+2014-09-01T12:00:00Z, or as a duration before now, such as 24h.
+
+The arguments of calls that may hold secrets, such as configuration
+settings, are not recorded.
+
+Examples:
+    juju audit-log --since 24h
+    juju audit-log --user bob wordpress
+`
+
+func (c *AuditLogCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "audit-log",
+		Args:    "[<service, unit or machine>]",
+		Purpose: "show the changes made to the environment",
+		Doc:     auditLogDoc,
+	}
+}
+
+func (c *AuditLogCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.filter.User, "user", "", "only show changes made by this user")
+	f.StringVar(&c.since, "since", "", "only show changes made from this time")
+	f.StringVar(&c.until, "until", "", "only show changes made before this time")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAuditLogTabular,
+	})
+}
+
+func (c *AuditLogCommand) Init(args []string) (err error) {
+	if len(args) > 0 {
+		c.filter.Entity, args = args[0], args[1:]
+		if !names.IsValidService(c.filter.Entity) &&
+			!names.IsValidUnit(c.filter.Entity) &&
+			!names.IsValidMachine(c.filter.Entity) {
+			return fmt.Errorf("%q is not a valid service, unit or machine name", c.filter.Entity)
+		}
+	}
+	if c.filter.User != "" && !names.IsValidUser(c.filter.User) {
+		return fmt.Errorf("%q is not a valid user name", c.filter.User)
+	}
+	now := time.Now()
+	if c.filter.Since, err = parseTimeFlag("since", c.since, now); err != nil {
+		return err
+	}
+	if c.filter.Until, err = parseTimeFlag("until", c.until, now); err != nil {
+		return err
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// AuditLogAPI defines the client API methods used by the audit-log
+// command.
+type AuditLogAPI interface {
+	AuditLog(filter params.AuditLogFilter) ([]params.AuditLogEntry, error)
+	Close() error
+}
+
+var getAuditLogAPI = func(c *AuditLogCommand) (AuditLogAPI, error) {
+	return c.NewAPIClient()
+}
+
+// auditLogInfo is the representation of an audit log entry written by
+// the audit-log command.
+type auditLogInfo struct {
+	Time    string   `json:"time" yaml:"time"`
+	User    string   `json:"user" yaml:"user"`
+	Call    string   `json:"call" yaml:"call"`
+	Targets []string `json:"targets,omitempty" yaml:"targets,omitempty"`
+	Args    string   `json:"args,omitempty" yaml:"args,omitempty"`
+	Error   string   `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func (c *AuditLogCommand) Run(ctx *cmd.Context) error {
+	client, err := getAuditLogAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	entries, err := client.AuditLog(c.filter)
+	if err != nil {
+		return err
+	}
+	infos := make([]auditLogInfo, len(entries))
+	for i, entry := range entries {
+		user := entry.User
+		if tag, err := names.ParseUserTag(user); err == nil {
+			user = tag.Id()
+		}
+		infos[i] = auditLogInfo{
+			Time:    entry.Time.UTC().Format(time.RFC3339),
+			User:    user,
+			Call:    entry.Facade + "." + entry.Method,
+			Targets: entry.Targets,
+			Args:    entry.Args,
+			Error:   entry.Error,
+		}
+	}
+	return c.out.Write(ctx, infos)
+}
+
+// formatAuditLogTabular writes the audit log entries as a table with a
+// header line. Call arguments are only shown by the other formats.
+func formatAuditLogTabular(value interface{}) ([]byte, error) {
+	infos, ok := value.([]auditLogInfo)
+	if !ok {
+		return nil, fmt.Errorf("expected value of type %T, got %T", infos, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tUSER\tCALL\tTARGETS\tERROR")
+	for _, info := range infos {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			info.Time, info.User, info.Call, strings.Join(info.Targets, ","), info.Error)
+	}
+	tw.Flush()
+	return trimLines(out.Bytes()), nil
+}
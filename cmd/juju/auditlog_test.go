@@ -0,0 +1,147 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+type AuditLogSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeAuditLogAPI
+}
+
+var _ = gc.Suite(&AuditLogSuite{})
+
+var auditLogTime = time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *AuditLogSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeAuditLogAPI{
+		entries: []params.AuditLogEntry{{
+			Time:    auditLogTime,
+			User:    "user-admin",
+			Facade:  "Client",
+			Method:  "ServiceDeploy",
+			Targets: []string{"wordpress"},
+			Args:    "<redacted>",
+		}, {
+			Time:    auditLogTime.Add(time.Minute),
+			User:    "user-bob",
+			Facade:  "Client",
+			Method:  "DestroyMachines",
+			Targets: []string{"1", "2"},
+			Args:    `{"MachineNames":["1","2"],"Force":false}`,
+			Error:   `machine 2 has unit "wordpress/0" assigned`,
+		}},
+	}
+	s.PatchValue(&getAuditLogAPI, func(*AuditLogCommand) (AuditLogAPI, error) {
+		return s.fake, nil
+	})
+}
+
+type fakeAuditLogAPI struct {
+	filter  params.AuditLogFilter
+	entries []params.AuditLogEntry
+	err     error
+}
+
+func (f *fakeAuditLogAPI) AuditLog(filter params.AuditLogFilter) ([]params.AuditLogEntry, error) {
+	f.filter = filter
+	return f.entries, f.err
+}
+
+func (f *fakeAuditLogAPI) Close() error {
+	return nil
+}
+
+func (s *AuditLogSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		expected params.AuditLogFilter
+		errMatch string
+	}{{
+		expected: params.AuditLogFilter{},
+	}, {
+		args:     []string{"--user", "bob", "wordpress/0"},
+		expected: params.AuditLogFilter{User: "bob", Entity: "wordpress/0"},
+	}, {
+		args:     []string{"0/lxc/1"},
+		expected: params.AuditLogFilter{Entity: "0/lxc/1"},
+	}, {
+		args: []string{"--since", "2014-09-01T12:00:00Z", "--until", "2014-09-02T12:00:00Z"},
+		expected: params.AuditLogFilter{
+			Since: auditLogTime,
+			Until: auditLogTime.Add(24 * time.Hour),
+		},
+	}, {
+		args:     []string{"wordpress/"},
+		errMatch: `"wordpress/" is not a valid service, unit or machine name`,
+	}, {
+		args:     []string{"--user", "bob/0"},
+		errMatch: `"bob/0" is not a valid user name`,
+	}, {
+		args:     []string{"--since", "yesterday"},
+		errMatch: `invalid since value "yesterday": expected a time or a duration`,
+	}, {
+		args:     []string{"wordpress", "mysql"},
+		errMatch: `unrecognized args: \["mysql"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &AuditLogCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.errMatch == "" {
+			c.Check(err, gc.IsNil)
+			c.Check(command.filter, jc.DeepEquals, test.expected)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *AuditLogSuite) TestTabular(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AuditLogCommand{}), "--user", "bob")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.fake.filter.User, gc.Equals, "bob")
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+TIME                  USER   CALL                    TARGETS    ERROR
+2014-09-01T12:00:00Z  admin  Client.ServiceDeploy    wordpress
+2014-09-01T12:01:00Z  bob    Client.DestroyMachines  1,2        machine 2 has unit "wordpress/0" assigned
+`[1:])
+}
+
+func (s *AuditLogSuite) TestYAML(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AuditLogCommand{}), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+- time: "2014-09-01T12:00:00Z"
+  user: admin
+  call: Client.ServiceDeploy
+  targets:
+  - wordpress
+  args: <redacted>
+- time: "2014-09-01T12:01:00Z"
+  user: bob
+  call: Client.DestroyMachines
+  targets:
+  - "1"
+  - "2"
+  args: '{"MachineNames":["1","2"],"Force":false}'
+  error: machine 2 has unit "wordpress/0" assigned
+`[1:])
+}
+
+func (s *AuditLogSuite) TestError(c *gc.C) {
+	s.fake.err = fmt.Errorf("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&AuditLogCommand{}))
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,119 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io"
+	"time"
+
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/backups"
+	"github.com/juju/juju/state/api/params"
+)
+
+type BackupsCommand struct {
+	*cmd.SuperCommand
+}
+
+type BackupsCommandBase struct {
+	envcmd.EnvCommandBase
+}
+
+// BackupsAPI defines the client API methods used by the backups
+// subcommands.
+type BackupsAPI interface {
+	Create(notes string) (*params.BackupsMetadataResult, error)
+	Info(id string) (*params.BackupsMetadataResult, error)
+	List() (*params.BackupsListResult, error)
+	LastGood() (*params.BackupsMetadataResult, error)
+	Download(id string) (io.ReadCloser, error)
+	Remove(id string) error
+	Upload(archive io.Reader, checksum string) (*params.BackupsMetadataResult, error)
+	Restore(id string) error
+	Close() error
+}
+
+var getBackupsAPI = func(c *BackupsCommandBase) (BackupsAPI, error) {
+	return c.NewBackupsClient()
+}
+
+// NewBackupsClient returns a backups client for the root api endpoint
+// that the environment command returns.
+func (c *BackupsCommandBase) NewBackupsClient() (*backups.Client, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return backups.NewClient(root), nil
+}
+
+const backupsCommandDoc = `
+"juju backups" is used to create and manage backups of the state of the
+Juju environment.  Backups are run on a state server, and the resulting
+archives are kept in the environment's storage, from which they may be
+downloaded.  An environment may be restored from a stored or uploaded
+backup.
+`
+
+const backupsCommandPurpose = "create and manage backups of juju state"
+
+func NewBackupsCommand() cmd.Command {
+	backupscmd := &BackupsCommand{
+		SuperCommand: cmd.NewSuperCommand(cmd.SuperCommandParams{
+			Name:        "backups",
+			Doc:         backupsCommandDoc,
+			UsagePrefix: "juju",
+			Purpose:     backupsCommandPurpose,
+		}),
+	}
+	// Define each subcommand in a separate "backups_FOO.go" source file
+	// (with tests in backups_FOO_test.go) and wire in here.
+	backupscmd.Register(envcmd.Wrap(&BackupsCreateCommand{}))
+	backupscmd.Register(envcmd.Wrap(&BackupsInfoCommand{}))
+	backupscmd.Register(envcmd.Wrap(&BackupsListCommand{}))
+	backupscmd.Register(envcmd.Wrap(&BackupsDownloadCommand{}))
+	backupscmd.Register(envcmd.Wrap(&BackupsRemoveCommand{}))
+	backupscmd.Register(envcmd.Wrap(&BackupsUploadCommand{}))
+	backupscmd.Register(envcmd.Wrap(&BackupsRestoreCommand{}))
+	return backupscmd
+}
+
+// backupInfo is the formatted representation of the metadata of a
+// backup, used by the backups subcommands.
+type backupInfo struct {
+	Id             string `yaml:"id" json:"id"`
+	Started        string `yaml:"started" json:"started"`
+	Finished       string `yaml:"finished" json:"finished"`
+	Checksum       string `yaml:"checksum" json:"checksum"`
+	ChecksumFormat string `yaml:"checksum-format" json:"checksum-format"`
+	Size           int64  `yaml:"size" json:"size"`
+	Stored         bool   `yaml:"stored" json:"stored"`
+	Notes          string `yaml:"notes,omitempty" json:"notes,omitempty"`
+	Error          string `yaml:"error,omitempty" json:"error,omitempty"`
+	Environment    string `yaml:"environment" json:"environment"`
+	Machine        string `yaml:"machine" json:"machine"`
+	Hostname       string `yaml:"hostname" json:"hostname"`
+	Version        string `yaml:"version" json:"version"`
+}
+
+// newBackupInfo converts an API result into its displayed form.
+func newBackupInfo(result *params.BackupsMetadataResult) backupInfo {
+	return backupInfo{
+		Id:             result.ID,
+		Started:        result.Started.Format(time.RFC3339),
+		Finished:       result.Finished.Format(time.RFC3339),
+		Checksum:       result.Checksum,
+		ChecksumFormat: result.ChecksumFormat,
+		Size:           result.Size,
+		Stored:         result.Stored,
+		Notes:          result.Notes,
+		Error:          result.Error,
+		Environment:    result.Environment,
+		Machine:        result.Machine,
+		Hostname:       result.Hostname,
+		Version:        result.Version.String(),
+	}
+}
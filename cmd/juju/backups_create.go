@@ -0,0 +1,59 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+)
+
+const backupsCreateDoc = `
+Create a backup of the state of the environment on a state server and
+store it in the environment's storage.  Any arguments are recorded with
+the backup as notes.  The metadata of the new backup is printed.
+
+Examples:
+  juju backups create
+  juju backups create "before upgrading to 1.20"
+`
+
+// BackupsCreateCommand creates a new backup.
+type BackupsCreateCommand struct {
+	BackupsCommandBase
+	out   cmd.Output
+	Notes string
+}
+
+func (c *BackupsCreateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create",
+		Args:    "[<notes>]",
+		Purpose: "create a backup of juju state",
+		Doc:     backupsCreateDoc,
+	}
+}
+
+func (c *BackupsCreateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *BackupsCreateCommand) Init(args []string) error {
+	c.Notes = strings.Join(args, " ")
+	return nil
+}
+
+func (c *BackupsCreateCommand) Run(ctx *cmd.Context) error {
+	client, err := getBackupsAPI(&c.BackupsCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	result, err := client.Create(c.Notes)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, newBackupInfo(result))
+}
@@ -0,0 +1,36 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type BackupsCreateCommandSuite struct {
+	BackupsCommandSuite
+}
+
+var _ = gc.Suite(&BackupsCreateCommandSuite{})
+
+func newBackupsCreateCommand() cmd.Command {
+	return envcmd.Wrap(&BackupsCreateCommand{})
+}
+
+func (s *BackupsCreateCommandSuite) TestCreate(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newBackupsCreateCommand(), "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(s.fake.notes, gc.Equals, "")
+	c.Check(testing.Stdout(ctx), gc.Equals, expectedBackupInfo("new-backup", "")+"\n")
+}
+
+func (s *BackupsCreateCommandSuite) TestCreateWithNotes(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newBackupsCreateCommand(), "before", "upgrade", "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(s.fake.notes, gc.Equals, "before upgrade")
+	c.Check(testing.Stdout(ctx), gc.Equals, expectedBackupInfo("new-backup", "before upgrade")+"\n")
+}
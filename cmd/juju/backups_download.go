@@ -0,0 +1,75 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+)
+
+const backupsDownloadDoc = `
+Download the archive of a stored backup.  Unless --filename is given,
+the archive is saved as juju-backup-<backup id>.tar.gz in the current
+directory.
+`
+
+// BackupsDownloadCommand downloads the archive of a backup.
+type BackupsDownloadCommand struct {
+	BackupsCommandBase
+	Id       string
+	Filename string
+}
+
+func (c *BackupsDownloadCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "download",
+		Args:    "<backup id>",
+		Purpose: "download the archive of a backup",
+		Doc:     backupsDownloadDoc,
+	}
+}
+
+func (c *BackupsDownloadCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Filename, "filename", "", "file to save the archive to")
+}
+
+func (c *BackupsDownloadCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no backup id specified")
+	}
+	c.Id = args[0]
+	if c.Filename == "" {
+		c.Filename = fmt.Sprintf("juju-backup-%s.tar.gz", c.Id)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *BackupsDownloadCommand) Run(ctx *cmd.Context) error {
+	client, err := getBackupsAPI(&c.BackupsCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	archive, err := client.Download(c.Id)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	filename := ctx.AbsPath(c.Filename)
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot create archive file: %v", err)
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, archive); err != nil {
+		return fmt.Errorf("cannot download backup %q: %v", c.Id, err)
+	}
+	fmt.Fprintf(ctx.Stdout, "backup %q downloaded to %s\n", c.Id, filename)
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type BackupsDownloadCommandSuite struct {
+	BackupsCommandSuite
+}
+
+var _ = gc.Suite(&BackupsDownloadCommandSuite{})
+
+func newBackupsDownloadCommand() cmd.Command {
+	return envcmd.Wrap(&BackupsDownloadCommand{})
+}
+
+func (s *BackupsDownloadCommandSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&BackupsDownloadCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no backup id specified")
+
+	command := &BackupsDownloadCommand{}
+	err = testing.InitCommand(command, []string{"some-id"})
+	c.Assert(err, gc.IsNil)
+	c.Check(command.Filename, gc.Equals, "juju-backup-some-id.tar.gz")
+}
+
+func (s *BackupsDownloadCommandSuite) TestDownload(c *gc.C) {
+	s.fake.backups["some-id"] = fakeBackupMetadata("some-id", "")
+	filename := filepath.Join(c.MkDir(), "backup.tar.gz")
+	ctx, err := testing.RunCommand(c, newBackupsDownloadCommand(), "some-id", "--filename", filename)
+	c.Assert(err, gc.IsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, `backup "some-id" downloaded to `+filename+"\n")
+	data, err := ioutil.ReadFile(filename)
+	c.Assert(err, gc.IsNil)
+	c.Check(string(data), gc.Equals, "archive of some-id")
+}
+
+func (s *BackupsDownloadCommandSuite) TestDownloadNotFound(c *gc.C) {
+	filename := filepath.Join(c.MkDir(), "backup.tar.gz")
+	_, err := testing.RunCommand(c, newBackupsDownloadCommand(), "spam", "--filename", filename)
+	c.Check(err, gc.ErrorMatches, `backup metadata "spam" not found`)
+}
@@ -0,0 +1,69 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/state/api/params"
+)
+
+const backupsInfoDoc = `
+Show the metadata of a stored backup.  With --last-good, the metadata of
+the most recent backup that succeeded is shown instead.
+`
+
+// BackupsInfoCommand shows the metadata of a backup.
+type BackupsInfoCommand struct {
+	BackupsCommandBase
+	out      cmd.Output
+	Id       string
+	LastGood bool
+}
+
+func (c *BackupsInfoCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "info",
+		Args:    "<backup id> | --last-good",
+		Purpose: "show the metadata of a backup",
+		Doc:     backupsInfoDoc,
+	}
+}
+
+func (c *BackupsInfoCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+	f.BoolVar(&c.LastGood, "last-good", false, "show the most recent successful backup")
+}
+
+func (c *BackupsInfoCommand) Init(args []string) error {
+	if c.LastGood {
+		return cmd.CheckEmpty(args)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("no backup id specified")
+	}
+	c.Id = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *BackupsInfoCommand) Run(ctx *cmd.Context) error {
+	client, err := getBackupsAPI(&c.BackupsCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	var result *params.BackupsMetadataResult
+	if c.LastGood {
+		result, err = client.LastGood()
+	} else {
+		result, err = client.Info(c.Id)
+	}
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, newBackupInfo(result))
+}
@@ -0,0 +1,55 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type BackupsInfoCommandSuite struct {
+	BackupsCommandSuite
+}
+
+var _ = gc.Suite(&BackupsInfoCommandSuite{})
+
+func newBackupsInfoCommand() cmd.Command {
+	return envcmd.Wrap(&BackupsInfoCommand{})
+}
+
+func (s *BackupsInfoCommandSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&BackupsInfoCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no backup id specified")
+	err = testing.InitCommand(&BackupsInfoCommand{}, []string{"a", "b"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b"\]`)
+	err = testing.InitCommand(&BackupsInfoCommand{}, []string{"--last-good", "a"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["a"\]`)
+}
+
+func (s *BackupsInfoCommandSuite) TestInfo(c *gc.C) {
+	s.fake.backups["some-id"] = fakeBackupMetadata("some-id", "some notes")
+	ctx, err := testing.RunCommand(c, newBackupsInfoCommand(), "some-id", "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, expectedBackupInfo("some-id", "some notes")+"\n")
+}
+
+func (s *BackupsInfoCommandSuite) TestInfoNotFound(c *gc.C) {
+	_, err := testing.RunCommand(c, newBackupsInfoCommand(), "spam")
+	c.Check(err, gc.ErrorMatches, `backup metadata "spam" not found`)
+}
+
+func (s *BackupsInfoCommandSuite) TestInfoLastGood(c *gc.C) {
+	s.fake.backups["some-id"] = fakeBackupMetadata("some-id", "")
+	ctx, err := testing.RunCommand(c, newBackupsInfoCommand(), "--last-good", "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, expectedBackupInfo("some-id", "")+"\n")
+}
+
+func (s *BackupsInfoCommandSuite) TestInfoLastGoodNotFound(c *gc.C) {
+	_, err := testing.RunCommand(c, newBackupsInfoCommand(), "--last-good")
+	c.Check(err, gc.ErrorMatches, "successful backup not found")
+}
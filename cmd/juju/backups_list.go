@@ -0,0 +1,52 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+)
+
+const backupsListDoc = `
+List the metadata of every stored backup, oldest first.
+`
+
+// BackupsListCommand lists the stored backups.
+type BackupsListCommand struct {
+	BackupsCommandBase
+	out cmd.Output
+}
+
+func (c *BackupsListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list the stored backups",
+		Doc:     backupsListDoc,
+	}
+}
+
+func (c *BackupsListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *BackupsListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *BackupsListCommand) Run(ctx *cmd.Context) error {
+	client, err := getBackupsAPI(&c.BackupsCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	result, err := client.List()
+	if err != nil {
+		return err
+	}
+	infos := make([]backupInfo, len(result.List))
+	for i := range result.List {
+		infos[i] = newBackupInfo(&result.List[i])
+	}
+	return c.out.Write(ctx, infos)
+}
@@ -0,0 +1,35 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type BackupsListCommandSuite struct {
+	BackupsCommandSuite
+}
+
+var _ = gc.Suite(&BackupsListCommandSuite{})
+
+func newBackupsListCommand() cmd.Command {
+	return envcmd.Wrap(&BackupsListCommand{})
+}
+
+func (s *BackupsListCommandSuite) TestList(c *gc.C) {
+	s.fake.backups["some-id"] = fakeBackupMetadata("some-id", "")
+	ctx, err := testing.RunCommand(c, newBackupsListCommand(), "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "["+expectedBackupInfo("some-id", "")+"]\n")
+}
+
+func (s *BackupsListCommandSuite) TestListEmpty(c *gc.C) {
+	ctx, err := testing.RunCommand(c, newBackupsListCommand(), "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "[]\n")
+}
@@ -0,0 +1,46 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+)
+
+const backupsRemoveDoc = `
+Remove a stored backup: both its archive and its metadata are deleted.
+`
+
+// BackupsRemoveCommand removes a backup.
+type BackupsRemoveCommand struct {
+	BackupsCommandBase
+	Id string
+}
+
+func (c *BackupsRemoveCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove",
+		Args:    "<backup id>",
+		Purpose: "remove a stored backup",
+		Doc:     backupsRemoveDoc,
+	}
+}
+
+func (c *BackupsRemoveCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no backup id specified")
+	}
+	c.Id = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *BackupsRemoveCommand) Run(ctx *cmd.Context) error {
+	client, err := getBackupsAPI(&c.BackupsCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.Remove(c.Id)
+}
@@ -0,0 +1,39 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type BackupsRemoveCommandSuite struct {
+	BackupsCommandSuite
+}
+
+var _ = gc.Suite(&BackupsRemoveCommandSuite{})
+
+func newBackupsRemoveCommand() cmd.Command {
+	return envcmd.Wrap(&BackupsRemoveCommand{})
+}
+
+func (s *BackupsRemoveCommandSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&BackupsRemoveCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no backup id specified")
+}
+
+func (s *BackupsRemoveCommandSuite) TestRemove(c *gc.C) {
+	s.fake.backups["some-id"] = fakeBackupMetadata("some-id", "")
+	_, err := testing.RunCommand(c, newBackupsRemoveCommand(), "some-id")
+	c.Assert(err, gc.IsNil)
+	c.Check(s.fake.removed, gc.DeepEquals, []string{"some-id"})
+}
+
+func (s *BackupsRemoveCommandSuite) TestRemoveNotFound(c *gc.C) {
+	_, err := testing.RunCommand(c, newBackupsRemoveCommand(), "spam")
+	c.Check(err, gc.ErrorMatches, `backup metadata "spam" not found`)
+}
@@ -0,0 +1,81 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+)
+
+const backupsRestoreDoc = `
+Restore the state of the environment from a backup.  The backup is
+either one stored in the environment, identified by its id, or a backup
+archive given with --file, which is uploaded first.
+
+The state server checks that the backup was made of this environment
+by a compatible version of juju before replacing its database and
+state-related files with those in the backup.  The state server then
+restarts, and the other agents of the environment reconnect to it.
+Environments with more than one state server cannot be restored.
+
+Examples:
+  juju backups restore 5f9c1c5e-3b2a-4c43-8a56-8e2d4f8f0a1b
+  juju backups restore --file juju-backup-5f9c1c5e-3b2a-4c43-8a56-8e2d4f8f0a1b.tar.gz
+`
+
+// BackupsRestoreCommand restores the environment from a backup.
+type BackupsRestoreCommand struct {
+	BackupsCommandBase
+	Id       string
+	Filename string
+}
+
+func (c *BackupsRestoreCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "restore",
+		Args:    "[<backup id>]",
+		Purpose: "restore the environment from a backup",
+		Doc:     backupsRestoreDoc,
+	}
+}
+
+func (c *BackupsRestoreCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Filename, "file", "", "backup archive to upload and restore")
+}
+
+func (c *BackupsRestoreCommand) Init(args []string) error {
+	if c.Filename == "" {
+		if len(args) == 0 {
+			return fmt.Errorf("no backup id or archive specified")
+		}
+		c.Id, args = args[0], args[1:]
+	} else if len(args) > 0 {
+		return fmt.Errorf("cannot specify both a backup id and an archive")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *BackupsRestoreCommand) Run(ctx *cmd.Context) error {
+	client, err := getBackupsAPI(&c.BackupsCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	id := c.Id
+	if c.Filename != "" {
+		result, err := uploadBackup(client, ctx.AbsPath(c.Filename))
+		if err != nil {
+			return err
+		}
+		id = result.ID
+		fmt.Fprintf(ctx.Stdout, "uploaded backup %q\n", id)
+	}
+	if err := client.Restore(id); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Stdout, "restored backup %q; the state server is restarting\n", id)
+	return nil
+}
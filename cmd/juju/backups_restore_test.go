@@ -0,0 +1,65 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type BackupsRestoreCommandSuite struct {
+	BackupsCommandSuite
+}
+
+var _ = gc.Suite(&BackupsRestoreCommandSuite{})
+
+func newBackupsRestoreCommand() cmd.Command {
+	return envcmd.Wrap(&BackupsRestoreCommand{})
+}
+
+func (s *BackupsRestoreCommandSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no backup id or archive specified",
+	}, {
+		args: []string{"--file", "backup.tar.gz", "some-id"},
+		err:  "cannot specify both a backup id and an archive",
+	}, {
+		args: []string{"some-id", "other-id"},
+		err:  `unrecognized args: \["other-id"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(&BackupsRestoreCommand{}, test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *BackupsRestoreCommandSuite) TestRestore(c *gc.C) {
+	s.fake.backups["some-id"] = fakeBackupMetadata("some-id", "")
+	ctx, err := testing.RunCommand(c, newBackupsRestoreCommand(), "some-id")
+	c.Assert(err, gc.IsNil)
+	c.Check(s.fake.restored, gc.DeepEquals, []string{"some-id"})
+	c.Check(testing.Stdout(ctx), gc.Equals, "restored backup \"some-id\"; the state server is restarting\n")
+}
+
+func (s *BackupsRestoreCommandSuite) TestRestoreNotFound(c *gc.C) {
+	_, err := testing.RunCommand(c, newBackupsRestoreCommand(), "spam")
+	c.Check(err, gc.ErrorMatches, `backup metadata "spam" not found`)
+}
+
+func (s *BackupsRestoreCommandSuite) TestRestoreFile(c *gc.C) {
+	filename := writeArchive(c, c.MkDir())
+	ctx, err := testing.RunCommand(c, newBackupsRestoreCommand(), "--file", filename)
+	c.Assert(err, gc.IsNil)
+	c.Check(s.fake.archive, gc.Equals, "archive data")
+	c.Check(s.fake.checksum, gc.Equals, archiveChecksum)
+	c.Check(s.fake.restored, gc.DeepEquals, []string{"uploaded-backup"})
+	c.Check(testing.Stdout(ctx), gc.Equals, "uploaded backup \"uploaded-backup\"\n"+
+		"restored backup \"uploaded-backup\"; the state server is restarting\n")
+}
@@ -0,0 +1,154 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+// BackupsCommandSuite provides a fake backups API shared by the tests
+// for the "backups" subcommands.
+type BackupsCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeBackupsAPI
+}
+
+func (s *BackupsCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeBackupsAPI{
+		backups: make(map[string]params.BackupsMetadataResult),
+	}
+	s.PatchValue(&getBackupsAPI, func(*BackupsCommandBase) (BackupsAPI, error) {
+		return s.fake, nil
+	})
+}
+
+type fakeBackupsAPI struct {
+	notes    string
+	backups  map[string]params.BackupsMetadataResult
+	removed  []string
+	archive  string
+	checksum string
+	restored []string
+}
+
+func fakeBackupMetadata(id, notes string) params.BackupsMetadataResult {
+	started := time.Date(2014, 8, 1, 12, 0, 0, 0, time.UTC)
+	return params.BackupsMetadataResult{
+		ID:             id,
+		Started:        started,
+		Finished:       started.Add(time.Minute),
+		Checksum:       "some-hash",
+		ChecksumFormat: "SHA-1, base64 encoded",
+		Size:           42,
+		Stored:         true,
+		Notes:          notes,
+		Environment:    "env-uuid",
+		Machine:        "0",
+		Hostname:       "host",
+		Version:        version.MustParse("1.21.0"),
+	}
+}
+
+func (*fakeBackupsAPI) Close() error {
+	return nil
+}
+
+func (f *fakeBackupsAPI) Create(notes string) (*params.BackupsMetadataResult, error) {
+	f.notes = notes
+	result := fakeBackupMetadata("new-backup", notes)
+	f.backups[result.ID] = result
+	return &result, nil
+}
+
+func (f *fakeBackupsAPI) Info(id string) (*params.BackupsMetadataResult, error) {
+	result, ok := f.backups[id]
+	if !ok {
+		return nil, fmt.Errorf("backup metadata %q not found", id)
+	}
+	return &result, nil
+}
+
+func (f *fakeBackupsAPI) List() (*params.BackupsListResult, error) {
+	var result params.BackupsListResult
+	for _, metadata := range f.backups {
+		result.List = append(result.List, metadata)
+	}
+	return &result, nil
+}
+
+func (f *fakeBackupsAPI) LastGood() (*params.BackupsMetadataResult, error) {
+	var last *params.BackupsMetadataResult
+	for _, metadata := range f.backups {
+		if metadata.Error != "" {
+			continue
+		}
+		if last == nil || metadata.Started.After(last.Started) {
+			metadata := metadata
+			last = &metadata
+		}
+	}
+	if last == nil {
+		return nil, fmt.Errorf("successful backup not found")
+	}
+	return last, nil
+}
+
+func (f *fakeBackupsAPI) Download(id string) (io.ReadCloser, error) {
+	if _, ok := f.backups[id]; !ok {
+		return nil, fmt.Errorf("backup metadata %q not found", id)
+	}
+	return ioutil.NopCloser(strings.NewReader("archive of " + id)), nil
+}
+
+func (f *fakeBackupsAPI) Remove(id string) error {
+	if _, ok := f.backups[id]; !ok {
+		return fmt.Errorf("backup metadata %q not found", id)
+	}
+	delete(f.backups, id)
+	f.removed = append(f.removed, id)
+	return nil
+}
+
+func (f *fakeBackupsAPI) Upload(archive io.Reader, checksum string) (*params.BackupsMetadataResult, error) {
+	data, err := ioutil.ReadAll(archive)
+	if err != nil {
+		return nil, err
+	}
+	f.archive, f.checksum = string(data), checksum
+	result := fakeBackupMetadata("uploaded-backup", "")
+	f.backups[result.ID] = result
+	return &result, nil
+}
+
+func (f *fakeBackupsAPI) Restore(id string) error {
+	if _, ok := f.backups[id]; !ok {
+		return fmt.Errorf("backup metadata %q not found", id)
+	}
+	f.restored = append(f.restored, id)
+	return nil
+}
+
+// expectedBackupInfo returns the JSON the backups subcommands print for
+// the metadata returned by fakeBackupMetadata.
+func expectedBackupInfo(id, notes string) string {
+	if notes != "" {
+		notes = fmt.Sprintf(`"notes":%q,`, notes)
+	}
+	return fmt.Sprintf(`{"id":%q,`+
+		`"started":"2014-08-01T12:00:00Z","finished":"2014-08-01T12:01:00Z",`+
+		`"checksum":"some-hash","checksum-format":"SHA-1, base64 encoded",`+
+		`"size":42,"stored":true,%s"environment":"env-uuid","machine":"0",`+
+		`"hostname":"host","version":"1.21.0"}`, id, notes)
+}
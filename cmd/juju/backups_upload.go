@@ -0,0 +1,83 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/state/api/params"
+)
+
+const backupsUploadDoc = `
+Upload a backup archive, such as one downloaded from another state
+server, to the environment's storage so that it can be restored.  The
+metadata of the stored backup is printed.
+`
+
+// BackupsUploadCommand uploads a backup archive.
+type BackupsUploadCommand struct {
+	BackupsCommandBase
+	out      cmd.Output
+	Filename string
+}
+
+func (c *BackupsUploadCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "upload",
+		Args:    "<filename>",
+		Purpose: "upload a backup archive",
+		Doc:     backupsUploadDoc,
+	}
+}
+
+func (c *BackupsUploadCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *BackupsUploadCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no backup archive specified")
+	}
+	c.Filename = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *BackupsUploadCommand) Run(ctx *cmd.Context) error {
+	client, err := getBackupsAPI(&c.BackupsCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	result, err := uploadBackup(client, ctx.AbsPath(c.Filename))
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, newBackupInfo(result))
+}
+
+// uploadBackup sends the backup archive in the named file to the
+// state server, along with its checksum.
+func uploadBackup(client BackupsAPI, filename string) (*params.BackupsMetadataResult, error) {
+	archive, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open backup archive: %v", err)
+	}
+	defer archive.Close()
+	hash := sha1.New()
+	if _, err := io.Copy(hash, archive); err != nil {
+		return nil, fmt.Errorf("cannot read backup archive: %v", err)
+	}
+	if _, err := archive.Seek(0, 0); err != nil {
+		return nil, fmt.Errorf("cannot read backup archive: %v", err)
+	}
+	checksum := base64.StdEncoding.EncodeToString(hash.Sum(nil))
+	return client.Upload(archive, checksum)
+}
@@ -0,0 +1,56 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type BackupsUploadCommandSuite struct {
+	BackupsCommandSuite
+}
+
+var _ = gc.Suite(&BackupsUploadCommandSuite{})
+
+func newBackupsUploadCommand() cmd.Command {
+	return envcmd.Wrap(&BackupsUploadCommand{})
+}
+
+// writeArchive writes a fake backup archive into dir and returns its
+// path.
+func writeArchive(c *gc.C, dir string) string {
+	filename := filepath.Join(dir, "backup.tar.gz")
+	err := ioutil.WriteFile(filename, []byte("archive data"), 0600)
+	c.Assert(err, gc.IsNil)
+	return filename
+}
+
+// archiveChecksum is the base64-encoded SHA-1 of "archive data".
+const archiveChecksum = "Zbu+CKP2aovKQe5gcEbyAeKPSPI="
+
+func (s *BackupsUploadCommandSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&BackupsUploadCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no backup archive specified")
+}
+
+func (s *BackupsUploadCommandSuite) TestUpload(c *gc.C) {
+	filename := writeArchive(c, c.MkDir())
+	ctx, err := testing.RunCommand(c, newBackupsUploadCommand(), filename, "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(s.fake.archive, gc.Equals, "archive data")
+	c.Check(s.fake.checksum, gc.Equals, archiveChecksum)
+	c.Check(testing.Stdout(ctx), gc.Equals, expectedBackupInfo("uploaded-backup", "")+"\n")
+}
+
+func (s *BackupsUploadCommandSuite) TestUploadMissingFile(c *gc.C) {
+	_, err := testing.RunCommand(c, newBackupsUploadCommand(), filepath.Join(c.MkDir(), "missing"))
+	c.Check(err, gc.ErrorMatches, "cannot open backup archive: .*")
+}
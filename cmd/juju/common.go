@@ -5,6 +5,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -95,3 +96,19 @@ func resolveCharmURL(url string, client *api.Client, conf *config.Config) (*char
 	logger.Errorf("The series is not specified in the environment (default-series) or with the charm. Did you mean:\n\t%s", &possibleURL)
 	return nil, fmt.Errorf("cannot resolve series for charm: %q", ref)
 }
+
+// parseTimeFlag parses the value of the named time flag, which is
+// either a time in RFC3339 format or a duration before now. An empty
+// value gives the zero time.
+func parseTimeFlag(name, value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s value %q: expected a time or a duration", name, value)
+}
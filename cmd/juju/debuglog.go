@@ -6,6 +6,8 @@ package main
 import (
 	"fmt"
 	"io"
+	"regexp"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/loggo"
@@ -19,6 +21,8 @@ type DebugLogCommand struct {
 	envcmd.EnvCommandBase
 
 	level  string
+	since  string
+	until  string
 	params api.DebugLogParams
 }
 
@@ -29,8 +33,25 @@ var DefaultLogLocation = "/var/log/juju/all-machines.log"
 const defaultLineCount = 10
 
 const debuglogDoc = `
-Stream the consolidated debug log file. This file contains the log messages
-from all nodes in the environment.
+Stream the consolidated debug log. The agents on all the nodes in the
+environment send their log messages to the state servers, which store
+them for debug-log to show.
+
+The --since and --until options restrict the messages shown to those
+logged in a time range. Their values are either times in RFC3339 format
+(2014-09-01T02:10:00Z) or durations before now (15m, 2h). With --since,
+all the messages logged since then are shown, and --lines is ignored.
+
+The --grep option only shows the messages matching a regular expression.
+
+By default debug-log keeps waiting for new messages. With --no-tail, it
+exits once the messages already logged have been shown. With --until,
+it exits once messages logged after that time appear.
+
+Examples:
+
+    juju debug-log --since 2014-09-01T02:10:00Z --until 2014-09-01T02:20:00Z --no-tail
+    juju debug-log --since 15m --grep 'hook failed'
 `
 
 func (c *DebugLogCommand) Info() *cmd.Info {
@@ -56,6 +77,10 @@ func (c *DebugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.UintVar(&c.params.Backlog, "lines", defaultLineCount, "")
 	f.UintVar(&c.params.Limit, "limit", 0, "show at most this many lines")
 	f.BoolVar(&c.params.Replay, "replay", false, "start filtering from the start")
+	f.StringVar(&c.since, "since", "", "only show log messages logged at or after this time")
+	f.StringVar(&c.until, "until", "", "only show log messages logged at or before this time")
+	f.StringVar(&c.params.Grep, "grep", "", "only show log messages matching this regular expression")
+	f.BoolVar(&c.params.NoTail, "no-tail", false, "exit once the log messages already logged are shown")
 }
 
 func (c *DebugLogCommand) Init(args []string) error {
@@ -67,6 +92,22 @@ func (c *DebugLogCommand) Init(args []string) error {
 		}
 		c.params.Level = level
 	}
+	now := time.Now()
+	var err error
+	if c.params.Since, err = parseTimeFlag("since", c.since, now); err != nil {
+		return err
+	}
+	if c.params.Until, err = parseTimeFlag("until", c.until, now); err != nil {
+		return err
+	}
+	if !c.params.Since.IsZero() && !c.params.Until.IsZero() && c.params.Until.Before(c.params.Since) {
+		return fmt.Errorf("--until is before --since")
+	}
+	if c.params.Grep != "" {
+		if _, err := regexp.Compile(c.params.Grep); err != nil {
+			return fmt.Errorf("invalid grep value %q: %v", c.params.Grep, err)
+		}
+	}
 	return cmd.CheckEmpty(args)
 }
 
@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
@@ -85,6 +86,29 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				Backlog: 10,
 				Limit:   100,
 			},
+		}, {
+			args: []string{"--since", "2014-09-01T02:10:00Z", "--until", "2014-09-01T02:20:00Z"},
+			expected: api.DebugLogParams{
+				Backlog: 10,
+				Since:   time.Date(2014, 9, 1, 2, 10, 0, 0, time.UTC),
+				Until:   time.Date(2014, 9, 1, 2, 20, 0, 0, time.UTC),
+			},
+		}, {
+			args:     []string{"--since", "yesterday"},
+			errMatch: `invalid since value "yesterday": expected a time or a duration`,
+		}, {
+			args:     []string{"--since", "2014-09-01T02:20:00Z", "--until", "2014-09-01T02:10:00Z"},
+			errMatch: `--until is before --since`,
+		}, {
+			args: []string{"--grep", "hook failed", "--no-tail"},
+			expected: api.DebugLogParams{
+				Backlog: 10,
+				Grep:    "hook failed",
+				NoTail:  true,
+			},
+		}, {
+			args:     []string{"--grep", "(foo"},
+			errMatch: `invalid grep value "\(foo": .*`,
 		},
 	} {
 		c.Logf("test %v", i)
@@ -99,6 +123,15 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 	}
 }
 
+func (s *DebugLogSuite) TestSinceDuration(c *gc.C) {
+	command := &DebugLogCommand{}
+	err := testing.InitCommand(envcmd.Wrap(command), []string{"--since", "15m"})
+	c.Assert(err, gc.IsNil)
+	since := time.Now().Add(-15 * time.Minute)
+	c.Assert(command.params.Since.Before(since.Add(time.Minute)), jc.IsTrue)
+	c.Assert(command.params.Since.After(since.Add(-time.Minute)), jc.IsTrue)
+}
+
 func (s *DebugLogSuite) TestParamsPassed(c *gc.C) {
 	fake := &fakeDebugLogAPI{}
 	s.PatchValue(&getDebugLogAPI, func(_ *DebugLogCommand) (DebugLogAPI, error) {
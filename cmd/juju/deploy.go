@@ -6,6 +6,7 @@ package main
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 
@@ -14,6 +15,7 @@ import (
 	"gopkg.in/juju/charm.v3"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/bundle"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
@@ -26,6 +28,7 @@ type DeployCommand struct {
 	envcmd.EnvCommandBase
 	UnitCommandBase
 	CharmName    string
+	BundlePath   string
 	ServiceName  string
 	Config       cmd.FileVar
 	Constraints  constraints.Value
@@ -102,16 +105,56 @@ to configure on it. The --networks argument instructs juju to add all the
 networks specified with it to all new machines deployed to host units of
 the service. Not supported on all providers.
 
+A bundle of services, and the relations between them, can be deployed by
+giving the path of a bundle file, whose name ends in .yaml or .yml, instead
+of a charm name. A bundle file looks like this:
+
+   services:
+     wordpress:
+       charm: cs:precise/wordpress
+       num_units: 2
+       options:
+         blog-title: My Blog
+       constraints: mem=2G
+       expose: true
+       annotations:
+         gui-x: "100"
+     mysql:
+       charm: local:precise/mysql
+       num_units: 1
+       to: lxc:wordpress/0
+   relations:
+     - [wordpress:db, mysql:server]
+
+Units are placed in order with "to", which takes a machine ("1"), a
+container ("1/lxc/0"), the machine of a unit of another service in the
+bundle ("mysql/0"), or a new container on either ("lxc:1",
+"lxc:mysql/0"). Local charms are added to the environment from the local
+repository. Deploying a bundle does not change the services, units and
+relations that already match it, so a bundle that failed to deploy
+completely can be deployed again. The service name, --num-units, --to,
+--config, --constraints and --networks cannot be used with a bundle.
+
+Examples:
+   juju deploy wordpress.yaml
+   juju deploy --repository=/home/user/charms wordpress.yaml
+
 See Also:
    juju help constraints
    juju help set-constraints
    juju help get-constraints
 `
 
+// isBundlePath returns whether the argument to deploy names a bundle
+// file rather than a charm.
+func isBundlePath(arg string) bool {
+	return strings.HasSuffix(arg, ".yaml") || strings.HasSuffix(arg, ".yml")
+}
+
 func (c *DeployCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "deploy",
-		Args:    "<charm name> [<service name>]",
+		Args:    "<charm name> [<service name>] | <bundle file>",
 		Purpose: "deploy a new service",
 		Doc:     deployDoc,
 	}
@@ -129,6 +172,9 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 }
 
 func (c *DeployCommand) Init(args []string) error {
+	if len(args) > 0 && isBundlePath(args[0]) {
+		return c.initBundle(args)
+	}
 	switch len(args) {
 	case 2:
 		if !names.IsValidService(args[1]) {
@@ -149,6 +195,26 @@ func (c *DeployCommand) Init(args []string) error {
 	return c.UnitCommandBase.Init(args)
 }
 
+func (c *DeployCommand) initBundle(args []string) error {
+	if len(args) > 1 {
+		return errors.New("cannot give a service name with a bundle")
+	}
+	switch {
+	case c.NumUnits != 1:
+		return errors.New("cannot use --num-units with a bundle")
+	case c.ToMachineSpec != "":
+		return errors.New("cannot use --to with a bundle")
+	case c.Config.Path != "":
+		return errors.New("cannot use --config with a bundle")
+	case !constraints.IsEmpty(&c.Constraints):
+		return errors.New("cannot use --constraints with a bundle")
+	case c.Networks != "":
+		return errors.New("cannot use --networks with a bundle")
+	}
+	c.BundlePath = args[0]
+	return nil
+}
+
 func (c *DeployCommand) Run(ctx *cmd.Context) error {
 	client, err := c.NewAPIClient()
 	if err != nil {
@@ -165,6 +231,10 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
+	if c.BundlePath != "" {
+		return c.deployBundle(ctx, client, conf)
+	}
+
 	curl, err := resolveCharmURL(c.CharmName, client, conf)
 	if err != nil {
 		return err
@@ -253,6 +323,64 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 	return err
 }
 
+// deployBundle adds the local charms of the bundle to the environment,
+// and asks the API server to deploy the bundle, reporting each step
+// it takes.
+func (c *DeployCommand) deployBundle(ctx *cmd.Context, client *api.Client, conf *config.Config) error {
+	bundleYAML, err := ioutil.ReadFile(ctx.AbsPath(c.BundlePath))
+	if err != nil {
+		return err
+	}
+	data, err := bundle.Parse(bundleYAML)
+	if err != nil {
+		return err
+	}
+	order, err := data.DeployOrder()
+	if err != nil {
+		return err
+	}
+	for _, name := range order {
+		spec := data.Services[name]
+		if !strings.HasPrefix(spec.Charm, "local:") {
+			continue
+		}
+		curl, err := resolveCharmURL(spec.Charm, client, conf)
+		if err != nil {
+			return err
+		}
+		// Local charms get a new revision each time they are added,
+		// so a service already deployed from the same charm keeps
+		// the revision it has.
+		deployedURL, err := client.ServiceGetCharmURL(name)
+		if err == nil && *deployedURL.WithRevision(-1) == *curl.WithRevision(-1) {
+			spec.Charm = deployedURL.String()
+			continue
+		} else if err != nil && !params.IsCodeNotFound(err) {
+			return err
+		}
+		repo, err := charm.InferRepository(curl.Reference(), ctx.AbsPath(c.RepoPath))
+		if err != nil {
+			return err
+		}
+		repo = config.SpecializeCharmRepo(repo, conf)
+		if curl, err = addCharmViaAPI(client, ctx, curl, repo); err != nil {
+			return err
+		}
+		spec.Charm = curl.String()
+	}
+	if bundleYAML, err = data.YAML(); err != nil {
+		return err
+	}
+	steps, err := client.DeployBundle(string(bundleYAML))
+	for _, step := range steps {
+		ctx.Infof("%s", step)
+	}
+	if params.IsCodeNotImplemented(err) {
+		return errors.New("cannot deploy bundles: not supported by the API server")
+	}
+	return err
+}
+
 // addCharmViaAPI calls the appropriate client API calls to add the
 // given charm URL to state. Also displays the charm URL of the added
 // charm on stdout.
@@ -4,6 +4,8 @@
 package main
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 
 	"github.com/juju/errors"
@@ -60,6 +62,21 @@ var initErrorTests = []struct {
 	}, {
 		args: []string{"craziness", "burble1", "--constraints", "gibber=plop"},
 		err:  `invalid value "gibber=plop" for flag --constraints: unknown constraint "gibber"`,
+	}, {
+		args: []string{"bundle.yaml", "burble1"},
+		err:  `cannot give a service name with a bundle`,
+	}, {
+		args: []string{"bundle.yaml", "-n", "2"},
+		err:  `cannot use --num-units with a bundle`,
+	}, {
+		args: []string{"bundle.yml", "--to", "1"},
+		err:  `cannot use --to with a bundle`,
+	}, {
+		args: []string{"bundle.yaml", "--constraints", "mem=2G"},
+		err:  `cannot use --constraints with a bundle`,
+	}, {
+		args: []string{"bundle.yaml", "--networks", "net1"},
+		err:  `cannot use --networks with a bundle`,
 	},
 }
 
@@ -120,6 +137,50 @@ func (s *DeploySuite) TestCharmBundle(c *gc.C) {
 	s.AssertService(c, "some-service-name", curl, 1, 0)
 }
 
+func (s *DeploySuite) TestBundle(c *gc.C) {
+	charmtesting.Charms.CharmArchivePath(s.SeriesPath, "dummy")
+	charmtesting.Charms.CharmArchivePath(s.SeriesPath, "logging")
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(path, []byte(`
+services:
+  dummy:
+    charm: local:dummy
+    num_units: 2
+  logging:
+    charm: local:precise/logging
+relations:
+  - [dummy, logging]
+`), 0644)
+	c.Assert(err, gc.IsNil)
+
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), path)
+	c.Assert(err, gc.IsNil)
+	s.AssertService(c, "dummy", charm.MustParseURL("local:precise/dummy-1"), 2, 1)
+	s.AssertService(c, "logging", charm.MustParseURL("local:precise/logging-1"), 0, 1)
+	c.Assert(coretesting.Stderr(ctx), gc.Matches, `(?s)`+
+		`Added charm "local:precise/dummy-1" to the environment.\n`+
+		`Added charm "local:precise/logging-1" to the environment.\n`+
+		`deployed service "dummy" using charm "local:precise/dummy-1"\n`+
+		`added unit "dummy/0" to machine ".*"\n`+
+		`added unit "dummy/1" to machine ".*"\n`+
+		`deployed service "logging" using charm "local:precise/logging-1"\n`+
+		`added relation ".*"\n`)
+
+	// Deploying the bundle again leaves it as it is.
+	ctx, err = coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, "")
+	s.AssertService(c, "dummy", charm.MustParseURL("local:precise/dummy-1"), 2, 1)
+}
+
+func (s *DeploySuite) TestInvalidBundle(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(path, []byte("services: {}"), 0644)
+	c.Assert(err, gc.IsNil)
+	err = runDeploy(c, path)
+	c.Assert(err, gc.ErrorMatches, "invalid bundle: no services")
+}
+
 func (s *DeploySuite) TestSubordinateCharm(c *gc.C) {
 	charmtesting.Charms.CharmArchivePath(s.SeriesPath, "logging")
 	err := runDeploy(c, "local:logging")
@@ -0,0 +1,215 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+)
+
+// DiffBundleCommand compares a bundle with the environment.
+type DiffBundleCommand struct {
+	envcmd.EnvCommandBase
+	out        cmd.Output
+	BundlePath string
+}
+
+const diffBundleDoc = `
+Compare a bundle with the environment, and show what differs between them:
+the services to add and to remove, the differences in charm, options,
+constraints, number of units and exposure of the services in both, and the
+relations that are missing from the environment or extra in it. Only the
+options set in the bundle are compared.
+
+Nothing is changed. Use "juju deploy" with the bundle to add what is
+missing from the environment.
+
+Examples:
+    juju diff-bundle wordpress.yaml
+    juju diff-bundle --format json wordpress.yaml
+
+See Also:
+    juju help deploy
+    juju help export-bundle
+`
+
+func (c *DiffBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "diff-bundle",
+		Args:    "<bundle file>",
+		Purpose: "compare a bundle with the environment",
+		Doc:     diffBundleDoc,
+	}
+}
+
+func (c *DiffBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "text", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+		"text": formatBundleDiffText,
+	})
+}
+
+func (c *DiffBundleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no bundle file specified")
+	}
+	c.BundlePath = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// DiffBundleAPI defines the client API methods used by the
+// diff-bundle command.
+type DiffBundleAPI interface {
+	DiffBundle(bundleYAML string) (params.BundleDiff, error)
+	Close() error
+}
+
+var getDiffBundleAPI = func(c *DiffBundleCommand) (DiffBundleAPI, error) {
+	return c.NewAPIClient()
+}
+
+// bundleDiffInfo is the representation of the differences between a
+// bundle and the environment written by the diff-bundle command.
+type bundleDiffInfo struct {
+	ServicesToAdd    []string                   `json:"services-to-add,omitempty" yaml:"services-to-add,omitempty"`
+	ServicesToRemove []string                   `json:"services-to-remove,omitempty" yaml:"services-to-remove,omitempty"`
+	Services         map[string]serviceDiffInfo `json:"services,omitempty" yaml:"services,omitempty"`
+	MissingRelations [][]string                 `json:"missing-relations,omitempty" yaml:"missing-relations,omitempty"`
+	ExtraRelations   [][]string                 `json:"extra-relations,omitempty" yaml:"extra-relations,omitempty"`
+}
+
+type serviceDiffInfo struct {
+	Charm       *valueDiffInfo           `json:"charm,omitempty" yaml:"charm,omitempty"`
+	Options     map[string]valueDiffInfo `json:"options,omitempty" yaml:"options,omitempty"`
+	Constraints *valueDiffInfo           `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+	NumUnits    *valueDiffInfo           `json:"num-units,omitempty" yaml:"num-units,omitempty"`
+	Expose      *valueDiffInfo           `json:"expose,omitempty" yaml:"expose,omitempty"`
+}
+
+type valueDiffInfo struct {
+	Bundle      interface{} `json:"bundle" yaml:"bundle"`
+	Environment interface{} `json:"environment" yaml:"environment"`
+}
+
+func newValueDiffInfo(diff *params.ValueDiff) *valueDiffInfo {
+	if diff == nil {
+		return nil
+	}
+	return &valueDiffInfo{Bundle: diff.Bundle, Environment: diff.Environment}
+}
+
+func (c *DiffBundleCommand) Run(ctx *cmd.Context) error {
+	bundleYAML, err := ioutil.ReadFile(ctx.AbsPath(c.BundlePath))
+	if err != nil {
+		return err
+	}
+	client, err := getDiffBundleAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	diff, err := client.DiffBundle(string(bundleYAML))
+	if params.IsCodeNotImplemented(err) {
+		return errors.New("cannot compare bundles: not supported by the API server")
+	} else if err != nil {
+		return err
+	}
+	info := bundleDiffInfo{
+		ServicesToAdd:    diff.ServicesToAdd,
+		ServicesToRemove: diff.ServicesToRemove,
+		MissingRelations: diff.MissingRelations,
+		ExtraRelations:   diff.ExtraRelations,
+	}
+	for name, serviceDiff := range diff.Services {
+		if info.Services == nil {
+			info.Services = make(map[string]serviceDiffInfo)
+		}
+		serviceInfo := serviceDiffInfo{
+			Charm:       newValueDiffInfo(serviceDiff.Charm),
+			Constraints: newValueDiffInfo(serviceDiff.Constraints),
+			NumUnits:    newValueDiffInfo(serviceDiff.NumUnits),
+			Expose:      newValueDiffInfo(serviceDiff.Expose),
+		}
+		for option, optionDiff := range serviceDiff.Options {
+			if serviceInfo.Options == nil {
+				serviceInfo.Options = make(map[string]valueDiffInfo)
+			}
+			serviceInfo.Options[option] = *newValueDiffInfo(&optionDiff)
+		}
+		info.Services[name] = serviceInfo
+	}
+	return c.out.Write(ctx, info)
+}
+
+// formatBundleDiffText writes the differences in a form meant to be
+// read by people, with the environment's value of each setting before
+// the bundle's.
+func formatBundleDiffText(value interface{}) ([]byte, error) {
+	info, ok := value.(bundleDiffInfo)
+	if !ok {
+		return nil, fmt.Errorf("expected value of type %T, got %T", info, value)
+	}
+	var out bytes.Buffer
+	if len(info.ServicesToAdd) > 0 {
+		fmt.Fprintf(&out, "services to add: %s\n", strings.Join(info.ServicesToAdd, ", "))
+	}
+	if len(info.ServicesToRemove) > 0 {
+		fmt.Fprintf(&out, "services to remove: %s\n", strings.Join(info.ServicesToRemove, ", "))
+	}
+	var serviceNames []string
+	for name := range info.Services {
+		serviceNames = append(serviceNames, name)
+	}
+	sort.Strings(serviceNames)
+	for _, name := range serviceNames {
+		serviceInfo := info.Services[name]
+		fmt.Fprintf(&out, "service %s:\n", name)
+		writeValueDiff(&out, "charm", serviceInfo.Charm)
+		var options []string
+		for option := range serviceInfo.Options {
+			options = append(options, option)
+		}
+		sort.Strings(options)
+		for _, option := range options {
+			optionDiff := serviceInfo.Options[option]
+			writeValueDiff(&out, "option "+option, &optionDiff)
+		}
+		writeValueDiff(&out, "constraints", serviceInfo.Constraints)
+		writeValueDiff(&out, "units", serviceInfo.NumUnits)
+		writeValueDiff(&out, "expose", serviceInfo.Expose)
+	}
+	writeRelations(&out, "missing relations", info.MissingRelations)
+	writeRelations(&out, "extra relations", info.ExtraRelations)
+	if out.Len() == 0 {
+		out.WriteString("the environment matches the bundle\n")
+	}
+	return trimLines(out.Bytes()), nil
+}
+
+func writeValueDiff(out *bytes.Buffer, name string, diff *valueDiffInfo) {
+	if diff != nil {
+		fmt.Fprintf(out, "  %s: %q -> %q\n", name, fmt.Sprint(diff.Environment), fmt.Sprint(diff.Bundle))
+	}
+}
+
+func writeRelations(out *bytes.Buffer, title string, relations [][]string) {
+	if len(relations) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "%s:\n", title)
+	for _, endpoints := range relations {
+		fmt.Fprintf(out, "  %s\n", strings.Join(endpoints, " "))
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+type DiffBundleSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeDiffBundleAPI
+	path string
+}
+
+var _ = gc.Suite(&DiffBundleSuite{})
+
+const diffBundleYAML = `
+services:
+  wordpress:
+    charm: cs:precise/wordpress
+    num_units: 3
+`
+
+func (s *DiffBundleSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeDiffBundleAPI{
+		diff: params.BundleDiff{
+			ServicesToAdd:    []string{"varnish"},
+			ServicesToRemove: []string{"extra"},
+			Services: map[string]params.ServiceDiff{
+				"wordpress": {
+					Options: map[string]params.ValueDiff{
+						"blog-title": {Bundle: "Changed", Environment: "My Title"},
+					},
+					Constraints: &params.ValueDiff{Bundle: "mem=4096M", Environment: ""},
+					NumUnits:    &params.ValueDiff{Bundle: 3.0, Environment: 1.0},
+				},
+			},
+			MissingRelations: [][]string{{"wordpress", "varnish"}},
+			ExtraRelations:   [][]string{{"wordpress:db", "mysql:server"}},
+		},
+	}
+	s.PatchValue(&getDiffBundleAPI, func(*DiffBundleCommand) (DiffBundleAPI, error) {
+		return s.fake, nil
+	})
+	s.path = filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(s.path, []byte(diffBundleYAML), 0644)
+	c.Assert(err, gc.IsNil)
+}
+
+type fakeDiffBundleAPI struct {
+	bundleYAML string
+	diff       params.BundleDiff
+	err        error
+}
+
+func (f *fakeDiffBundleAPI) DiffBundle(bundleYAML string) (params.BundleDiff, error) {
+	f.bundleYAML = bundleYAML
+	return f.diff, f.err
+}
+
+func (f *fakeDiffBundleAPI) Close() error {
+	return nil
+}
+
+func (s *DiffBundleSuite) TestDiffBundle(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&DiffBundleCommand{}), s.path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.fake.bundleYAML, gc.Equals, diffBundleYAML)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"services to add: varnish\n"+
+		"services to remove: extra\n"+
+		"service wordpress:\n"+
+		"  option blog-title: \"My Title\" -> \"Changed\"\n"+
+		"  constraints: \"\" -> \"mem=4096M\"\n"+
+		"  units: \"1\" -> \"3\"\n"+
+		"missing relations:\n"+
+		"  wordpress varnish\n"+
+		"extra relations:\n"+
+		"  wordpress:db mysql:server\n")
+}
+
+func (s *DiffBundleSuite) TestDiffBundleJSON(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&DiffBundleCommand{}), "--format", "json", s.path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `{`+
+		`"services-to-add":["varnish"],`+
+		`"services-to-remove":["extra"],`+
+		`"services":{"wordpress":{`+
+		`"options":{"blog-title":{"bundle":"Changed","environment":"My Title"}},`+
+		`"constraints":{"bundle":"mem=4096M","environment":""},`+
+		`"num-units":{"bundle":3,"environment":1}}},`+
+		`"missing-relations":[["wordpress","varnish"]],`+
+		`"extra-relations":[["wordpress:db","mysql:server"]]}`+"\n")
+}
+
+func (s *DiffBundleSuite) TestDiffBundleNoDifferences(c *gc.C) {
+	s.fake.diff = params.BundleDiff{}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&DiffBundleCommand{}), s.path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "the environment matches the bundle\n")
+}
+
+func (s *DiffBundleSuite) TestDiffBundleNotImplemented(c *gc.C) {
+	s.fake.err = &params.Error{Code: params.CodeNotImplemented, Message: "not implemented"}
+	_, err := testing.RunCommand(c, envcmd.Wrap(&DiffBundleCommand{}), s.path)
+	c.Assert(err, gc.ErrorMatches, "cannot compare bundles: not supported by the API server")
+}
+
+func (s *DiffBundleSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&DiffBundleCommand{}), nil)
+	c.Assert(err, gc.ErrorMatches, "no bundle file specified")
+	err = testing.InitCommand(envcmd.Wrap(&DiffBundleCommand{}), []string{"a.yaml", "b.yaml"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b.yaml"\]`)
+}
@@ -0,0 +1,85 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/bundle"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// ExportBundleCommand writes a bundle describing the services in the
+// environment.
+type ExportBundleCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+}
+
+const exportBundleDoc = `
+Write a bundle that describes the services in the environment: their
+charms, the options that differ from the charm defaults, their
+constraints, exposure, annotations and number of units, and the relations
+between them. Units sharing a machine with a unit of another service, or
+in a container on such a machine, are placed with that unit.
+
+Deploying the bundle with "juju deploy" in another environment reproduces
+the same topology. Local charms must be available in the local repository
+of the environment the bundle is deployed to.
+
+Examples:
+    juju export-bundle
+    juju export-bundle -o production.yaml
+    juju deploy -e staging production.yaml
+
+See Also:
+    juju help deploy
+`
+
+func (c *ExportBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "export-bundle",
+		Purpose: "export the services in the environment as a bundle",
+		Doc:     exportBundleDoc,
+	}
+}
+
+func (c *ExportBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+	})
+}
+
+func (c *ExportBundleCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// ExportBundleAPI defines the client API methods used by the
+// export-bundle command.
+type ExportBundleAPI interface {
+	ExportBundle() (string, error)
+	Close() error
+}
+
+var getExportBundleAPI = func(c *ExportBundleCommand) (ExportBundleAPI, error) {
+	return c.NewAPIClient()
+}
+
+func (c *ExportBundleCommand) Run(ctx *cmd.Context) error {
+	client, err := getExportBundleAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	bundleYAML, err := client.ExportBundle()
+	if err != nil {
+		return err
+	}
+	data, err := bundle.Parse([]byte(bundleYAML))
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, data)
+}
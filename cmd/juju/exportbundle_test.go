@@ -0,0 +1,99 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type ExportBundleSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeExportBundleAPI
+}
+
+var _ = gc.Suite(&ExportBundleSuite{})
+
+func (s *ExportBundleSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeExportBundleAPI{
+		bundle: `
+services:
+  wordpress:
+    charm: cs:precise/wordpress-3
+    num_units: 1
+    expose: true
+  mysql:
+    charm: cs:precise/mysql-1
+    num_units: 1
+    to: wordpress/0
+relations:
+  - [wordpress:db, mysql:server]
+`,
+	}
+	s.PatchValue(&getExportBundleAPI, func(*ExportBundleCommand) (ExportBundleAPI, error) {
+		return s.fake, nil
+	})
+}
+
+type fakeExportBundleAPI struct {
+	bundle string
+	err    error
+}
+
+func (f *fakeExportBundleAPI) ExportBundle() (string, error) {
+	return f.bundle, f.err
+}
+
+func (f *fakeExportBundleAPI) Close() error {
+	return nil
+}
+
+const exportedBundle = `
+services:
+  mysql:
+    charm: cs:precise/mysql-1
+    num_units: 1
+    to:
+    - wordpress/0
+  wordpress:
+    charm: cs:precise/wordpress-3
+    num_units: 1
+    expose: true
+relations:
+- - wordpress:db
+  - mysql:server
+`[1:]
+
+func (s *ExportBundleSuite) TestExportBundle(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ExportBundleCommand{}))
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, exportedBundle)
+}
+
+func (s *ExportBundleSuite) TestExportBundleToFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&ExportBundleCommand{}), "-o", path)
+	c.Assert(err, gc.IsNil)
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, exportedBundle)
+}
+
+func (s *ExportBundleSuite) TestExportBundleError(c *gc.C) {
+	s.fake.err = errors.New("cannot export bundle: environment has no services")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&ExportBundleCommand{}))
+	c.Assert(err, gc.ErrorMatches, "cannot export bundle: environment has no services")
+}
+
+func (s *ExportBundleSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&ExportBundleCommand{}), []string{"extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
@@ -474,6 +474,10 @@ back to the bootstrap node, and they are accumulated into the all-machines.log
 file.  Each line is prefixed with the source agent tag (also the same as
 the filename without the extension).
 
+The agents also send their log messages to the state servers, which store
+them in the database. This is where 'debug-log' reads them from, so any
+state server can show the log messages of the whole environment.
+
 Juju has a hierarchical logging system internally, and as a user you can
 control how much information is logged out.
 
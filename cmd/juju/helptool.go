@@ -5,11 +5,13 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/cmd"
 	"gopkg.in/juju/charm.v3"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/state/api/params"
 	"github.com/juju/juju/worker/uniter/jujuc"
 )
 
@@ -38,6 +40,21 @@ func (dummyHookContext) ConfigSettings() (charm.Settings, error) {
 func (dummyHookContext) ActionParams() map[string]interface{} {
 	return nil
 }
+func (dummyHookContext) UpdateActionResults(keys []string, value string) error {
+	return nil
+}
+func (dummyHookContext) SetActionFailed(message string) error {
+	return nil
+}
+func (dummyHookContext) AddMetric(key, value string, created time.Time) error {
+	return nil
+}
+func (dummyHookContext) SetWorkloadStatus(status params.WorkloadStatus, info string) error {
+	return nil
+}
+func (dummyHookContext) WorkloadStatus() (params.WorkloadStatus, string, error) {
+	return params.WorkloadUnknown, "", nil
+}
 
 func (dummyHookContext) HookRelation() (jujuc.ContextRelation, bool) {
 	return nil, false
@@ -105,6 +105,11 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&StatusCommand{}))
 	r.Register(&SwitchCommand{})
 	r.Register(wrapEnvCommand(&EndpointCommand{}))
+	r.Register(wrapEnvCommand(&MetricsCommand{}))
+	r.Register(wrapEnvCommand(&StatusHistoryCommand{}))
+	r.Register(wrapEnvCommand(&AuditLogCommand{}))
+	r.Register(wrapEnvCommand(&ExportBundleCommand{}))
+	r.Register(wrapEnvCommand(&DiffBundleCommand{}))
 
 	// Error resolution and debugging commands.
 	r.Register(wrapEnvCommand(&RunCommand{}))
@@ -115,6 +120,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&DebugHooksCommand{}))
 	r.Register(wrapEnvCommand(&RetryProvisioningCommand{}))
 
+	// Charm action commands.
+	r.Register(NewActionCommand())
+
 	// Configuration commands.
 	r.Register(&InitCommand{})
 	r.Register(wrapEnvCommand(&GetCommand{}))
@@ -130,6 +138,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&UnexposeCommand{}))
 	r.Register(wrapEnvCommand(&UpgradeJujuCommand{}))
 	r.Register(wrapEnvCommand(&UpgradeCharmCommand{}))
+	r.Register(wrapEnvCommand(&RollingUpgradeCommand{}))
 
 	// Charm publishing commands.
 	r.Register(wrapEnvCommand(&PublishCommand{}))
@@ -143,6 +152,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Manage users and access
 	r.Register(NewUserCommand())
 
+	// Manage backups of juju state.
+	r.Register(NewBackupsCommand())
+
 	// Manage state server availability.
 	r.Register(wrapEnvCommand(&EnsureAvailabilityCommand{}))
 }
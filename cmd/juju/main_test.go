@@ -206,12 +206,15 @@ func (s *MainSuite) TestActualRunJujuArgOrder(c *gc.C) {
 }
 
 var commandNames = []string{
+	"action",
 	"add-machine",
 	"add-relation",
 	"add-unit",
 	"api-endpoints",
+	"audit-log",
 	"authorised-keys", // alias for authorized-keys
 	"authorized-keys",
+	"backups",
 	"bootstrap",
 	"debug-hooks",
 	"debug-log",
@@ -221,8 +224,10 @@ var commandNames = []string{
 	"destroy-relation",
 	"destroy-service",
 	"destroy-unit",
+	"diff-bundle",
 	"ensure-availability",
 	"env", // alias for switch
+	"export-bundle",
 	"expose",
 	"generate-config", // alias for init
 	"get",
@@ -232,6 +237,7 @@ var commandNames = []string{
 	"help",
 	"help-tool",
 	"init",
+	"metrics",
 	"publish",
 	"remove-machine",  // alias for destroy-machine
 	"remove-relation", // alias for destroy-relation
@@ -239,6 +245,7 @@ var commandNames = []string{
 	"remove-unit",     // alias for destroy-unit
 	"resolved",
 	"retry-provisioning",
+	"rolling-upgrade",
 	"run",
 	"scp",
 	"set",
@@ -248,6 +255,7 @@ var commandNames = []string{
 	"ssh",
 	"stat", // alias for status
 	"status",
+	"status-history",
 	"switch",
 	"sync-tools",
 	"terminate-machine", // alias for destroy-machine
@@ -0,0 +1,235 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+)
+
+// MetricsCommand shows the metrics recorded by units.
+type MetricsCommand struct {
+	envcmd.EnvCommandBase
+	out       cmd.Output
+	since     string
+	until     string
+	aggregate string
+	filter    params.MetricsFilter
+}
+
+const metricsDoc = `
+Show the metrics recorded by the given units and by the units of the given
+services, or by all units when none are given.  Metrics may be further
+selected by key and by the time they were recorded.  Times are given either
+in RFC3339 format, such as 2014-09-01T12:00:00Z, or as a duration before
+now, such as 24h.
+
+With --aggregate, the metrics are summarised per key instead of being listed:
+"last" shows the most recent value of each key, "sum" and "avg" the sum and
+the mean of its values, which must then be numbers.
+
+Examples:
+    juju metrics wordpress --since 24h
+    juju metrics wordpress/0 --key requests --aggregate sum
+`
+
+var aggregations = map[string]func(metrics []params.MetricResult) (string, error){
+	"last": aggregateLast,
+	"sum":  aggregateSum,
+	"avg":  aggregateAvg,
+}
+
+func (c *MetricsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "metrics",
+		Args:    "[<service or unit> ...]",
+		Purpose: "show the metrics recorded by units",
+		Doc:     metricsDoc,
+	}
+}
+
+func (c *MetricsCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(cmd.NewAppendStringsValue(&c.filter.Keys), "key", "only show metrics with this key")
+	f.StringVar(&c.since, "since", "", "only show metrics recorded from this time")
+	f.StringVar(&c.until, "until", "", "only show metrics recorded before this time")
+	f.StringVar(&c.aggregate, "aggregate", "", "summarise the metrics of each key, one of [last, sum, avg]")
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatMetricsTabular,
+	})
+}
+
+func (c *MetricsCommand) Init(args []string) (err error) {
+	for _, arg := range args {
+		switch {
+		case names.IsValidUnit(arg):
+			c.filter.Units = append(c.filter.Units, arg)
+		case names.IsValidService(arg):
+			c.filter.Services = append(c.filter.Services, arg)
+		default:
+			return fmt.Errorf("%q is not a valid service or unit name", arg)
+		}
+	}
+	now := time.Now()
+	if c.filter.Since, err = parseTimeFlag("since", c.since, now); err != nil {
+		return err
+	}
+	if c.filter.Until, err = parseTimeFlag("until", c.until, now); err != nil {
+		return err
+	}
+	if c.aggregate != "" && aggregations[c.aggregate] == nil {
+		return fmt.Errorf("aggregate value %q is not one of %q, %q, %q", c.aggregate, "last", "sum", "avg")
+	}
+	return nil
+}
+
+// MetricsAPI defines the client API methods used by the metrics
+// command.
+type MetricsAPI interface {
+	Metrics(filter params.MetricsFilter) ([]params.MetricResult, error)
+	Close() error
+}
+
+var getMetricsAPI = func(c *MetricsCommand) (MetricsAPI, error) {
+	return c.NewAPIClient()
+}
+
+// metricInfo is the representation of a metric written by the metrics
+// command.
+type metricInfo struct {
+	Unit  string `json:"unit" yaml:"unit"`
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+	Time  string `json:"time" yaml:"time"`
+}
+
+// aggregateInfo is the representation of the metrics of one key
+// summarised by the metrics command.
+type aggregateInfo struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+func (c *MetricsCommand) Run(ctx *cmd.Context) error {
+	client, err := getMetricsAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	metrics, err := client.Metrics(c.filter)
+	if err != nil {
+		return err
+	}
+	if c.aggregate != "" {
+		infos, err := aggregateMetrics(metrics, aggregations[c.aggregate])
+		if err != nil {
+			return err
+		}
+		return c.out.Write(ctx, infos)
+	}
+	infos := make([]metricInfo, len(metrics))
+	for i, metric := range metrics {
+		infos[i] = metricInfo{
+			Unit:  metric.Unit,
+			Key:   metric.Key,
+			Value: metric.Value,
+			Time:  metric.Time.UTC().Format(time.RFC3339),
+		}
+	}
+	return c.out.Write(ctx, infos)
+}
+
+// aggregateMetrics summarises the metrics of each key with the given
+// aggregation. The keys are given in the order they first appear.
+func aggregateMetrics(
+	metrics []params.MetricResult,
+	aggregate func([]params.MetricResult) (string, error),
+) ([]aggregateInfo, error) {
+	var keys []string
+	byKey := make(map[string][]params.MetricResult)
+	for _, metric := range metrics {
+		if _, ok := byKey[metric.Key]; !ok {
+			keys = append(keys, metric.Key)
+		}
+		byKey[metric.Key] = append(byKey[metric.Key], metric)
+	}
+	infos := make([]aggregateInfo, len(keys))
+	for i, key := range keys {
+		value, err := aggregate(byKey[key])
+		if err != nil {
+			return nil, fmt.Errorf("cannot aggregate %q: %v", key, err)
+		}
+		infos[i] = aggregateInfo{Key: key, Value: value, Count: len(byKey[key])}
+	}
+	return infos, nil
+}
+
+// aggregateLast returns the most recent value of the metrics, which
+// are ordered by time.
+func aggregateLast(metrics []params.MetricResult) (string, error) {
+	return metrics[len(metrics)-1].Value, nil
+}
+
+func aggregateSum(metrics []params.MetricResult) (string, error) {
+	sum, err := sumMetrics(metrics)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(sum, 'f', -1, 64), nil
+}
+
+func aggregateAvg(metrics []params.MetricResult) (string, error) {
+	sum, err := sumMetrics(metrics)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(sum/float64(len(metrics)), 'f', -1, 64), nil
+}
+
+func sumMetrics(metrics []params.MetricResult) (float64, error) {
+	var sum float64
+	for _, metric := range metrics {
+		v, err := strconv.ParseFloat(metric.Value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("value %q is not a number", metric.Value)
+		}
+		sum += v
+	}
+	return sum, nil
+}
+
+// formatMetricsTabular writes the metrics, or their summaries, as a
+// table with a header line.
+func formatMetricsTabular(value interface{}) ([]byte, error) {
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 2, ' ', 0)
+	switch infos := value.(type) {
+	case []metricInfo:
+		fmt.Fprintln(tw, "UNIT\tKEY\tVALUE\tTIME")
+		for _, info := range infos {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Unit, info.Key, info.Value, info.Time)
+		}
+	case []aggregateInfo:
+		fmt.Fprintln(tw, "KEY\tVALUE\tCOUNT")
+		for _, info := range infos {
+			fmt.Fprintf(tw, "%s\t%s\t%d\n", info.Key, info.Value, info.Count)
+		}
+	default:
+		return nil, fmt.Errorf("expected metrics, got %T", value)
+	}
+	tw.Flush()
+	return bytes.TrimRight(out.Bytes(), "\n"), nil
+}
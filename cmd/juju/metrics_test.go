@@ -0,0 +1,180 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+type MetricsSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeMetricsAPI
+}
+
+var _ = gc.Suite(&MetricsSuite{})
+
+var metricsTime = time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *MetricsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeMetricsAPI{
+		metrics: []params.MetricResult{
+			{Unit: "wordpress/0", Key: "pings", Value: "5", Time: metricsTime},
+			{Unit: "wordpress/1", Key: "users", Value: "2", Time: metricsTime},
+			{Unit: "wordpress/0", Key: "pings", Value: "10", Time: metricsTime.Add(time.Hour)},
+		},
+	}
+	s.PatchValue(&getMetricsAPI, func(*MetricsCommand) (MetricsAPI, error) {
+		return s.fake, nil
+	})
+}
+
+type fakeMetricsAPI struct {
+	filter  params.MetricsFilter
+	metrics []params.MetricResult
+	err     error
+}
+
+func (f *fakeMetricsAPI) Metrics(filter params.MetricsFilter) ([]params.MetricResult, error) {
+	f.filter = filter
+	return f.metrics, f.err
+}
+
+func (f *fakeMetricsAPI) Close() error {
+	return nil
+}
+
+func (s *MetricsSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		expected params.MetricsFilter
+		errMatch string
+	}{{
+		expected: params.MetricsFilter{},
+	}, {
+		args: []string{"wordpress", "mysql/0", "--key", "pings", "--key", "users"},
+		expected: params.MetricsFilter{
+			Units:    []string{"mysql/0"},
+			Services: []string{"wordpress"},
+			Keys:     []string{"pings", "users"},
+		},
+	}, {
+		args: []string{"--since", "2014-09-01T12:00:00Z", "--until", "2014-09-02T12:00:00Z"},
+		expected: params.MetricsFilter{
+			Since: metricsTime,
+			Until: metricsTime.Add(24 * time.Hour),
+		},
+	}, {
+		args:     []string{"wordpress/"},
+		errMatch: `"wordpress/" is not a valid service or unit name`,
+	}, {
+		args:     []string{"--since", "yesterday"},
+		errMatch: `invalid since value "yesterday": expected a time or a duration`,
+	}, {
+		args:     []string{"--until=-1h"},
+		errMatch: `invalid until value "-1h": expected a time or a duration`,
+	}, {
+		args:     []string{"--aggregate", "max"},
+		errMatch: `aggregate value "max" is not one of "last", "sum", "avg"`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &MetricsCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.errMatch == "" {
+			c.Check(err, gc.IsNil)
+			c.Check(command.filter, jc.DeepEquals, test.expected)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *MetricsSuite) TestSinceDuration(c *gc.C) {
+	command := &MetricsCommand{}
+	err := testing.InitCommand(envcmd.Wrap(command), []string{"--since", "24h"})
+	c.Assert(err, gc.IsNil)
+	since := time.Now().Add(-24 * time.Hour)
+	c.Assert(command.filter.Since.Before(since.Add(time.Minute)), jc.IsTrue)
+	c.Assert(command.filter.Since.After(since.Add(-time.Minute)), jc.IsTrue)
+}
+
+func (s *MetricsSuite) TestList(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&MetricsCommand{}), "wordpress")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.fake.filter.Services, gc.DeepEquals, []string{"wordpress"})
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+- unit: wordpress/0
+  key: pings
+  value: "5"
+  time: "2014-09-01T12:00:00Z"
+- unit: wordpress/1
+  key: users
+  value: "2"
+  time: "2014-09-01T12:00:00Z"
+- unit: wordpress/0
+  key: pings
+  value: "10"
+  time: "2014-09-01T13:00:00Z"
+`[1:])
+}
+
+func (s *MetricsSuite) TestListTabular(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&MetricsCommand{}), "--format", "tabular")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+UNIT         KEY    VALUE  TIME
+wordpress/0  pings  5      2014-09-01T12:00:00Z
+wordpress/1  users  2      2014-09-01T12:00:00Z
+wordpress/0  pings  10     2014-09-01T13:00:00Z
+`[1:])
+}
+
+func (s *MetricsSuite) TestAggregate(c *gc.C) {
+	for i, test := range []struct {
+		aggregate string
+		expected  string
+	}{{
+		aggregate: "last",
+		expected:  "KEY    VALUE  COUNT\npings  10     2\nusers  2      1\n",
+	}, {
+		aggregate: "sum",
+		expected:  "KEY    VALUE  COUNT\npings  15     2\nusers  2      1\n",
+	}, {
+		aggregate: "avg",
+		expected:  "KEY    VALUE  COUNT\npings  7.5    2\nusers  2      1\n",
+	}} {
+		c.Logf("test %d: %s", i, test.aggregate)
+		ctx, err := testing.RunCommand(c, envcmd.Wrap(&MetricsCommand{}),
+			"--aggregate", test.aggregate, "--format", "tabular")
+		c.Check(err, gc.IsNil)
+		c.Check(testing.Stdout(ctx), gc.Equals, test.expected)
+	}
+}
+
+func (s *MetricsSuite) TestAggregateJSON(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&MetricsCommand{}), "--aggregate", "sum", "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals,
+		`[{"key":"pings","value":"15","count":2},{"key":"users","value":"2","count":1}]`+"\n")
+}
+
+func (s *MetricsSuite) TestAggregateNotNumeric(c *gc.C) {
+	s.fake.metrics[1].Value = "many"
+	_, err := testing.RunCommand(c, envcmd.Wrap(&MetricsCommand{}), "--aggregate", "avg")
+	c.Assert(err, gc.ErrorMatches, `cannot aggregate "users": value "many" is not a number`)
+}
+
+func (s *MetricsSuite) TestError(c *gc.C) {
+	s.fake.err = fmt.Errorf("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&MetricsCommand{}))
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
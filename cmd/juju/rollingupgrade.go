@@ -0,0 +1,160 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+)
+
+// RollingUpgradeCommand shows and controls the rolling upgrade of a
+// service's charm.
+type RollingUpgradeCommand struct {
+	envcmd.EnvCommandBase
+	out         cmd.Output
+	ServiceName string
+	Pause       bool
+	Resume      bool
+	Abort       bool
+}
+
+const rollingUpgradeDoc = `
+Show the progress of the rolling upgrade of a service's charm, started with
+"juju upgrade-charm --batch-size", or pause, resume or abort it.
+
+A rolling upgrade is paused when a unit fails to upgrade, or when its charm
+sets its workload status to blocked. Once the problem is fixed, for instance
+with "juju resolved", resume the upgrade with --resume.
+
+Aborting a rolling upgrade sets the service's charm back to the charm it used
+before, so units that were already upgraded are upgraded back to it.
+
+Examples:
+    juju rolling-upgrade wordpress
+    juju rolling-upgrade --pause wordpress
+    juju rolling-upgrade --abort wordpress
+
+See Also:
+    juju help upgrade-charm
+`
+
+func (c *RollingUpgradeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "rolling-upgrade",
+		Args:    "<service>",
+		Purpose: "show or control the rolling upgrade of a service's charm",
+		Doc:     rollingUpgradeDoc,
+	}
+}
+
+func (c *RollingUpgradeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.BoolVar(&c.Pause, "pause", false, "stop upgrading further units")
+	f.BoolVar(&c.Resume, "resume", false, "continue a paused upgrade")
+	f.BoolVar(&c.Abort, "abort", false, "set the service's charm back to the one it used before")
+}
+
+func (c *RollingUpgradeCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return fmt.Errorf("invalid service name %q", args[0])
+	}
+	c.ServiceName = args[0]
+	actions := 0
+	for _, set := range []bool{c.Pause, c.Resume, c.Abort} {
+		if set {
+			actions++
+		}
+	}
+	if actions > 1 {
+		return errors.New("only one of --pause, --resume and --abort may be given")
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// RollingUpgradeAPI defines the client API methods used by the
+// rolling-upgrade command.
+type RollingUpgradeAPI interface {
+	RollingUpgradeStatus(serviceName string) (params.RollingUpgradeStatus, error)
+	PauseRollingUpgrade(serviceName string) error
+	ResumeRollingUpgrade(serviceName string) error
+	AbortRollingUpgrade(serviceName string) error
+	Close() error
+}
+
+var getRollingUpgradeAPI = func(c *RollingUpgradeCommand) (RollingUpgradeAPI, error) {
+	return c.NewAPIClient()
+}
+
+// rollingUpgradeInfo is the representation of a rolling upgrade
+// written by the rolling-upgrade command.
+type rollingUpgradeInfo struct {
+	Service        string   `json:"service" yaml:"service"`
+	FromCharm      string   `json:"from-charm" yaml:"from-charm"`
+	ToCharm        string   `json:"to-charm" yaml:"to-charm"`
+	BatchSize      int      `json:"batch-size" yaml:"batch-size"`
+	WaitActive     bool     `json:"wait-active,omitempty" yaml:"wait-active,omitempty"`
+	Upgrading      []string `json:"upgrading,omitempty" yaml:"upgrading,omitempty"`
+	UpgradingSince string   `json:"upgrading-since,omitempty" yaml:"upgrading-since,omitempty"`
+	Waiting        []string `json:"waiting,omitempty" yaml:"waiting,omitempty"`
+	Paused         bool     `json:"paused,omitempty" yaml:"paused,omitempty"`
+	PauseReason    string   `json:"pause-reason,omitempty" yaml:"pause-reason,omitempty"`
+}
+
+func (c *RollingUpgradeCommand) Run(ctx *cmd.Context) error {
+	client, err := getRollingUpgradeAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	switch {
+	case c.Pause:
+		err = client.PauseRollingUpgrade(c.ServiceName)
+	case c.Resume:
+		err = client.ResumeRollingUpgrade(c.ServiceName)
+	case c.Abort:
+		err = client.AbortRollingUpgrade(c.ServiceName)
+	default:
+		var status params.RollingUpgradeStatus
+		status, err = client.RollingUpgradeStatus(c.ServiceName)
+		if err == nil {
+			return c.out.Write(ctx, newRollingUpgradeInfo(status))
+		}
+	}
+	if params.IsCodeNotImplemented(err) {
+		return errors.New("cannot manage rolling upgrades: not supported by the API server")
+	}
+	return err
+}
+
+func newRollingUpgradeInfo(status params.RollingUpgradeStatus) rollingUpgradeInfo {
+	info := rollingUpgradeInfo{
+		Service:     status.ServiceName,
+		FromCharm:   status.FromCharmURL,
+		ToCharm:     status.ToCharmURL,
+		BatchSize:   status.BatchSize,
+		WaitActive:  status.WaitActive,
+		Upgrading:   status.Batch,
+		Waiting:     status.HeldUnits,
+		Paused:      status.Paused,
+		PauseReason: status.PauseReason,
+	}
+	if len(status.Batch) > 0 {
+		info.UpgradingSince = status.BatchStarted.UTC().Format(time.RFC3339)
+	}
+	return info
+}
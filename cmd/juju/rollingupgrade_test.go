@@ -0,0 +1,125 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+type RollingUpgradeSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeRollingUpgradeAPI
+}
+
+var _ = gc.Suite(&RollingUpgradeSuite{})
+
+func (s *RollingUpgradeSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeRollingUpgradeAPI{
+		status: params.RollingUpgradeStatus{
+			ServiceName:  "wordpress",
+			FromCharmURL: "cs:precise/wordpress-3",
+			ToCharmURL:   "cs:precise/wordpress-4",
+			BatchSize:    2,
+			WaitActive:   true,
+			Batch:        []string{"wordpress/0", "wordpress/1"},
+			BatchStarted: time.Date(2014, 10, 1, 12, 0, 0, 0, time.UTC),
+			HeldUnits:    []string{"wordpress/2"},
+			Paused:       true,
+			PauseReason:  `unit wordpress/1: hook failed: "upgrade-charm"`,
+		},
+	}
+	s.PatchValue(&getRollingUpgradeAPI, func(*RollingUpgradeCommand) (RollingUpgradeAPI, error) {
+		return s.fake, nil
+	})
+}
+
+type fakeRollingUpgradeAPI struct {
+	calls  []string
+	status params.RollingUpgradeStatus
+	err    error
+}
+
+func (f *fakeRollingUpgradeAPI) RollingUpgradeStatus(serviceName string) (params.RollingUpgradeStatus, error) {
+	f.calls = append(f.calls, "status "+serviceName)
+	return f.status, f.err
+}
+
+func (f *fakeRollingUpgradeAPI) PauseRollingUpgrade(serviceName string) error {
+	f.calls = append(f.calls, "pause "+serviceName)
+	return f.err
+}
+
+func (f *fakeRollingUpgradeAPI) ResumeRollingUpgrade(serviceName string) error {
+	f.calls = append(f.calls, "resume "+serviceName)
+	return f.err
+}
+
+func (f *fakeRollingUpgradeAPI) AbortRollingUpgrade(serviceName string) error {
+	f.calls = append(f.calls, "abort "+serviceName)
+	return f.err
+}
+
+func (f *fakeRollingUpgradeAPI) Close() error {
+	return nil
+}
+
+func (s *RollingUpgradeSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no service specified"},
+		{[]string{"invalid:name"}, `invalid service name "invalid:name"`},
+		{[]string{"wordpress", "extra"}, `unrecognized args: \["extra"\]`},
+		{[]string{"--pause", "--abort", "wordpress"}, "only one of --pause, --resume and --abort may be given"},
+	} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(envcmd.Wrap(&RollingUpgradeCommand{}), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *RollingUpgradeSuite) TestStatus(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&RollingUpgradeCommand{}), "wordpress")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.fake.calls, gc.DeepEquals, []string{"status wordpress"})
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+service: wordpress
+from-charm: cs:precise/wordpress-3
+to-charm: cs:precise/wordpress-4
+batch-size: 2
+wait-active: true
+upgrading:
+- wordpress/0
+- wordpress/1
+upgrading-since: "2014-10-01T12:00:00Z"
+waiting:
+- wordpress/2
+paused: true
+pause-reason: 'unit wordpress/1: hook failed: "upgrade-charm"'
+`[1:])
+}
+
+func (s *RollingUpgradeSuite) TestActions(c *gc.C) {
+	for _, action := range []string{"pause", "resume", "abort"} {
+		s.fake.calls = nil
+		ctx, err := testing.RunCommand(c, envcmd.Wrap(&RollingUpgradeCommand{}), "--"+action, "wordpress")
+		c.Assert(err, gc.IsNil)
+		c.Assert(s.fake.calls, gc.DeepEquals, []string{action + " wordpress"})
+		c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	}
+}
+
+func (s *RollingUpgradeSuite) TestNotImplemented(c *gc.C) {
+	s.fake.err = &params.Error{Code: params.CodeNotImplemented, Message: "not implemented"}
+	_, err := testing.RunCommand(c, envcmd.Wrap(&RollingUpgradeCommand{}), "wordpress")
+	c.Assert(err, gc.ErrorMatches, "cannot manage rolling upgrades: not supported by the API server")
+}
@@ -20,8 +20,11 @@ import (
 
 type StatusCommand struct {
 	envcmd.EnvCommandBase
-	out      cmd.Output
-	patterns []string
+	out        cmd.Output
+	patterns   []string
+	errorsOnly bool
+	watch      bool
+	jsonDeltas bool
 }
 
 var statusDoc = `
@@ -36,6 +39,19 @@ will be displayed.
 Wildcards ('*') may be specified in service/unit names to match any sequence
 of characters. For example, 'nova-*' will match any service whose name begins
 with 'nova-': 'nova-compute', 'nova-volume', etc.
+
+With --errors-only, only the units and machines in an error state are
+reported, along with the services and machines needed to place them.
+
+Besides yaml and json, the status may be shown in a compact table, one line
+per machine, service and unit, with --format tabular, or as counts of the
+machines and units in each state, followed by the machines and units in
+error, with --format summary.
+
+With --watch, the status is shown again in the chosen format each time it
+changes, until interrupted. With --json-deltas as well, each change is instead
+written as a single line of JSON, as reported by the API server, starting with
+the current state of the environment.
 `
 
 func (c *StatusCommand) Info() *cmd.Info {
@@ -50,13 +66,24 @@ func (c *StatusCommand) Info() *cmd.Info {
 
 func (c *StatusCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
-		"yaml": cmd.FormatYaml,
-		"json": cmd.FormatJson,
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": FormatTabular,
+		"summary": FormatSummary,
 	})
+	f.BoolVar(&c.errorsOnly, "errors-only", false, "only show units and machines in an error state")
+	f.BoolVar(&c.watch, "watch", false, "keep showing changes to the status")
+	f.BoolVar(&c.jsonDeltas, "json-deltas", false, "with --watch, show each change as a line of JSON")
 }
 
 func (c *StatusCommand) Init(args []string) error {
 	c.patterns = args
+	if c.jsonDeltas && !c.watch {
+		return fmt.Errorf("--json-deltas requires --watch")
+	}
+	if c.watch && (len(c.patterns) > 0 || c.errorsOnly) {
+		return fmt.Errorf("--watch cannot be used with patterns or --errors-only")
+	}
 	return nil
 }
 
@@ -68,12 +95,17 @@ Error details:
 `
 
 type statusAPI interface {
-	Status(patterns []string) (*api.Status, error)
+	FullStatus(args params.StatusParams) (*api.Status, error)
+	WatchAll() (statusWatcher, error)
 	Close() error
 }
 
 var newApiClientForStatus = func(c *StatusCommand) (statusAPI, error) {
-	return c.NewAPIClient()
+	apiclient, err := c.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return statusClient{apiclient}, nil
 }
 
 func (c *StatusCommand) Run(ctx *cmd.Context) error {
@@ -88,7 +120,13 @@ func (c *StatusCommand) Run(ctx *cmd.Context) error {
 	}
 	defer apiclient.Close()
 
-	status, err := apiclient.Status(c.patterns)
+	if c.jsonDeltas {
+		return c.writeDeltas(ctx, apiclient)
+	}
+	status, err := apiclient.FullStatus(params.StatusParams{
+		Patterns:   c.patterns,
+		ErrorsOnly: c.errorsOnly,
+	})
 	if err != nil {
 		if status == nil {
 			// Status call completely failed, there is nothing to report
@@ -98,7 +136,10 @@ func (c *StatusCommand) Run(ctx *cmd.Context) error {
 		fmt.Fprintf(ctx.Stderr, "%v\n", err)
 	}
 	result := newStatusFormatter(status).format()
-	return c.out.Write(ctx, result)
+	if err := c.out.Write(ctx, result); err != nil || !c.watch {
+		return err
+	}
+	return c.watchStatus(ctx, apiclient, status, result)
 }
 
 type formattedStatus struct {
@@ -187,6 +228,8 @@ type unitStatus struct {
 	AgentStateInfo string                `json:"agent-state-info,omitempty" yaml:"agent-state-info,omitempty"`
 	AgentVersion   string                `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
 	Life           string                `json:"life,omitempty" yaml:"life,omitempty"`
+	WorkloadStatus string                `json:"workload-status,omitempty" yaml:"workload-status,omitempty"`
+	WorkloadInfo   string                `json:"workload-status-info,omitempty" yaml:"workload-status-info,omitempty"`
 	Machine        string                `json:"machine,omitempty" yaml:"machine,omitempty"`
 	OpenedPorts    []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
 	PublicAddress  string                `json:"public-address,omitempty" yaml:"public-address,omitempty"`
@@ -364,6 +407,12 @@ func (sf *statusFormatter) formatUnit(unit api.UnitStatus, serviceName string) u
 		Charm:          unit.Charm,
 		Subordinates:   make(map[string]unitStatus),
 	}
+	// The workload status is only shown once the charm has set it;
+	// older servers do not report it at all.
+	if unit.WorkloadStatus != "" && unit.WorkloadStatus != params.WorkloadUnknown {
+		out.WorkloadStatus = string(unit.WorkloadStatus)
+		out.WorkloadInfo = unit.WorkloadStatusInfo
+	}
 	for k, m := range unit.Subordinates {
 		out.Subordinates[k] = sf.formatUnit(m, serviceName)
 	}
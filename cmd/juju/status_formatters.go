@@ -0,0 +1,254 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/juju/charm.v3"
+
+	"github.com/juju/juju/state/api/params"
+)
+
+// FormatTabular writes a compact table of the machines, services and
+// units in the status, one line each.
+func FormatTabular(value interface{}) ([]byte, error) {
+	fs, ok := value.(formattedStatus)
+	if !ok {
+		return nil, fmt.Errorf("expected value of type %T, got %T", fs, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 2, ' ', 0)
+	p := func(values ...interface{}) {
+		for i, v := range values {
+			if i > 0 {
+				fmt.Fprint(tw, "\t")
+			}
+			fmt.Fprint(tw, v)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	p("[Machines]")
+	p("ID", "STATE", "VERSION", "DNS", "INS-ID", "SERIES", "HARDWARE")
+	var printMachines func(machines map[string]machineStatus)
+	printMachines = func(machines map[string]machineStatus) {
+		for _, id := range sortedNaturally(machineIds(machines)) {
+			m := machines[id]
+			p(id, machineState(m), m.AgentVersion, m.DNSName, m.InstanceId, m.Series, m.Hardware)
+			printMachines(m.Containers)
+		}
+	}
+	printMachines(fs.Machines)
+	p()
+
+	p("[Services]")
+	p("NAME", "EXPOSED", "CHARM", "REV")
+	for _, name := range sortedNaturally(serviceNames(fs.Services)) {
+		svc := fs.Services[name]
+		charmName, rev := charmNameAndRevision(svc.Charm)
+		p(name, svc.Exposed, charmName, rev)
+	}
+	p()
+
+	p("[Units]")
+	p("ID", "STATE", "WORKLOAD", "VERSION", "MACHINE", "PORTS", "PUBLIC-ADDRESS")
+	var printUnits func(units map[string]unitStatus, indent string)
+	printUnits = func(units map[string]unitStatus, indent string) {
+		for _, name := range sortedNaturally(unitNames(units)) {
+			u := units[name]
+			p(indent+name, unitState(u), u.WorkloadStatus, u.AgentVersion, u.Machine,
+				strings.Join(u.OpenedPorts, ","), u.PublicAddress)
+			printUnits(u.Subordinates, indent+"  ")
+		}
+	}
+	for _, name := range sortedNaturally(serviceNames(fs.Services)) {
+		printUnits(fs.Services[name].Units, "")
+	}
+	tw.Flush()
+	return trimLines(out.Bytes()), nil
+}
+
+// FormatSummary writes the number of machines and units in each state,
+// the number of services, and a line for each machine or unit in error.
+func FormatSummary(value interface{}) ([]byte, error) {
+	fs, ok := value.(formattedStatus)
+	if !ok {
+		return nil, fmt.Errorf("expected value of type %T, got %T", fs, value)
+	}
+	machineStates := make(map[string]int)
+	unitStates := make(map[string]int)
+	var errors []string
+
+	var countMachines func(machines map[string]machineStatus)
+	countMachines = func(machines map[string]machineStatus) {
+		for _, id := range sortedNaturally(machineIds(machines)) {
+			m := machines[id]
+			state := machineState(m)
+			machineStates[state]++
+			if state == string(params.StatusError) {
+				errors = append(errors, fmt.Sprintf("machine %s: %s", id, errorInfo(m.Err, m.AgentStateInfo)))
+			}
+			countMachines(m.Containers)
+		}
+	}
+	countMachines(fs.Machines)
+
+	var countUnits func(units map[string]unitStatus)
+	countUnits = func(units map[string]unitStatus) {
+		for _, name := range sortedNaturally(unitNames(units)) {
+			u := units[name]
+			state := unitState(u)
+			unitStates[state]++
+			if state == string(params.StatusError) {
+				errors = append(errors, fmt.Sprintf("unit %s: %s", name, errorInfo(u.Err, u.AgentStateInfo)))
+			}
+			countUnits(u.Subordinates)
+		}
+	}
+	exposed := 0
+	for _, name := range sortedNaturally(serviceNames(fs.Services)) {
+		svc := fs.Services[name]
+		if svc.Exposed {
+			exposed++
+		}
+		countUnits(svc.Units)
+	}
+
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 2, ' ', 0)
+	printStates := func(heading string, states map[string]int) {
+		total := 0
+		var names []string
+		for state, n := range states {
+			total += n
+			names = append(names, state)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(tw, "# %s: (%d)\n", heading, total)
+		for _, state := range names {
+			fmt.Fprintf(tw, "\t%s:\t%d\n", state, states[state])
+		}
+		fmt.Fprintln(tw)
+	}
+	printStates("MACHINES", machineStates)
+	printStates("UNITS", unitStates)
+	fmt.Fprintf(tw, "# SERVICES: (%d)\n", len(fs.Services))
+	fmt.Fprintf(tw, "\texposed:\t%d\n", exposed)
+	if len(errors) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintf(tw, "# ERRORS: (%d)\n", len(errors))
+		for _, line := range errors {
+			fmt.Fprintf(tw, "\t%s\n", line)
+		}
+	}
+	tw.Flush()
+	return trimLines(out.Bytes()), nil
+}
+
+// trimLines removes the padding tabwriter leaves after the last
+// non-empty cell of each line, and the final newline.
+func trimLines(data []byte) []byte {
+	lines := bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n"))
+	for i, line := range lines {
+		lines[i] = bytes.TrimRight(line, " ")
+	}
+	return bytes.Join(lines, []byte("\n"))
+}
+
+// machineState returns the state shown for a machine. Machines
+// without an instance have no agent state, and are pending.
+func machineState(m machineStatus) string {
+	switch {
+	case m.Err != nil:
+		return string(params.StatusError)
+	case m.AgentState == "":
+		return string(params.StatusPending)
+	}
+	return string(m.AgentState)
+}
+
+// unitState returns the state shown for a unit.
+func unitState(u unitStatus) string {
+	if u.Err != nil {
+		return string(params.StatusError)
+	}
+	return string(u.AgentState)
+}
+
+// errorInfo returns the description of an entity in error.
+func errorInfo(err error, info string) string {
+	if err != nil {
+		return err.Error()
+	}
+	return info
+}
+
+// charmNameAndRevision splits a charm URL into the charm's name and
+// revision. If the URL cannot be parsed, it is returned as the name.
+func charmNameAndRevision(curl string) (string, string) {
+	url, err := charm.ParseURL(curl)
+	if err != nil {
+		return curl, ""
+	}
+	return url.Name, strconv.Itoa(url.Revision)
+}
+
+func machineIds(machines map[string]machineStatus) []string {
+	ids := make([]string, 0, len(machines))
+	for id := range machines {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func serviceNames(services map[string]serviceStatus) []string {
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	return names
+}
+
+func unitNames(units map[string]unitStatus) []string {
+	names := make([]string, 0, len(units))
+	for name := range units {
+		names = append(names, name)
+	}
+	return names
+}
+
+// sortedNaturally sorts machine ids and unit names so that their
+// numeric parts are in numeric order: "2" before "10", and "mysql/2"
+// before "mysql/10".
+func sortedNaturally(names []string) []string {
+	sort.Sort(naturally(names))
+	return names
+}
+
+type naturally []string
+
+func (n naturally) Len() int      { return len(n) }
+func (n naturally) Swap(i, j int) { n[i], n[j] = n[j], n[i] }
+
+func (n naturally) Less(i, j int) bool {
+	a, b := strings.Split(n[i], "/"), strings.Split(n[j], "/")
+	for k := 0; k < len(a) && k < len(b); k++ {
+		if a[k] == b[k] {
+			continue
+		}
+		an, aerr := strconv.Atoi(a[k])
+		bn, berr := strconv.Atoi(b[k])
+		if aerr == nil && berr == nil {
+			return an < bn
+		}
+		return a[k] < b[k]
+	}
+	return len(a) < len(b)
+}
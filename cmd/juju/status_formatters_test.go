@@ -0,0 +1,138 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state/api/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type statusFormattersSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&statusFormattersSuite{})
+
+var formatterStatus = formattedStatus{
+	Environment: "dummyenv",
+	Machines: map[string]machineStatus{
+		"0": {
+			AgentState:   params.StatusStarted,
+			AgentVersion: "1.21.0",
+			DNSName:      "10.0.0.1",
+			InstanceId:   "i-0",
+			Series:       "trusty",
+		},
+		"1": {
+			AgentState:     params.StatusError,
+			AgentStateInfo: "cannot start",
+			InstanceId:     "i-1",
+			Series:         "trusty",
+			Containers: map[string]machineStatus{
+				"1/lxc/0": {
+					InstanceId: "pending",
+					Series:     "trusty",
+				},
+			},
+		},
+		"10": {
+			AgentState:   params.StatusStarted,
+			AgentVersion: "1.21.0",
+			DNSName:      "10.0.0.10",
+			InstanceId:   "i-10",
+			Series:       "trusty",
+			Hardware:     "arch=amd64",
+		},
+	},
+	Services: map[string]serviceStatus{
+		"logging": {
+			Charm: "cs:trusty/logging-1",
+		},
+		"wordpress": {
+			Charm:   "cs:trusty/wordpress-3",
+			Exposed: true,
+			Units: map[string]unitStatus{
+				"wordpress/0": {
+					AgentState:     params.StatusStarted,
+					AgentVersion:   "1.21.0",
+					WorkloadStatus: "active",
+					Machine:        "0",
+					OpenedPorts:    []string{"80/tcp"},
+					PublicAddress:  "10.0.0.1",
+					Subordinates: map[string]unitStatus{
+						"logging/0": {
+							AgentState:    params.StatusStarted,
+							AgentVersion:  "1.21.0",
+							PublicAddress: "10.0.0.1",
+						},
+					},
+				},
+				"wordpress/1": {
+					AgentState:     params.StatusError,
+					AgentStateInfo: `hook failed: "install"`,
+					Machine:        "10",
+					PublicAddress:  "10.0.0.10",
+				},
+			},
+		},
+	},
+}
+
+func (s *statusFormattersSuite) TestFormatTabular(c *gc.C) {
+	out, err := FormatTabular(formatterStatus)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(out), gc.Equals, `
+[Machines]
+ID       STATE    VERSION  DNS        INS-ID   SERIES  HARDWARE
+0        started  1.21.0   10.0.0.1   i-0      trusty
+1        error                        i-1      trusty
+1/lxc/0  pending                      pending  trusty
+10       started  1.21.0   10.0.0.10  i-10     trusty  arch=amd64
+
+[Services]
+NAME       EXPOSED  CHARM      REV
+logging    false    logging    1
+wordpress  true     wordpress  3
+
+[Units]
+ID           STATE    WORKLOAD  VERSION  MACHINE  PORTS   PUBLIC-ADDRESS
+wordpress/0  started  active    1.21.0   0        80/tcp  10.0.0.1
+  logging/0  started            1.21.0                    10.0.0.1
+wordpress/1  error                       10               10.0.0.10`[1:])
+}
+
+func (s *statusFormattersSuite) TestFormatSummary(c *gc.C) {
+	out, err := FormatSummary(formatterStatus)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(out), gc.Equals, `
+# MACHINES: (4)
+  error:    1
+  pending:  1
+  started:  2
+
+# UNITS: (3)
+  error:    1
+  started:  2
+
+# SERVICES: (2)
+  exposed:  1
+
+# ERRORS: (2)
+  machine 1: cannot start
+  unit wordpress/1: hook failed: "install"`[1:])
+}
+
+func (s *statusFormattersSuite) TestFormatWrongType(c *gc.C) {
+	_, err := FormatTabular("foo")
+	c.Assert(err, gc.ErrorMatches, `expected value of type main.formattedStatus, got string`)
+	_, err = FormatSummary("foo")
+	c.Assert(err, gc.ErrorMatches, `expected value of type main.formattedStatus, got string`)
+}
+
+func (s *statusFormattersSuite) TestSortedNaturally(c *gc.C) {
+	names := sortedNaturally([]string{"10", "mysql/10", "2", "1/lxc/0", "mysql/2", "1"})
+	c.Assert(names, gc.DeepEquals, []string{"1", "1/lxc/0", "2", "10", "mysql/2", "mysql/10"})
+}
@@ -954,6 +954,44 @@ var statusTests = []testCase{
 				},
 			},
 		},
+	), test(
+		"a unit with a workload status set by its charm",
+		addMachine{machineId: "0", job: state.JobHostUnits},
+		addCharm{"wordpress"},
+		addService{name: "wordpress", charm: "wordpress"},
+		addUnit{"wordpress", "0"},
+		addUnit{"wordpress", "0"},
+		setUnitWorkloadStatus{"wordpress/0", params.WorkloadWaiting, "waiting for database relation"},
+		expect{
+			"only the unit that set its workload status shows it",
+			M{
+				"environment": "dummyenv",
+				"machines": M{
+					"0": M{
+						"instance-id": "pending",
+						"series":      "quantal",
+					},
+				},
+				"services": M{
+					"wordpress": M{
+						"charm":   "cs:quantal/wordpress-3",
+						"exposed": false,
+						"units": M{
+							"wordpress/0": M{
+								"machine":              "0",
+								"agent-state":          "pending",
+								"workload-status":      "waiting",
+								"workload-status-info": "waiting for database relation",
+							},
+							"wordpress/1": M{
+								"machine":     "0",
+								"agent-state": "pending",
+							},
+						},
+					},
+				},
+			},
+		},
 	),
 
 	// Relation tests
@@ -1973,6 +2011,19 @@ func (sus setUnitStatus) step(c *gc.C, ctx *context) {
 	c.Assert(err, gc.IsNil)
 }
 
+type setUnitWorkloadStatus struct {
+	unitName   string
+	status     params.WorkloadStatus
+	statusInfo string
+}
+
+func (sus setUnitWorkloadStatus) step(c *gc.C, ctx *context) {
+	u, err := ctx.st.Unit(sus.unitName)
+	c.Assert(err, gc.IsNil)
+	err = u.SetWorkloadStatus(sus.status, sus.statusInfo)
+	c.Assert(err, gc.IsNil)
+}
+
 type setUnitCharmURL struct {
 	unitName string
 	charm    string
@@ -2169,7 +2220,9 @@ func (s *StatusSuite) TestStatusFilterErrors(c *gc.C) {
 type fakeApiClient struct {
 	statusReturn *api.Status
 	patternsUsed []string
+	errorsOnly   bool
 	closeCalled  bool
+	watcher      *fakeStatusWatcher
 }
 
 func newFakeApiClient(statusReturn *api.Status) fakeApiClient {
@@ -2178,16 +2231,45 @@ func newFakeApiClient(statusReturn *api.Status) fakeApiClient {
 	}
 }
 
-func (a *fakeApiClient) Status(patterns []string) (*api.Status, error) {
-	a.patternsUsed = patterns
+func (a *fakeApiClient) FullStatus(args params.StatusParams) (*api.Status, error) {
+	a.patternsUsed = args.Patterns
+	a.errorsOnly = args.ErrorsOnly
 	return a.statusReturn, nil
 }
 
+func (a *fakeApiClient) WatchAll() (statusWatcher, error) {
+	if a.watcher == nil {
+		return nil, fmt.Errorf("cannot watch")
+	}
+	return a.watcher, nil
+}
+
 func (a *fakeApiClient) Close() error {
 	a.closeCalled = true
 	return nil
 }
 
+// fakeStatusWatcher returns each of its sets of deltas in turn, and
+// then fails.
+type fakeStatusWatcher struct {
+	deltas     [][]params.Delta
+	stopCalled bool
+}
+
+func (w *fakeStatusWatcher) Next() ([]params.Delta, error) {
+	if len(w.deltas) == 0 {
+		return nil, fmt.Errorf("watcher stopped")
+	}
+	deltas := w.deltas[0]
+	w.deltas = w.deltas[1:]
+	return deltas, nil
+}
+
+func (w *fakeStatusWatcher) Stop() error {
+	w.stopCalled = true
+	return nil
+}
+
 // Check that the client works with an older server which doesn't
 // return the top level Relations field nor the unit and machine level
 // Agent field (they were introduced at the same time).
@@ -2311,3 +2393,138 @@ func (s *StatusSuite) TestStatusWithPreRelationsServer(c *gc.C) {
 	defer s.resetContext(c, ctx)
 	ctx.run(c, []stepper{expected})
 }
+
+func (s *StatusSuite) TestStatusErrorsOnlyPassedToServer(c *gc.C) {
+	client := newFakeApiClient(&api.Status{EnvironmentName: "dummyenv"})
+	s.PatchValue(&newApiClientForStatus, func(_ *StatusCommand) (statusAPI, error) {
+		return &client, nil
+	})
+	code, _, stderr := runStatus(c, "--errors-only", "mysql")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(string(stderr), gc.Equals, "")
+	c.Assert(client.errorsOnly, jc.IsTrue)
+	c.Assert(client.patternsUsed, gc.DeepEquals, []string{"mysql"})
+	c.Assert(client.closeCalled, jc.IsTrue)
+}
+
+var statusWatchDeltas = [][]params.Delta{{{
+	// The initial deltas are reconciled with the status already shown.
+	Entity: &params.MachineInfo{Id: "0", InstanceId: "dummyenv-0", Status: params.StatusStarted, Series: "quantal", Life: params.Alive},
+}, {
+	Entity: &params.ServiceInfo{Name: "wordpress", CharmURL: "cs:quantal/wordpress-3", Life: params.Alive},
+}, {
+	Entity: &params.UnitInfo{Name: "wordpress/0", Service: "wordpress", CharmURL: "cs:quantal/wordpress-3", MachineId: "0", Status: params.StatusStarted},
+}, {
+	Entity: &params.RelationInfo{Key: "wordpress:cache", Endpoints: []params.Endpoint{{
+		ServiceName: "wordpress",
+		Relation:    charm.Relation{Name: "cache", Role: charm.RolePeer, Interface: "memcache", Scope: charm.ScopeGlobal},
+	}}},
+}, {
+	Entity: &params.AnnotationInfo{Tag: "machine-0"},
+}}, {{
+	// Unchanged entities do not cause the status to be shown again.
+	Entity: &params.MachineInfo{Id: "0", InstanceId: "dummyenv-0", Status: params.StatusStarted, Series: "quantal", Life: params.Alive},
+}}, {{
+	Entity: &params.UnitInfo{
+		Name: "wordpress/0", Service: "wordpress", CharmURL: "cs:quantal/wordpress-3", MachineId: "0",
+		Status: params.StatusError, StatusInfo: "hook failed",
+	},
+}, {
+	Entity: &params.ServiceInfo{Name: "wordpress", CharmURL: "cs:quantal/wordpress-3", Exposed: true, Life: params.Dying},
+}, {
+	Entity: &params.MachineInfo{Id: "0/lxc/0", Status: params.StatusPending, Life: params.Alive},
+}, {
+	Removed: true,
+	Entity:  &params.RelationInfo{Key: "wordpress:cache"},
+}, {
+	// Entities already removed are ignored.
+	Removed: true,
+	Entity:  &params.MachineInfo{Id: "1"},
+}}}
+
+func (s *StatusSuite) TestStatusWatch(c *gc.C) {
+	watcher := &fakeStatusWatcher{deltas: statusWatchDeltas}
+	client := newFakeApiClient(&api.Status{
+		EnvironmentName: "dummyenv",
+		Machines: map[string]api.MachineStatus{
+			"0": {
+				Agent:      api.AgentStatus{Status: params.StatusStarted, Version: "1.2.3"},
+				AgentState: params.StatusStarted,
+				Id:         "0",
+				InstanceId: instance.Id("dummyenv-0"),
+				Series:     "quantal",
+			},
+			// Machine 1 is removed before the watcher starts.
+			"1": {
+				Agent:      api.AgentStatus{Status: params.StatusStarted, Version: "1.2.3"},
+				AgentState: params.StatusStarted,
+				Id:         "1",
+				InstanceId: instance.Id("dummyenv-1"),
+				Series:     "quantal",
+			},
+		},
+		Services: map[string]api.ServiceStatus{
+			"wordpress": {
+				Charm: "cs:quantal/wordpress-3",
+				Units: map[string]api.UnitStatus{
+					"wordpress/0": {
+						Agent:      api.AgentStatus{Status: params.StatusPending, Version: "1.2.3"},
+						AgentState: params.StatusPending,
+						Machine:    "0",
+					},
+				},
+			},
+		},
+	})
+	client.watcher = watcher
+	s.PatchValue(&newApiClientForStatus, func(_ *StatusCommand) (statusAPI, error) {
+		return &client, nil
+	})
+	code, stdout, stderr := runStatus(c, "--watch", "--format", "json")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(string(stderr), gc.Equals, "error: watcher stopped\n")
+	c.Assert(string(stdout), gc.Equals, `
+{"environment":"dummyenv","machines":{"0":{"agent-state":"started","agent-version":"1.2.3","instance-id":"dummyenv-0","series":"quantal"},"1":{"agent-state":"started","agent-version":"1.2.3","instance-id":"dummyenv-1","series":"quantal"}},"services":{"wordpress":{"charm":"cs:quantal/wordpress-3","exposed":false,"units":{"wordpress/0":{"agent-state":"pending","agent-version":"1.2.3","machine":"0"}}}}}
+{"environment":"dummyenv","machines":{"0":{"agent-state":"started","agent-version":"1.2.3","instance-id":"dummyenv-0","series":"quantal"}},"services":{"wordpress":{"charm":"cs:quantal/wordpress-3","exposed":false,"relations":{"cache":["wordpress"]},"units":{"wordpress/0":{"agent-state":"started","agent-version":"1.2.3","machine":"0"}}}}}
+{"environment":"dummyenv","machines":{"0":{"agent-state":"started","agent-version":"1.2.3","instance-id":"dummyenv-0","series":"quantal","containers":{"0/lxc/0":{"instance-id":"pending"}}}},"services":{"wordpress":{"charm":"cs:quantal/wordpress-3","exposed":true,"life":"dying","units":{"wordpress/0":{"agent-state":"error","agent-state-info":"hook failed","agent-version":"1.2.3","machine":"0"}}}}}
+`[1:])
+	c.Assert(watcher.stopCalled, jc.IsTrue)
+}
+
+func (s *StatusSuite) TestStatusWatchJSONDeltas(c *gc.C) {
+	client := newFakeApiClient(nil)
+	client.watcher = &fakeStatusWatcher{deltas: [][]params.Delta{statusWatchDeltas[0][:3]}}
+	s.PatchValue(&newApiClientForStatus, func(_ *StatusCommand) (statusAPI, error) {
+		return &client, nil
+	})
+	code, stdout, _ := runStatus(c, "--watch", "--json-deltas")
+	c.Assert(code, gc.Equals, 1)
+	lines := strings.Split(strings.TrimSuffix(string(stdout), "\n"), "\n")
+	c.Assert(lines, gc.HasLen, 3)
+	for i, line := range lines {
+		var delta params.Delta
+		err := json.Unmarshal([]byte(line), &delta)
+		c.Assert(err, gc.IsNil)
+		c.Assert(delta, jc.DeepEquals, statusWatchDeltas[0][i])
+	}
+}
+
+func (s *StatusSuite) TestStatusWatchInvalidArgs(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--json-deltas"},
+		err:  "--json-deltas requires --watch",
+	}, {
+		args: []string{"--watch", "mysql"},
+		err:  "--watch cannot be used with patterns or --errors-only",
+	}, {
+		args: []string{"--watch", "--errors-only"},
+		err:  "--watch cannot be used with patterns or --errors-only",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := coretesting.InitCommand(envcmd.Wrap(&StatusCommand{}), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
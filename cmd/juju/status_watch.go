@@ -0,0 +1,406 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"gopkg.in/juju/charm.v3"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state/api"
+	"github.com/juju/juju/state/api/params"
+)
+
+// statusWatcher is the part of the AllWatcher API used by
+// status --watch.
+type statusWatcher interface {
+	Next() ([]params.Delta, error)
+	Stop() error
+}
+
+// statusClient adapts the API client to statusAPI.
+type statusClient struct {
+	*api.Client
+}
+
+func (c statusClient) WatchAll() (statusWatcher, error) {
+	w, err := c.Client.WatchAll()
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// writeDeltas writes each change to the environment reported by the
+// AllWatcher as a line of JSON, until the watcher fails.
+func (c *StatusCommand) writeDeltas(ctx *cmd.Context, apiclient statusAPI) error {
+	watcher, err := apiclient.WatchAll()
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+	for {
+		deltas, err := watcher.Next()
+		if err != nil {
+			return err
+		}
+		for _, delta := range deltas {
+			data, err := json.Marshal(&delta)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(ctx.Stdout, "%s\n", data)
+		}
+	}
+}
+
+// watchStatus applies the changes reported by the AllWatcher to the
+// given status, which has already been written as shown, and writes
+// the status again in the chosen format whenever it changes, until
+// the watcher fails. The first set of deltas describes the whole
+// environment, and is reconciled with the status in the same way.
+func (c *StatusCommand) watchStatus(ctx *cmd.Context, apiclient statusAPI, status *api.Status, shown formattedStatus) error {
+	watcher, err := apiclient.WatchAll()
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+	for initial := true; ; initial = false {
+		deltas, err := watcher.Next()
+		if err != nil {
+			return err
+		}
+		applyDeltas(status, deltas, initial)
+		result := newStatusFormatter(status).format()
+		if reflect.DeepEqual(result, shown) {
+			continue
+		}
+		if err := c.out.Write(ctx, result); err != nil {
+			return err
+		}
+		shown = result
+	}
+}
+
+// applyDeltas updates the status with the given AllWatcher deltas.
+// The AllWatcher does not report everything shown by status, so
+// whatever it does not report is left as it was. The initial deltas
+// describe the whole environment, so anything they leave out has been
+// removed since the status was fetched.
+func applyDeltas(status *api.Status, deltas []params.Delta, initial bool) {
+	if status.Machines == nil {
+		status.Machines = make(map[string]api.MachineStatus)
+	}
+	if status.Services == nil {
+		status.Services = make(map[string]api.ServiceStatus)
+	}
+	relationsChanged := initial
+	if initial {
+		pruneStatus(status, deltas)
+	}
+	for _, delta := range deltas {
+		switch info := delta.Entity.(type) {
+		case *params.MachineInfo:
+			applyMachineInfo(status.Machines, machineIdPath(info.Id), info, delta.Removed)
+		case *params.ServiceInfo:
+			applyServiceInfo(status, info, delta.Removed)
+		case *params.UnitInfo:
+			applyUnitInfo(status, info, delta.Removed)
+		case *params.RelationInfo:
+			applyRelationInfo(status, info, delta.Removed)
+			relationsChanged = true
+		}
+	}
+	if relationsChanged {
+		for name, service := range status.Services {
+			related := serviceRelations(status.Relations, name)
+			if len(related) > 0 || len(service.Relations) > 0 {
+				service.Relations = related
+				status.Services[name] = service
+			}
+		}
+	}
+}
+
+// pruneStatus removes the machines, services, units and relations
+// not described by the given deltas from the status.
+func pruneStatus(status *api.Status, deltas []params.Delta) {
+	seen := make(map[params.EntityId]bool)
+	for _, delta := range deltas {
+		if !delta.Removed {
+			seen[delta.Entity.EntityId()] = true
+		}
+	}
+	pruneMachines(status.Machines, seen)
+	for name, service := range status.Services {
+		if !seen[params.EntityId{Kind: "service", Id: name}] {
+			delete(status.Services, name)
+			continue
+		}
+		pruneUnits(service.Units, seen)
+	}
+	var relations []api.RelationStatus
+	for _, relation := range status.Relations {
+		if seen[params.EntityId{Kind: "relation", Id: relation.Key}] {
+			relations = append(relations, relation)
+		}
+	}
+	status.Relations = relations
+}
+
+func pruneMachines(machines map[string]api.MachineStatus, seen map[params.EntityId]bool) {
+	for id, m := range machines {
+		if !seen[params.EntityId{Kind: "machine", Id: id}] {
+			delete(machines, id)
+			continue
+		}
+		pruneMachines(m.Containers, seen)
+	}
+}
+
+func pruneUnits(units map[string]api.UnitStatus, seen map[params.EntityId]bool) {
+	for name, u := range units {
+		if !seen[params.EntityId{Kind: "unit", Id: name}] {
+			delete(units, name)
+			continue
+		}
+		pruneUnits(u.Subordinates, seen)
+	}
+}
+
+// machineIdPath returns the ids of the machine with the given id and
+// of the machines that host it, outermost first.
+func machineIdPath(id string) []string {
+	parts := strings.Split(id, "/")
+	var path []string
+	for i := 1; i <= len(parts); i += 2 {
+		path = append(path, strings.Join(parts[:i], "/"))
+	}
+	return path
+}
+
+// applyMachineInfo updates the machine at the given path below
+// machines. A host not yet known is added so that its containers
+// have somewhere to go; its own delta fills it in.
+func applyMachineInfo(machines map[string]api.MachineStatus, path []string, info *params.MachineInfo, removed bool) {
+	id := path[0]
+	m, ok := machines[id]
+	if len(path) == 1 && removed {
+		delete(machines, id)
+		return
+	}
+	if !ok {
+		if removed {
+			return
+		}
+		m = api.MachineStatus{Id: id}
+	}
+	if m.Containers == nil {
+		m.Containers = make(map[string]api.MachineStatus)
+	}
+	if len(path) > 1 {
+		applyMachineInfo(m.Containers, path[1:], info, removed)
+	} else {
+		m = machineStatusFromInfo(m, info)
+	}
+	machines[id] = m
+}
+
+func machineStatusFromInfo(m api.MachineStatus, info *params.MachineInfo) api.MachineStatus {
+	m.AgentState, m.AgentStateInfo = agentStateFromInfo(m.AgentState, m.AgentStateInfo, m.Agent, info.Status, info.StatusInfo)
+	m.Agent.Status = info.Status
+	m.Agent.Info = info.StatusInfo
+	m.Agent.Data = info.StatusData
+	m.Agent.Life = lifeFromInfo(info.Life)
+	m.Agent.Err = nil
+	m.Life = m.Agent.Life
+	m.Err = nil
+	m.Series = info.Series
+	m.Jobs = info.Jobs
+	if info.InstanceId != "" {
+		m.InstanceId = instance.Id(info.InstanceId)
+		m.DNSName = network.SelectPublicAddress(info.Addresses)
+	} else {
+		// As for FullStatus, an unprovisioned machine has no agent
+		// state worth reporting.
+		m.InstanceId = "pending"
+		m.AgentState = ""
+	}
+	if info.HardwareCharacteristics != nil {
+		m.Hardware = info.HardwareCharacteristics.String()
+	}
+	return m
+}
+
+// agentStateFromInfo returns the agent state and info to show for an
+// agent whose status is now as given. The AllWatcher does not report
+// agent presence, so an agent shown as down stays down until its
+// status changes.
+func agentStateFromInfo(state params.Status, stateInfo string, agent api.AgentStatus, status params.Status, info string) (params.Status, string) {
+	if state == params.StatusDown && agent.Status == status && agent.Info == info {
+		return state, stateInfo
+	}
+	return status, info
+}
+
+func lifeFromInfo(life params.Life) string {
+	if life == params.Alive {
+		// alive is the usual state so omit it, as FullStatus does.
+		return ""
+	}
+	return string(life)
+}
+
+func applyServiceInfo(status *api.Status, info *params.ServiceInfo, removed bool) {
+	if removed {
+		delete(status.Services, info.Name)
+		return
+	}
+	service, ok := status.Services[info.Name]
+	if !ok {
+		service = api.ServiceStatus{
+			Relations: serviceRelations(status.Relations, info.Name),
+			Units:     make(map[string]api.UnitStatus),
+		}
+	}
+	service.Charm = info.CharmURL
+	service.Exposed = info.Exposed
+	service.Life = lifeFromInfo(info.Life)
+	status.Services[info.Name] = service
+}
+
+// applyUnitInfo updates the unit wherever it is shown. A subordinate
+// unit not yet shown is left out, as the AllWatcher does not report
+// which principal unit it belongs to.
+func applyUnitInfo(status *api.Status, info *params.UnitInfo, removed bool) {
+	for name, service := range status.Services {
+		if applyUnitInfoTo(service.Units, info, service.Charm, true, removed) {
+			status.Services[name] = service
+			return
+		}
+	}
+	service, ok := status.Services[info.Service]
+	if removed || !ok || len(service.SubordinateTo) > 0 {
+		return
+	}
+	if service.Units == nil {
+		service.Units = make(map[string]api.UnitStatus)
+	}
+	service.Units[info.Name] = unitStatusFromInfo(api.UnitStatus{}, info, service.Charm, true)
+	status.Services[info.Service] = service
+}
+
+// applyUnitInfoTo updates the unit if it is found among the given
+// units or their subordinates, and reports whether it was.
+func applyUnitInfoTo(units map[string]api.UnitStatus, info *params.UnitInfo, serviceCharm string, principal, removed bool) bool {
+	for name, unit := range units {
+		if name == info.Name {
+			if removed {
+				delete(units, name)
+			} else {
+				units[name] = unitStatusFromInfo(unit, info, serviceCharm, principal)
+			}
+			return true
+		}
+		if applyUnitInfoTo(unit.Subordinates, info, serviceCharm, false, removed) {
+			return true
+		}
+	}
+	return false
+}
+
+func unitStatusFromInfo(u api.UnitStatus, info *params.UnitInfo, serviceCharm string, principal bool) api.UnitStatus {
+	u.AgentState, u.AgentStateInfo = agentStateFromInfo(u.AgentState, u.AgentStateInfo, u.Agent, info.Status, info.StatusInfo)
+	u.Agent.Status = info.Status
+	u.Agent.Info = info.StatusInfo
+	u.Agent.Data = info.StatusData
+	u.Agent.Err = nil
+	u.Err = nil
+	u.WorkloadStatus = info.WorkloadStatus
+	u.WorkloadStatusInfo = info.WorkloadStatusInfo
+	if principal {
+		// Subordinate units are shown on their principal's machine.
+		u.Machine = info.MachineId
+	}
+	u.PublicAddress = info.PublicAddress
+	u.OpenedPorts = nil
+	for _, port := range info.Ports {
+		u.OpenedPorts = append(u.OpenedPorts, port.String())
+	}
+	u.Charm = ""
+	if info.CharmURL != "" && info.CharmURL != serviceCharm {
+		u.Charm = info.CharmURL
+	}
+	return u
+}
+
+func applyRelationInfo(status *api.Status, info *params.RelationInfo, removed bool) {
+	var relations []api.RelationStatus
+	for _, relation := range status.Relations {
+		if relation.Key != info.Key {
+			relations = append(relations, relation)
+		}
+	}
+	if !removed {
+		relation := api.RelationStatus{
+			Id:  info.Id,
+			Key: info.Key,
+		}
+		for _, ep := range info.Endpoints {
+			relation.Endpoints = append(relation.Endpoints, api.EndpointStatus{
+				ServiceName: ep.ServiceName,
+				Name:        ep.Relation.Name,
+				Role:        ep.Relation.Role,
+				Subordinate: isSubordinateEndpoint(status, ep),
+			})
+			// these should match on both sides so use the last
+			relation.Interface = ep.Relation.Interface
+			relation.Scope = ep.Relation.Scope
+		}
+		relations = append(relations, relation)
+	}
+	status.Relations = relations
+}
+
+// isSubordinateEndpoint reports whether the endpoint is the
+// subordinate end of a container relation, as far as the status
+// already knows the service to be a subordinate.
+func isSubordinateEndpoint(status *api.Status, ep params.Endpoint) bool {
+	service, ok := status.Services[ep.ServiceName]
+	return ok && ep.Relation.Scope == charm.ScopeContainer && len(service.SubordinateTo) > 0
+}
+
+// serviceRelations returns the services related to the named service,
+// keyed by the name of its relation, as reported by FullStatus.
+func serviceRelations(relations []api.RelationStatus, serviceName string) map[string][]string {
+	related := make(map[string][]string)
+	for _, relation := range relations {
+		for _, ep := range relation.Endpoints {
+			if ep.ServiceName != serviceName {
+				continue
+			}
+			if len(relation.Endpoints) == 1 {
+				// A peer relation relates the service to itself.
+				related[ep.Name] = append(related[ep.Name], serviceName)
+			}
+			for _, other := range relation.Endpoints {
+				if other.ServiceName != serviceName {
+					related[ep.Name] = append(related[ep.Name], other.ServiceName)
+				}
+			}
+		}
+	}
+	for name, serviceNames := range related {
+		sort.Strings(serviceNames)
+		related[name] = serviceNames
+	}
+	return related
+}
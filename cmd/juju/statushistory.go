@@ -0,0 +1,128 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+)
+
+// StatusHistoryCommand shows the statuses recently set for a unit or
+// machine.
+type StatusHistoryCommand struct {
+	envcmd.EnvCommandBase
+	out  cmd.Output
+	name string
+	size int
+}
+
+const statusHistoryDoc = `
+Show the statuses recently set for a unit or machine, most recent first.
+The state server keeps a limited history of each unit and machine, so the
+oldest statuses may no longer be available.
+
+Examples:
+    juju status-history wordpress/0
+    juju status-history -n 5 0
+`
+
+func (c *StatusHistoryCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "status-history",
+		Args:    "<unit or machine>",
+		Purpose: "show the status history of a unit or machine",
+		Doc:     statusHistoryDoc,
+	}
+}
+
+func (c *StatusHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.IntVar(&c.size, "n", 20, "the number of statuses to show")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatStatusHistoryTabular,
+	})
+}
+
+func (c *StatusHistoryCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no unit or machine specified")
+	}
+	c.name, args = args[0], args[1:]
+	if !names.IsValidUnit(c.name) && !names.IsValidMachine(c.name) {
+		return fmt.Errorf("%q is not a valid unit name or machine id", c.name)
+	}
+	if c.size <= 0 {
+		return fmt.Errorf("invalid number of statuses %d", c.size)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// StatusHistoryAPI defines the client API methods used by the
+// status-history command.
+type StatusHistoryAPI interface {
+	StatusHistory(name string, size int) ([]params.StatusHistoryEntry, error)
+	Close() error
+}
+
+var getStatusHistoryAPI = func(c *StatusHistoryCommand) (StatusHistoryAPI, error) {
+	return c.NewAPIClient()
+}
+
+// statusHistoryInfo is the representation of a status written by the
+// status-history command.
+type statusHistoryInfo struct {
+	Since  string            `json:"since" yaml:"since"`
+	Status params.Status     `json:"status" yaml:"status"`
+	Info   string            `json:"info,omitempty" yaml:"info,omitempty"`
+	Data   params.StatusData `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+func (c *StatusHistoryCommand) Run(ctx *cmd.Context) error {
+	client, err := getStatusHistoryAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	history, err := client.StatusHistory(c.name, c.size)
+	if err != nil {
+		return err
+	}
+	infos := make([]statusHistoryInfo, len(history))
+	for i, status := range history {
+		infos[i] = statusHistoryInfo{
+			Since:  status.Since.UTC().Format(time.RFC3339),
+			Status: status.Status,
+			Info:   status.Info,
+			Data:   status.Data,
+		}
+	}
+	return c.out.Write(ctx, infos)
+}
+
+// formatStatusHistoryTabular writes the statuses as a table with a
+// header line. Status data is only shown by the other formats.
+func formatStatusHistoryTabular(value interface{}) ([]byte, error) {
+	infos, ok := value.([]statusHistoryInfo)
+	if !ok {
+		return nil, fmt.Errorf("expected value of type %T, got %T", infos, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tSTATUS\tINFO")
+	for _, info := range infos {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", info.Since, info.Status, info.Info)
+	}
+	tw.Flush()
+	return trimLines(out.Bytes()), nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+type StatusHistorySuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeStatusHistoryAPI
+}
+
+var _ = gc.Suite(&StatusHistorySuite{})
+
+var statusHistoryTime = time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *StatusHistorySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeStatusHistoryAPI{
+		history: []params.StatusHistoryEntry{{
+			Status: params.StatusError,
+			Info:   `hook failed: "install"`,
+			Data:   params.StatusData{"hook": "install"},
+			Since:  statusHistoryTime.Add(time.Minute),
+		}, {
+			Status: params.StatusInstalled,
+			Since:  statusHistoryTime,
+		}},
+	}
+	s.PatchValue(&getStatusHistoryAPI, func(*StatusHistoryCommand) (StatusHistoryAPI, error) {
+		return s.fake, nil
+	})
+}
+
+type fakeStatusHistoryAPI struct {
+	name    string
+	size    int
+	history []params.StatusHistoryEntry
+	err     error
+}
+
+func (f *fakeStatusHistoryAPI) StatusHistory(name string, size int) ([]params.StatusHistoryEntry, error) {
+	f.name = name
+	f.size = size
+	return f.history, f.err
+}
+
+func (f *fakeStatusHistoryAPI) Close() error {
+	return nil
+}
+
+func (s *StatusHistorySuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		name     string
+		size     int
+		errMatch string
+	}{{
+		args: []string{"wordpress/0"},
+		name: "wordpress/0",
+		size: 20,
+	}, {
+		args: []string{"-n", "5", "0/lxc/1"},
+		name: "0/lxc/1",
+		size: 5,
+	}, {
+		errMatch: "no unit or machine specified",
+	}, {
+		args:     []string{"wordpress"},
+		errMatch: `"wordpress" is not a valid unit name or machine id`,
+	}, {
+		args:     []string{"-n", "0", "wordpress/0"},
+		errMatch: "invalid number of statuses 0",
+	}, {
+		args:     []string{"wordpress/0", "mysql/0"},
+		errMatch: `unrecognized args: \["mysql/0"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &StatusHistoryCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.errMatch == "" {
+			c.Check(err, gc.IsNil)
+			c.Check(command.name, gc.Equals, test.name)
+			c.Check(command.size, gc.Equals, test.size)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *StatusHistorySuite) TestTabular(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "-n", "2", "wordpress/0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.fake.name, gc.Equals, "wordpress/0")
+	c.Assert(s.fake.size, gc.Equals, 2)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+TIME                  STATUS     INFO
+2014-09-01T12:01:00Z  error      hook failed: "install"
+2014-09-01T12:00:00Z  installed
+`[1:])
+}
+
+func (s *StatusHistorySuite) TestYAML(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "--format", "yaml", "wordpress/0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+- since: "2014-09-01T12:01:00Z"
+  status: error
+  info: 'hook failed: "install"'
+  data:
+    hook: install
+- since: "2014-09-01T12:00:00Z"
+  status: installed
+`[1:])
+}
+
+func (s *StatusHistorySuite) TestError(c *gc.C) {
+	s.fake.err = fmt.Errorf("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "0")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
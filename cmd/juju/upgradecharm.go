@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state/api/params"
 )
 
 // UpgradeCharm is responsible for upgrading a service's charm.
@@ -24,6 +25,8 @@ type UpgradeCharmCommand struct {
 	RepoPath    string // defaults to JUJU_REPOSITORY
 	SwitchURL   string
 	Revision    int // defaults to -1 (latest)
+	BatchSize   int // 0 upgrades all units at once
+	WaitActive  bool
 }
 
 const upgradeCharmDoc = `
@@ -62,6 +65,13 @@ would specify revision number 5 of the wordpress charm.
 Use of the --force flag is not generally recommended; units upgraded while in an
 error state will not have upgrade-charm hooks executed, and may cause unexpected
 behavior.
+
+The --batch-size flag upgrades the units of the service a batch of the given
+size at a time, rather than all at once. Each batch is upgraded once every unit
+of the previous batch has run its upgrade-charm hook without error and, with
+--wait-active, its charm has set its workload status to active. The upgrade is
+paused if a unit fails to upgrade or its charm sets its workload status to
+blocked. Use "juju rolling-upgrade" to follow, pause, resume or abort it.
 `
 
 func (c *UpgradeCharmCommand) Info() *cmd.Info {
@@ -78,6 +88,8 @@ func (c *UpgradeCharmCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.RepoPath, "repository", os.Getenv("JUJU_REPOSITORY"), "local charm repository path")
 	f.StringVar(&c.SwitchURL, "switch", "", "crossgrade to a different charm")
 	f.IntVar(&c.Revision, "revision", -1, "explicit revision of current charm")
+	f.IntVar(&c.BatchSize, "batch-size", 0, "upgrade this many units at a time")
+	f.BoolVar(&c.WaitActive, "wait-active", false, "wait for upgraded units to report an active workload before upgrading more")
 }
 
 func (c *UpgradeCharmCommand) Init(args []string) error {
@@ -95,6 +107,12 @@ func (c *UpgradeCharmCommand) Init(args []string) error {
 	if c.SwitchURL != "" && c.Revision != -1 {
 		return fmt.Errorf("--switch and --revision are mutually exclusive")
 	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("invalid batch size %d", c.BatchSize)
+	}
+	if c.WaitActive && c.BatchSize == 0 {
+		return fmt.Errorf("--wait-active requires --batch-size")
+	}
 	return nil
 }
 
@@ -164,5 +182,16 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
-	return client.ServiceSetCharm(c.ServiceName, addedURL.String(), c.Force)
+	if c.BatchSize == 0 {
+		return client.ServiceSetCharm(c.ServiceName, addedURL.String(), c.Force)
+	}
+	err = client.ServiceRollingUpgrade(c.ServiceName, addedURL.String(), c.Force, c.BatchSize, c.WaitActive)
+	if params.IsCodeNotImplemented(err) {
+		return fmt.Errorf("cannot upgrade units in batches: not supported by the API server")
+	}
+	if err != nil {
+		return err
+	}
+	ctx.Infof("upgrading service %q to charm %q, %d units at a time", c.ServiceName, addedURL, c.BatchSize)
+	return nil
 }
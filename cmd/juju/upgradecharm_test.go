@@ -94,6 +94,13 @@ func (s *UpgradeCharmErrorsSuite) TestInvalidRevision(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid value "blah" for flag --revision: strconv.ParseInt: parsing "blah": invalid syntax`)
 }
 
+func (s *UpgradeCharmErrorsSuite) TestInvalidBatchSize(c *gc.C) {
+	err := runUpgradeCharm(c, "riak", "--batch-size=-1")
+	c.Assert(err, gc.ErrorMatches, "invalid batch size -1")
+	err = runUpgradeCharm(c, "riak", "--wait-active")
+	c.Assert(err, gc.ErrorMatches, "--wait-active requires --batch-size")
+}
+
 type UpgradeCharmSuccessSuite struct {
 	jujutesting.RepoSuite
 	path string
@@ -217,3 +224,23 @@ func (s *UpgradeCharmSuccessSuite) TestSwitch(c *gc.C) {
 	c.Assert(curl.String(), gc.Equals, "local:precise/myriak-42")
 	s.assertLocalRevision(c, 42, myriakPath)
 }
+
+func (s *UpgradeCharmSuccessSuite) TestRollingUpgrade(c *gc.C) {
+	_, err := s.riak.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = runUpgradeCharm(c, "riak", "--batch-size=1", "--wait-active")
+	c.Assert(err, gc.IsNil)
+	s.assertUpgraded(c, 8, false)
+
+	ru, err := s.State.RollingUpgrade("riak")
+	c.Assert(err, gc.IsNil)
+	c.Assert(ru.BatchSize(), gc.Equals, 1)
+	c.Assert(ru.WaitActive(), gc.Equals, true)
+	held, err := ru.HeldUnits()
+	c.Assert(err, gc.IsNil)
+	c.Assert(held, gc.DeepEquals, []string{"riak/0", "riak/1"})
+
+	// The charm cannot be upgraded again until the rolling upgrade ends.
+	err = runUpgradeCharm(c, "riak")
+	c.Assert(err, gc.ErrorMatches, `rolling upgrade of service "riak" in progress`)
+}
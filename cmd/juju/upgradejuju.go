@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/juju/cmd"
+	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
@@ -33,6 +34,9 @@ type UpgradeJujuCommand struct {
 	UploadTools bool
 	DryRun      bool
 	Series      []string
+	Canaries    []string
+	Continue    bool
+	RollBack    bool
 }
 
 var upgradeJujuDoc = `
@@ -66,6 +70,22 @@ value of the environment's agent-version setting:
 Both of these depend on tools availability, which some situations (no
 outgoing internet access) and provider types (such as maas) require that
 you manage yourself; see the documentation for "sync-tools".
+
+The state servers always upgrade before the other machines. To try an
+upgrade on a few machines before the rest of the environment, name them
+with the --canary flag, for example:
+
+    juju upgrade-juju --canary 3,7
+
+Only the state servers and machines 3 and 7 then upgrade. Once they run
+the new version, upgrade all the other agents with:
+
+    juju upgrade-juju --continue
+
+Running "juju upgrade-juju --rollback" instead, before or after
+--continue, returns agents to the version they ran before the upgrade.
+Agents that have already run the upgrade steps for the new version,
+which cannot be undone, keep running it.
 `
 
 func (c *UpgradeJujuCommand) Info() *cmd.Info {
@@ -81,6 +101,9 @@ func (c *UpgradeJujuCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.UploadTools, "upload-tools", false, "upload local version of tools")
 	f.BoolVar(&c.DryRun, "dry-run", false, "don't change anything, just report what would change")
 	f.Var(newSeriesValue(nil, &c.Series), "series", "upload tools for supplied comma-separated series list")
+	f.Var(cmd.NewStringsValue(nil, &c.Canaries), "canary", "upgrade only the state servers and these comma-separated machines until --continue")
+	f.BoolVar(&c.Continue, "continue", false, "upgrade all agents after the canary machines have upgraded")
+	f.BoolVar(&c.RollBack, "rollback", false, "return agents that have not run upgrade steps to the previous version")
 }
 
 func (c *UpgradeJujuCommand) Init(args []string) error {
@@ -106,6 +129,19 @@ func (c *UpgradeJujuCommand) Init(args []string) error {
 	if len(c.Series) > 0 && !c.UploadTools {
 		return fmt.Errorf("--series requires --upload-tools")
 	}
+	for _, id := range c.Canaries {
+		if !names.IsMachine(id) {
+			return fmt.Errorf("invalid machine id %q", id)
+		}
+	}
+	if c.Continue || c.RollBack {
+		if c.Continue && c.RollBack {
+			return fmt.Errorf("cannot specify both --continue and --rollback")
+		}
+		if c.vers != "" || c.UploadTools || c.DryRun || len(c.Canaries) > 0 {
+			return fmt.Errorf("--continue and --rollback cannot be combined with other flags")
+		}
+	}
 	return cmd.CheckEmpty(args)
 }
 
@@ -132,6 +168,20 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 			err = nil
 		}
 	}()
+	if c.Continue {
+		err := client.ContinueAgentUpgrade()
+		if params.IsCodeNotImplemented(err) {
+			return fmt.Errorf("cannot continue upgrade: not supported by the API server")
+		}
+		return err
+	}
+	if c.RollBack {
+		err := client.RollBackAgentUpgrade()
+		if params.IsCodeNotImplemented(err) {
+			return fmt.Errorf("cannot roll back upgrade: not supported by the API server")
+		}
+		return err
+	}
 
 	// Determine the version to upgrade to, uploading tools if necessary.
 	attrs, err := client.EnvironmentGet()
@@ -163,7 +213,7 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 	if c.DryRun {
 		ctx.Infof("upgrade to this version by running\n    juju upgrade-juju --version=\"%s\"\n", context.chosen)
 	} else {
-		if err := client.SetEnvironAgentVersion(context.chosen); err != nil {
+		if err := c.startUpgrade(client, context.chosen); err != nil {
 			return err
 		}
 		logger.Infof("started upgrade to %s", context.chosen)
@@ -171,6 +221,20 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 	return nil
 }
 
+// startUpgrade starts upgrading the environment's agents to the given
+// version, only on the state servers and canary machines if any were
+// given.
+func (c *UpgradeJujuCommand) startUpgrade(client *api.Client, vers version.Number) error {
+	err := client.StartAgentUpgrade(vers, c.Canaries)
+	if params.IsCodeNotImplemented(err) {
+		if len(c.Canaries) > 0 {
+			return fmt.Errorf("cannot upgrade canary machines: not supported by the API server")
+		}
+		return client.SetEnvironAgentVersion(vers)
+	}
+	return err
+}
+
 // initVersions collects state relevant to an upgrade decision. The returned
 // agent and client versions, and the list of currently available tools, will
 // always be accurate; the chosen version, and the flag indicating development
@@ -23,6 +23,7 @@ import (
 	envtools "github.com/juju/juju/environs/tools"
 	toolstesting "github.com/juju/juju/environs/tools/testing"
 	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
 )
@@ -50,6 +51,21 @@ var upgradeJujuTests = []struct {
 	currentVersion: "1.0.0-quantal-amd64",
 	args:           []string{"foo"},
 	expectInitErr:  "unrecognized args:.*",
+}, {
+	about:          "invalid --canary machine id",
+	currentVersion: "1.0.0-quantal-amd64",
+	args:           []string{"--canary", "0,foo"},
+	expectInitErr:  `invalid machine id "foo"`,
+}, {
+	about:          "--continue with --rollback",
+	currentVersion: "1.0.0-quantal-amd64",
+	args:           []string{"--continue", "--rollback"},
+	expectInitErr:  "cannot specify both --continue and --rollback",
+}, {
+	about:          "--rollback with --version",
+	currentVersion: "1.0.0-quantal-amd64",
+	args:           []string{"--rollback", "--version", "1.0.1"},
+	expectInitErr:  "--continue and --rollback cannot be combined with other flags",
 }, {
 	about:          "removed arg --dev specified",
 	currentVersion: "1.0.0-quantal-amd64",
@@ -405,6 +421,56 @@ func (s *UpgradeJujuSuite) TestUpgradeJujuWithRealUpload(c *gc.C) {
 	c.Assert(len(tools), gc.Equals, 1)
 }
 
+func (s *UpgradeJujuSuite) TestUpgradeJujuCanaries(c *gc.C) {
+	s.Reset(c)
+	s.PatchValue(&version.Current, version.MustParseBinary("2.0.0-quantal-amd64"))
+	toolsDir := c.MkDir()
+	updateAttrs := map[string]interface{}{
+		"agent-version":      "2.0.0",
+		"tools-metadata-url": "file://" + toolsDir,
+	}
+	err := s.State.UpdateEnvironConfig(updateAttrs, nil, nil)
+	c.Assert(err, gc.IsNil)
+	newTools := version.MustParseBinary("2.2.0-quantal-amd64")
+	envtesting.MustUploadFakeToolsVersions(s.Environ.Storage(), newTools)
+	stor, err := filestorage.NewFileStorageWriter(toolsDir)
+	c.Assert(err, gc.IsNil)
+	envtesting.MustUploadFakeToolsVersions(stor, newTools)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	err = machine.SetAgentVersion(version.Current)
+	c.Assert(err, gc.IsNil)
+
+	assertAgentVersion := func(expect string) {
+		cfg, err := s.State.EnvironConfig()
+		c.Assert(err, gc.IsNil)
+		agentVersion, _ := cfg.AgentVersion()
+		c.Assert(agentVersion, gc.Equals, version.MustParse(expect))
+	}
+
+	// Only the canary machine is upgraded at first.
+	_, err = coretesting.RunCommand(c, envcmd.Wrap(&UpgradeJujuCommand{}), "--canary", machine.Id())
+	c.Assert(err, gc.IsNil)
+	assertAgentVersion("2.0.0")
+	au, err := s.State.AgentUpgrade()
+	c.Assert(err, gc.IsNil)
+	c.Assert(au.TargetVersion(), gc.Equals, newTools.Number)
+	c.Assert(au.Canaries(), gc.DeepEquals, []string{machine.Id()})
+
+	_, err = coretesting.RunCommand(c, envcmd.Wrap(&UpgradeJujuCommand{}), "--continue")
+	c.Assert(err, gc.ErrorMatches, `cannot continue agent upgrade to 2.2.0: machine \d+ not upgraded`)
+
+	err = machine.SetAgentVersion(newTools)
+	c.Assert(err, gc.IsNil)
+	_, err = coretesting.RunCommand(c, envcmd.Wrap(&UpgradeJujuCommand{}), "--continue")
+	c.Assert(err, gc.IsNil)
+	assertAgentVersion("2.2.0")
+
+	_, err = coretesting.RunCommand(c, envcmd.Wrap(&UpgradeJujuCommand{}), "--rollback")
+	c.Assert(err, gc.IsNil)
+	assertAgentVersion("2.0.0")
+}
+
 type DryRunTest struct {
 	about             string
 	cmdArgs           []string
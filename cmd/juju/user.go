@@ -4,6 +4,9 @@
 package main
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/juju/cmd"
 
 	"github.com/juju/juju/cmd/envcmd"
@@ -48,5 +51,24 @@ func NewUserCommand() cmd.Command {
 	// (with tests in user_FOO_test.go) and wire in here.
 	usercmd.Register(envcmd.Wrap(&UserAddCommand{}))
 	usercmd.Register(envcmd.Wrap(&UserChangePasswordCommand{}))
+	usercmd.Register(envcmd.Wrap(&UserSetAccessCommand{}))
+	usercmd.Register(envcmd.Wrap(&UserListCommand{}))
+	usercmd.Register(envcmd.Wrap(&UserDisableCommand{}))
+	usercmd.Register(envcmd.Wrap(&UserEnableCommand{}))
+	usercmd.Register(envcmd.Wrap(&UserUnlockCommand{}))
 	return usercmd
 }
+
+// userAccessLevels holds the levels of access a user may have to the
+// environment, from the least to the most.
+var userAccessLevels = []string{"read", "write", "admin"}
+
+// checkUserAccess returns an error if access is not a known level.
+func checkUserAccess(access string) error {
+	for _, level := range userAccessLevels {
+		if access == level {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid access %q, expected one of %s", access, strings.Join(userAccessLevels, ", "))
+}
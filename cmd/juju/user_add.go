@@ -24,10 +24,16 @@ will be lost when the environent is destroyed.  An environment file
 (.jenv) identifying the new user and the environment can be generated
 using --output.
 
+The --access option sets what the new user may do in the environment:
+  read   see the environment, its status, configuration and logs
+  write  also deploy, change and remove services, units and machines
+  admin  also manage users, ssh keys and backups (the default)
+
 Examples:
   juju user add foobar                    (Add user "foobar". A strong password will be generated and printed)
   juju user add foobar --password=mypass  (Add user "foobar" with password "mypass")
   juju user add foobar --output filename  (Add user "foobar" and save environment file to "filename")
+  juju user add foobar --access read      (Add user "foobar", who can only see the environment)
 `
 
 type UserAddCommand struct {
@@ -36,6 +42,7 @@ type UserAddCommand struct {
 	DisplayName string
 	Password    string
 	OutPath     string
+	Access      string
 }
 
 func (c *UserAddCommand) Info() *cmd.Info {
@@ -51,6 +58,7 @@ func (c *UserAddCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.Password, "password", "", "Password for new user")
 	f.StringVar(&c.OutPath, "o", "", "Output an environment file for new user")
 	f.StringVar(&c.OutPath, "output", "", "")
+	f.StringVar(&c.Access, "access", "admin", "Access for new user: read, write or admin")
 }
 
 func (c *UserAddCommand) Init(args []string) error {
@@ -61,11 +69,14 @@ func (c *UserAddCommand) Init(args []string) error {
 	if len(args) > 0 {
 		c.DisplayName, args = args[0], args[1:]
 	}
+	if err := checkUserAccess(c.Access); err != nil {
+		return err
+	}
 	return cmd.CheckEmpty(args)
 }
 
 type addUserAPI interface {
-	AddUser(username, displayname, password string) error
+	AddUser(username, displayname, password, access string) error
 	Close() error
 }
 
@@ -86,7 +97,7 @@ func (c *UserAddCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
-	err = client.AddUser(c.User, c.DisplayName, c.Password)
+	err = client.AddUser(c.User, c.DisplayName, c.Password, c.Access)
 	if err != nil {
 		return err
 	}
@@ -95,7 +106,7 @@ func (c *UserAddCommand) Run(ctx *cmd.Context) error {
 		user = fmt.Sprintf("%s (%s)", c.DisplayName, user)
 	}
 
-	fmt.Fprintf(ctx.Stdout, "user %q added with password %q\n", user, c.Password)
+	fmt.Fprintf(ctx.Stdout, "user %q added with %s access and password %q\n", user, c.Access, c.Password)
 
 	if c.OutPath != "" {
 		outPath := NormaliseJenvPath(ctx, c.OutPath)
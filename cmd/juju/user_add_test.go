@@ -47,7 +47,7 @@ func (s *UserAddCommandSuite) TestAddUserJustUsername(c *gc.C) {
 	c.Assert(s.mockAPI.displayname, gc.Equals, "")
 	// Password is generated
 	c.Assert(s.mockAPI.password, gc.Not(gc.Equals), "")
-	expected := fmt.Sprintf(`user "foobar" added with password %q`, s.mockAPI.password)
+	expected := fmt.Sprintf(`user "foobar" added with admin access and password %q`, s.mockAPI.password)
 	c.Assert(testing.Stdout(context), gc.Equals, expected+"\n")
 }
 
@@ -58,7 +58,7 @@ func (s *UserAddCommandSuite) TestAddUserUsernameAndDisplayname(c *gc.C) {
 	c.Assert(s.mockAPI.displayname, gc.Equals, "Foo Bar")
 	// Password is generated
 	c.Assert(s.mockAPI.password, gc.Not(gc.Equals), "")
-	expected := fmt.Sprintf(`user "Foo Bar (foobar)" added with password %q`, s.mockAPI.password)
+	expected := fmt.Sprintf(`user "Foo Bar (foobar)" added with admin access and password %q`, s.mockAPI.password)
 	c.Assert(testing.Stdout(context), gc.Equals, expected+"\n")
 }
 
@@ -68,7 +68,16 @@ func (s *UserAddCommandSuite) TestAddUserUsernameAndDisplaynameWithPassword(c *g
 	c.Assert(s.mockAPI.username, gc.Equals, "foobar")
 	c.Assert(s.mockAPI.displayname, gc.Equals, "Foo Bar")
 	c.Assert(s.mockAPI.password, gc.Equals, "password")
-	expected := `user "Foo Bar (foobar)" added with password "password"`
+	expected := `user "Foo Bar (foobar)" added with admin access and password "password"`
+	c.Assert(testing.Stdout(context), gc.Equals, expected+"\n")
+}
+
+func (s *UserAddCommandSuite) TestAddUserWithAccess(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserAddCommand(), "foobar", "--access", "read")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.username, gc.Equals, "foobar")
+	c.Assert(s.mockAPI.access, gc.Equals, "read")
+	expected := fmt.Sprintf(`user "foobar" added with read access and password %q`, s.mockAPI.password)
 	c.Assert(testing.Stdout(context), gc.Equals, expected+"\n")
 }
 
@@ -88,17 +97,20 @@ func (s *UserAddCommandSuite) TestInit(c *gc.C) {
 		displayname string
 		password    string
 		outPath     string
+		access      string
 		errorString string
 	}{
 		{
 			errorString: "no username supplied",
 		}, {
-			args: []string{"foobar"},
-			user: "foobar",
+			args:   []string{"foobar"},
+			user:   "foobar",
+			access: "admin",
 		}, {
 			args:        []string{"foobar", "Foo Bar"},
 			user:        "foobar",
 			displayname: "Foo Bar",
+			access:      "admin",
 		}, {
 			args:        []string{"foobar", "Foo Bar", "extra"},
 			errorString: `unrecognized args: \["extra"\]`,
@@ -106,14 +118,24 @@ func (s *UserAddCommandSuite) TestInit(c *gc.C) {
 			args:     []string{"foobar", "--password", "password"},
 			user:     "foobar",
 			password: "password",
+			access:   "admin",
 		}, {
 			args:    []string{"foobar", "--output", "somefile"},
 			user:    "foobar",
 			outPath: "somefile",
+			access:  "admin",
 		}, {
 			args:    []string{"foobar", "-o", "somefile"},
 			user:    "foobar",
 			outPath: "somefile",
+			access:  "admin",
+		}, {
+			args:   []string{"foobar", "--access", "write"},
+			user:   "foobar",
+			access: "write",
+		}, {
+			args:        []string{"foobar", "--access", "superuser"},
+			errorString: `invalid access "superuser", expected one of read, write, admin`,
 		},
 	} {
 		c.Logf("test %d", i)
@@ -124,6 +146,7 @@ func (s *UserAddCommandSuite) TestInit(c *gc.C) {
 			c.Check(addUserCmd.DisplayName, gc.Equals, test.displayname)
 			c.Check(addUserCmd.Password, gc.Equals, test.password)
 			c.Check(addUserCmd.OutPath, gc.Equals, test.outPath)
+			c.Check(addUserCmd.Access, gc.Equals, test.access)
 		} else {
 			c.Check(err, gc.ErrorMatches, test.errorString)
 		}
@@ -154,7 +177,7 @@ func (s *UserAddCommandSuite) TestJenvOutput(c *gc.C) {
 		"foobar", "--password", "password", "--output", outputName)
 	c.Assert(err, gc.IsNil)
 
-	expected := fmt.Sprintf(`user "foobar" added with password %q`, s.mockAPI.password)
+	expected := fmt.Sprintf(`user "foobar" added with admin access and password %q`, s.mockAPI.password)
 	expected = fmt.Sprintf("%s\nenvironment file written to %s.jenv\n", expected, outputName)
 	c.Assert(testing.Stdout(ctx), gc.Equals, expected)
 
@@ -176,12 +199,14 @@ type mockAddUserAPI struct {
 	username    string
 	displayname string
 	password    string
+	access      string
 }
 
-func (m *mockAddUserAPI) AddUser(username, displayname, password string) error {
+func (m *mockAddUserAPI) AddUser(username, displayname, password, access string) error {
 	m.username = username
 	m.displayname = displayname
 	m.password = password
+	m.access = access
 	if m.failMessage == "" {
 		return nil
 	}
@@ -0,0 +1,64 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+)
+
+const userDisableCommandDoc = `
+Disable a user, who can then no longer log in.  The user's existing
+connections to the environment are dropped.  The user is not removed, and
+can be enabled again with "juju user enable".  The admin user cannot be
+disabled.
+
+Examples:
+  juju user disable foobar
+`
+
+type UserDisableCommand struct {
+	UserCommandBase
+	User string
+}
+
+func (c *UserDisableCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "disable",
+		Args:    "<username>",
+		Purpose: "disables a user",
+		Doc:     userDisableCommandDoc,
+	}
+}
+
+func (c *UserDisableCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no username supplied")
+	}
+	c.User = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+type disableUserAPI interface {
+	DisableUser(username string) error
+	Close() error
+}
+
+var getDisableUserAPI = func(c *UserDisableCommand) (disableUserAPI, error) {
+	return c.NewUserManagerClient()
+}
+
+func (c *UserDisableCommand) Run(ctx *cmd.Context) error {
+	client, err := getDisableUserAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.DisableUser(c.User); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Stdout, "user %q disabled\n", c.User)
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type UserDisableCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockDisableUserAPI
+}
+
+var _ = gc.Suite(&UserDisableCommandSuite{})
+
+func (s *UserDisableCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockDisableUserAPI{}
+	s.PatchValue(&getDisableUserAPI, func(c *UserDisableCommand) (disableUserAPI, error) {
+		return s.mockAPI, nil
+	})
+	s.PatchValue(&getEnableUserAPI, func(c *UserEnableCommand) (enableUserAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func (s *UserDisableCommandSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&UserDisableCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no username supplied")
+	err = testing.InitCommand(&UserDisableCommand{}, []string{"foobar", "extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+	err = testing.InitCommand(&UserEnableCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no username supplied")
+	err = testing.InitCommand(&UserEnableCommand{}, []string{"foobar", "extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *UserDisableCommandSuite) TestDisable(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&UserDisableCommand{}), "foobar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.disabled, gc.DeepEquals, []string{"foobar"})
+	c.Assert(testing.Stdout(context), gc.Equals, "user \"foobar\" disabled\n")
+}
+
+func (s *UserDisableCommandSuite) TestDisableError(c *gc.C) {
+	s.mockAPI.err = errors.New("cannot disable admin user")
+	context, err := testing.RunCommand(c, envcmd.Wrap(&UserDisableCommand{}), "admin")
+	c.Assert(err, gc.ErrorMatches, "cannot disable admin user")
+	c.Assert(testing.Stdout(context), gc.Equals, "")
+}
+
+func (s *UserDisableCommandSuite) TestEnable(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&UserEnableCommand{}), "foobar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.enabled, gc.DeepEquals, []string{"foobar"})
+	c.Assert(testing.Stdout(context), gc.Equals, "user \"foobar\" enabled\n")
+}
+
+type mockDisableUserAPI struct {
+	disabled []string
+	enabled  []string
+	err      error
+}
+
+func (m *mockDisableUserAPI) DisableUser(username string) error {
+	m.disabled = append(m.disabled, username)
+	return m.err
+}
+
+func (m *mockDisableUserAPI) EnableUser(username string) error {
+	m.enabled = append(m.enabled, username)
+	return m.err
+}
+
+func (*mockDisableUserAPI) Close() error {
+	return nil
+}
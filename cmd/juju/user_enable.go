@@ -0,0 +1,61 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+)
+
+const userEnableCommandDoc = `
+Enable a user that was disabled, who can then log in again.
+
+Examples:
+  juju user enable foobar
+`
+
+type UserEnableCommand struct {
+	UserCommandBase
+	User string
+}
+
+func (c *UserEnableCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "enable",
+		Args:    "<username>",
+		Purpose: "enables a disabled user",
+		Doc:     userEnableCommandDoc,
+	}
+}
+
+func (c *UserEnableCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no username supplied")
+	}
+	c.User = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+type enableUserAPI interface {
+	EnableUser(username string) error
+	Close() error
+}
+
+var getEnableUserAPI = func(c *UserEnableCommand) (enableUserAPI, error) {
+	return c.NewUserManagerClient()
+}
+
+func (c *UserEnableCommand) Run(ctx *cmd.Context) error {
+	client, err := getEnableUserAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.EnableUser(c.User); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Stdout, "user %q enabled\n", c.User)
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/state/apiserver/usermanager"
+)
+
+const userListCommandDoc = `
+List the users of the environment, with their access, who created them
+and when, and when they last connected.  Disabled users are listed too.
+
+Examples:
+  juju user list                (List the users as a table)
+  juju user list --format yaml  (List the users in YAML format)
+`
+
+type UserListCommand struct {
+	UserCommandBase
+	out cmd.Output
+}
+
+func (c *UserListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "lists the users",
+		Doc:     userListCommandDoc,
+	}
+}
+
+func (c *UserListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatUserListTabular,
+	})
+}
+
+func (c *UserListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+type userListAPI interface {
+	ListUsers() ([]usermanager.UserInfo, error)
+	Close() error
+}
+
+var getUserListAPI = func(c *UserListCommand) (userListAPI, error) {
+	return c.NewUserManagerClient()
+}
+
+// userListInfo is the representation of a user written by the list
+// command.
+type userListInfo struct {
+	Username       string `yaml:"user-name" json:"user-name"`
+	DisplayName    string `yaml:"display-name,omitempty" json:"display-name,omitempty"`
+	Access         string `yaml:"access" json:"access"`
+	CreatedBy      string `yaml:"created-by,omitempty" json:"created-by,omitempty"`
+	DateCreated    string `yaml:"date-created" json:"date-created"`
+	LastConnection string `yaml:"last-connection" json:"last-connection"`
+	Disabled       bool   `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+}
+
+func (c *UserListCommand) Run(ctx *cmd.Context) error {
+	client, err := getUserListAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	users, err := client.ListUsers()
+	if err != nil {
+		return err
+	}
+	infos := make([]userListInfo, len(users))
+	for i, user := range users {
+		lastConnection := "never"
+		if user.LastConnection != nil {
+			lastConnection = user.LastConnection.UTC().Format(time.RFC3339)
+		}
+		infos[i] = userListInfo{
+			Username:       user.Username,
+			DisplayName:    user.DisplayName,
+			Access:         user.Access,
+			CreatedBy:      user.CreatedBy,
+			DateCreated:    user.DateCreated.UTC().Format(time.RFC3339),
+			LastConnection: lastConnection,
+			Disabled:       user.Disabled,
+		}
+	}
+	return c.out.Write(ctx, infos)
+}
+
+// formatUserListTabular writes the users as a table with a header line.
+func formatUserListTabular(value interface{}) ([]byte, error) {
+	infos, ok := value.([]userListInfo)
+	if !ok {
+		return nil, fmt.Errorf("expected value of type %T, got %T", infos, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tDISPLAY NAME\tACCESS\tCREATED BY\tDATE CREATED\tLAST CONNECTION\tDISABLED")
+	for _, info := range infos {
+		disabled := ""
+		if info.Disabled {
+			disabled = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			info.Username, info.DisplayName, info.Access, info.CreatedBy,
+			info.DateCreated, info.LastConnection, disabled)
+	}
+	tw.Flush()
+	return trimLines(out.Bytes()), nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/cmd"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/apiserver/usermanager"
+	"github.com/juju/juju/testing"
+)
+
+type UserListCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockUserListAPI
+}
+
+var _ = gc.Suite(&UserListCommandSuite{})
+
+func (s *UserListCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	created := time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC)
+	connected := created.Add(time.Hour)
+	s.mockAPI = &mockUserListAPI{
+		users: []usermanager.UserInfo{{
+			Username:       "admin",
+			DateCreated:    created,
+			LastConnection: &connected,
+			Access:         "admin",
+		}, {
+			Username:    "bob",
+			DisplayName: "Bob Brown",
+			CreatedBy:   "admin",
+			DateCreated: created.Add(time.Minute),
+			Access:      "read",
+			Disabled:    true,
+		}},
+	}
+	s.PatchValue(&getUserListAPI, func(c *UserListCommand) (userListAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func newUserListCommand() cmd.Command {
+	return envcmd.Wrap(&UserListCommand{})
+}
+
+func (s *UserListCommandSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&UserListCommand{}, []string{"extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *UserListCommandSuite) TestTabular(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserListCommand())
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, `
+NAME   DISPLAY NAME  ACCESS  CREATED BY  DATE CREATED          LAST CONNECTION       DISABLED
+admin                admin               2014-09-01T12:00:00Z  2014-09-01T13:00:00Z
+bob    Bob Brown     read    admin       2014-09-01T12:01:00Z  never                 yes
+`[1:])
+}
+
+func (s *UserListCommandSuite) TestYAML(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserListCommand(), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, `
+- user-name: admin
+  access: admin
+  date-created: "2014-09-01T12:00:00Z"
+  last-connection: "2014-09-01T13:00:00Z"
+- user-name: bob
+  display-name: Bob Brown
+  access: read
+  created-by: admin
+  date-created: "2014-09-01T12:01:00Z"
+  last-connection: never
+  disabled: true
+`[1:])
+}
+
+func (s *UserListCommandSuite) TestError(c *gc.C) {
+	s.mockAPI.err = errors.New("permission denied")
+	_, err := testing.RunCommand(c, newUserListCommand())
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+type mockUserListAPI struct {
+	users []usermanager.UserInfo
+	err   error
+}
+
+func (m *mockUserListAPI) ListUsers() ([]usermanager.UserInfo, error) {
+	return m.users, m.err
+}
+
+func (*mockUserListAPI) Close() error {
+	return nil
+}
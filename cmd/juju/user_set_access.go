@@ -0,0 +1,76 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+)
+
+const userSetAccessCommandDoc = `
+Set what a user may do in the environment.
+
+The access levels are:
+  read   see the environment, its status, configuration and logs
+  write  also deploy, change and remove services, units and machines
+  admin  also manage users, ssh keys and backups
+
+The access of the admin user cannot be changed.
+
+Examples:
+  juju user set-access foobar read   (User "foobar" can only see the environment)
+  juju user set-access foobar write  (User "foobar" can change the environment)
+`
+
+type UserSetAccessCommand struct {
+	UserCommandBase
+	User   string
+	Access string
+}
+
+func (c *UserSetAccessCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-access",
+		Args:    "<username> <access>",
+		Purpose: "sets the access of a user",
+		Doc:     userSetAccessCommandDoc,
+	}
+}
+
+func (c *UserSetAccessCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return fmt.Errorf("no username supplied")
+	case 1:
+		return fmt.Errorf("no access supplied")
+	}
+	c.User, c.Access = args[0], args[1]
+	if err := checkUserAccess(c.Access); err != nil {
+		return err
+	}
+	return cmd.CheckEmpty(args[2:])
+}
+
+type setAccessAPI interface {
+	SetAccess(username, access string) error
+	Close() error
+}
+
+var getSetAccessAPI = func(c *UserSetAccessCommand) (setAccessAPI, error) {
+	return c.NewUserManagerClient()
+}
+
+func (c *UserSetAccessCommand) Run(ctx *cmd.Context) error {
+	client, err := getSetAccessAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.SetAccess(c.User, c.Access); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Stdout, "user %q now has %s access\n", c.User, c.Access)
+	return nil
+}
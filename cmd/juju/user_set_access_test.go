@@ -0,0 +1,102 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+
+	"github.com/juju/cmd"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type UserSetAccessCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockSetAccessAPI
+}
+
+var _ = gc.Suite(&UserSetAccessCommandSuite{})
+
+func (s *UserSetAccessCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockSetAccessAPI{}
+	s.PatchValue(&getSetAccessAPI, func(c *UserSetAccessCommand) (setAccessAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func newUserSetAccessCommand() cmd.Command {
+	return envcmd.Wrap(&UserSetAccessCommand{})
+}
+
+func (s *UserSetAccessCommandSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		user        string
+		access      string
+		errorString string
+	}{{
+		errorString: "no username supplied",
+	}, {
+		args:        []string{"foobar"},
+		errorString: "no access supplied",
+	}, {
+		args:        []string{"foobar", "superuser"},
+		errorString: `invalid access "superuser", expected one of read, write, admin`,
+	}, {
+		args:        []string{"foobar", "read", "extra"},
+		errorString: `unrecognized args: \["extra"\]`,
+	}, {
+		args:   []string{"foobar", "read"},
+		user:   "foobar",
+		access: "read",
+	}} {
+		c.Logf("test %d", i)
+		setAccessCmd := &UserSetAccessCommand{}
+		err := testing.InitCommand(setAccessCmd, test.args)
+		if test.errorString == "" {
+			c.Check(err, gc.IsNil)
+			c.Check(setAccessCmd.User, gc.Equals, test.user)
+			c.Check(setAccessCmd.Access, gc.Equals, test.access)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *UserSetAccessCommandSuite) TestSetAccess(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserSetAccessCommand(), "foobar", "write")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.username, gc.Equals, "foobar")
+	c.Assert(s.mockAPI.access, gc.Equals, "write")
+	c.Assert(testing.Stdout(context), gc.Equals, "user \"foobar\" now has write access\n")
+}
+
+func (s *UserSetAccessCommandSuite) TestSetAccessErrorResponse(c *gc.C) {
+	s.mockAPI.failMessage = "permission denied"
+	context, err := testing.RunCommand(c, newUserSetAccessCommand(), "foobar", "write")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(testing.Stdout(context), gc.Equals, "")
+}
+
+type mockSetAccessAPI struct {
+	failMessage string
+	username    string
+	access      string
+}
+
+func (m *mockSetAccessAPI) SetAccess(username, access string) error {
+	m.username = username
+	m.access = access
+	if m.failMessage == "" {
+		return nil
+	}
+	return errors.New(m.failMessage)
+}
+
+func (*mockSetAccessAPI) Close() error {
+	return nil
+}
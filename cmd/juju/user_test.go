@@ -20,7 +20,12 @@ var _ = gc.Suite(&UserCommandSuite{})
 var expectedUserCommmandNames = []string{
 	"add",
 	"change-password",
+	"disable",
+	"enable",
 	"help",
+	"list",
+	"set-access",
+	"unlock",
 }
 
 func (s *UserCommandSuite) TestHelp(c *gc.C) {
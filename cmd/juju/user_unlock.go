@@ -0,0 +1,87 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+)
+
+const userUnlockCommandDoc = `
+After too many failed logins, logins as a user from the address they
+failed from, or from the address as any user, are refused for a while
+(see the login-lockout-threshold and login-lockout-duration environment
+settings).  Unlock a user from all addresses, or an address with
+--address, so that logins are accepted again at once.  Lockouts are
+recorded in the audit log.
+
+Examples:
+  juju user unlock foobar                (Accept logins as foobar)
+  juju user unlock --address 10.0.0.1    (Accept logins from 10.0.0.1)
+`
+
+type UserUnlockCommand struct {
+	UserCommandBase
+	User    string
+	Address string
+}
+
+func (c *UserUnlockCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "unlock",
+		Args:    "[<username>]",
+		Purpose: "accepts logins again after too many failures",
+		Doc:     userUnlockCommandDoc,
+	}
+}
+
+func (c *UserUnlockCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Address, "address", "", "unlock logins from this address")
+}
+
+func (c *UserUnlockCommand) Init(args []string) error {
+	if len(args) > 0 {
+		c.User = args[0]
+		args = args[1:]
+	}
+	switch {
+	case c.User == "" && c.Address == "":
+		return fmt.Errorf("no username or address supplied")
+	case c.User != "" && c.Address != "":
+		return fmt.Errorf("cannot unlock a username and an address at once")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+type unlockUserAPI interface {
+	ClearUserLockout(username string) error
+	ClearAddressLockout(address string) error
+	Close() error
+}
+
+var getUnlockUserAPI = func(c *UserUnlockCommand) (unlockUserAPI, error) {
+	return c.NewUserManagerClient()
+}
+
+func (c *UserUnlockCommand) Run(ctx *cmd.Context) error {
+	client, err := getUnlockUserAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if c.Address != "" {
+		if err := client.ClearAddressLockout(c.Address); err != nil {
+			return err
+		}
+		fmt.Fprintf(ctx.Stdout, "address %q unlocked\n", c.Address)
+		return nil
+	}
+	if err := client.ClearUserLockout(c.User); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Stdout, "user %q unlocked\n", c.User)
+	return nil
+}
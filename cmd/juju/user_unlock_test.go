@@ -0,0 +1,104 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type UserUnlockCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockUnlockUserAPI
+}
+
+var _ = gc.Suite(&UserUnlockCommandSuite{})
+
+func (s *UserUnlockCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockUnlockUserAPI{}
+	s.PatchValue(&getUnlockUserAPI, func(c *UserUnlockCommand) (unlockUserAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func (s *UserUnlockCommandSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		user        string
+		address     string
+		errorString string
+	}{{
+		errorString: "no username or address supplied",
+	}, {
+		args: []string{"foobar"},
+		user: "foobar",
+	}, {
+		args:    []string{"--address", "10.0.0.1"},
+		address: "10.0.0.1",
+	}, {
+		args:        []string{"foobar", "--address", "10.0.0.1"},
+		errorString: "cannot unlock a username and an address at once",
+	}, {
+		args:        []string{"foobar", "extra"},
+		errorString: `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d", i)
+		unlockCmd := &UserUnlockCommand{}
+		err := testing.InitCommand(unlockCmd, test.args)
+		if test.errorString == "" {
+			c.Check(err, gc.IsNil)
+			c.Check(unlockCmd.User, gc.Equals, test.user)
+			c.Check(unlockCmd.Address, gc.Equals, test.address)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *UserUnlockCommandSuite) TestUnlockUser(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&UserUnlockCommand{}), "foobar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.user, gc.Equals, "foobar")
+	c.Assert(s.mockAPI.address, gc.Equals, "")
+	c.Assert(testing.Stdout(context), gc.Equals, "user \"foobar\" unlocked\n")
+}
+
+func (s *UserUnlockCommandSuite) TestUnlockAddress(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&UserUnlockCommand{}), "--address", "10.0.0.1")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.user, gc.Equals, "")
+	c.Assert(s.mockAPI.address, gc.Equals, "10.0.0.1")
+	c.Assert(testing.Stdout(context), gc.Equals, "address \"10.0.0.1\" unlocked\n")
+}
+
+func (s *UserUnlockCommandSuite) TestUnlockError(c *gc.C) {
+	s.mockAPI.err = errors.New("permission denied")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&UserUnlockCommand{}), "foobar")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+type mockUnlockUserAPI struct {
+	user    string
+	address string
+	err     error
+}
+
+func (m *mockUnlockUserAPI) ClearUserLockout(username string) error {
+	m.user = username
+	return m.err
+}
+
+func (m *mockUnlockUserAPI) ClearAddressLockout(address string) error {
+	m.address = address
+	return m.err
+}
+
+func (*mockUnlockUserAPI) Close() error {
+	return nil
+}
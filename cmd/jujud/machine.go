@@ -46,6 +46,7 @@ import (
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/backupscheduler"
 	"github.com/juju/juju/worker/charmrevisionworker"
 	"github.com/juju/juju/worker/cleaner"
 	"github.com/juju/juju/worker/deployer"
@@ -53,15 +54,19 @@ import (
 	"github.com/juju/juju/worker/instancepoller"
 	"github.com/juju/juju/worker/localstorage"
 	workerlogger "github.com/juju/juju/worker/logger"
+	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/machineenvironmentworker"
 	"github.com/juju/juju/worker/machiner"
+	"github.com/juju/juju/worker/metricsender"
 	"github.com/juju/juju/worker/minunitsworker"
 	"github.com/juju/juju/worker/networker"
 	"github.com/juju/juju/worker/peergrouper"
 	"github.com/juju/juju/worker/provisioner"
 	"github.com/juju/juju/worker/resumer"
+	"github.com/juju/juju/worker/rollingupgrader"
 	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/singular"
+	"github.com/juju/juju/worker/statushistorypruner"
 	"github.com/juju/juju/worker/terminationworker"
 	"github.com/juju/juju/worker/upgrader"
 )
@@ -315,6 +320,9 @@ func (a *MachineAgent) APIWorker() (worker.Worker, error) {
 	a.startWorkerAfterUpgrade(runner, "logger", func() (worker.Worker, error) {
 		return workerlogger.NewLogger(st.Logger(), agentConfig), nil
 	})
+	a.startWorkerAfterUpgrade(runner, "logsender", func() (worker.Worker, error) {
+		return logsender.New(bufferedLogs, st.Logger()), nil
+	})
 	a.startWorkerAfterUpgrade(runner, "machineenvironmentworker", func() (worker.Worker, error) {
 		return machineenvironmentworker.NewMachineEnvironmentWorker(st.Environment(), agentConfig), nil
 	})
@@ -517,7 +525,6 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 					return nil, &fatalError{"configuration does not have state server cert/key"}
 				}
 				dataDir := agentConfig.DataDir()
-				logDir := agentConfig.LogDir()
 
 				endpoint := net.JoinHostPort("", strconv.Itoa(info.APIPort))
 				listener, err := net.Listen("tcp", endpoint)
@@ -528,7 +535,6 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 					Cert:      cert,
 					Key:       key,
 					DataDir:   dataDir,
-					LogDir:    logDir,
 					Validator: a.limitLoginsDuringUpgrade,
 				})
 			})
@@ -544,6 +550,18 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 			a.startWorkerAfterUpgrade(singularRunner, "minunitsworker", func() (worker.Worker, error) {
 				return minunitsworker.NewMinUnitsWorker(st), nil
 			})
+			a.startWorkerAfterUpgrade(singularRunner, "backupscheduler", func() (worker.Worker, error) {
+				return backupscheduler.NewBackupScheduler(st, m.Id()), nil
+			})
+			a.startWorkerAfterUpgrade(singularRunner, "metricsender", func() (worker.Worker, error) {
+				return metricsender.NewMetricSender(st), nil
+			})
+			a.startWorkerAfterUpgrade(singularRunner, "statushistorypruner", func() (worker.Worker, error) {
+				return statushistorypruner.New(st, statushistorypruner.NewHistoryPrunerParams()), nil
+			})
+			a.startWorkerAfterUpgrade(singularRunner, "rollingupgrader", func() (worker.Worker, error) {
+				return rollingupgrader.New(st, rollingupgrader.DefaultInterval), nil
+			})
 		case state.JobManageStateDeprecated:
 			// Legacy environments may set this, but we ignore it.
 		default:
@@ -400,12 +400,16 @@ func (s *MachineSuite) TestManageEnviron(c *gc.C) {
 	}
 
 	c.Assert(s.singularRecord.started(), jc.DeepEquals, []string{
+		"backupscheduler",
 		"charm-revision-updater",
 		"cleaner",
 		"environ-provisioner",
 		"firewaller",
+		"metricsender",
 		"minunitsworker",
 		"resumer",
+		"rollingupgrader",
+		"statushistorypruner",
 	})
 }
 
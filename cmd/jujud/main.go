@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/juju/sockets"
 	// Import the providers.
 	_ "github.com/juju/juju/provider/all"
+	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/uniter/jujuc"
 )
 
@@ -93,9 +94,16 @@ func jujuCMain(commandName string, args []string) (code int, err error) {
 	return resp.Code, nil
 }
 
+// bufferedLogs holds the log messages written by an agent until the
+// logsender worker sends them to the state servers.
+var bufferedLogs = logsender.NewBufferedLogWriter(logsender.DefaultBufferSize)
+
 // Main registers subcommands for the jujud executable, and hands over control
 // to the cmd package.
 func jujuDMain(args []string, ctx *cmd.Context) (code int, err error) {
+	if err := loggo.RegisterWriter("logsender", bufferedLogs, loggo.TRACE); err != nil {
+		return 1, err
+	}
 	jujud := jujucmd.NewSuperCommand(cmd.SuperCommandParams{
 		Name: "jujud",
 		Doc:  jujudDoc,
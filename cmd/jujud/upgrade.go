@@ -182,6 +182,22 @@ func (c *upgradeWorkerContext) runUpgrades() error {
 		}
 	}
 
+	// Once upgrade steps have run, the machine cannot return to the
+	// previous version if a staged upgrade is rolled back, so record
+	// that they are about to run. This fails if the upgrade has
+	// already been rolled back, in which case the upgrader will
+	// return the agent to the previous version.
+	err = setUpgradeStepsStarted(c.apiState, tag)
+	if err != nil && !params.IsCodeNotImplemented(err) {
+		if connectionIsDead(c.apiState) {
+			return &apiLostDuringUpgrade{err}
+		}
+		logger.Errorf("cannot start upgrade to %v: %v", version.Current, err)
+		a.setMachineStatus(c.apiState, params.StatusError,
+			fmt.Sprintf("upgrade to %v not started: %v", version.Current, err))
+		return err
+	}
+
 	err = a.ChangeConfig(func(agentConfig agent.ConfigSetter) error {
 		var upgradeErr error
 		a.setMachineStatus(c.apiState, params.StatusStarted,
@@ -233,6 +249,10 @@ func (c *upgradeWorkerContext) runUpgrades() error {
 	return nil
 }
 
+var setUpgradeStepsStarted = func(st *api.State, tag names.MachineTag) error {
+	return st.Upgrader().SetUpgradeStepsStarted(tag.String(), version.Current.Number)
+}
+
 var openStateForUpgrade = func(
 	agent upgradingMachineAgent,
 	agentConfig agent.Config,
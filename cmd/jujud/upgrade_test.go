@@ -45,6 +45,7 @@ type UpgradeSuite struct {
 	connectionDead              bool
 	machineIsMaster             bool
 	waitForOtherStateServersErr error
+	setUpgradeStepsStartedErr   error
 }
 
 var _ = gc.Suite(&UpgradeSuite{})
@@ -101,6 +102,12 @@ func (s *UpgradeSuite) SetUpTest(c *gc.C) {
 		return s.waitForOtherStateServersErr
 	}
 	s.PatchValue(&waitForOtherStateServers, fakeWaitForOtherStateServers)
+
+	s.setUpgradeStepsStartedErr = nil
+	fakeSetUpgradeStepsStarted := func(*api.State, names.MachineTag) error {
+		return s.setUpgradeStepsStartedErr
+	}
+	s.PatchValue(&setUpgradeStepsStarted, fakeSetUpgradeStepsStarted)
 }
 
 func (s *UpgradeSuite) captureLogs(c *gc.C) {
@@ -250,6 +257,30 @@ func (s *UpgradeSuite) TestApiConnectionFailure(c *gc.C) {
 	assertUpgradeNotComplete(c, context)
 }
 
+func (s *UpgradeSuite) TestUpgradeNotStartedWhenRolledBack(c *gc.C) {
+	// This test checks that no upgrade steps are run once a staged
+	// upgrade has been rolled back, leaving the upgrader to return
+	// the agent to the previous version.
+	attemptCount := 0
+	fakePerformUpgrade := func(_ version.Number, _ upgrades.Target, _ upgrades.Context) error {
+		attemptCount++
+		return nil
+	}
+	s.PatchValue(&upgradesPerformUpgrade, fakePerformUpgrade)
+	s.setUpgradeStepsStartedErr = errors.New("agent upgrade rolled back")
+
+	workerErr, config, agent, context := s.runUpgradeWorker(params.JobHostUnits)
+
+	c.Check(workerErr, gc.IsNil)
+	c.Check(attemptCount, gc.Equals, 0)
+	c.Check(config.Version, gc.Equals, s.oldVersion.Number) // Upgrade didn't start
+	c.Assert(agent.MachineStatusCalls, jc.DeepEquals, []MachineStatusCall{{
+		params.StatusError,
+		fmt.Sprintf("upgrade to %s not started: agent upgrade rolled back", version.Current),
+	}})
+	assertUpgradeNotComplete(c, context)
+}
+
 func (s *UpgradeSuite) TestAbortWhenOtherStateServerDoesntStartUpgrade(c *gc.C) {
 	// This test checks when a state server is upgrading and one of
 	// the other state servers doesn't signal it is ready in time.
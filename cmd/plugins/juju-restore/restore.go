@@ -70,6 +70,10 @@ talk to it.
 It verifies that the existing bootstrap instance is
 not running. The given constraints will be used
 to choose the new instance.
+
+When the state server is still running, use
+"juju backups restore" instead, which restores the
+backup through the API without replacing the instance.
 `
 
 type restoreCommand struct {
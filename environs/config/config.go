@@ -6,6 +6,7 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -59,6 +60,18 @@ const (
 	// refresh addresses from the provider each time.
 	DefaultBootstrapSSHAddressesDelay int = 10
 
+	// DefaultMetricsRetention is how long metrics are kept by the
+	// state server after they have been sent to the collector.
+	DefaultMetricsRetention = 24 * time.Hour
+
+	// DefaultLoginLockoutThreshold is the number of failed password
+	// logins after which a user or address is locked out.
+	DefaultLoginLockoutThreshold = 5
+
+	// DefaultLoginLockoutDuration is how long a user or address stays
+	// locked out after too many failed password logins.
+	DefaultLoginLockoutDuration = 15 * time.Minute
+
 	// fallbackLtsSeries is the latest LTS series we'll use, if we fail to
 	// obtain this information from the system.
 	fallbackLtsSeries string = "precise"
@@ -335,6 +348,47 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	// Check the scheduled backups settings.
+	for _, attr := range []string{"backups-interval", "backups-max-age"} {
+		if v, ok := cfg.defined[attr].(string); ok {
+			if d, err := time.ParseDuration(v); err != nil || d < 0 {
+				return fmt.Errorf("invalid %s in environment configuration: %q", attr, v)
+			}
+		}
+	}
+	if v, ok := cfg.defined["backups-max-count"].(int); ok && v < 0 {
+		return fmt.Errorf("invalid backups-max-count in environment configuration: %d", v)
+	}
+
+	// Check the metrics collector settings.
+	if v, ok := cfg.defined["metrics-collector-url"].(string); ok {
+		if u, err := url.Parse(v); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid metrics-collector-url in environment configuration: %q", v)
+		}
+	}
+	if v, ok := cfg.defined["metrics-retention"].(string); ok {
+		if d, err := time.ParseDuration(v); err != nil || d <= 0 {
+			return fmt.Errorf("invalid metrics-retention in environment configuration: %q", v)
+		}
+	}
+	if v, ok := cfg.defined["metrics-hooks"].(string); ok && v != "" {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name == "" || strings.ContainsAny(name, " \t") {
+				return fmt.Errorf("invalid metrics-hooks in environment configuration: %q", v)
+			}
+		}
+	}
+
+	// Check the login lockout settings.
+	if v, ok := cfg.defined["login-lockout-threshold"].(int); ok && v <= 0 {
+		return fmt.Errorf("invalid login-lockout-threshold in environment configuration: %d", v)
+	}
+	if v, ok := cfg.defined["login-lockout-duration"].(string); ok {
+		if d, err := time.ParseDuration(v); err != nil || d <= 0 {
+			return fmt.Errorf("invalid login-lockout-duration in environment configuration: %q", v)
+		}
+	}
+
 	// Ensure that the auth token is a set of key=value pairs.
 	authToken, _ := cfg.CharmStoreAuth()
 	validAuthToken := regexp.MustCompile(`^([^\s=]+=[^\s=]+(,\s*)?)*$`)
@@ -621,6 +675,73 @@ func (c *Config) BootstrapSSHOpts() SSHTimeoutOpts {
 	return opts
 }
 
+// BackupsOpts returns the schedule and retention policy of the
+// backups taken by the state server.
+func (c *Config) BackupsOpts() BackupsOpts {
+	var opts BackupsOpts
+	if v, ok := c.defined["backups-interval"].(string); ok {
+		opts.Interval, _ = time.ParseDuration(v)
+	}
+	if v, ok := c.defined["backups-max-count"].(int); ok {
+		opts.MaxCount = v
+	}
+	if v, ok := c.defined["backups-max-age"].(string); ok {
+		opts.MaxAge, _ = time.ParseDuration(v)
+	}
+	return opts
+}
+
+// MetricsCollectorURL returns the URL of the HTTP endpoint the state
+// server sends metrics to, and whether it has been set.
+func (c *Config) MetricsCollectorURL() (string, bool) {
+	v, ok := c.defined["metrics-collector-url"].(string)
+	return v, ok && v != ""
+}
+
+// MetricsRetention returns how long metrics are kept after they have
+// been sent to the collector.
+func (c *Config) MetricsRetention() time.Duration {
+	if v, ok := c.defined["metrics-retention"].(string); ok {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return DefaultMetricsRetention
+}
+
+// MetricsHooks returns the names of the hooks in which charms may
+// record metrics with add-metric. When none are set, metrics may be
+// recorded in any hook.
+func (c *Config) MetricsHooks() []string {
+	v, _ := c.defined["metrics-hooks"].(string)
+	if v == "" {
+		return nil
+	}
+	var names []string
+	for _, name := range strings.Split(v, ",") {
+		names = append(names, strings.TrimSpace(name))
+	}
+	return names
+}
+
+// LoginLockoutOpts returns when users and addresses are locked out
+// after failed password logins, and for how long.
+func (c *Config) LoginLockoutOpts() LoginLockoutOpts {
+	opts := LoginLockoutOpts{
+		Threshold: DefaultLoginLockoutThreshold,
+		Duration:  DefaultLoginLockoutDuration,
+	}
+	if v, ok := c.defined["login-lockout-threshold"].(int); ok {
+		opts.Threshold = v
+	}
+	if v, ok := c.defined["login-lockout-duration"].(string); ok {
+		if d, err := time.ParseDuration(v); err == nil {
+			opts.Duration = d
+		}
+	}
+	return opts
+}
+
 // CACert returns the certificate of the CA that signed the state server
 // certificate, in PEM format, and whether the setting is available.
 func (c *Config) CACert() (string, bool) {
@@ -835,6 +956,14 @@ var fields = schema.Fields{
 	"lxc-clone":                 schema.Bool(),
 	"lxc-clone-aufs":            schema.Bool(),
 	"prefer-ipv6":               schema.Bool(),
+	"backups-interval":          schema.String(),
+	"backups-max-count":         schema.ForceInt(),
+	"backups-max-age":           schema.String(),
+	"metrics-collector-url":     schema.String(),
+	"metrics-retention":         schema.String(),
+	"metrics-hooks":             schema.String(),
+	"login-lockout-threshold":   schema.ForceInt(),
+	"login-lockout-duration":    schema.String(),
 
 	// Deprecated fields, retain for backwards compatibility.
 	"tools-url":     schema.String(),
@@ -870,6 +999,14 @@ var alwaysOptional = schema.Defaults{
 	"apt-https-proxy":           schema.Omit,
 	"apt-ftp-proxy":             schema.Omit,
 	"lxc-clone":                 schema.Omit,
+	"backups-interval":          schema.Omit,
+	"backups-max-count":         schema.Omit,
+	"backups-max-age":           schema.Omit,
+	"metrics-collector-url":     schema.Omit,
+	"metrics-retention":         schema.Omit,
+	"metrics-hooks":             schema.Omit,
+	"login-lockout-threshold":   schema.Omit,
+	"login-lockout-duration":    schema.Omit,
 
 	// Deprecated fields, retain for backwards compatibility.
 	"tools-url":     "",
@@ -1054,6 +1191,36 @@ type SSHTimeoutOpts struct {
 	AddressesDelay time.Duration
 }
 
+// BackupsOpts holds the schedule and retention policy of the backups
+// taken by the state server.
+type BackupsOpts struct {
+	// Interval is the amount of time between scheduled backups.
+	// Backups are not scheduled if it is zero.
+	Interval time.Duration
+
+	// MaxCount is the number of scheduled backups to keep.  Older
+	// scheduled backups are removed.  There is no limit if it is
+	// zero.  Backups taken by hand are never removed.
+	MaxCount int
+
+	// MaxAge is the amount of time scheduled backups are kept for.
+	// There is no limit if it is zero.
+	MaxAge time.Duration
+}
+
+// LoginLockoutOpts holds the policy applied to failed password logins
+// to the API.
+type LoginLockoutOpts struct {
+	// Threshold is the number of failed logins, made as a user or
+	// from an address, after which further logins as that user or
+	// from that address are refused.
+	Threshold int
+
+	// Duration is how long logins are refused for once the threshold
+	// is reached. Failed logins older than this are forgotten.
+	Duration time.Duration
+}
+
 func addIfNotEmpty(settings map[string]interface{}, key, value string) {
 	if value != "" {
 		settings[key] = value
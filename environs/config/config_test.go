@@ -584,6 +584,114 @@ var configTests = []configTest{
 			"bootstrap-timeout": "illegal",
 		},
 		err: `bootstrap-timeout: expected number, got string\("illegal"\)`,
+	}, {
+		about:       "Scheduled backups",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"backups-interval":  "24h",
+			"backups-max-count": 7,
+			"backups-max-age":   "720h",
+		},
+	}, {
+		about:       "Invalid backups interval",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":             "my-type",
+			"name":             "my-name",
+			"backups-interval": "daily",
+		},
+		err: `invalid backups-interval in environment configuration: "daily"`,
+	}, {
+		about:       "Negative backups max age",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":            "my-type",
+			"name":            "my-name",
+			"backups-max-age": "-1h",
+		},
+		err: `invalid backups-max-age in environment configuration: "-1h"`,
+	}, {
+		about:       "Negative backups max count",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"backups-max-count": -1,
+		},
+		err: `invalid backups-max-count in environment configuration: -1`,
+	}, {
+		about:       "Metrics collector",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"metrics-collector-url": "https://metrics.example.com/batches",
+			"metrics-retention":     "168h",
+		},
+	}, {
+		about:       "Invalid metrics collector URL",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"metrics-collector-url": "metrics.example.com",
+		},
+		err: `invalid metrics-collector-url in environment configuration: "metrics.example.com"`,
+	}, {
+		about:       "Invalid metrics retention",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"metrics-retention": "0",
+		},
+		err: `invalid metrics-retention in environment configuration: "0"`,
+	}, {
+		about:       "Metrics hooks",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":          "my-type",
+			"name":          "my-name",
+			"metrics-hooks": "collect-metrics, update-status",
+		},
+	}, {
+		about:       "Invalid metrics hooks",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":          "my-type",
+			"name":          "my-name",
+			"metrics-hooks": "collect-metrics,,",
+		},
+		err: `invalid metrics-hooks in environment configuration: "collect-metrics,,"`,
+	}, {
+		about:       "Login lockout",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                    "my-type",
+			"name":                    "my-name",
+			"login-lockout-threshold": 10,
+			"login-lockout-duration":  "1h",
+		},
+	}, {
+		about:       "Invalid login lockout threshold",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                    "my-type",
+			"name":                    "my-name",
+			"login-lockout-threshold": 0,
+		},
+		err: `invalid login-lockout-threshold in environment configuration: 0`,
+	}, {
+		about:       "Invalid login lockout duration",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"login-lockout-duration": "forever",
+		},
+		err: `invalid login-lockout-duration in environment configuration: "forever"`,
 	}, {
 		about:       "Explicit bootstrap retry delay",
 		useDefaults: config.UseDefaults,
@@ -1021,6 +1129,53 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 	} else {
 		c.Assert(cfg.ProvisionerSafeMode(), gc.Equals, false)
 	}
+	backupsOpts := cfg.BackupsOpts()
+	if v, ok := test.attrs["backups-interval"].(string); ok {
+		c.Assert(backupsOpts.Interval, gc.Equals, mustParseDuration(c, v))
+	} else {
+		c.Assert(backupsOpts.Interval, gc.Equals, time.Duration(0))
+	}
+	if v, ok := test.attrs["backups-max-count"].(int); ok {
+		c.Assert(backupsOpts.MaxCount, gc.Equals, v)
+	} else {
+		c.Assert(backupsOpts.MaxCount, gc.Equals, 0)
+	}
+	if v, ok := test.attrs["backups-max-age"].(string); ok {
+		c.Assert(backupsOpts.MaxAge, gc.Equals, mustParseDuration(c, v))
+	} else {
+		c.Assert(backupsOpts.MaxAge, gc.Equals, time.Duration(0))
+	}
+
+	if v, ok := test.attrs["metrics-collector-url"].(string); ok {
+		collectorURL, ok := cfg.MetricsCollectorURL()
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(collectorURL, gc.Equals, v)
+	} else {
+		_, ok := cfg.MetricsCollectorURL()
+		c.Assert(ok, jc.IsFalse)
+	}
+	if v, ok := test.attrs["metrics-retention"].(string); ok {
+		c.Assert(cfg.MetricsRetention(), gc.Equals, mustParseDuration(c, v))
+	} else {
+		c.Assert(cfg.MetricsRetention(), gc.Equals, config.DefaultMetricsRetention)
+	}
+	if _, ok := test.attrs["metrics-hooks"].(string); ok {
+		c.Assert(cfg.MetricsHooks(), gc.DeepEquals, []string{"collect-metrics", "update-status"})
+	} else {
+		c.Assert(cfg.MetricsHooks(), gc.HasLen, 0)
+	}
+	lockoutOpts := cfg.LoginLockoutOpts()
+	if v, ok := test.attrs["login-lockout-threshold"].(int); ok {
+		c.Assert(lockoutOpts.Threshold, gc.Equals, v)
+	} else {
+		c.Assert(lockoutOpts.Threshold, gc.Equals, config.DefaultLoginLockoutThreshold)
+	}
+	if v, ok := test.attrs["login-lockout-duration"].(string); ok {
+		c.Assert(lockoutOpts.Duration, gc.Equals, mustParseDuration(c, v))
+	} else {
+		c.Assert(lockoutOpts.Duration, gc.Equals, config.DefaultLoginLockoutDuration)
+	}
+
 	sshOpts := cfg.BootstrapSSHOpts()
 	test.assertDuration(
 		c,
@@ -1104,6 +1259,12 @@ func (test configTest) assertDuration(c *gc.C, name string, actual time.Duration
 	}
 }
 
+func mustParseDuration(c *gc.C, s string) time.Duration {
+	d, err := time.ParseDuration(s)
+	c.Assert(err, gc.IsNil)
+	return d
+}
+
 func (s *ConfigSuite) TestConfigAttrs(c *gc.C) {
 	// Normally this is handled by gitjujutesting.FakeHome
 	s.PatchEnvironment(osenv.JujuLoggingConfigEnvKey, "")
@@ -491,6 +491,22 @@ func (s *JujuConnSuite) AddTestingCharm(c *gc.C, name string) *state.Charm {
 	return sch
 }
 
+// AddActionsCharm clones a testing charm, replaces its actions schema
+// with the given YAML, and adds it to the state under the same URL that
+// AddTestingCharm would use.
+func (s *JujuConnSuite) AddActionsCharm(c *gc.C, name, actionsYaml string) *state.Charm {
+	path := charmtesting.Charms.ClonedDirPath(c.MkDir(), name)
+	err := ioutil.WriteFile(filepath.Join(path, "actions.yaml"), []byte(actionsYaml), 0644)
+	c.Assert(err, gc.IsNil)
+	ch, err := charm.ReadCharmDir(path)
+	c.Assert(err, gc.IsNil)
+	ident := fmt.Sprintf("%s-%d", ch.Meta().Name, ch.Revision())
+	curl := charm.MustParseURL("local:quantal/" + ident)
+	sch, err := addCharm(s.State, curl, ch)
+	c.Assert(err, gc.IsNil)
+	return sch
+}
+
 func (s *JujuConnSuite) AddTestingService(c *gc.C, name string, ch *state.Charm) *state.Service {
 	return s.AddTestingServiceWithNetworks(c, name, ch, nil)
 }
@@ -697,7 +697,6 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.Bootstr
 			Cert:    []byte(testing.ServerCert),
 			Key:     []byte(testing.ServerKey),
 			DataDir: DataDir,
-			LogDir:  LogDir,
 		})
 		if err != nil {
 			panic(err)
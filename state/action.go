@@ -8,7 +8,10 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/juju/errors"
+	"github.com/juju/gojsonschema"
 	"github.com/juju/names"
+	"gopkg.in/juju/charm.v3"
 	"gopkg.in/mgo.v2/txn"
 )
 
@@ -44,8 +47,7 @@ type ActionReceiver interface {
 
 var (
 	_ ActionReceiver = (*Unit)(nil)
-	// TODO(jcw4) - use when Actions can be queued for Services
-	//_ ActionReceiver = (*Service)(nil)
+	_ ActionReceiver = (*Service)(nil)
 )
 
 const actionMarker string = "_a_"
@@ -56,13 +58,35 @@ type actionDoc struct {
 	// to facilitate indexing and prefix filtering
 	Id string `bson:"_id"`
 
-	// Name identifies the action that should be run; it should
-	// match an action defined by the unit's charm.
+	// Name identifies the action that should be run; it is checked
+	// against the actions defined by the unit's charm when queued.
 	Name string
 
-	// Payload holds the action's parameters, if any; it should validate
-	// against the schema defined by the named action in the unit's charm
+	// Payload holds the action's parameters, if any, including any
+	// defaults filled in from the schema defined by the named action
+	// in the unit's charm, which it was validated against when queued.
 	Payload map[string]interface{}
+
+	// Parent holds the id of the service action that queued this
+	// action on one of the service's units, if any.
+	Parent string `bson:",omitempty"`
+
+	// Units lists, in the order they will be run, the units that an
+	// action queued on a service is fanned out to.
+	Units []string `bson:",omitempty"`
+
+	// Children holds the ids of the unit actions queued so far by an
+	// action queued on a service, in the same order as Units. Units
+	// that were dead by the time their turn came hold an empty id.
+	Children []string `bson:",omitempty"`
+
+	// BatchSize limits how many of the unit actions queued by a
+	// service action may be pending at once; zero means no limit.
+	BatchSize int `bson:",omitempty"`
+
+	// Finished counts the unit actions of a service action that have
+	// finished or were skipped.
+	Finished int
 }
 
 // Action represents an instruction to do some "action" and is expected
@@ -121,27 +145,170 @@ func (a *Action) Payload() map[string]interface{} {
 // Complete removes action from the pending queue and creates an ActionResult
 // to capture the output and end state of the action.
 func (a *Action) Complete(output string) error {
-	return a.removeAndLog(ActionCompleted, output)
+	return a.Finish(ActionCompleted, nil, output)
 }
 
 // Fail removes an Action from the queue, and creates an ActionResult that
 // will capture the reason for the failure.
 func (a *Action) Fail(reason string) error {
-	return a.removeAndLog(ActionFailed, reason)
+	return a.Finish(ActionFailed, nil, reason)
 }
 
-// removeAndLog takes the action off of the pending queue, and creates an
-// actionresult to capture the outcome of the action.
-func (a *Action) removeAndLog(finalStatus ActionStatus, output string) error {
-	doc := newActionResultDoc(a, finalStatus, output)
-	return a.st.runTransaction([]txn.Op{
+// Finish takes the action off of the pending queue, and creates an
+// ActionResult holding the given end state, the structured results set
+// by the action and any output or failure message.
+func (a *Action) Finish(finalStatus ActionStatus, results map[string]interface{}, output string) error {
+	doc := newActionResultDoc(a, finalStatus, results, output)
+	ops := []txn.Op{
 		addActionResultOp(a.st, &doc),
 		{
 			C:      actionsC,
 			Id:     a.doc.Id,
 			Remove: true,
 		},
-	})
+	}
+	if a.doc.Parent == "" {
+		return a.st.runTransaction(ops)
+	}
+	// The action was queued by a service action, which must learn of
+	// its completion in the same transaction.
+	ops[1].Assert = txn.DocExists
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if _, err := a.st.Action(a.doc.Id); errors.IsNotFound(err) {
+				return nil, fmt.Errorf("cannot finish action %q: already finished", a.doc.Id)
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		parentOps, err := a.finishChildOps(&doc)
+		if err != nil {
+			return nil, err
+		}
+		return append(ops[:len(ops):len(ops)], parentOps...), nil
+	}
+	return a.st.run(buildTxn)
+}
+
+// UnitStatuses reports the progress of an action queued on a service,
+// giving the status of the action on each of the service's units,
+// keyed by unit name. It returns nil for actions queued on a unit.
+func (a *Action) UnitStatuses() (map[string]ActionStatus, error) {
+	if len(a.doc.Units) == 0 {
+		return nil, nil
+	}
+	statuses := make(map[string]ActionStatus)
+	for i, unitName := range a.doc.Units {
+		if i >= len(a.doc.Children) {
+			statuses[unitName] = ActionWaiting
+			continue
+		}
+		status, err := a.st.childActionStatus(a.doc.Children[i])
+		if err != nil {
+			return nil, err
+		}
+		statuses[unitName] = status
+	}
+	return statuses, nil
+}
+
+// ActionValidationError is returned when an action that is being queued
+// is not defined by the charm, or its payload does not satisfy the
+// schema the charm defines for it.
+type ActionValidationError struct {
+	ActionName string
+
+	// Errors describes each problem found with the action.
+	Errors []string
+}
+
+func (e *ActionValidationError) Error() string {
+	return fmt.Sprintf("invalid action %q: %s", e.ActionName, strings.Join(e.Errors, "; "))
+}
+
+// IsActionValidationError returns whether err is an ActionValidationError.
+func IsActionValidationError(err error) bool {
+	_, ok := err.(*ActionValidationError)
+	return ok
+}
+
+// validateActionPayload checks that the charm defines the named action,
+// and that the payload satisfies the action's schema once any defaults
+// the schema gives for missing parameters are filled in. It returns the
+// completed payload.
+func validateActionPayload(ch *Charm, name string, payload map[string]interface{}) (map[string]interface{}, error) {
+	var spec charm.ActionSpec
+	var ok bool
+	if actions := ch.Actions(); actions != nil {
+		spec, ok = actions.ActionSpecs[name]
+	}
+	if !ok {
+		return nil, &ActionValidationError{
+			ActionName: name,
+			Errors:     []string{fmt.Sprintf("not defined by charm %q", ch.URL())},
+		}
+	}
+	completed := make(map[string]interface{})
+	for key, value := range payload {
+		completed[key] = value
+	}
+	properties, _ := spec.Params["properties"].(map[string]interface{})
+	for key, property := range properties {
+		if _, ok := completed[key]; ok {
+			continue
+		}
+		if schema, ok := property.(map[string]interface{}); ok {
+			if value, ok := schema["default"]; ok {
+				completed[key] = value
+			}
+		}
+	}
+	schema, err := gojsonschema.NewJsonSchemaDocument(spec.Params)
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid schema for action %q", name)
+	}
+	if result := schema.Validate(completed); !result.Valid() {
+		verr := &ActionValidationError{ActionName: name}
+		for _, resultErr := range result.Errors() {
+			field := strings.TrimPrefix(resultErr.Context.String(), "(root)")
+			field = strings.TrimPrefix(field, ".")
+			if field == "" {
+				verr.Errors = append(verr.Errors, resultErr.Description)
+			} else {
+				verr.Errors = append(verr.Errors, field+": "+resultErr.Description)
+			}
+		}
+		return nil, verr
+	}
+	if payload == nil && len(completed) == 0 {
+		return nil, nil
+	}
+	return completed, nil
+}
+
+// UnitResults reports the progress of an action queued on a service in
+// the form of the results the action will have once finished: an entry
+// for each of the service's units, keyed by unit name, giving the status
+// of the action on the unit along with the id, results and output of the
+// unit action where there are any. It returns nil for actions queued on
+// a unit.
+func (a *Action) UnitResults() (map[string]interface{}, error) {
+	if len(a.doc.Units) == 0 {
+		return nil, nil
+	}
+	results := make(map[string]interface{})
+	for i, unitName := range a.doc.Units {
+		if i >= len(a.doc.Children) {
+			results[unitName] = unitActionResult{Status: ActionWaiting}.entry()
+			continue
+		}
+		unitResult, err := a.st.childActionResult(a.doc.Children[i])
+		if err != nil {
+			return nil, err
+		}
+		results[unitName] = unitResult.entry()
+	}
+	return results, nil
 }
 
 // globalKey returns the global database key for the action.
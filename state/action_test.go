@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/txn"
 	"github.com/juju/utils/set"
@@ -26,9 +27,34 @@ type ActionSuite struct {
 
 var _ = gc.Suite(&ActionSuite{})
 
+// testActionsYaml defines the actions queued by the tests in this package.
+var testActionsYaml = `
+actions:
+   snapshot:
+      description: Take a snapshot of the database.
+      params:
+         type: object
+         properties:
+            outfile:
+               description: The file to write out to.
+               type: string
+               default: foo.bz2
+   backup:
+      description: Back up the database.
+   fakeaction:
+      description: An action that does nothing.
+      params:
+         type: object
+         properties:
+            outfile:
+               type: string
+            infile:
+               type: string
+`
+
 func (s *ActionSuite) SetUpTest(c *gc.C) {
 	s.ConnSuite.SetUpTest(c)
-	s.charm = s.AddTestingCharm(c, "wordpress")
+	s.charm = s.AddActionsCharm(c, "wordpress", testActionsYaml, 1)
 	var err error
 	s.service = s.AddTestingService(c, "wordpress", s.charm)
 	c.Assert(err, gc.IsNil)
@@ -78,6 +104,40 @@ func (s *ActionSuite) TestAddAction(c *gc.C) {
 	c.Assert(action.Payload(), jc.DeepEquals, params)
 }
 
+func (s *ActionSuite) TestAddActionFillsDefaults(c *gc.C) {
+	action, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Payload(), jc.DeepEquals, map[string]interface{}{"outfile": "foo.bz2"})
+
+	action, err = s.unit.AddAction("snapshot", map[string]interface{}{"outfile": "out.tgz"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Payload(), jc.DeepEquals, map[string]interface{}{"outfile": "out.tgz"})
+}
+
+func (s *ActionSuite) TestAddActionValidatesPayload(c *gc.C) {
+	_, err := s.unit.AddAction("snapshop", nil)
+	c.Assert(err, gc.ErrorMatches, `invalid action "snapshop": not defined by charm "local:quantal/quantal-wordpress-1"`)
+	c.Assert(err, jc.Satisfies, state.IsActionValidationError)
+
+	_, err = s.unit.AddAction("snapshot", map[string]interface{}{"outfile": 42})
+	c.Assert(err, gc.ErrorMatches, `invalid action "snapshot": .*outfile.*`)
+	c.Assert(err, jc.Satisfies, state.IsActionValidationError)
+	verr := err.(*state.ActionValidationError)
+	c.Assert(verr.Errors, gc.HasLen, 1)
+	c.Assert(verr.Errors[0], gc.Matches, `outfile: .+`)
+
+	_, err = s.service.AddAction("snapshop", nil)
+	c.Assert(err, jc.Satisfies, state.IsActionValidationError)
+
+	// nothing was queued
+	actions, err := s.unit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 0)
+	actions, err = s.service.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 0)
+}
+
 func (s *ActionSuite) TestAddActionAcceptsDuplicateNames(c *gc.C) {
 	name := "fakeaction"
 	params1 := map[string]interface{}{"outfile": "outfile.tar.bz2"}
@@ -124,7 +184,7 @@ func (s *ActionSuite) TestAddActionLifecycle(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 
 	// can add action to a dying unit
-	_, err = unit.AddAction("fakeaction1", map[string]interface{}{})
+	_, err = unit.AddAction("fakeaction", map[string]interface{}{})
 	c.Assert(err, gc.IsNil)
 
 	// make sure unit is dead
@@ -132,7 +192,7 @@ func (s *ActionSuite) TestAddActionLifecycle(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 
 	// cannot add action to a dead unit
-	_, err = unit.AddAction("fakeaction2", map[string]interface{}{})
+	_, err = unit.AddAction("fakeaction", map[string]interface{}{})
 	c.Assert(err, gc.ErrorMatches, "unit .* is dead")
 }
 
@@ -159,7 +219,7 @@ func (s *ActionSuite) TestFail(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	preventUnitDestroyRemove(c, unit)
 
-	a, err := unit.AddAction("action1", nil)
+	a, err := unit.AddAction("fakeaction", nil)
 	c.Assert(err, gc.IsNil)
 
 	action, err := s.State.Action(a.Id())
@@ -196,7 +256,7 @@ func (s *ActionSuite) TestComplete(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	preventUnitDestroyRemove(c, unit)
 
-	a, err := unit.AddAction("action1", nil)
+	a, err := unit.AddAction("fakeaction", nil)
 	c.Assert(err, gc.IsNil)
 
 	action, err := s.State.Action(a.Id())
@@ -227,6 +287,269 @@ func (s *ActionSuite) TestComplete(c *gc.C) {
 	c.Assert(len(actions), gc.Equals, 0)
 }
 
+func (s *ActionSuite) TestFinishWithResults(c *gc.C) {
+	action, err := s.unit.AddAction("backup", nil)
+	c.Assert(err, gc.IsNil)
+
+	results := map[string]interface{}{
+		"path": "/srv/backups/latest.tgz",
+		"stats": map[string]interface{}{
+			"rows": "42",
+		},
+	}
+	err = action.Finish(state.ActionFailed, results, "backup truncated")
+	c.Assert(err, gc.IsNil)
+
+	actionResults, err := s.unit.ActionResults()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actionResults, gc.HasLen, 1)
+	c.Assert(actionResults[0].Status(), gc.Equals, state.ActionFailed)
+	c.Assert(actionResults[0].Results(), jc.DeepEquals, results)
+	c.Assert(actionResults[0].Output(), gc.Equals, "backup truncated")
+
+	actions, err := s.unit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 0)
+}
+
+func (s *ActionSuite) TestActionResultByActionTag(c *gc.C) {
+	action, err := s.unit.AddAction("snapshot", map[string]interface{}{"outfile": "out.tgz"})
+	c.Assert(err, gc.IsNil)
+
+	// no result is available until the action has finished
+	_, err = s.State.ActionResultByActionTag(action.ActionTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = action.Complete("done")
+	c.Assert(err, gc.IsNil)
+
+	result, err := s.State.ActionResultByActionTag(action.ActionTag())
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.ActionName(), gc.Equals, "snapshot")
+	c.Assert(result.Status(), gc.Equals, state.ActionCompleted)
+	c.Assert(result.Output(), gc.Equals, "done")
+	c.Assert(result.ActionTag(), gc.Equals, action.ActionTag())
+}
+
+func (s *ActionSuite) TestServiceAddAction(c *gc.C) {
+	params := map[string]interface{}{"outfile": "out.tgz"}
+	action, err := s.service.AddAction("snapshot", params)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Tag().String(), gc.Equals, "action-wordpress_a_0")
+
+	// the action is queued on every unit of the service
+	for _, unit := range []*state.Unit{s.unit, s.unit2} {
+		actions, err := unit.Actions()
+		c.Assert(err, gc.IsNil)
+		c.Assert(actions, gc.HasLen, 1)
+		c.Assert(actions[0].Name(), gc.Equals, "snapshot")
+		c.Assert(actions[0].Payload(), jc.DeepEquals, params)
+	}
+	statuses, err := action.UnitStatuses()
+	c.Assert(err, gc.IsNil)
+	c.Assert(statuses, jc.DeepEquals, map[string]state.ActionStatus{
+		"wordpress/0": state.ActionPending,
+		"wordpress/1": state.ActionPending,
+	})
+
+	actions, err := s.service.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].Id(), gc.Equals, action.Id())
+
+	// a unit action does not report per-unit progress
+	unitActions, err := s.unit.Actions()
+	c.Assert(err, gc.IsNil)
+	statuses, err = unitActions[0].UnitStatuses()
+	c.Assert(err, gc.IsNil)
+	c.Assert(statuses, gc.IsNil)
+}
+
+func (s *ActionSuite) TestServiceActionAggregatesResults(c *gc.C) {
+	action, err := s.service.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	unitActions, err := s.unit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(unitActions, gc.HasLen, 1)
+	unitAction0 := unitActions[0]
+	err = unitAction0.Finish(state.ActionCompleted, map[string]interface{}{"path": "/tmp/foo.bz2"}, "done")
+	c.Assert(err, gc.IsNil)
+
+	// the service action remains pending until every unit has finished
+	action, err = s.State.Action(action.Id())
+	c.Assert(err, gc.IsNil)
+	statuses, err := action.UnitStatuses()
+	c.Assert(err, gc.IsNil)
+	c.Assert(statuses, jc.DeepEquals, map[string]state.ActionStatus{
+		"wordpress/0": state.ActionCompleted,
+		"wordpress/1": state.ActionPending,
+	})
+
+	unitActions, err = s.unit2.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(unitActions, gc.HasLen, 1)
+	unitAction1 := unitActions[0]
+	err = unitAction1.Fail("disk full")
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.State.Action(action.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	results, err := s.service.ActionResults()
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].ActionTag(), gc.Equals, action.ActionTag())
+	c.Assert(results[0].Status(), gc.Equals, state.ActionFailed)
+	c.Assert(results[0].Output(), gc.Equals, "1 of 2 units completed")
+	c.Assert(results[0].Results(), jc.DeepEquals, map[string]interface{}{
+		"wordpress/0": map[string]interface{}{
+			"status":  "complete",
+			"id":      unitAction0.Id(),
+			"results": map[string]interface{}{"path": "/tmp/foo.bz2"},
+			"output":  "done",
+		},
+		"wordpress/1": map[string]interface{}{
+			"status": "fail",
+			"id":     unitAction1.Id(),
+			"output": "disk full",
+		},
+	})
+}
+
+func (s *ActionSuite) TestServiceActionBatchSize(c *gc.C) {
+	unit3, err := s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+
+	action, err := s.service.AddActionWithOptions("snapshot", nil, state.ServiceActionOptions{BatchSize: 2})
+	c.Assert(err, gc.IsNil)
+	statuses, err := action.UnitStatuses()
+	c.Assert(err, gc.IsNil)
+	c.Assert(statuses, jc.DeepEquals, map[string]state.ActionStatus{
+		"wordpress/0": state.ActionPending,
+		"wordpress/1": state.ActionPending,
+		"wordpress/2": state.ActionWaiting,
+	})
+	queued, err := unit3.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(queued, gc.HasLen, 0)
+
+	// finishing a unit queues the action on the next waiting unit
+	queued, err = s.unit2.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(queued, gc.HasLen, 1)
+	err = queued[0].Complete("done")
+	c.Assert(err, gc.IsNil)
+	queued, err = unit3.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(queued, gc.HasLen, 1)
+
+	for _, unit := range []*state.Unit{s.unit, unit3} {
+		queued, err := unit.Actions()
+		c.Assert(err, gc.IsNil)
+		c.Assert(queued, gc.HasLen, 1)
+		err = queued[0].Complete("done")
+		c.Assert(err, gc.IsNil)
+	}
+	result, err := s.State.ActionResultByActionTag(action.ActionTag())
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Status(), gc.Equals, state.ActionCompleted)
+	c.Assert(result.Output(), gc.Equals, "3 of 3 units completed")
+}
+
+func (s *ActionSuite) TestServiceActionFirstUnitOnly(c *gc.C) {
+	action, err := s.service.AddActionWithOptions("snapshot", nil, state.ServiceActionOptions{FirstUnitOnly: true})
+	c.Assert(err, gc.IsNil)
+	statuses, err := action.UnitStatuses()
+	c.Assert(err, gc.IsNil)
+	c.Assert(statuses, jc.DeepEquals, map[string]state.ActionStatus{
+		"wordpress/0": state.ActionPending,
+	})
+	queued, err := s.unit2.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(queued, gc.HasLen, 0)
+}
+
+func (s *ActionSuite) TestServiceActionSkipsDeadUnits(c *gc.C) {
+	preventUnitDestroyRemove(c, s.unit2)
+	action, err := s.service.AddActionWithOptions("snapshot", nil, state.ServiceActionOptions{BatchSize: 1})
+	c.Assert(err, gc.IsNil)
+
+	err = s.unit2.Destroy()
+	c.Assert(err, gc.IsNil)
+	err = s.unit2.EnsureDead()
+	c.Assert(err, gc.IsNil)
+
+	queued, err := s.unit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(queued, gc.HasLen, 1)
+	err = queued[0].Complete("done")
+	c.Assert(err, gc.IsNil)
+
+	result, err := s.State.ActionResultByActionTag(action.ActionTag())
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Status(), gc.Equals, state.ActionFailed)
+	c.Assert(result.Results(), jc.DeepEquals, map[string]interface{}{
+		"wordpress/0": map[string]interface{}{
+			"status": "complete",
+			"id":     queued[0].Id(),
+			"output": "done",
+		},
+		"wordpress/1": map[string]interface{}{
+			"status": "skipped",
+		},
+	})
+}
+
+func (s *ActionSuite) TestServiceActionValidatedPerUnit(c *gc.C) {
+	// The service is upgraded to a charm that changes the snapshot
+	// default and adds an action, while wordpress/1 still runs the
+	// old charm.
+	upgraded := s.AddActionsCharm(c, "wordpress", `
+actions:
+   snapshot:
+      description: Take a snapshot of the database.
+      params:
+         type: object
+         properties:
+            outfile:
+               type: string
+               default: bar.bz2
+   restore:
+      description: Restore the database.
+`, 2)
+	err := s.unit2.SetCharmURL(s.charm.URL())
+	c.Assert(err, gc.IsNil)
+	err = s.service.SetCharm(upgraded, false)
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.service.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	for unit, outfile := range map[*state.Unit]string{s.unit: "bar.bz2", s.unit2: "foo.bz2"} {
+		queued, err := unit.Actions()
+		c.Assert(err, gc.IsNil)
+		c.Assert(queued, gc.HasLen, 1)
+		c.Check(queued[0].Payload(), jc.DeepEquals, map[string]interface{}{"outfile": outfile})
+	}
+
+	action, err := s.service.AddAction("restore", nil)
+	c.Assert(err, gc.IsNil)
+	statuses, err := action.UnitStatuses()
+	c.Assert(err, gc.IsNil)
+	c.Assert(statuses, jc.DeepEquals, map[string]state.ActionStatus{
+		"wordpress/0": state.ActionPending,
+		"wordpress/1": state.ActionSkipped,
+	})
+}
+
+func (s *ActionSuite) TestServiceAddActionNoLiveUnits(c *gc.C) {
+	service := s.AddTestingService(c, "mysql", s.AddActionsCharm(c, "mysql", testActionsYaml, 1))
+	_, err := service.AddAction("snapshot", nil)
+	c.Assert(err, gc.ErrorMatches, `service "mysql" has no live units`)
+
+	_, err = s.service.AddActionWithOptions("snapshot", nil, state.ServiceActionOptions{BatchSize: -1})
+	c.Assert(err, gc.ErrorMatches, "cannot add action; invalid batch size -1")
+}
+
 func (s *ActionSuite) TestUnitWatchActions(c *gc.C) {
 	// get units
 	unit1, err := s.State.Unit(s.unit.Name())
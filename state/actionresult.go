@@ -5,16 +5,30 @@ package state
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/juju/names"
 	"gopkg.in/mgo.v2/txn"
 )
 
-// ActionStatus represents the possible end states for an action.
+// ActionStatus represents the possible states for an action.
 type ActionStatus string
 
 const (
+	// ActionPending signifies that the action is queued but has not
+	// yet finished.
+	ActionPending ActionStatus = "pending"
+
+	// ActionWaiting indicates that an action queued on a service has
+	// not yet been queued on the unit, because the service's units are
+	// being run in batches.
+	ActionWaiting ActionStatus = "waiting"
+
+	// ActionSkipped indicates that an action queued on a service was
+	// never run on the unit, because the unit died before its turn.
+	ActionSkipped ActionStatus = "skipped"
+
 	// ActionFailed signifies that the action did not complete successfully.
 	ActionFailed ActionStatus = "fail"
 
@@ -42,7 +56,12 @@ type actionResultDoc struct {
 	// ActionCompleted for an action that successfully completed.
 	Status ActionStatus
 
-	// Output captures any text emitted by the action.
+	// Results holds the structured values set by the action while it
+	// ran, keyed by the names the action gave them.
+	Results map[string]interface{}
+
+	// Output captures any text emitted by the action, or the reason
+	// given for its failure.
 	Output string
 }
 
@@ -70,6 +89,16 @@ func (a *ActionResult) ActionResultTag() names.ActionResultTag {
 	return names.NewActionResultTag(a.Id())
 }
 
+// ActionTag returns the ActionTag of the Action that produced this
+// ActionResult.
+func (a *ActionResult) ActionTag() names.ActionTag {
+	prefix, sequence, ok := extractActionResultPrefixAndSequence(a.doc.Id)
+	if !ok {
+		panic(fmt.Sprintf("cannot extract action tag from _id %v", a.doc.Id))
+	}
+	return names.JoinActionTag(prefix, sequence)
+}
+
 // ActionName returns the name of the Action.
 func (a *ActionResult) ActionName() string {
 	return a.doc.ActionName
@@ -86,6 +115,12 @@ func (a *ActionResult) Status() ActionStatus {
 	return a.doc.Status
 }
 
+// Results returns the structured values set by the action as it was
+// executed.
+func (a *ActionResult) Results() map[string]interface{} {
+	return a.doc.Results
+}
+
 // Output returns the text caputured from the action as it was executed.
 func (a *ActionResult) Output() string {
 	return a.doc.Output
@@ -111,8 +146,8 @@ func newActionResult(st *State, adoc actionResultDoc) *ActionResult {
 }
 
 // newActionResultDoc converts an Action into an actionResultDoc given
-// the finalStatus and the output of the action
-func newActionResultDoc(a *Action, finalStatus ActionStatus, output string) actionResultDoc {
+// the finalStatus, results and output of the action
+func newActionResultDoc(a *Action, finalStatus ActionStatus, results map[string]interface{}, output string) actionResultDoc {
 	actionId := a.Id()
 	id, ok := convertActionIdToActionResultId(actionId)
 	if !ok {
@@ -123,6 +158,7 @@ func newActionResultDoc(a *Action, finalStatus ActionStatus, output string) acti
 		ActionName: a.doc.Name,
 		Payload:    a.doc.Payload,
 		Status:     finalStatus,
+		Results:    results,
 		Output:     output,
 	}
 }
@@ -137,6 +173,20 @@ func convertActionIdToActionResultId(actionId string) (string, bool) {
 	return actionResultId, true
 }
 
+// extractActionResultPrefixAndSequence splits an actionResultId into the
+// name of the ActionReceiver and the sequence of the originating Action.
+func extractActionResultPrefixAndSequence(id string) (string, int, bool) {
+	parts := strings.Split(id, actionResultMarker)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", -1, false
+	}
+	sequence, err := strconv.ParseInt(parts[1], 10, 0)
+	if err != nil {
+		return "", -1, false
+	}
+	return parts[0], int(sequence), true
+}
+
 // actionResultPrefix returns a string prefix for matching action results for
 // the given ActionReceiver
 func actionResultPrefix(ar ActionReceiver) string {
@@ -0,0 +1,342 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/version"
+)
+
+// AgentUpgrade represents a staged upgrade of the environment's agents.
+// The state servers upgrade first, followed by a chosen set of canary
+// machines; the environment's agent version, which all other agents
+// follow, only changes when the upgrade is continued. Until then, and
+// after an upgrade has been continued, it can be rolled back: agents
+// that have not yet run upgrade steps for the new version return to
+// the previous version.
+type AgentUpgrade struct {
+	st  *State
+	doc agentUpgradeDoc
+}
+
+type agentUpgradeDoc struct {
+	Id              string         `bson:"_id"`
+	PreviousVersion version.Number `bson:"previousversion"`
+	TargetVersion   version.Number `bson:"targetversion"`
+	Canaries        []string       `bson:"canaries"`
+	Continued       bool           `bson:"continued"`
+	RolledBack      bool           `bson:"rolledback"`
+	StepsStarted    []string       `bson:"stepsstarted"`
+}
+
+// StartAgentUpgrade starts upgrading the environment's agents to the
+// target version. If canaries holds any machine ids, only the state
+// servers and those machines are upgraded until the upgrade is
+// continued; otherwise the environment's agent version is set to the
+// target version straight away.
+func (st *State) StartAgentUpgrade(target version.Number, canaries []string) (*AgentUpgrade, error) {
+	for _, id := range canaries {
+		if _, err := st.Machine(id); err != nil {
+			return nil, errors.Annotatef(err, "cannot start agent upgrade to %s", target)
+		}
+	}
+	var doc agentUpgradeDoc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		settings, err := readSettings(st, environGlobalKey)
+		if err != nil {
+			return nil, err
+		}
+		agentVersion, ok := settings.Get("agent-version")
+		if !ok {
+			return nil, fmt.Errorf("no agent version set in the environment")
+		}
+		current, err := version.Parse(fmt.Sprint(agentVersion))
+		if err != nil {
+			return nil, err
+		}
+		if current == target {
+			return nil, fmt.Errorf("agents already at version %s", target)
+		}
+		if err := st.checkCanUpgrade(current.String(), target.String()); err != nil {
+			return nil, err
+		}
+		doc = agentUpgradeDoc{
+			Id:              environGlobalKey,
+			PreviousVersion: current,
+			TargetVersion:   target,
+			Canaries:        canaries,
+			Continued:       len(canaries) == 0,
+		}
+		ops := []txn.Op{{
+			C:      settingsC,
+			Id:     environGlobalKey,
+			Assert: bson.D{{"txn-revno", settings.txnRevno}},
+		}}
+		existing, err := st.AgentUpgrade()
+		if errors.IsNotFound(err) {
+			return append(ops, txn.Op{
+				C:      agentUpgradesC,
+				Id:     environGlobalKey,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}), nil
+		} else if err != nil {
+			return nil, err
+		}
+		if existing.InCanaryStage() {
+			return nil, fmt.Errorf("agent upgrade to %s already in progress", existing.doc.TargetVersion)
+		}
+		return append(ops, txn.Op{
+			C:  agentUpgradesC,
+			Id: environGlobalKey,
+			Assert: bson.D{{"$or", []bson.D{
+				{{"continued", true}},
+				{{"rolledback", true}},
+			}}},
+			Update: bson.D{{"$set", bson.D{
+				{"previousversion", doc.PreviousVersion},
+				{"targetversion", doc.TargetVersion},
+				{"canaries", doc.Canaries},
+				{"continued", doc.Continued},
+				{"rolledback", false},
+				{"stepsstarted", []string(nil)},
+			}}},
+		}), nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return nil, errors.Annotatef(err, "cannot start agent upgrade to %s", target)
+	}
+	if doc.Continued {
+		if err := st.SetEnvironAgentVersion(target); err != nil {
+			return nil, err
+		}
+	}
+	return &AgentUpgrade{st: st, doc: doc}, nil
+}
+
+// AgentUpgrade returns the most recently started agent upgrade.
+func (st *State) AgentUpgrade() (*AgentUpgrade, error) {
+	upgrades, closer := st.getCollection(agentUpgradesC)
+	defer closer()
+
+	au := &AgentUpgrade{st: st}
+	err := upgrades.FindId(environGlobalKey).One(&au.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("agent upgrade")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot get agent upgrade: %v", err)
+	}
+	return au, nil
+}
+
+// PreviousVersion returns the agent version the environment used
+// before the upgrade.
+func (au *AgentUpgrade) PreviousVersion() version.Number {
+	return au.doc.PreviousVersion
+}
+
+// TargetVersion returns the agent version the environment is upgraded
+// to.
+func (au *AgentUpgrade) TargetVersion() version.Number {
+	return au.doc.TargetVersion
+}
+
+// Canaries returns the ids of the machines upgraded along with the
+// state servers before the upgrade is continued.
+func (au *AgentUpgrade) Canaries() []string {
+	return au.doc.Canaries
+}
+
+// Continued returns whether the upgrade has been continued to all
+// agents.
+func (au *AgentUpgrade) Continued() bool {
+	return au.doc.Continued
+}
+
+// RolledBack returns whether the upgrade has been rolled back.
+func (au *AgentUpgrade) RolledBack() bool {
+	return au.doc.RolledBack
+}
+
+// InCanaryStage returns whether only the state servers and canary
+// machines are being upgraded.
+func (au *AgentUpgrade) InCanaryStage() bool {
+	return !au.doc.Continued && !au.doc.RolledBack
+}
+
+// Refresh refreshes the contents of the agent upgrade from state.
+func (au *AgentUpgrade) Refresh() error {
+	fresh, err := au.st.AgentUpgrade()
+	if err != nil {
+		return err
+	}
+	au.doc = fresh.doc
+	return nil
+}
+
+// AgentVersion returns the agent version the given machine should
+// run, given the environment's agent version. The returned flag is
+// true if the machine should return to the previous version because
+// the upgrade was rolled back.
+func (au *AgentUpgrade) AgentVersion(machineId string, isManager bool, global version.Number) (version.Number, bool) {
+	if global != au.doc.PreviousVersion || au.doc.Continued && !au.doc.RolledBack {
+		// The upgrade applies to all agents, or has been
+		// superseded by a later change of agent version.
+		return global, false
+	}
+	if au.doc.RolledBack {
+		// Upgrade steps cannot be undone, so machines that have
+		// started running them stay at the target version.
+		if containsString(au.doc.StepsStarted, machineId) {
+			return au.doc.TargetVersion, false
+		}
+		return au.doc.PreviousVersion, true
+	}
+	if isManager || containsString(au.doc.Canaries, machineId) {
+		return au.doc.TargetVersion, false
+	}
+	return global, false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Continue upgrades all agents to the target version, once the state
+// servers and canary machines are running it.
+func (au *AgentUpgrade) Continue() error {
+	target := au.doc.TargetVersion
+	if au.doc.RolledBack {
+		return fmt.Errorf("cannot continue agent upgrade to %s: upgrade rolled back", target)
+	}
+	if au.doc.Continued {
+		return fmt.Errorf("cannot continue agent upgrade to %s: upgrade already continued", target)
+	}
+	info, err := au.st.StateServerInfo()
+	if err != nil {
+		return err
+	}
+	ids := append(append([]string(nil), info.MachineIds...), au.doc.Canaries...)
+	for _, id := range ids {
+		if err := au.checkUpgraded(id); err != nil {
+			return fmt.Errorf("cannot continue agent upgrade to %s: %v", target, err)
+		}
+	}
+	ops := []txn.Op{{
+		C:      agentUpgradesC,
+		Id:     environGlobalKey,
+		Assert: bson.D{{"targetversion", target}, {"rolledback", false}},
+		Update: bson.D{{"$set", bson.D{{"continued", true}}}},
+	}}
+	if err := au.st.runTransaction(ops); err != nil {
+		return fmt.Errorf("cannot continue agent upgrade to %s: %v", target, onAbort(err, errors.New("upgrade rolled back or superseded")))
+	}
+	au.doc.Continued = true
+	return au.st.SetEnvironAgentVersion(target)
+}
+
+// checkUpgraded returns an error unless the machine with the given id
+// runs the target version and has not failed to upgrade.
+func (au *AgentUpgrade) checkUpgraded(id string) error {
+	m, err := au.st.Machine(id)
+	if errors.IsNotFound(err) {
+		// Removed machines do not hold up the upgrade.
+		return nil
+	} else if err != nil {
+		return err
+	}
+	agentTools, err := m.AgentTools()
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err != nil || agentTools.Version.Number != au.doc.TargetVersion {
+		return fmt.Errorf("machine %s not upgraded", id)
+	}
+	status, info, _, err := m.Status()
+	if err != nil {
+		return err
+	}
+	if status == params.StatusError {
+		return fmt.Errorf("machine %s: %s", id, info)
+	}
+	return nil
+}
+
+// RollBack returns the agents that have not started running upgrade
+// steps for the target version to the previous version.
+func (au *AgentUpgrade) RollBack() error {
+	ops := []txn.Op{{
+		C:      agentUpgradesC,
+		Id:     environGlobalKey,
+		Assert: bson.D{{"targetversion", au.doc.TargetVersion}},
+		Update: bson.D{{"$set", bson.D{{"rolledback", true}}}},
+	}}
+	if err := au.st.runTransaction(ops); err != nil {
+		return fmt.Errorf("cannot roll back agent upgrade to %s: %v", au.doc.TargetVersion, onAbort(err, errors.New("upgrade superseded")))
+	}
+	au.doc.RolledBack = true
+	return au.st.SetEnvironAgentVersion(au.doc.PreviousVersion)
+}
+
+// agentsLeftByRollBack returns the target version of the most recent
+// agent upgrade, if it has been rolled back, and the ids of the
+// machines that stayed at that version because they had started its
+// upgrade steps.
+func (st *State) agentsLeftByRollBack() (version.Number, []string, error) {
+	au, err := st.AgentUpgrade()
+	if errors.IsNotFound(err) {
+		return version.Zero, nil, nil
+	} else if err != nil {
+		return version.Zero, nil, err
+	}
+	if !au.doc.RolledBack {
+		return version.Zero, nil, nil
+	}
+	return au.doc.TargetVersion, au.doc.StepsStarted, nil
+}
+
+// SetUpgradeStepsStarted records that the machine with the given id is
+// about to run the upgrade steps for the given version, after which it
+// is no longer rolled back with the rest of the environment. It fails
+// if the upgrade to that version has already been rolled back.
+func (st *State) SetUpgradeStepsStarted(machineId string, vers version.Number) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		au, err := st.AgentUpgrade()
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, err
+		}
+		if au.doc.TargetVersion != vers || containsString(au.doc.StepsStarted, machineId) {
+			return nil, jujutxn.ErrNoOperations
+		}
+		if au.doc.RolledBack {
+			return nil, fmt.Errorf("agent upgrade to %s rolled back", vers)
+		}
+		return []txn.Op{{
+			C:      agentUpgradesC,
+			Id:     environGlobalKey,
+			Assert: bson.D{{"targetversion", vers}, {"rolledback", false}},
+			Update: bson.D{{"$addToSet", bson.D{{"stepsstarted", machineId}}}},
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot start upgrade steps of machine %s", machineId)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/version"
+)
+
+type AgentUpgradeSuite struct {
+	ConnSuite
+	previous version.Number
+	target   version.Number
+	machines []*state.Machine
+}
+
+var _ = gc.Suite(&AgentUpgradeSuite{})
+
+func (s *AgentUpgradeSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	envConfig, err := s.State.EnvironConfig()
+	c.Assert(err, gc.IsNil)
+	var ok bool
+	s.previous, ok = envConfig.AgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	s.target = s.previous
+	s.target.Minor++
+
+	// Machine 0 is a state server; 1 and 2 host units.
+	s.machines = nil
+	for _, job := range []state.MachineJob{state.JobManageEnviron, state.JobHostUnits, state.JobHostUnits} {
+		m, err := s.State.AddMachine("quantal", job)
+		c.Assert(err, gc.IsNil)
+		s.setAgentVersion(c, m, s.previous)
+		s.machines = append(s.machines, m)
+	}
+}
+
+func (s *AgentUpgradeSuite) setAgentVersion(c *gc.C, m *state.Machine, vers version.Number) {
+	err := m.SetAgentVersion(version.Binary{Number: vers, Series: "quantal", Arch: "amd64"})
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *AgentUpgradeSuite) assertEnvironAgentVersion(c *gc.C, expect version.Number) {
+	envConfig, err := s.State.EnvironConfig()
+	c.Assert(err, gc.IsNil)
+	agentVersion, _ := envConfig.AgentVersion()
+	c.Assert(agentVersion, gc.Equals, expect)
+}
+
+func (s *AgentUpgradeSuite) assertAgentVersions(c *gc.C, au *state.AgentUpgrade, rollBack bool, expect ...version.Number) {
+	envConfig, err := s.State.EnvironConfig()
+	c.Assert(err, gc.IsNil)
+	global, _ := envConfig.AgentVersion()
+	for i, m := range s.machines {
+		vers, gotRollBack := au.AgentVersion(m.Id(), m.IsManager(), global)
+		c.Check(vers, gc.Equals, expect[i], gc.Commentf("machine %s", m))
+		c.Check(gotRollBack, gc.Equals, rollBack && vers == s.previous, gc.Commentf("machine %s", m))
+	}
+}
+
+func (s *AgentUpgradeSuite) TestStartWithCanaries(c *gc.C) {
+	au, err := s.State.StartAgentUpgrade(s.target, []string{"1"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(au.PreviousVersion(), gc.Equals, s.previous)
+	c.Assert(au.TargetVersion(), gc.Equals, s.target)
+	c.Assert(au.Canaries(), gc.DeepEquals, []string{"1"})
+	c.Assert(au.InCanaryStage(), jc.IsTrue)
+
+	// Only the state server and the canary are upgraded.
+	s.assertEnvironAgentVersion(c, s.previous)
+	s.assertAgentVersions(c, au, false, s.target, s.target, s.previous)
+
+	stored, err := s.State.AgentUpgrade()
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored.Canaries(), gc.DeepEquals, []string{"1"})
+
+	_, err = s.State.StartAgentUpgrade(s.target, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot start agent upgrade to .*: agent upgrade to .* already in progress`)
+}
+
+func (s *AgentUpgradeSuite) TestStartWithoutCanaries(c *gc.C) {
+	au, err := s.State.StartAgentUpgrade(s.target, nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(au.Continued(), jc.IsTrue)
+	s.assertEnvironAgentVersion(c, s.target)
+	s.assertAgentVersions(c, au, false, s.target, s.target, s.target)
+}
+
+func (s *AgentUpgradeSuite) TestStartErrors(c *gc.C) {
+	_, err := s.State.StartAgentUpgrade(s.target, []string{"42"})
+	c.Assert(err, gc.ErrorMatches, `cannot start agent upgrade to .*: machine 42 not found`)
+	_, err = s.State.StartAgentUpgrade(s.previous, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot start agent upgrade to .*: agents already at version .*`)
+	_, err = s.State.AgentUpgrade()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *AgentUpgradeSuite) TestContinue(c *gc.C) {
+	au, err := s.State.StartAgentUpgrade(s.target, []string{"1"})
+	c.Assert(err, gc.IsNil)
+	err = au.Continue()
+	c.Assert(err, gc.ErrorMatches, `cannot continue agent upgrade to .*: machine 0 not upgraded`)
+
+	s.setAgentVersion(c, s.machines[0], s.target)
+	s.setAgentVersion(c, s.machines[1], s.target)
+	err = s.machines[1].SetStatus(params.StatusError, "upgrade failed", nil)
+	c.Assert(err, gc.IsNil)
+	err = au.Continue()
+	c.Assert(err, gc.ErrorMatches, `cannot continue agent upgrade to .*: machine 1: upgrade failed`)
+
+	err = s.machines[1].SetStatus(params.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
+	err = au.Continue()
+	c.Assert(err, gc.IsNil)
+	s.assertEnvironAgentVersion(c, s.target)
+	s.assertAgentVersions(c, au, false, s.target, s.target, s.target)
+
+	err = au.Continue()
+	c.Assert(err, gc.ErrorMatches, `cannot continue agent upgrade to .*: upgrade already continued`)
+}
+
+func (s *AgentUpgradeSuite) TestRollBack(c *gc.C) {
+	au, err := s.State.StartAgentUpgrade(s.target, []string{"1", "2"})
+	c.Assert(err, gc.IsNil)
+	s.setAgentVersion(c, s.machines[0], s.target)
+	s.setAgentVersion(c, s.machines[1], s.target)
+	err = s.State.SetUpgradeStepsStarted("0", s.target)
+	c.Assert(err, gc.IsNil)
+
+	err = au.RollBack()
+	c.Assert(err, gc.IsNil)
+	c.Assert(au.RolledBack(), jc.IsTrue)
+	s.assertEnvironAgentVersion(c, s.previous)
+
+	// The state server has started its upgrade steps, so it stays
+	// at the target version.
+	err = au.Refresh()
+	c.Assert(err, gc.IsNil)
+	s.assertAgentVersions(c, au, true, s.target, s.previous, s.previous)
+
+	err = au.Continue()
+	c.Assert(err, gc.ErrorMatches, `cannot continue agent upgrade to .*: upgrade rolled back`)
+	err = s.State.SetUpgradeStepsStarted("1", s.target)
+	c.Assert(err, gc.ErrorMatches, `cannot start upgrade steps of machine 1: agent upgrade to .* rolled back`)
+	err = s.State.SetUpgradeStepsStarted("0", s.target)
+	c.Assert(err, gc.IsNil)
+
+	// Another upgrade can be started once the last one is rolled back.
+	au, err = s.State.StartAgentUpgrade(s.target, nil)
+	c.Assert(err, gc.IsNil)
+	s.assertAgentVersions(c, au, false, s.target, s.target, s.target)
+}
+
+func (s *AgentUpgradeSuite) TestRollBackAfterContinue(c *gc.C) {
+	au, err := s.State.StartAgentUpgrade(s.target, nil)
+	c.Assert(err, gc.IsNil)
+	for _, m := range s.machines[:2] {
+		s.setAgentVersion(c, m, s.target)
+		err = s.State.SetUpgradeStepsStarted(m.Id(), s.target)
+		c.Assert(err, gc.IsNil)
+	}
+	err = au.RollBack()
+	c.Assert(err, gc.IsNil)
+	s.assertEnvironAgentVersion(c, s.previous)
+	err = au.Refresh()
+	c.Assert(err, gc.IsNil)
+	s.assertAgentVersions(c, au, true, s.target, s.target, s.previous)
+}
+
+func (s *AgentUpgradeSuite) TestUpgradeAfterRollBack(c *gc.C) {
+	au, err := s.State.StartAgentUpgrade(s.target, []string{"1"})
+	c.Assert(err, gc.IsNil)
+	s.setAgentVersion(c, s.machines[0], s.target)
+	err = s.State.SetUpgradeStepsStarted("0", s.target)
+	c.Assert(err, gc.IsNil)
+	err = au.RollBack()
+	c.Assert(err, gc.IsNil)
+
+	// The state server stayed at the rolled back version, which
+	// does not prevent an upgrade to a third version.
+	third := s.target
+	third.Minor++
+	au, err = s.State.StartAgentUpgrade(third, nil)
+	c.Assert(err, gc.IsNil)
+	s.assertEnvironAgentVersion(c, third)
+	s.assertAgentVersions(c, au, false, third, third, third)
+
+	// Other agents at other versions still prevent upgrades.
+	s.setAgentVersion(c, s.machines[0], third)
+	s.setAgentVersion(c, s.machines[1], s.target)
+	s.setAgentVersion(c, s.machines[2], third)
+	fourth := third
+	fourth.Minor++
+	_, err = s.State.StartAgentUpgrade(fourth, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot start agent upgrade to .*: some agents have not upgraded to the current environment version .*: machine-1`)
+}
+
+func (s *AgentUpgradeSuite) TestSetUpgradeStepsStartedWithoutUpgrade(c *gc.C) {
+	err := s.State.SetUpgradeStepsStarted("1", s.target)
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.AgentUpgrade()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *AgentUpgradeSuite) TestWatchAgentVersion(c *gc.C) {
+	w := s.State.WatchAgentVersion()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	au, err := s.State.StartAgentUpgrade(s.target, []string{"1"})
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	err = au.RollBack()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
@@ -8,6 +8,7 @@ import (
 	"crypto/x509"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 	"time"
 
@@ -325,6 +326,20 @@ func (s *State) Addr() string {
 	return s.addr
 }
 
+// SendHTTPRequest sends an HTTPS request for the given path (and
+// query) on the API server, authenticated with the credentials used
+// to log in. The caller is responsible for closing the response body.
+func (s *State) SendHTTPRequest(method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.serverRoot+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create %s request: %v", method, err)
+	}
+	req.SetBasicAuth(s.tag, s.password)
+	// See the comment in Client.UploadTools for why the server
+	// certificate is not validated here.
+	return utils.GetNonValidatingHTTPClient().Do(req)
+}
+
 // EnvironTag returns the Environment Tag describing the environment we are
 // connected to.
 func (s *State) EnvironTag() string {
@@ -0,0 +1,148 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/juju/juju/state/api/base"
+	"github.com/juju/juju/state/api/params"
+)
+
+// httpClient sends HTTP requests to the API server.
+type httpClient interface {
+	SendHTTPRequest(method, path string, body io.Reader) (*http.Response, error)
+}
+
+// apiState is the part of the API connection used by the client.
+type apiState interface {
+	base.APICallCloser
+	httpClient
+}
+
+// Client wraps the backups API for the client.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+	http   httpClient
+}
+
+// NewClient returns a new backups API client.
+func NewClient(st apiState) *Client {
+	frontend, backend := base.NewClientFacade(st, "Backups")
+	return &Client{ClientFacade: frontend, facade: backend, http: st}
+}
+
+// Create sends a request to create a backup of juju's state.  It
+// returns the metadata associated with the resulting backup.
+func (c *Client) Create(notes string) (*params.BackupsMetadataResult, error) {
+	var result params.BackupsMetadataResult
+	args := params.BackupsCreateArgs{Notes: notes}
+	if err := c.facade.FacadeCall("Create", args, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Info implements the API method.
+func (c *Client) Info(id string) (*params.BackupsMetadataResult, error) {
+	var result params.BackupsMetadataResult
+	args := params.BackupsInfoArgs{ID: id}
+	if err := c.facade.FacadeCall("Info", args, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// List implements the API method.
+func (c *Client) List() (*params.BackupsListResult, error) {
+	var result params.BackupsListResult
+	args := params.BackupsListArgs{}
+	if err := c.facade.FacadeCall("List", args, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// LastGood returns the metadata of the most recent backup that
+// succeeded.
+func (c *Client) LastGood() (*params.BackupsMetadataResult, error) {
+	var result params.BackupsMetadataResult
+	args := params.BackupsLastGoodArgs{}
+	if err := c.facade.FacadeCall("LastGood", args, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Remove implements the API method.
+func (c *Client) Remove(id string) error {
+	args := params.BackupsRemoveArgs{ID: id}
+	return c.facade.FacadeCall("Remove", args, nil)
+}
+
+// Download returns the archive of the identified backup.  The caller
+// is responsible for closing it.
+func (c *Client) Download(id string) (io.ReadCloser, error) {
+	query := url.Values{"id": {id}}
+	resp, err := c.http.SendHTTPRequest("GET", "/backups?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot download backup: %v", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp.Body, nil
+	}
+	defer resp.Body.Close()
+	return nil, responseError(resp, "download")
+}
+
+// Upload sends a backup archive, such as one downloaded from another
+// state server, to be stored so that it can be restored.  It returns
+// the metadata of the stored backup.
+func (c *Client) Upload(archive io.Reader, checksum string) (*params.BackupsMetadataResult, error) {
+	query := url.Values{"checksum": {checksum}}
+	resp, err := c.http.SendHTTPRequest("POST", "/backups?"+query.Encode(), archive)
+	if err != nil {
+		return nil, fmt.Errorf("cannot upload backup: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp, "upload")
+	}
+	var result params.BackupsMetadataResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal backup upload response: %v", err)
+	}
+	return &result, nil
+}
+
+// Restore replaces the state of the environment with the identified
+// backup.  The state server restarts shortly afterwards, so the
+// connection should not be used further.
+func (c *Client) Restore(id string) error {
+	args := params.BackupsRestoreArgs{ID: id}
+	return c.facade.FacadeCall("Restore", args, nil)
+}
+
+// responseError returns the error reported in a failed backups HTTP
+// response.
+func responseError(resp *http.Response, action string) error {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("cannot read backup %s response: %v", action, err)
+	}
+	var result params.ErrorResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("cannot unmarshal backup %s response: %v", action, err)
+	}
+	if result.Error == nil {
+		return fmt.Errorf("cannot %s backup: %s", action, resp.Status)
+	}
+	return result.Error
+}
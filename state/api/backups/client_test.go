@@ -0,0 +1,171 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/environs"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/backups"
+	"github.com/juju/juju/state/api/params"
+	statebackups "github.com/juju/juju/state/backups"
+)
+
+type backupsSuite struct {
+	jujutesting.JujuConnSuite
+
+	client *backups.Client
+}
+
+var _ = gc.Suite(&backupsSuite{})
+
+func (s *backupsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.client = backups.NewClient(s.APIState)
+	c.Assert(s.client, gc.NotNil)
+}
+
+// addBackup stores a backup directly in state and environment
+// storage, as if it had been created through the API.
+func (s *backupsSuite) addBackup(c *gc.C, content, notes string) string {
+	origin := state.NewBackupOrigin(s.State, "0")
+	metadata := statebackups.NewMetadata("some-hash", int64(len(content)), *origin, notes)
+	metaStorage := state.NewBackupMetadataStorage(s.State)
+	id, err := metaStorage.Add(metadata)
+	c.Assert(err, gc.IsNil)
+	stor, err := environs.GetStorage(s.State)
+	c.Assert(err, gc.IsNil)
+	name := statebackups.StorageName("juju-backup-" + id + ".tar.gz")
+	err = stor.Put(name, strings.NewReader(content), int64(len(content)))
+	c.Assert(err, gc.IsNil)
+	err = metaStorage.SetStored(id)
+	c.Assert(err, gc.IsNil)
+	return id
+}
+
+func (s *backupsSuite) TestInfo(c *gc.C) {
+	id := s.addBackup(c, "archive data", "some notes")
+
+	result, err := s.client.Info(id)
+	c.Assert(err, gc.IsNil)
+	c.Check(result.ID, gc.Equals, id)
+	c.Check(result.Checksum, gc.Equals, "some-hash")
+	c.Check(result.Size, gc.Equals, int64(len("archive data")))
+	c.Check(result.Notes, gc.Equals, "some notes")
+	c.Check(result.Stored, jc.IsTrue)
+	c.Check(result.Environment, gc.Equals, s.State.EnvironTag().Id())
+}
+
+func (s *backupsSuite) TestInfoNotFound(c *gc.C) {
+	_, err := s.client.Info("spam")
+	c.Check(err, gc.ErrorMatches, `backup metadata "spam" not found`)
+	c.Check(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *backupsSuite) TestList(c *gc.C) {
+	first := s.addBackup(c, "first", "")
+	second := s.addBackup(c, "second", "")
+
+	result, err := s.client.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.List, gc.HasLen, 2)
+	ids := []string{result.List[0].ID, result.List[1].ID}
+	c.Check(ids, jc.SameContents, []string{first, second})
+}
+
+func (s *backupsSuite) TestLastGood(c *gc.C) {
+	id := s.addBackup(c, "archive data", "")
+
+	result, err := s.client.LastGood()
+	c.Assert(err, gc.IsNil)
+	c.Check(result.ID, gc.Equals, id)
+}
+
+func (s *backupsSuite) TestLastGoodNotFound(c *gc.C) {
+	_, err := s.client.LastGood()
+	c.Check(err, gc.ErrorMatches, "successful backup not found")
+	c.Check(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *backupsSuite) TestRemove(c *gc.C) {
+	id := s.addBackup(c, "archive data", "")
+
+	err := s.client.Remove(id)
+	c.Assert(err, gc.IsNil)
+	_, err = state.NewBackupMetadataStorage(s.State).Get(id)
+	c.Check(err, gc.ErrorMatches, `backup metadata ".*" not found`)
+}
+
+func (s *backupsSuite) TestDownload(c *gc.C) {
+	id := s.addBackup(c, "archive data", "")
+
+	archive, err := s.client.Download(id)
+	c.Assert(err, gc.IsNil)
+	defer archive.Close()
+	data, err := ioutil.ReadAll(archive)
+	c.Assert(err, gc.IsNil)
+	c.Check(string(data), gc.Equals, "archive data")
+}
+
+func (s *backupsSuite) TestDownloadNotFound(c *gc.C) {
+	_, err := s.client.Download("spam")
+	c.Check(err, gc.ErrorMatches, `backup metadata "spam" not found`)
+}
+
+// archive returns a minimal backup archive of the environment, along
+// with its checksum.
+func (s *backupsSuite) archive(c *gc.C) ([]byte, string) {
+	data, err := json.Marshal(state.NewBackupOrigin(s.State, "0"))
+	c.Assert(err, gc.IsNil)
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tarw := tar.NewWriter(gzw)
+	err = tarw.WriteHeader(&tar.Header{
+		Name: "juju-backup/metadata.json",
+		Mode: 0600,
+		Size: int64(len(data)),
+	})
+	c.Assert(err, gc.IsNil)
+	_, err = tarw.Write(data)
+	c.Assert(err, gc.IsNil)
+	c.Assert(tarw.Close(), gc.IsNil)
+	c.Assert(gzw.Close(), gc.IsNil)
+	hash := sha1.Sum(buf.Bytes())
+	return buf.Bytes(), base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func (s *backupsSuite) TestUpload(c *gc.C) {
+	data, checksum := s.archive(c)
+
+	result, err := s.client.Upload(bytes.NewReader(data), checksum)
+	c.Assert(err, gc.IsNil)
+	c.Check(result.Checksum, gc.Equals, checksum)
+	c.Check(result.Stored, jc.IsTrue)
+
+	archive, err := s.client.Download(result.ID)
+	c.Assert(err, gc.IsNil)
+	defer archive.Close()
+	downloaded, err := ioutil.ReadAll(archive)
+	c.Assert(err, gc.IsNil)
+	c.Check(downloaded, gc.DeepEquals, data)
+}
+
+func (s *backupsSuite) TestUploadChecksumMismatch(c *gc.C) {
+	data, _ := s.archive(c)
+
+	_, err := s.client.Upload(bytes.NewReader(data), "bogus")
+	c.Check(err, gc.ErrorMatches, `backup archive checksum mismatch: expected "bogus", got .*`)
+}
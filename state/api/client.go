@@ -105,6 +105,11 @@ type UnitStatus struct {
 	Life           string
 	Err            error
 
+	// WorkloadStatus and WorkloadStatusInfo hold the status of the
+	// unit's workload, as set by its charm.
+	WorkloadStatus     params.WorkloadStatus
+	WorkloadStatusInfo string
+
 	Machine       string
 	OpenedPorts   []string
 	PublicAddress string
@@ -152,9 +157,14 @@ type Status struct {
 
 // Status returns the status of the juju environment.
 func (c *Client) Status(patterns []string) (*Status, error) {
+	return c.FullStatus(params.StatusParams{Patterns: patterns})
+}
+
+// FullStatus returns the status of the juju environment, filtered as
+// described by args.
+func (c *Client) FullStatus(args params.StatusParams) (*Status, error) {
 	var result Status
-	p := params.StatusParams{Patterns: patterns}
-	if err := c.facade.FacadeCall("FullStatus", p, &result); err != nil {
+	if err := c.facade.FacadeCall("FullStatus", args, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -568,6 +578,27 @@ func (c *Client) SetEnvironAgentVersion(version version.Number) error {
 	return c.facade.FacadeCall("SetEnvironAgentVersion", args, nil)
 }
 
+// StartAgentUpgrade starts upgrading the environment's agents to the
+// given version. If canary machine ids are given, only the state
+// servers and those machines are upgraded until ContinueAgentUpgrade
+// is called.
+func (c *Client) StartAgentUpgrade(version version.Number, canaries []string) error {
+	args := params.StartAgentUpgrade{Version: version, Canaries: canaries}
+	return c.facade.FacadeCall("StartAgentUpgrade", args, nil)
+}
+
+// ContinueAgentUpgrade upgrades all the environment's agents once the
+// state servers and canary machines of a staged upgrade have upgraded.
+func (c *Client) ContinueAgentUpgrade() error {
+	return c.facade.FacadeCall("ContinueAgentUpgrade", nil, nil)
+}
+
+// RollBackAgentUpgrade returns the agents that have not yet run
+// upgrade steps to the version they ran before the last upgrade.
+func (c *Client) RollBackAgentUpgrade() error {
+	return c.facade.FacadeCall("RollBackAgentUpgrade", nil, nil)
+}
+
 // FindTools returns a List containing all tools matching the specified parameters.
 func (c *Client) FindTools(majorVersion, minorVersion int,
 	series, arch string) (result params.FindToolsResults, err error) {
@@ -599,6 +630,146 @@ func (c *Client) Run(run params.RunParams) ([]params.RunResult, error) {
 	return results.Results, err
 }
 
+// Metrics returns the metrics recorded by units that match the given
+// filter, ordered by time.
+func (c *Client) Metrics(filter params.MetricsFilter) ([]params.MetricResult, error) {
+	var results params.MetricsResults
+	err := c.facade.FacadeCall("Metrics", filter, &results)
+	return results.Metrics, err
+}
+
+// StatusHistory returns at most size statuses recently set for the
+// named unit or machine, most recent first.
+func (c *Client) StatusHistory(name string, size int) ([]params.StatusHistoryEntry, error) {
+	var results params.StatusHistoryResults
+	args := params.StatusHistory{Name: name, Size: size}
+	err := c.facade.FacadeCall("StatusHistory", args, &results)
+	return results.Statuses, err
+}
+
+// AuditLog returns the audit log entries that match the given filter,
+// oldest first.
+func (c *Client) AuditLog(filter params.AuditLogFilter) ([]params.AuditLogEntry, error) {
+	var results params.AuditLogResults
+	err := c.facade.FacadeCall("AuditLog", filter, &results)
+	return results.Entries, err
+}
+
+// DeployBundle deploys the services, units and relations described by
+// the given bundle, and returns the steps taken, in order. Deploying a
+// bundle again only takes the steps needed for the environment to
+// match it. The steps are returned even if the deployment fails.
+func (c *Client) DeployBundle(bundleYAML string) ([]string, error) {
+	var results params.DeployBundleResults
+	args := params.DeployBundle{YAML: bundleYAML}
+	if err := c.facade.FacadeCall("DeployBundle", args, &results); err != nil {
+		return nil, err
+	}
+	if results.Error != nil {
+		return results.Steps, results.Error
+	}
+	return results.Steps, nil
+}
+
+// ExportBundle returns a bundle, in YAML, that describes the services
+// deployed in the environment and the relations between them.
+func (c *Client) ExportBundle() (string, error) {
+	var result params.StringResult
+	if err := c.facade.FacadeCall("ExportBundle", nil, &result); err != nil {
+		return "", err
+	}
+	return result.Result, nil
+}
+
+// DiffBundle compares the given bundle with the environment, and
+// returns the differences between them.
+func (c *Client) DiffBundle(bundleYAML string) (params.BundleDiff, error) {
+	var diff params.BundleDiff
+	args := params.DiffBundle{YAML: bundleYAML}
+	err := c.facade.FacadeCall("DiffBundle", args, &diff)
+	return diff, err
+}
+
+// ServiceRollingUpgrade sets the charm of the given service, like
+// ServiceSetCharm, but upgrades its units batchSize at a time. A batch
+// is only upgraded once the units of the previous batch have run their
+// upgrade-charm hook without error and, if waitActive is true, report
+// an active workload status.
+func (c *Client) ServiceRollingUpgrade(serviceName, charmURL string, force bool, batchSize int, waitActive bool) error {
+	args := params.ServiceRollingUpgrade{
+		ServiceName: serviceName,
+		CharmUrl:    charmURL,
+		Force:       force,
+		BatchSize:   batchSize,
+		WaitActive:  waitActive,
+	}
+	return c.facade.FacadeCall("ServiceRollingUpgrade", args, nil)
+}
+
+// RollingUpgradeStatus returns the progress of the rolling upgrade of
+// the given service.
+func (c *Client) RollingUpgradeStatus(serviceName string) (params.RollingUpgradeStatus, error) {
+	var status params.RollingUpgradeStatus
+	args := params.RollingUpgrade{ServiceName: serviceName}
+	err := c.facade.FacadeCall("RollingUpgradeStatus", args, &status)
+	return status, err
+}
+
+// PauseRollingUpgrade stops further units of the given service from
+// being upgraded.
+func (c *Client) PauseRollingUpgrade(serviceName string) error {
+	args := params.RollingUpgrade{ServiceName: serviceName}
+	return c.facade.FacadeCall("PauseRollingUpgrade", args, nil)
+}
+
+// ResumeRollingUpgrade continues the paused rolling upgrade of the
+// given service.
+func (c *Client) ResumeRollingUpgrade(serviceName string) error {
+	args := params.RollingUpgrade{ServiceName: serviceName}
+	return c.facade.FacadeCall("ResumeRollingUpgrade", args, nil)
+}
+
+// AbortRollingUpgrade ends the rolling upgrade of the given service,
+// setting its charm back to the one it used before. Units already
+// upgraded are upgraded back to that charm.
+func (c *Client) AbortRollingUpgrade(serviceName string) error {
+	args := params.RollingUpgrade{ServiceName: serviceName}
+	return c.facade.FacadeCall("AbortRollingUpgrade", args, nil)
+}
+
+// EnqueueActions queues the given Actions on their receivers and
+// returns the tag and status of each queued Action.
+func (c *Client) EnqueueActions(actions ...params.ActionParams) ([]params.ActionStatusResult, error) {
+	var results params.ActionStatusResults
+	args := params.ActionsParams{Actions: actions}
+	err := c.facade.FacadeCall("EnqueueActions", args, &results)
+	return results.Results, err
+}
+
+// ListActions returns the queued and completed Actions for each of the
+// given receivers.
+func (c *Client) ListActions(receiverTags ...string) ([]params.ReceiverActionsResult, error) {
+	var results params.ReceiverActionsResults
+	args := params.Entities{Entities: make([]params.Entity, len(receiverTags))}
+	for i, tag := range receiverTags {
+		args.Entities[i] = params.Entity{Tag: tag}
+	}
+	err := c.facade.FacadeCall("ListActions", args, &results)
+	return results.Results, err
+}
+
+// ActionResults returns the current status of each of the Actions
+// with the given tags.
+func (c *Client) ActionResults(actionTags ...string) ([]params.ActionStatusResult, error) {
+	var results params.ActionStatusResults
+	args := params.Entities{Entities: make([]params.Entity, len(actionTags))}
+	for i, tag := range actionTags {
+		args.Entities[i] = params.Entity{Tag: tag}
+	}
+	err := c.facade.FacadeCall("ActionResults", args, &results)
+	return results.Results, err
+}
+
 // DestroyEnvironment puts the environment into a "dying" state,
 // and removes all non-manager machine instances. DestroyEnvironment
 // will fail if there are any manually-provisioned non-manager machines
@@ -855,6 +1026,17 @@ type DebugLogParams struct {
 	// Replay tells the server to start at the start of the log file rather
 	// than the end. If replay is true, backlog is ignored.
 	Replay bool
+	// Since and Until, if not zero, restrict the lines returned to those
+	// logged in that time range. If Since is set, all the lines logged
+	// since then are returned, and backlog is ignored.
+	Since time.Time
+	Until time.Time
+	// Grep, if not empty, is a regular expression that the messages of
+	// the lines returned must match.
+	Grep string
+	// NoTail tells the server to close the connection once the lines
+	// already logged have been sent, rather than waiting for new ones.
+	NoTail bool
 }
 
 // WatchDebugLog returns a ReadCloser that the caller can read the log
@@ -887,6 +1069,18 @@ func (c *Client) WatchDebugLog(args DebugLogParams) (io.ReadCloser, error) {
 	if args.Level != loggo.UNSPECIFIED {
 		attrs.Set("level", fmt.Sprint(args.Level))
 	}
+	if !args.Since.IsZero() {
+		attrs.Set("since", args.Since.UTC().Format(time.RFC3339Nano))
+	}
+	if !args.Until.IsZero() {
+		attrs.Set("until", args.Until.UTC().Format(time.RFC3339Nano))
+	}
+	if args.Grep != "" {
+		attrs.Set("grep", args.Grep)
+	}
+	if args.NoTail {
+		attrs.Set("noTail", fmt.Sprint(args.NoTail))
+	}
 	attrs["includeEntity"] = args.IncludeEntity
 	attrs["includeModule"] = args.IncludeModule
 	attrs["excludeEntity"] = args.ExcludeEntity
@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"code.google.com/p/go.net/websocket"
 	"github.com/juju/loggo"
@@ -166,6 +167,10 @@ func (s *clientSuite) TestParamsEncoded(c *gc.C) {
 		Backlog:       200,
 		Level:         loggo.ERROR,
 		Replay:        true,
+		Since:         time.Date(2014, 9, 1, 2, 10, 0, 0, time.UTC),
+		Until:         time.Date(2014, 9, 1, 2, 20, 0, 0, time.UTC),
+		Grep:          "hook failed",
+		NoTail:        true,
 	}
 
 	client := s.APIState.Client()
@@ -185,6 +190,10 @@ func (s *clientSuite) TestParamsEncoded(c *gc.C) {
 		"backlog":       {"200"},
 		"level":         {"ERROR"},
 		"replay":        {"true"},
+		"since":         {"2014-09-01T02:10:00Z"},
+		"until":         {"2014-09-01T02:20:00Z"},
+		"grep":          {"hook failed"},
+		"noTail":        {"true"},
 	})
 }
 
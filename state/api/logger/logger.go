@@ -71,3 +71,10 @@ func (st *State) WatchLoggingConfig(agentTag names.Tag) (watcher.NotifyWatcher,
 	w := watcher.NewNotifyWatcher(st.facade.RawAPICaller(), result)
 	return w, nil
 }
+
+// WriteLogs sends log records written by the agent to the state
+// server, which records them under the agent's tag.
+func (st *State) WriteLogs(records []params.LogRecord) error {
+	args := params.LogRecords{Records: records}
+	return st.facade.FacadeCall("WriteLogs", args, nil)
+}
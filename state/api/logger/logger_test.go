@@ -4,14 +4,21 @@
 package logger_test
 
 import (
+	"time"
+
+	"github.com/juju/loggo"
 	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
 	gc "launchpad.net/gocheck"
 
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api"
 	"github.com/juju/juju/state/api/logger"
+	"github.com/juju/juju/state/api/params"
 	"github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/logsender"
 )
 
 type loggerSuite struct {
@@ -77,3 +84,71 @@ func (s *loggerSuite) TestWatchLoggingConfig(c *gc.C) {
 	testing.AssertStop(c, watcher)
 	wc.AssertClosed()
 }
+
+func (s *loggerSuite) TestWriteLogs(c *gc.C) {
+	err := s.logger.WriteLogs([]params.LogRecord{{
+		Time:     time.Now(),
+		Module:   "juju.worker",
+		Location: "worker.go:42",
+		Level:    "INFO",
+		Message:  "hello",
+	}})
+	c.Assert(err, gc.IsNil)
+
+	tailer, err := s.BackingState.NewLogTailer(&state.LogTailerParams{FromTheStart: true})
+	c.Assert(err, gc.IsNil)
+	defer tailer.Stop()
+	select {
+	case record := <-tailer.Logs():
+		c.Assert(record.Entity, gc.Equals, s.rawMachine.Tag().String())
+		c.Assert(record.Level, gc.Equals, loggo.INFO)
+		c.Assert(record.Message, gc.Equals, "hello")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for log record")
+	}
+}
+
+// countingLogWriter passes log records on to the API, noting each
+// batch sent.
+type countingLogWriter struct {
+	logger  *logger.State
+	batches chan int
+}
+
+func (w *countingLogWriter) WriteLogs(records []params.LogRecord) error {
+	w.batches <- len(records)
+	return w.logger.WriteLogs(records)
+}
+
+func (s *loggerSuite) TestLogSenderGoesIdle(c *gc.C) {
+	// An agent running a state server at DEBUG level, with wire
+	// traces enabled, must not log the records it sends.
+	err := loggo.ConfigureLoggers("<root>=DEBUG;juju.rpc.jsoncodec=TRACE")
+	c.Assert(err, gc.IsNil)
+	logs := logsender.NewBufferedLogWriter(logsender.DefaultBufferSize)
+	err = loggo.RegisterWriter("logsender-test", logs, loggo.TRACE)
+	c.Assert(err, gc.IsNil)
+	defer loggo.RemoveWriter("logsender-test")
+
+	api := &countingLogWriter{logger: s.logger, batches: make(chan int, 100)}
+	w := logsender.New(logs, api)
+	defer func() {
+		w.Kill()
+		c.Assert(w.Wait(), gc.IsNil)
+	}()
+	loggo.GetLogger("juju.test").Infof("hello")
+
+	select {
+	case <-api.batches:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for log records to be sent")
+	}
+	for i := 0; ; i++ {
+		select {
+		case <-api.batches:
+			c.Assert(i < 10, jc.IsTrue, gc.Commentf("log sender did not go idle"))
+		case <-time.After(coretesting.ShortWait):
+			return
+		}
+	}
+}
@@ -82,3 +82,51 @@ func (status Status) Valid() bool {
 	}
 	return true
 }
+
+// WorkloadStatus describes the state of a unit's workload, as reported
+// by its charm. It is independent of the unit agent's Status.
+type WorkloadStatus string
+
+const (
+	// The charm has not reported the workload status.
+	WorkloadUnknown WorkloadStatus = "unknown"
+
+	// The unit is not yet providing service, but is actively doing
+	// work in preparation for providing it.
+	WorkloadMaintenance WorkloadStatus = "maintenance"
+
+	// The unit cannot continue without operator intervention.
+	WorkloadBlocked WorkloadStatus = "blocked"
+
+	// The unit is unable to progress to an active state because
+	// something it depends on, such as a relation, is not ready.
+	WorkloadWaiting WorkloadStatus = "waiting"
+
+	// The unit believes it is correctly offering all the services it
+	// has been asked to offer.
+	WorkloadActive WorkloadStatus = "active"
+)
+
+// Valid returns true if status has a known value that may be set by
+// a charm.
+func (status WorkloadStatus) Valid() bool {
+	switch status {
+	case
+		WorkloadMaintenance,
+		WorkloadBlocked,
+		WorkloadWaiting,
+		WorkloadActive:
+	default:
+		return false
+	}
+	return true
+}
+
+// These values report the progress of an Action queued through the
+// client API. ActionCompleted and ActionFailed match the end states
+// recorded in state.ActionResult.
+const (
+	ActionPending   = "pending"
+	ActionCompleted = "complete"
+	ActionFailed    = "fail"
+)
@@ -269,6 +269,7 @@ type Action struct {
 // the confusing name.
 type ActionResult struct {
 	ActionTag string
+	Results   map[string]interface{}
 	Output    string
 }
 
@@ -285,6 +286,26 @@ type EntitiesPorts struct {
 	Entities []EntityPort
 }
 
+// Metric holds a single metric reported by a unit.
+type Metric struct {
+	Key   string
+	Value string
+	Time  time.Time
+}
+
+// MetricsParam holds a batch of metrics reported by the entity with
+// the given tag.
+type MetricsParam struct {
+	Tag     string
+	Metrics []Metric
+}
+
+// MetricsParams holds the parameters for making an AddMetrics call on
+// some entities.
+type MetricsParams struct {
+	Metrics []MetricsParam
+}
+
 // EntityCharmURL holds an entity's tag and a charm URL.
 type EntityCharmURL struct {
 	Tag      string
@@ -444,6 +465,33 @@ type StatusResults struct {
 	Results []StatusResult
 }
 
+// EntityWorkloadStatus holds a unit tag and the workload status to set
+// for it.
+type EntityWorkloadStatus struct {
+	Tag    string
+	Status WorkloadStatus
+	Info   string
+}
+
+// SetWorkloadStatus holds the parameters for making a SetWorkloadStatus
+// call.
+type SetWorkloadStatus struct {
+	Entities []EntityWorkloadStatus
+}
+
+// WorkloadStatusResult holds a unit's workload status and message, or
+// an error.
+type WorkloadStatusResult struct {
+	Error  *Error
+	Status WorkloadStatus
+	Info   string
+}
+
+// WorkloadStatusResults holds multiple workload status results.
+type WorkloadStatusResults struct {
+	Results []WorkloadStatusResult
+}
+
 // MachineAddresses holds an machine tag and addresses.
 type MachineAddresses struct {
 	Tag       string
@@ -485,7 +533,11 @@ type AgentGetEntitiesResult struct {
 // DesiredVersion() API call.
 type VersionResult struct {
 	Version *version.Number
-	Error   *Error
+	// RollBack is true if the agent should return to Version
+	// because a staged upgrade was rolled back, even if Version
+	// is older than the version the agent runs.
+	RollBack bool
+	Error    *Error
 }
 
 // VersionResults is a list of versions for the requested entities.
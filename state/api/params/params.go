@@ -541,17 +541,19 @@ func (i *ServiceInfo) EntityId() EntityId {
 }
 
 type UnitInfo struct {
-	Name           string `bson:"_id"`
-	Service        string
-	Series         string
-	CharmURL       string
-	PublicAddress  string
-	PrivateAddress string
-	MachineId      string
-	Ports          []network.Port
-	Status         Status
-	StatusInfo     string
-	StatusData     StatusData
+	Name               string `bson:"_id"`
+	Service            string
+	Series             string
+	CharmURL           string
+	PublicAddress      string
+	PrivateAddress     string
+	MachineId          string
+	Ports              []network.Port
+	Status             Status
+	StatusInfo         string
+	StatusData         StatusData
+	WorkloadStatus     WorkloadStatus
+	WorkloadStatusInfo string
 }
 
 func (i *UnitInfo) EntityId() EntityId {
@@ -659,6 +661,14 @@ type SetEnvironAgentVersion struct {
 	Version version.Number
 }
 
+// StartAgentUpgrade contains the arguments for the StartAgentUpgrade
+// client API call. Canaries holds the ids of the machines upgraded
+// along with the state servers before the upgrade is continued.
+type StartAgentUpgrade struct {
+	Version  version.Number
+	Canaries []string
+}
+
 // DeployerConnectionValues containers the result of deployer.ConnectionInfo
 // API call.
 type DeployerConnectionValues struct {
@@ -669,6 +679,64 @@ type DeployerConnectionValues struct {
 // StatusParams holds parameters for the Status call.
 type StatusParams struct {
 	Patterns []string
+
+	// ErrorsOnly restricts the status to units and machines in an
+	// error state, along with the services and machines they need to
+	// be displayed.
+	ErrorsOnly bool
+}
+
+// ActionParams holds the parameters used to queue a single Action for
+// a unit or service. FirstUnitOnly and BatchSize only apply to Actions
+// queued for a service, and control how the Action is fanned out to
+// the service's units.
+type ActionParams struct {
+	Receiver      string
+	Name          string
+	Params        map[string]interface{}
+	FirstUnitOnly bool
+	BatchSize     int
+}
+
+// ActionsParams holds the parameters for the EnqueueActions call.
+type ActionsParams struct {
+	Actions []ActionParams
+}
+
+// ActionStatusResult holds the name, parameters and status of a single
+// queued or completed Action.
+type ActionStatusResult struct {
+	Error     *Error
+	ActionTag string
+	Name      string
+	Params    map[string]interface{}
+	Status    string
+	Results   map[string]interface{}
+	Output    string
+
+	// ValidationErrors holds each problem found with an Action that
+	// could not be queued because the charm does not define it, or
+	// its parameters do not satisfy the charm's schema for it.
+	ValidationErrors []string
+}
+
+// ActionStatusResults holds the results of the EnqueueActions and
+// ActionResults calls.
+type ActionStatusResults struct {
+	Results []ActionStatusResult
+}
+
+// ReceiverActionsResult holds the Actions queued and completed for a
+// single ActionReceiver.
+type ReceiverActionsResult struct {
+	Error    *Error
+	Receiver string
+	Actions  []ActionStatusResult
+}
+
+// ReceiverActionsResults holds the results of the ListActions call.
+type ReceiverActionsResults struct {
+	Results []ReceiverActionsResult
 }
 
 // SetRsyslogCertParams holds parameters for the SetRsyslogCert call.
@@ -768,3 +836,266 @@ type StateServersChanges struct {
 	Promoted   []string `json:promoted,omitempty`
 	Demoted    []string `json:demoted,omitempty`
 }
+
+// BackupsCreateArgs holds the args for the Backups API Create method.
+type BackupsCreateArgs struct {
+	Notes string
+}
+
+// BackupsInfoArgs holds the args for the Backups API Info method.
+type BackupsInfoArgs struct {
+	ID string
+}
+
+// BackupsListArgs holds the args for the Backups API List method.
+type BackupsListArgs struct {
+}
+
+// BackupsRemoveArgs holds the args for the Backups API Remove method.
+type BackupsRemoveArgs struct {
+	ID string
+}
+
+// BackupsLastGoodArgs holds the args for the Backups API LastGood
+// method.
+type BackupsLastGoodArgs struct{}
+
+// BackupsRestoreArgs holds the args for the Backups API Restore method.
+type BackupsRestoreArgs struct {
+	ID string
+}
+
+// BackupsMetadataResult holds the metadata for a backup as returned by
+// the Backups API.
+type BackupsMetadataResult struct {
+	ID             string
+	Started        time.Time
+	Finished       time.Time
+	Checksum       string
+	ChecksumFormat string
+	Size           int64
+	Stored         bool
+	Notes          string
+	Error          string
+
+	Environment string
+	Machine     string
+	Hostname    string
+	Version     version.Number
+}
+
+// BackupsListResult holds the list of all stored backups.
+type BackupsListResult struct {
+	List []BackupsMetadataResult
+}
+
+// MetricsFilter selects the metrics returned by the Client API Metrics
+// method. Empty fields do not restrict the selection.
+type MetricsFilter struct {
+	// Units and Services select the metrics recorded by the named
+	// units and by the units of the named services.
+	Units    []string
+	Services []string
+
+	// Keys selects the metrics with the given keys.
+	Keys []string
+
+	// Since and Until select the metrics recorded in the given time
+	// range, including Since and excluding Until.
+	Since time.Time
+	Until time.Time
+}
+
+// MetricResult holds a single metric recorded by a unit.
+type MetricResult struct {
+	Unit     string
+	CharmURL string
+	Key      string
+	Value    string
+	Time     time.Time
+}
+
+// MetricsResults holds the results of a Client API Metrics call.
+type MetricsResults struct {
+	Metrics []MetricResult
+}
+
+// StatusHistory holds the parameters of a Client API StatusHistory
+// call.
+type StatusHistory struct {
+	// Name is the name of a unit or the id of a machine.
+	Name string
+
+	// Size is the largest number of statuses returned.
+	Size int
+}
+
+// StatusHistoryEntry holds a status recorded in the status history of
+// a unit or machine.
+type StatusHistoryEntry struct {
+	Status Status
+	Info   string
+	Data   StatusData
+	Since  time.Time
+}
+
+// StatusHistoryResults holds the results of a Client API StatusHistory
+// call, most recent first.
+type StatusHistoryResults struct {
+	Statuses []StatusHistoryEntry
+}
+
+// AuditLogFilter selects the entries returned by the Client API
+// AuditLog method. Empty fields do not restrict the selection.
+type AuditLogFilter struct {
+	// User selects the entries recorded for the named user.
+	User string
+
+	// Entity selects the entries for changes made to the named
+	// service, unit or machine.
+	Entity string
+
+	// Since and Until select the entries recorded in the given time
+	// range, including Since and excluding Until.
+	Since time.Time
+	Until time.Time
+}
+
+// AuditLogEntry holds a change made to the environment through the
+// API.
+type AuditLogEntry struct {
+	Time    time.Time
+	User    string
+	Facade  string
+	Method  string
+	Targets []string
+	Args    string
+	Error   string
+}
+
+// AuditLogResults holds the results of a Client API AuditLog call,
+// oldest first.
+type AuditLogResults struct {
+	Entries []AuditLogEntry
+}
+
+// LogRecord holds a log message written by an agent. Level is the
+// name of a loggo level.
+type LogRecord struct {
+	Time     time.Time
+	Module   string
+	Location string
+	Level    string
+	Message  string
+}
+
+// LogRecords holds the log records sent by an agent with the Logger
+// API WriteLogs method.
+type LogRecords struct {
+	Records []LogRecord
+}
+
+// DeployBundle holds the parameters of a Client API DeployBundle call.
+type DeployBundle struct {
+	// YAML holds the bundle, in the format defined by the bundle
+	// package.
+	YAML string
+}
+
+// DeployBundleResults holds the results of a Client API DeployBundle
+// call: the steps taken to deploy the bundle, in order, and the error
+// that stopped the deployment, if any.
+type DeployBundleResults struct {
+	Steps []string
+	Error *Error
+}
+
+// DiffBundle holds the parameters of a Client API DiffBundle call.
+type DiffBundle struct {
+	// YAML holds the bundle, in the format defined by the bundle
+	// package.
+	YAML string
+}
+
+// BundleDiff holds the differences between a bundle and the
+// environment.
+type BundleDiff struct {
+	// ServicesToAdd holds the services in the bundle that are not
+	// deployed.
+	ServicesToAdd []string
+
+	// ServicesToRemove holds the deployed services that are not in
+	// the bundle.
+	ServicesToRemove []string
+
+	// Services holds the differences between the deployed services
+	// and the bundle, for each service that differs.
+	Services map[string]ServiceDiff
+
+	// MissingRelations holds the relations in the bundle that are not
+	// in the environment, by their endpoints.
+	MissingRelations [][]string
+
+	// ExtraRelations holds the relations in the environment that are
+	// not in the bundle, by their endpoints.
+	ExtraRelations [][]string
+}
+
+// ServiceDiff holds the differences between a deployed service and
+// the same service in a bundle. Fields that do not differ are nil.
+type ServiceDiff struct {
+	Charm       *ValueDiff
+	Options     map[string]ValueDiff
+	Constraints *ValueDiff
+	NumUnits    *ValueDiff
+	Expose      *ValueDiff
+}
+
+// ValueDiff holds a value that differs between a bundle and the
+// environment.
+type ValueDiff struct {
+	Bundle      interface{}
+	Environment interface{}
+}
+
+// ServiceRollingUpgrade holds the parameters of a Client API
+// ServiceRollingUpgrade call, which sets the charm of a service but
+// upgrades its units a batch at a time.
+type ServiceRollingUpgrade struct {
+	ServiceName string
+	CharmUrl    string
+	Force       bool
+
+	// BatchSize holds the number of units upgraded at a time.
+	BatchSize int
+
+	// WaitActive holds whether the units of a batch must report an
+	// active workload status before the next batch is upgraded.
+	WaitActive bool
+}
+
+// RollingUpgrade identifies the rolling upgrade of a service.
+type RollingUpgrade struct {
+	ServiceName string
+}
+
+// RollingUpgradeStatus holds the progress of the rolling upgrade of a
+// service.
+type RollingUpgradeStatus struct {
+	ServiceName  string
+	FromCharmURL string
+	ToCharmURL   string
+	BatchSize    int
+	WaitActive   bool
+
+	// Batch holds the units being upgraded, and BatchStarted the time
+	// they were released to upgrade.
+	Batch        []string
+	BatchStarted time.Time
+
+	// HeldUnits holds the units waiting to be upgraded.
+	HeldUnits []string
+
+	Paused      bool
+	PauseReason string
+}
@@ -85,7 +85,9 @@ func (s *actionSuite) TestActionComplete(c *gc.C) {
 	action, err := s.uniterSuite.wordpressUnit.AddAction("gabloxi", nil)
 	c.Assert(err, gc.IsNil)
 
-	err = s.uniter.ActionComplete(action.ActionTag(), "it worked!")
+	err = s.uniter.ActionComplete(action.ActionTag(), "it worked!", map[string]interface{}{
+		"outfile": "/tmp/out.tgz",
+	})
 	c.Assert(err, gc.IsNil)
 
 	results, err = s.uniterSuite.wordpressUnit.ActionResults()
@@ -93,6 +95,9 @@ func (s *actionSuite) TestActionComplete(c *gc.C) {
 	c.Assert(len(results), gc.Equals, 1)
 	c.Assert(results[0].Status(), gc.Equals, state.ActionCompleted)
 	c.Assert(results[0].Output(), gc.Equals, "it worked!")
+	c.Assert(results[0].Results(), gc.DeepEquals, map[string]interface{}{
+		"outfile": "/tmp/out.tgz",
+	})
 	c.Assert(results[0].ActionName(), gc.Equals, "gabloxi")
 }
 
@@ -104,7 +109,7 @@ func (s *actionSuite) TestActionFail(c *gc.C) {
 	action, err := s.uniterSuite.wordpressUnit.AddAction("beebz", nil)
 	c.Assert(err, gc.IsNil)
 
-	err = s.uniter.ActionFail(action.ActionTag(), "it failed!")
+	err = s.uniter.ActionFail(action.ActionTag(), "it failed!", nil)
 	c.Assert(err, gc.IsNil)
 
 	results, err = s.uniterSuite.wordpressUnit.ActionResults()
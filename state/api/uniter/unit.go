@@ -67,6 +67,43 @@ func (u *Unit) SetStatus(status params.Status, info string, data params.StatusDa
 	return result.OneError()
 }
 
+// SetWorkloadStatus sets the status of the unit's workload, along with
+// a message for the operator.
+func (u *Unit) SetWorkloadStatus(status params.WorkloadStatus, info string) error {
+	var result params.ErrorResults
+	args := params.SetWorkloadStatus{
+		Entities: []params.EntityWorkloadStatus{
+			{Tag: u.tag.String(), Status: status, Info: info},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetWorkloadStatus", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// WorkloadStatus returns the status of the unit's workload and the
+// accompanying message.
+func (u *Unit) WorkloadStatus() (params.WorkloadStatus, string, error) {
+	var results params.WorkloadStatusResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("WorkloadStatus", args, &results)
+	if err != nil {
+		return "", "", err
+	}
+	if len(results.Results) != 1 {
+		return "", "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", "", result.Error
+	}
+	return result.Status, result.Info, nil
+}
+
 // EnsureDead sets the unit lifecycle to Dead if it is Alive or
 // Dying. It does nothing otherwise.
 func (u *Unit) EnsureDead() error {
@@ -233,6 +270,31 @@ func (u *Unit) HasSubordinates() (bool, error) {
 	return result.Result, nil
 }
 
+// CharmUpgradeHeld returns whether the unit is held back from upgrading
+// to its service's charm by a rolling upgrade. Units of state servers
+// that do not support rolling upgrades are never held.
+func (u *Unit) CharmUpgradeHeld() (bool, error) {
+	var results params.BoolResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("CharmUpgradeHeld", args, &results)
+	if params.IsCodeNotImplemented(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if len(results.Results) != 1 {
+		return false, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
+}
+
 // PublicAddress returns the public address of the unit and whether it
 // is valid.
 //
@@ -325,6 +387,22 @@ func (u *Unit) ClosePort(protocol string, number int) error {
 	return result.OneError()
 }
 
+// AddMetrics adds a batch of metrics for the unit, recorded against
+// the charm the unit is running.
+func (u *Unit) AddMetrics(metrics []params.Metric) error {
+	var result params.ErrorResults
+	args := params.MetricsParams{
+		Metrics: []params.MetricsParam{
+			{Tag: u.tag.String(), Metrics: metrics},
+		},
+	}
+	err := u.st.facade.FacadeCall("AddMetrics", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
 var ErrNoCharmURLSet = errors.New("unit has no charm url set")
 
 // CharmURL returns the charm URL this unit is currently using.
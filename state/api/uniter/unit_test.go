@@ -6,6 +6,7 @@ package uniter_test
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -66,6 +67,26 @@ func (s *unitSuite) TestSetStatus(c *gc.C) {
 	c.Assert(data, gc.HasLen, 0)
 }
 
+func (s *unitSuite) TestGetSetWorkloadStatus(c *gc.C) {
+	status, info, err := s.apiUnit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.WorkloadUnknown)
+	c.Assert(info, gc.Equals, "")
+
+	err = s.apiUnit.SetWorkloadStatus(params.WorkloadWaiting, "waiting for database relation")
+	c.Assert(err, gc.IsNil)
+
+	status, info, err = s.wordpressUnit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.WorkloadWaiting)
+	c.Assert(info, gc.Equals, "waiting for database relation")
+
+	status, info, err = s.apiUnit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.WorkloadWaiting)
+	c.Assert(info, gc.Equals, "waiting for database relation")
+}
+
 func (s *unitSuite) TestEnsureDead(c *gc.C) {
 	c.Assert(s.wordpressUnit.Life(), gc.Equals, state.Alive)
 
@@ -201,6 +222,22 @@ func (s *unitSuite) TestHasSubordinates(c *gc.C) {
 	c.Assert(found, jc.IsTrue)
 }
 
+func (s *unitSuite) TestCharmUpgradeHeld(c *gc.C) {
+	held, err := s.apiUnit.CharmUpgradeHeld()
+	c.Assert(err, gc.IsNil)
+	c.Assert(held, jc.IsFalse)
+
+	_, err = s.wordpressService.StartRollingUpgrade(state.RollingUpgradeParams{
+		Charm:     s.AddTestingCharm(c, "dummy"),
+		BatchSize: 1,
+	})
+	c.Assert(err, gc.IsNil)
+
+	held, err = s.apiUnit.CharmUpgradeHeld()
+	c.Assert(err, gc.IsNil)
+	c.Assert(held, jc.IsTrue)
+}
+
 func (s *unitSuite) TestPublicAddress(c *gc.C) {
 	address, err := s.apiUnit.PublicAddress()
 	c.Assert(err, gc.ErrorMatches, `"unit-wordpress-0" has no public address set`)
@@ -263,6 +300,24 @@ func (s *unitSuite) TestOpenClosePort(c *gc.C) {
 	c.Assert(ports, gc.HasLen, 0)
 }
 
+func (s *unitSuite) TestAddMetrics(c *gc.C) {
+	err := s.wordpressUnit.SetCharmURL(s.wordpressCharm.URL())
+	c.Assert(err, gc.IsNil)
+	now := time.Now().Round(time.Second).UTC()
+
+	err = s.apiUnit.AddMetrics([]params.Metric{{Key: "pings", Value: "5", Time: now}})
+	c.Assert(err, gc.IsNil)
+
+	batches, err := s.State.MetricBatches(state.MetricFilter{Units: []string{s.wordpressUnit.Name()}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(batches, gc.HasLen, 1)
+	metrics := batches[0].Metrics()
+	c.Assert(metrics, gc.HasLen, 1)
+	c.Assert(metrics[0].Key(), gc.Equals, "pings")
+	c.Assert(metrics[0].Value(), gc.Equals, "5")
+	c.Assert(metrics[0].Time().Equal(now), jc.IsTrue)
+}
+
 func (s *unitSuite) TestGetSetCharmURL(c *gc.C) {
 	// No charm URL set yet.
 	curl, ok := s.wordpressUnit.CharmURL()
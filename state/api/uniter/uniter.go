@@ -177,15 +177,19 @@ func (st *State) Action(tag names.ActionTag) (*Action, error) {
 	}, nil
 }
 
-func (st *State) ActionComplete(tag names.ActionTag, output string) error {
+// ActionComplete records the given output and results for the Action
+// with the given tag, and marks it as completed.
+func (st *State) ActionComplete(tag names.ActionTag, output string, results map[string]interface{}) error {
 	var result params.BoolResult
-	args := params.ActionResult{ActionTag: tag.String(), Output: output}
+	args := params.ActionResult{ActionTag: tag.String(), Results: results, Output: output}
 	return st.facade.FacadeCall("ActionComplete", args, &result)
 }
 
-func (st *State) ActionFail(tag names.ActionTag, errorMessage string) error {
+// ActionFail records the given failure message, and any results set
+// before the failure, for the Action with the given tag.
+func (st *State) ActionFail(tag names.ActionTag, errorMessage string, results map[string]interface{}) error {
 	var result params.BoolResult
-	args := params.ActionResult{ActionTag: tag.String(), Output: errorMessage}
+	args := params.ActionResult{ActionTag: tag.String(), Results: results, Output: errorMessage}
 	return st.facade.FacadeCall("ActionFail", args, &result)
 }
 
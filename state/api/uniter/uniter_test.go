@@ -38,6 +38,19 @@ func TestAll(t *stdtesting.T) {
 	coretesting.MgoTestPackage(t)
 }
 
+// testActionsYaml defines the actions queued on units by these tests.
+var testActionsYaml = `
+actions:
+   snapshot:
+      description: Take a snapshot of the database.
+   backup:
+      description: Back up the database.
+   gabloxi:
+      description: Gabloxify the service.
+   beebz:
+      description: Beebz the service.
+`
+
 func (s *uniterSuite) SetUpTest(c *gc.C) {
 	s.setUpTest(c, true)
 }
@@ -50,7 +63,9 @@ func (s *uniterSuite) setUpTest(c *gc.C, addStateServer bool) {
 	}
 
 	// Create a machine, a service and add a unit so we can log in as
-	// its agent.
+	// its agent. The wordpress charm is added first with the actions
+	// the tests queue, so it is used in place of the testing charm.
+	s.AddActionsCharm(c, "wordpress", testActionsYaml)
 	s.wordpressMachine, s.wordpressService, s.wordpressCharm, s.wordpressUnit = s.addMachineServiceCharmAndUnit(c, "wordpress")
 	password, err := utils.RandomPassword()
 	c.Assert(err, gc.IsNil)
@@ -45,7 +45,18 @@ func (st *State) SetVersion(tag string, v version.Binary) error {
 	return results.OneError()
 }
 
+// DesiredVersion returns the agent version the entity with the given
+// tag should run.
 func (st *State) DesiredVersion(tag string) (version.Number, error) {
+	vers, _, err := st.DesiredVersionWithRollBack(tag)
+	return vers, err
+}
+
+// DesiredVersionWithRollBack returns the agent version the entity with
+// the given tag should run, and whether it should return to that
+// version, even if it is older than the version it runs, because a
+// staged upgrade was rolled back.
+func (st *State) DesiredVersionWithRollBack(tag string) (version.Number, bool, error) {
 	var results params.VersionResults
 	args := params.Entities{
 		Entities: []params.Entity{{Tag: tag}},
@@ -53,21 +64,39 @@ func (st *State) DesiredVersion(tag string) (version.Number, error) {
 	err := st.facade.FacadeCall("DesiredVersion", args, &results)
 	if err != nil {
 		// TODO: Not directly tested
-		return version.Number{}, err
+		return version.Number{}, false, err
 	}
 	if len(results.Results) != 1 {
 		// TODO: Not directly tested
-		return version.Number{}, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+		return version.Number{}, false, fmt.Errorf("expected 1 result, got %d", len(results.Results))
 	}
 	result := results.Results[0]
 	if err := result.Error; err != nil {
-		return version.Number{}, err
+		return version.Number{}, false, err
 	}
 	if result.Version == nil {
 		// TODO: Not directly tested
-		return version.Number{}, fmt.Errorf("received no error, but got a nil Version")
+		return version.Number{}, false, fmt.Errorf("received no error, but got a nil Version")
+	}
+	return *result.Version, result.RollBack, nil
+}
+
+// SetUpgradeStepsStarted records that the machine agent with the given
+// tag is about to run the upgrade steps for the given version. It
+// fails if the upgrade to that version has been rolled back.
+func (st *State) SetUpgradeStepsStarted(tag string, v version.Number) error {
+	var results params.ErrorResults
+	args := params.EntitiesVersion{
+		AgentTools: []params.EntityVersion{{
+			Tag:   tag,
+			Tools: &params.Version{version.Binary{Number: v}},
+		}},
 	}
-	return *result.Version, nil
+	err := st.facade.FacadeCall("SetUpgradeStepsStarted", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
 }
 
 // Tools returns the agent tools that should run on the given entity,
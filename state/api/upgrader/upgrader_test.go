@@ -152,3 +152,37 @@ func (s *machineUpgraderSuite) TestDesiredVersion(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	c.Assert(stateVersion, gc.Equals, cur.Number)
 }
+
+func (s *machineUpgraderSuite) TestDesiredVersionWithRollBack(c *gc.C) {
+	err := s.rawMachine.SetAgentVersion(version.Current)
+	c.Assert(err, gc.IsNil)
+	newer := version.Current.Number
+	newer.Patch++
+	au, err := s.State.StartAgentUpgrade(newer, []string{s.rawMachine.Id()})
+	c.Assert(err, gc.IsNil)
+	err = au.RollBack()
+	c.Assert(err, gc.IsNil)
+
+	stateVersion, rollBack, err := s.st.DesiredVersionWithRollBack(s.rawMachine.Tag().String())
+	c.Assert(err, gc.IsNil)
+	c.Assert(stateVersion, gc.Equals, version.Current.Number)
+	c.Assert(rollBack, jc.IsTrue)
+}
+
+func (s *machineUpgraderSuite) TestSetUpgradeStepsStarted(c *gc.C) {
+	err := s.rawMachine.SetAgentVersion(version.Current)
+	c.Assert(err, gc.IsNil)
+	newer := version.Current.Number
+	newer.Patch++
+	au, err := s.State.StartAgentUpgrade(newer, []string{s.rawMachine.Id()})
+	c.Assert(err, gc.IsNil)
+
+	err = s.st.SetUpgradeStepsStarted("machine-42", newer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(err, jc.Satisfies, params.IsCodeUnauthorized)
+
+	err = au.RollBack()
+	c.Assert(err, gc.IsNil)
+	err = s.st.SetUpgradeStepsStarted(s.rawMachine.Tag().String(), newer)
+	c.Assert(err, gc.ErrorMatches, "cannot start upgrade steps of machine .*: agent upgrade to .* rolled back")
+}
@@ -26,12 +26,20 @@ func NewClient(st base.APICallCloser) *Client {
 	return &Client{ClientFacade: frontend, facade: backend}
 }
 
-func (c *Client) AddUser(username, displayName, password string) error {
+// AddUser adds a user with the given access to the environment:
+// "read", "write" or "admin". If access is empty, the user is given
+// admin access.
+func (c *Client) AddUser(username, displayName, password, access string) error {
 	if !names.IsValidUser(username) {
 		return fmt.Errorf("invalid user name %q", username)
 	}
 	userArgs := usermanager.ModifyUsers{
-		Changes: []usermanager.ModifyUser{{Username: username, DisplayName: displayName, Password: password}},
+		Changes: []usermanager.ModifyUser{{
+			Username:    username,
+			DisplayName: displayName,
+			Password:    password,
+			Access:      access,
+		}},
 	}
 	results := new(params.ErrorResults)
 	err := c.facade.FacadeCall("AddUser", userArgs, results)
@@ -83,3 +91,91 @@ func (c *Client) SetPassword(username, password string) error {
 	}
 	return results.OneError()
 }
+
+// SetAccess sets the user's access to the environment: "read",
+// "write" or "admin".
+func (c *Client) SetAccess(username, access string) error {
+	userArgs := usermanager.ModifyUsers{
+		Changes: []usermanager.ModifyUser{{
+			Username: username,
+			Access:   access,
+		}},
+	}
+	results := new(params.ErrorResults)
+	err := c.facade.FacadeCall("SetAccess", userArgs, results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ListUsers returns information on all the users, including the
+// disabled ones, ordered by name.
+func (c *Client) ListUsers() ([]usermanager.UserInfo, error) {
+	results := new(usermanager.UserInfoResults)
+	err := c.facade.FacadeCall("ListUsers", nil, results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	infos := make([]usermanager.UserInfo, len(results.Results))
+	for i, result := range results.Results {
+		if result.Error != nil {
+			return nil, errors.Trace(result.Error)
+		}
+		infos[i] = *result.Result
+	}
+	return infos, nil
+}
+
+// DisableUser disables the user, who can then no longer log in, and drops
+// the user's API connections.
+func (c *Client) DisableUser(username string) error {
+	return c.userCall("DisableUser", username)
+}
+
+// EnableUser enables the user, who can then log in again.
+func (c *Client) EnableUser(username string) error {
+	return c.userCall("EnableUser", username)
+}
+
+func (c *Client) userCall(method, username string) error {
+	if !names.IsValidUser(username) {
+		return fmt.Errorf("invalid user name %q", username)
+	}
+	p := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewUserTag(username).String()}},
+	}
+	results := new(params.ErrorResults)
+	err := c.facade.FacadeCall(method, p, results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ClearUserLockout forgets the failed logins made as the user, so that
+// the user can log in again at once.
+func (c *Client) ClearUserLockout(username string) error {
+	if !names.IsValidUser(username) {
+		return fmt.Errorf("invalid user name %q", username)
+	}
+	return c.clearLoginLockout(usermanager.LoginLockout{Username: username})
+}
+
+// ClearAddressLockout forgets the failed logins made from the address,
+// so that users can log in from it again at once.
+func (c *Client) ClearAddressLockout(address string) error {
+	return c.clearLoginLockout(usermanager.LoginLockout{Address: address})
+}
+
+func (c *Client) clearLoginLockout(lockout usermanager.LoginLockout) error {
+	p := usermanager.LoginLockouts{
+		Lockouts: []usermanager.LoginLockout{lockout},
+	}
+	results := new(params.ErrorResults)
+	err := c.facade.FacadeCall("ClearLoginLockouts", p, results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
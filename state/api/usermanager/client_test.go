@@ -4,6 +4,8 @@
 package usermanager_test
 
 import (
+	"time"
+
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "launchpad.net/gocheck"
@@ -31,12 +33,20 @@ func (s *usermanagerSuite) SetUpTest(c *gc.C) {
 }
 
 func (s *usermanagerSuite) TestAddUser(c *gc.C) {
-	err := s.usermanager.AddUser("foobar", "Foo Bar", "password")
+	err := s.usermanager.AddUser("foobar", "Foo Bar", "password", "")
 	c.Assert(err, gc.IsNil)
 	_, err = s.State.User("foobar")
 	c.Assert(err, gc.IsNil)
 }
 
+func (s *usermanagerSuite) TestAddUserWithAccess(c *gc.C) {
+	err := s.usermanager.AddUser("foobar", "Foo Bar", "password", "read")
+	c.Assert(err, gc.IsNil)
+	user, err := s.State.User("foobar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.Access(), gc.Equals, state.UserAccessRead)
+}
+
 func (s *usermanagerSuite) TestAddUserOldClient(c *gc.C) {
 	userArgs := params.EntityPasswords{
 		Changes: []params.EntityPassword{{Tag: "foobar", Password: "password"}},
@@ -52,7 +62,7 @@ func (s *usermanagerSuite) TestAddUserOldClient(c *gc.C) {
 }
 
 func (s *usermanagerSuite) TestRemoveUser(c *gc.C) {
-	err := s.usermanager.AddUser("foobar", "Foo Bar", "password")
+	err := s.usermanager.AddUser("foobar", "Foo Bar", "password", "")
 	c.Assert(err, gc.IsNil)
 	_, err = s.State.User("foobar")
 	c.Assert(err, gc.IsNil)
@@ -64,11 +74,11 @@ func (s *usermanagerSuite) TestRemoveUser(c *gc.C) {
 }
 
 func (s *usermanagerSuite) TestAddExistingUser(c *gc.C) {
-	err := s.usermanager.AddUser("foobar", "Foo Bar", "password")
+	err := s.usermanager.AddUser("foobar", "Foo Bar", "password", "")
 	c.Assert(err, gc.IsNil)
 
 	// Try adding again
-	err = s.usermanager.AddUser("foobar", "Foo Bar", "password")
+	err = s.usermanager.AddUser("foobar", "Foo Bar", "password", "")
 	c.Assert(err, gc.ErrorMatches, "failed to create user: user already exists")
 }
 
@@ -89,6 +99,7 @@ func (s *usermanagerSuite) TestUserInfo(c *gc.C) {
 			DisplayName: "Foo Bar",
 			CreatedBy:   "admin",
 			DateCreated: user.DateCreated(),
+			Access:      "admin",
 		},
 	}
 
@@ -130,3 +141,74 @@ func (s *usermanagerSuite) TestSetUserPassword(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	c.Assert(user.PasswordValid("new-password"), gc.Equals, true)
 }
+
+func (s *usermanagerSuite) TestSetAccess(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
+	err := s.usermanager.SetAccess("foobar", "read")
+	c.Assert(err, gc.IsNil)
+	user, err := s.State.User("foobar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.Access(), gc.Equals, state.UserAccessRead)
+
+	err = s.usermanager.SetAccess("admin", "read")
+	c.Assert(err, gc.ErrorMatches, "failed to set access: cannot change access of admin user")
+}
+
+func (s *usermanagerSuite) TestListUsers(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", Access: state.UserAccessRead})
+	err := s.usermanager.DisableUser("foobar")
+	c.Assert(err, gc.IsNil)
+
+	infos, err := s.usermanager.ListUsers()
+	c.Assert(err, gc.IsNil)
+	c.Assert(infos, gc.HasLen, 2)
+	c.Assert(infos[0].Username, gc.Equals, "admin")
+	c.Assert(infos[0].Disabled, jc.IsFalse)
+	c.Assert(infos[1].Username, gc.Equals, "foobar")
+	c.Assert(infos[1].Access, gc.Equals, "read")
+	c.Assert(infos[1].Disabled, jc.IsTrue)
+}
+
+func (s *usermanagerSuite) TestDisableEnableUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
+
+	err := s.usermanager.DisableUser("foobar")
+	c.Assert(err, gc.IsNil)
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.IsDeactivated(), jc.IsTrue)
+
+	err = s.usermanager.EnableUser("foobar")
+	c.Assert(err, gc.IsNil)
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.IsDeactivated(), jc.IsFalse)
+}
+
+func (s *usermanagerSuite) TestCantDisableAdminUser(c *gc.C) {
+	err := s.usermanager.DisableUser(state.AdminUser)
+	c.Assert(err, gc.ErrorMatches, "cannot disable admin user")
+}
+
+func (s *usermanagerSuite) TestClearLockouts(c *gc.C) {
+	lockedUntil := time.Now().Add(time.Hour)
+	err := s.State.LockOutLogins(state.UserLoginAttemptsKey("foobar", "10.0.0.1"), lockedUntil)
+	c.Assert(err, gc.IsNil)
+	err = s.State.LockOutLogins(state.AddressLoginAttemptsKey("10.0.0.1"), lockedUntil)
+	c.Assert(err, gc.IsNil)
+
+	err = s.usermanager.ClearUserLockout("foobar")
+	c.Assert(err, gc.IsNil)
+	attempts, err := s.State.LoginAttempts(state.UserLoginAttemptsKey("foobar", "10.0.0.1"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(attempts.LockedUntil.IsZero(), jc.IsTrue)
+
+	err = s.usermanager.ClearAddressLockout("10.0.0.1")
+	c.Assert(err, gc.IsNil)
+	attempts, err = s.State.LoginAttempts(state.AddressLoginAttemptsKey("10.0.0.1"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(attempts.LockedUntil.IsZero(), jc.IsTrue)
+
+	err = s.usermanager.ClearAddressLockout("nowhere")
+	c.Assert(err, gc.ErrorMatches, `address "nowhere" not valid`)
+}
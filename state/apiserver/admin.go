@@ -10,6 +10,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	"github.com/juju/utils"
+	"launchpad.net/tomb"
 
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/state"
@@ -17,9 +18,10 @@ import (
 	"github.com/juju/juju/state/apiserver/authentication"
 	"github.com/juju/juju/state/apiserver/common"
 	"github.com/juju/juju/state/presence"
+	"github.com/juju/juju/state/watcher"
 )
 
-func newStateServer(srv *Server, rpcConn *rpc.Conn, reqNotifier *requestNotifier, limiter utils.Limiter) *initialRoot {
+func newStateServer(srv *Server, rpcConn *rpc.Conn, reqNotifier *requestNotifier, limiter utils.Limiter, remoteAddr string) *initialRoot {
 	r := &initialRoot{
 		srv:     srv,
 		rpcConn: rpcConn,
@@ -27,8 +29,10 @@ func newStateServer(srv *Server, rpcConn *rpc.Conn, reqNotifier *requestNotifier
 	r.admin = &srvAdmin{
 		root:        r,
 		limiter:     limiter,
+		throttle:    &loginThrottle{srv.state},
 		validator:   srv.validator,
 		reqNotifier: reqNotifier,
+		remoteAddr:  remoteAddr,
 	}
 	return r
 }
@@ -60,10 +64,12 @@ func (r *initialRoot) Admin(id string) (*srvAdmin, error) {
 type srvAdmin struct {
 	mu          sync.Mutex
 	limiter     utils.Limiter
+	throttle    *loginThrottle
 	validator   LoginValidator
 	root        *initialRoot
 	loggedIn    bool
 	reqNotifier *requestNotifier
+	remoteAddr  string
 }
 
 var UpgradeInProgressError = errors.New("upgrade in progress")
@@ -100,10 +106,21 @@ func (a *srvAdmin) Login(c params.Creds) (params.LoginResult, error) {
 		}
 		defer a.limiter.Release()
 	}
+	if err := a.throttle.check(c.AuthTag, a.remoteAddr, time.Now()); err != nil {
+		return params.LoginResult{}, err
+	}
 	entity, err := doCheckCreds(a.root.srv.state, c)
+	if err == common.ErrBadCreds {
+		if err := a.throttle.failed(c.AuthTag, a.remoteAddr, time.Now()); err != nil {
+			logger.Errorf("cannot record failed login: %v", err)
+		}
+	}
 	if err != nil {
 		return params.LoginResult{}, err
 	}
+	if err := a.throttle.succeeded(c.AuthTag, a.remoteAddr); err != nil {
+		return params.LoginResult{}, err
+	}
 	if a.reqNotifier != nil {
 		a.reqNotifier.login(entity.Tag().String())
 	}
@@ -118,6 +135,7 @@ func (a *srvAdmin) Login(c params.Creds) (params.LoginResult, error) {
 	if err := a.startPingerIfAgent(newRoot, entity); err != nil {
 		return params.LoginResult{}, err
 	}
+	a.closeOnUserChange(newRoot, entity)
 
 	// Fetch the API server addresses from state.
 	hostPorts, err := a.root.srv.state.APIHostPorts()
@@ -229,6 +247,79 @@ func (a *srvAdmin) startPingerIfAgent(newRoot apiRoot, entity state.Entity) erro
 	return nil
 }
 
+// closeOnUserChange closes the connection of a user that has logged in
+// as soon as the user is deactivated or their access changes, so that
+// disabled users lose their access at once, and the methods allowed on
+// open connections always follow the user's current access.
+func (a *srvAdmin) closeOnUserChange(newRoot apiRoot, entity state.Entity) {
+	user, ok := entity.(*state.User)
+	if !ok {
+		return
+	}
+	action := func() {
+		if err := newRoot.getRpcConn().Close(); err != nil {
+			logger.Errorf("error closing the RPC connection: %v", err)
+		}
+	}
+	newRoot.getResources().Register(newUserChangeWatcher(a.root.srv.state, user, action))
+}
+
+// userChangeWatcher invokes an action when the user it watches is
+// deactivated or removed, or given a different access.
+type userChangeWatcher struct {
+	tomb   tomb.Tomb
+	st     *state.State
+	user   *state.User
+	action func()
+}
+
+func newUserChangeWatcher(st *state.State, user *state.User, action func()) *userChangeWatcher {
+	w := &userChangeWatcher{
+		st:     st,
+		user:   user,
+		action: action,
+	}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Stop stops the watcher.
+func (w *userChangeWatcher) Stop() error {
+	w.tomb.Kill(nil)
+	return w.tomb.Wait()
+}
+
+func (w *userChangeWatcher) loop() error {
+	access := w.user.Access()
+	userWatcher := w.user.Watch()
+	defer watcher.Stop(userWatcher, &w.tomb)
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return nil
+		case _, ok := <-userWatcher.Changes():
+			if !ok {
+				return watcher.MustErr(userWatcher)
+			}
+			// The user is read again, rather than refreshed, because
+			// the connection's root may be using it.
+			user, err := w.st.User(w.user.Name())
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			if err != nil || user.IsDeactivated() || user.Access() != access {
+				// The action closes the connection, which stops
+				// this watcher, so it must not be waited for.
+				go w.action()
+				return nil
+			}
+		}
+	}
+}
+
 // errRoot implements the API that a client first sees
 // when connecting to the API. It exposes the same API as initialRoot, except
 // it returns the requested error when the client makes any request.
@@ -130,6 +130,174 @@ func (s *loginSuite) TestLoginAsDeactivatedUser(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `unknown object type "Client"`)
 }
 
+func (s *loginSuite) TestDeactivatingUserDropsConnection(c *gc.C) {
+	info, cleanup := s.setupServer(c)
+	defer cleanup()
+
+	password := "password"
+	u := s.Factory.MakeUser(c, &factory.UserParams{Password: password})
+	info.Tag = u.Tag()
+	info.Password = password
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+	_, err = st.Client().Status([]string{})
+	c.Assert(err, gc.IsNil)
+
+	err = u.Deactivate()
+	c.Assert(err, gc.IsNil)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		s.State.StartSync()
+		if _, err = st.Client().Status([]string{}); err != nil {
+			break
+		}
+	}
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *loginSuite) TestChangingUserAccessDropsConnection(c *gc.C) {
+	info, cleanup := s.setupServer(c)
+	defer cleanup()
+
+	password := "password"
+	u := s.Factory.MakeUser(c, &factory.UserParams{Password: password, Access: state.UserAccessWrite})
+	info.Tag = u.Tag()
+	info.Password = password
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+	_, err = st.Client().Status([]string{})
+	c.Assert(err, gc.IsNil)
+
+	err = u.SetAccess(state.UserAccessRead)
+	c.Assert(err, gc.IsNil)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		s.State.StartSync()
+		if _, err = st.Client().Status([]string{}); err != nil {
+			break
+		}
+	}
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *loginSuite) TestFailedLoginsAreDelayed(c *gc.C) {
+	info, cleanup := s.setupServer(c)
+	defer cleanup()
+	s.PatchValue(apiserver.LoginDelayBase, time.Hour)
+
+	u := s.Factory.MakeUser(c, &factory.UserParams{Password: "password"})
+	info.Tag = nil
+	info.Password = ""
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+
+	// The first failed logins are not delayed.
+	for i := 0; i < 3; i++ {
+		err = st.Login(u.Tag().String(), "wrong password", "")
+		c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	}
+	err = st.Login(u.Tag().String(), "password", "")
+	c.Assert(err, gc.ErrorMatches, "try again")
+	c.Assert(err, jc.Satisfies, params.IsCodeTryAgain)
+}
+
+func (s *loginSuite) TestFailedLoginsLockOutUser(c *gc.C) {
+	info, cleanup := s.setupServer(c)
+	defer cleanup()
+	s.PatchValue(apiserver.LoginDelayFreeFailures, 10)
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"login-lockout-threshold": 3,
+	}, nil, nil)
+	c.Assert(err, gc.IsNil)
+
+	u := s.Factory.MakeUser(c, &factory.UserParams{Password: "password"})
+	info.Tag = nil
+	info.Password = ""
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+
+	for i := 0; i < 3; i++ {
+		err = st.Login(u.Tag().String(), "wrong password", "")
+		c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	}
+	err = st.Login(u.Tag().String(), "password", "")
+	c.Assert(err, gc.ErrorMatches, "too many failed logins, try again later")
+	c.Assert(err, jc.Satisfies, params.IsCodeUnauthorized)
+
+	// Both the user at the address and the address were locked
+	// out, and the lockouts were audited.
+	entries, err := s.State.AuditEntries(state.AuditFilter{User: u.Tag().String()})
+	c.Assert(err, gc.IsNil)
+	c.Assert(entries, gc.HasLen, 2)
+	c.Assert(entries[0].Method, gc.Equals, "Login")
+	c.Assert(entries[0].Targets, gc.DeepEquals, []string{u.Tag().String()})
+	c.Assert(entries[0].Error, gc.Matches, "locked out until .* after 3 failed logins")
+	c.Assert(entries[1].Targets, gc.HasLen, 1)
+	remoteAddr := entries[1].Targets[0]
+	c.Assert(remoteAddr, gc.Matches, "127.0.0.1|::1")
+
+	// Once the lockouts are cleared, the user can log in.
+	err = s.State.ClearLoginAttempts(state.UserLoginAttemptsKey(u.Name(), remoteAddr))
+	c.Assert(err, gc.IsNil)
+	err = st.Login(u.Tag().String(), "password", "")
+	c.Assert(err, gc.ErrorMatches, "too many failed logins, try again later")
+	err = s.State.ClearLoginAttempts(state.AddressLoginAttemptsKey(remoteAddr))
+	c.Assert(err, gc.IsNil)
+	err = st.Login(u.Tag().String(), "password", "")
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *loginSuite) TestLockoutFromOtherAddressIgnored(c *gc.C) {
+	info, cleanup := s.setupServer(c)
+	defer cleanup()
+
+	u := s.Factory.MakeUser(c, &factory.UserParams{Password: "password"})
+	err := s.State.LockOutLogins(state.UserLoginAttemptsKey(u.Name(), "10.0.0.1"), time.Now().Add(time.Hour))
+	c.Assert(err, gc.IsNil)
+
+	info.Tag = nil
+	info.Password = ""
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+	err = st.Login(u.Tag().String(), "password", "")
+	c.Assert(err, gc.IsNil)
+}
+
+// userLoginFailures returns the failed logins recorded for the named
+// user from the local host.
+func (s *loginSuite) userLoginFailures(c *gc.C, name string) int {
+	failures := 0
+	for _, addr := range []string{"127.0.0.1", "::1"} {
+		attempts, err := s.State.LoginAttempts(state.UserLoginAttemptsKey(name, addr))
+		c.Assert(err, gc.IsNil)
+		failures += attempts.Failures
+	}
+	return failures
+}
+
+func (s *loginSuite) TestSuccessfulLoginForgetsUserFailures(c *gc.C) {
+	info, cleanup := s.setupServer(c)
+	defer cleanup()
+
+	u := s.Factory.MakeUser(c, &factory.UserParams{Password: "password"})
+	info.Tag = nil
+	info.Password = ""
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+
+	err = st.Login(u.Tag().String(), "wrong password", "")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	c.Assert(s.userLoginFailures(c, u.Name()), gc.Equals, 1)
+
+	err = st.Login(u.Tag().String(), "password", "")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.userLoginFailures(c, u.Name()), gc.Equals, 0)
+}
+
 func (s *loginSuite) TestLoginSetsLogIdentifier(c *gc.C) {
 	info, cleanup := s.setupServer(c)
 	defer cleanup()
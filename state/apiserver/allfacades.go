@@ -8,6 +8,7 @@ package apiserver
 // function will get called to register it.
 import (
 	_ "github.com/juju/juju/state/apiserver/agent"
+	_ "github.com/juju/juju/state/apiserver/backups"
 	_ "github.com/juju/juju/state/apiserver/charmrevisionupdater"
 	_ "github.com/juju/juju/state/apiserver/client"
 	_ "github.com/juju/juju/state/apiserver/deployer"
@@ -15,6 +15,7 @@ import (
 	"code.google.com/p/go.net/websocket"
 	"github.com/bmizerany/pat"
 	"github.com/juju/loggo"
+	"github.com/juju/names"
 	"github.com/juju/utils"
 	"launchpad.net/tomb"
 
@@ -38,7 +39,6 @@ type Server struct {
 	state     *state.State
 	addr      string
 	dataDir   string
-	logDir    string
 	limiter   utils.Limiter
 	validator LoginValidator
 
@@ -56,7 +56,6 @@ type ServerConfig struct {
 	Cert      []byte
 	Key       []byte
 	DataDir   string
-	LogDir    string
 	Validator LoginValidator
 }
 
@@ -77,7 +76,6 @@ func NewServer(s *state.State, lis net.Listener, cfg ServerConfig) (*Server, err
 		state:     s,
 		addr:      net.JoinHostPort("localhost", listeningPort),
 		dataDir:   cfg.DataDir,
-		logDir:    cfg.LogDir,
 		limiter:   utils.NewLimiter(loginRateLimit),
 		validator: cfg.Validator,
 	}
@@ -112,21 +110,29 @@ func (srv *Server) Wait() error {
 	return srv.tomb.Wait()
 }
 
+// requestNotifier logs the requests made on an API connection, and
+// records the audited ones in the audit log.
 type requestNotifier struct {
 	id    int64
 	start time.Time
+	state *state.State
 
 	mu   sync.Mutex
 	tag_ string
+	// audits holds the audit entries of the requests in progress,
+	// by request id.
+	audits map[uint64]*state.AuditEntry
 }
 
 var globalCounter int64
 
-func newRequestNotifier() *requestNotifier {
+func newRequestNotifier(st *state.State) *requestNotifier {
 	return &requestNotifier{
-		id:    atomic.AddInt64(&globalCounter, 1),
-		tag_:  "<unknown>",
-		start: time.Now(),
+		id:     atomic.AddInt64(&globalCounter, 1),
+		tag_:   "<unknown>",
+		start:  time.Now(),
+		state:  st,
+		audits: make(map[uint64]*state.AuditEntry),
 	}
 }
 
@@ -143,19 +149,61 @@ func (n *requestNotifier) tag() (tag string) {
 	return
 }
 
+// isUser reports whether the connection is logged in as a user. Only
+// the requests of users are audited; agents are not.
+func (n *requestNotifier) isUser() bool {
+	kind, err := names.TagKind(n.tag())
+	return err == nil && kind == names.UserTagKind
+}
+
+// isQuietRequest reports whether the request is left out of the debug
+// log. Logging the log records that agents send would feed them back
+// into the agents' logs, which are sent again, without end.
+func isQuietRequest(req rpc.Request) bool {
+	return req.Type == "Logger" && req.Action == "WriteLogs"
+}
+
 func (n *requestNotifier) ServerRequest(hdr *rpc.Header, body interface{}) {
 	if hdr.Request.Type == "Pinger" && hdr.Request.Action == "Ping" {
 		return
 	}
-	// TODO(rog) 2013-10-11 remove secrets from some requests.
-	logger.Debugf("<- [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, body))
+	if n.isUser() {
+		if entry := newAuditEntry(hdr.Request, body); entry != nil {
+			n.mu.Lock()
+			n.audits[hdr.RequestId] = entry
+			n.mu.Unlock()
+		}
+	}
+	if isQuietRequest(hdr.Request) {
+		return
+	}
+	if logger.EffectiveLogLevel() <= loggo.DEBUG {
+		// TODO(rog) 2013-10-11 remove secrets from some requests.
+		logger.Debugf("<- [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, body))
+	}
 }
 
 func (n *requestNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}, timeSpent time.Duration) {
 	if req.Type == "Pinger" && req.Action == "Ping" {
 		return
 	}
-	logger.Debugf("-> [%X] %s %s %s %s[%q].%s", n.id, n.tag(), timeSpent, jsoncodec.DumpRequest(hdr, body), req.Type, req.Id, req.Action)
+	n.mu.Lock()
+	entry := n.audits[hdr.RequestId]
+	delete(n.audits, hdr.RequestId)
+	n.mu.Unlock()
+	if entry != nil {
+		entry.User = n.tag()
+		entry.Error = hdr.Error
+		if err := n.state.AddAuditEntry(*entry); err != nil {
+			logger.Errorf("[%X] cannot audit %s.%s: %v", n.id, req.Type, req.Action, err)
+		}
+	}
+	if isQuietRequest(req) {
+		return
+	}
+	if logger.EffectiveLogLevel() <= loggo.DEBUG {
+		logger.Debugf("-> [%X] %s %s %s %s[%q].%s", n.id, n.tag(), timeSpent, jsoncodec.DumpRequest(hdr, body), req.Type, req.Id, req.Action)
+	}
 }
 
 func (n *requestNotifier) join(req *http.Request) {
@@ -202,9 +250,7 @@ func (srv *Server) run(lis net.Listener) {
 	mux := pat.New()
 	// For backwards compatibility we register all the old paths
 	handleAll(mux, "/environment/:envuuid/log",
-		&debugLogHandler{
-			httpHandler: httpHandler{state: srv.state},
-			logDir:      srv.logDir},
+		&debugLogHandler{httpHandler{state: srv.state}},
 	)
 	handleAll(mux, "/environment/:envuuid/charms",
 		&charmsHandler{
@@ -218,12 +264,13 @@ func (srv *Server) run(lis net.Listener) {
 	handleAll(mux, "/environment/:envuuid/tools",
 		&toolsHandler{httpHandler{state: srv.state}},
 	)
+	handleAll(mux, "/environment/:envuuid/backups",
+		&backupsHandler{httpHandler{state: srv.state}},
+	)
 	handleAll(mux, "/environment/:envuuid/api", http.HandlerFunc(srv.apiHandler))
 	// For backwards compatibility we register all the old paths
 	handleAll(mux, "/log",
-		&debugLogHandler{
-			httpHandler: httpHandler{state: srv.state},
-			logDir:      srv.logDir},
+		&debugLogHandler{httpHandler{state: srv.state}},
 	)
 	handleAll(mux, "/charms",
 		&charmsHandler{
@@ -233,13 +280,16 @@ func (srv *Server) run(lis net.Listener) {
 	handleAll(mux, "/tools",
 		&toolsHandler{httpHandler{state: srv.state}},
 	)
+	handleAll(mux, "/backups",
+		&backupsHandler{httpHandler{state: srv.state}},
+	)
 	handleAll(mux, "/", http.HandlerFunc(srv.apiHandler))
 	// The error from http.Serve is not interesting.
 	http.Serve(lis, mux)
 }
 
 func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
-	reqNotifier := newRequestNotifier()
+	reqNotifier := newRequestNotifier(srv.state)
 	reqNotifier.join(req)
 	defer reqNotifier.leave()
 	wsServer := websocket.Server{
@@ -255,7 +305,7 @@ func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 			}
 			envUUID := req.URL.Query().Get(":envuuid")
 			logger.Tracef("got a request for env %q", envUUID)
-			if err := srv.serveConn(conn, reqNotifier, envUUID); err != nil {
+			if err := srv.serveConn(conn, reqNotifier, envUUID, req.RemoteAddr); err != nil {
 				logger.Errorf("error serving RPCs: %v", err)
 			}
 		},
@@ -312,23 +362,19 @@ func (srv *Server) setEnvironUUID(uuid string) {
 	srv.environUUID = uuid
 }
 
-func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifier, envUUID string) error {
+func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifier, envUUID, remoteAddr string) error {
 	codec := jsoncodec.NewWebsocket(wsConn)
 	if loggo.GetLogger("juju.rpc.jsoncodec").EffectiveLogLevel() <= loggo.TRACE {
 		codec.SetLogging(true)
 	}
-	var notifier rpc.RequestNotifier
-	if logger.EffectiveLogLevel() <= loggo.DEBUG {
-		// Incur request monitoring overhead only if we
-		// know we'll need it.
-		notifier = reqNotifier
-	}
-	conn := rpc.NewConn(codec, notifier)
+	// The notifier is always needed, as it records audited
+	// requests, but only logs requests at debug level.
+	conn := rpc.NewConn(codec, reqNotifier)
 	err := srv.validateEnvironUUID(envUUID)
 	if err != nil {
 		conn.Serve(&errRoot{err}, serverError)
 	} else {
-		conn.Serve(newStateServer(srv, conn, reqNotifier, srv.limiter), serverError)
+		conn.Serve(newStateServer(srv, conn, reqNotifier, srv.limiter, remoteAddr), serverError)
 	}
 	conn.Start()
 	select {
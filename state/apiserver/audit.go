@@ -0,0 +1,147 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
+)
+
+// readOnlyClientMethods holds the Client facade methods that do not
+// change the environment. Users with read access may call them, and
+// they are not audited. Any other Client method needs write access.
+var readOnlyClientMethods = set.NewStrings(
+	"APIHostPorts",
+	"ActionResults",
+	"AgentVersion",
+	"AuditLog",
+	"CharmInfo",
+	"DiffBundle",
+	"EnvironmentGet",
+	"EnvironmentInfo",
+	"ExportBundle",
+	"FindTools",
+	"FullStatus",
+	"GetAnnotations",
+	"GetEnvironmentConstraints",
+	"GetServiceConstraints",
+	"ListActions",
+	"Metrics",
+	"PrivateAddress",
+	"ProvisioningScript",
+	"PublicAddress",
+	"ResolveCharms",
+	"RollingUpgradeStatus",
+	"ServiceCharmRelations",
+	"ServiceGet",
+	"ServiceGetCharmURL",
+	"Status",
+	"StatusHistory",
+	"WatchAll",
+)
+
+// redactedArgsMethods holds the audited methods whose arguments may
+// hold secrets, such as configuration settings or passwords. Their
+// arguments are not recorded, although their targets are.
+var redactedArgsMethods = set.NewStrings(
+	"Admin.Login",
+	"Client.DeployBundle",
+	"Client.EnvironmentSet",
+	"Client.InjectMachines",
+	"Client.ServiceDeploy",
+	"Client.ServiceDeployWithNetworks",
+	"Client.ServiceSet",
+	"Client.ServiceSetYAML",
+	"Client.ServiceUpdate",
+	"UserManager.AddUser",
+)
+
+// auditTargetFields holds the names of the argument fields that name
+// the entities changed by a call.
+var auditTargetFields = []string{
+	"ServiceName",
+	"UnitName",
+	"UnitNames",
+	"MachineNames",
+	"Endpoints",
+	"Tag",
+	"URL",
+	"Machines",
+	"Services",
+	"Units",
+}
+
+// maxAuditArgsLen is the length at which the recorded arguments of a
+// call are truncated.
+const maxAuditArgsLen = 1024
+
+// newAuditEntry returns the audit entry to record for a request made
+// by a user with the given arguments, or nil if the request is not
+// audited. Every method that needs more than read access is audited.
+// The entry's user, time and error are filled in when the call
+// returns.
+func newAuditEntry(req rpc.Request, body interface{}) *state.AuditEntry {
+	if requiredUserAccess(req.Type, req.Action) == state.UserAccessRead {
+		return nil
+	}
+	entry := &state.AuditEntry{
+		Facade: req.Type,
+		Method: req.Action,
+	}
+	if body == nil {
+		return entry
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		entry.Args = fmt.Sprintf("<cannot marshal arguments: %v>", err)
+		return entry
+	}
+	entry.Targets = auditTargets(data)
+	switch {
+	case redactedArgsMethods.Contains(req.Type + "." + req.Action):
+		entry.Args = "<redacted>"
+	case len(data) > maxAuditArgsLen:
+		entry.Args = string(data[:maxAuditArgsLen]) + "..."
+	default:
+		entry.Args = string(data)
+	}
+	return entry
+}
+
+// auditTargets returns the names of the entities named by the given
+// JSON-encoded call arguments.
+func auditTargets(data []byte) []string {
+	var args map[string]interface{}
+	if err := json.Unmarshal(data, &args); err != nil {
+		return nil
+	}
+	var targets []string
+	add := func(v interface{}) {
+		if s, ok := v.(string); ok && s != "" {
+			targets = append(targets, s)
+		}
+	}
+	for _, field := range auditTargetFields {
+		if values, ok := args[field].([]interface{}); ok {
+			for _, v := range values {
+				add(v)
+			}
+		} else {
+			add(args[field])
+		}
+	}
+	if entities, ok := args["Entities"].([]interface{}); ok {
+		for _, entity := range entities {
+			if entity, ok := entity.(map[string]interface{}); ok {
+				add(entity["Tag"])
+			}
+		}
+	}
+	return targets
+}
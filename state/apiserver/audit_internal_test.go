@@ -0,0 +1,78 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"strings"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver/usermanager"
+)
+
+type auditInternalSuite struct{}
+
+var _ = gc.Suite(&auditInternalSuite{})
+
+func (*auditInternalSuite) TestNewAuditEntry(c *gc.C) {
+	entry := newAuditEntry(rpc.Request{Type: "Client", Action: "DestroyMachines"},
+		params.DestroyMachines{MachineNames: []string{"1", "2"}})
+	c.Assert(entry, gc.NotNil)
+	c.Assert(entry.Facade, gc.Equals, "Client")
+	c.Assert(entry.Method, gc.Equals, "DestroyMachines")
+	c.Assert(entry.Targets, gc.DeepEquals, []string{"1", "2"})
+	c.Assert(entry.Args, gc.Equals, `{"MachineNames":["1","2"],"Force":false}`)
+}
+
+func (*auditInternalSuite) TestNewAuditEntryEntities(c *gc.C) {
+	entry := newAuditEntry(rpc.Request{Type: "Client", Action: "RetryProvisioning"},
+		params.Entities{Entities: []params.Entity{{Tag: "machine-0"}, {Tag: "machine-1"}}})
+	c.Assert(entry, gc.NotNil)
+	c.Assert(entry.Targets, gc.DeepEquals, []string{"machine-0", "machine-1"})
+}
+
+func (*auditInternalSuite) TestNewAuditEntryNotAudited(c *gc.C) {
+	for i, test := range []struct {
+		req  rpc.Request
+		body interface{}
+	}{
+		{rpc.Request{Type: "Client", Action: "FullStatus"}, params.StatusParams{}},
+		{rpc.Request{Type: "UserManager", Action: "UserInfo"}, params.Entities{}},
+		{rpc.Request{Type: "KeyManager", Action: "ListKeys"}, params.ListSSHKeys{}},
+		{rpc.Request{Type: "AllWatcher", Action: "Next"}, nil},
+	} {
+		c.Logf("test %d: %v", i, test.req)
+		c.Check(newAuditEntry(test.req, test.body), gc.IsNil)
+	}
+}
+
+func (*auditInternalSuite) TestNewAuditEntryWithoutArgs(c *gc.C) {
+	entry := newAuditEntry(rpc.Request{Type: "Client", Action: "DestroyEnvironment"}, nil)
+	c.Assert(entry, gc.NotNil)
+	c.Assert(entry.Method, gc.Equals, "DestroyEnvironment")
+	c.Assert(entry.Args, gc.Equals, "")
+}
+
+func (*auditInternalSuite) TestNewAuditEntryOtherFacades(c *gc.C) {
+	entry := newAuditEntry(rpc.Request{Type: "KeyManager", Action: "DeleteKeys"},
+		params.ModifyUserSSHKeys{User: "admin", Keys: []string{"user@host"}})
+	c.Assert(entry, gc.NotNil)
+	c.Assert(entry.Facade, gc.Equals, "KeyManager")
+	c.Assert(entry.Args, gc.Equals, `{"User":"admin","Keys":["user@host"]}`)
+
+	entry = newAuditEntry(rpc.Request{Type: "UserManager", Action: "AddUser"},
+		usermanager.ModifyUsers{Changes: []usermanager.ModifyUser{{Username: "bob", Password: "secret"}}})
+	c.Assert(entry, gc.NotNil)
+	c.Assert(entry.Args, gc.Equals, "<redacted>")
+}
+
+func (*auditInternalSuite) TestNewAuditEntryTruncatesArgs(c *gc.C) {
+	entry := newAuditEntry(rpc.Request{Type: "Client", Action: "Run"},
+		params.RunParams{Commands: strings.Repeat("x", 2*maxAuditArgsLen)})
+	c.Assert(entry, gc.NotNil)
+	c.Assert(entry.Args, gc.HasLen, maxAuditArgsLen+len("..."))
+	c.Assert(strings.HasSuffix(entry.Args, "..."), gc.Equals, true)
+}
@@ -0,0 +1,98 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"github.com/juju/names"
+	gc "launchpad.net/gocheck"
+
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/api/usermanager"
+)
+
+type auditSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&auditSuite{})
+
+func (s *auditSuite) TestClientChangesAudited(c *gc.C) {
+	s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	client := s.APIState.Client()
+
+	err := client.ServiceExpose("dummy")
+	c.Assert(err, gc.IsNil)
+	err = client.ServiceExpose("mysql")
+	c.Assert(err, gc.ErrorMatches, `service "mysql" not found`)
+	err = client.ServiceSet("dummy", map[string]string{"title": "secret"})
+	c.Assert(err, gc.IsNil)
+
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(entries, gc.HasLen, 3)
+	for i, expected := range []state.AuditEntry{{
+		User:    "user-admin",
+		Facade:  "Client",
+		Method:  "ServiceExpose",
+		Targets: []string{"dummy"},
+		Args:    `{"ServiceName":"dummy"}`,
+	}, {
+		User:    "user-admin",
+		Facade:  "Client",
+		Method:  "ServiceExpose",
+		Targets: []string{"mysql"},
+		Args:    `{"ServiceName":"mysql"}`,
+		Error:   `service "mysql" not found`,
+	}, {
+		User:    "user-admin",
+		Facade:  "Client",
+		Method:  "ServiceSet",
+		Targets: []string{"dummy"},
+		Args:    "<redacted>",
+	}} {
+		c.Logf("entry %d", i)
+		c.Check(entries[i].Time.IsZero(), gc.Equals, false)
+		entries[i].Time = expected.Time
+		c.Check(entries[i], gc.DeepEquals, expected)
+	}
+}
+
+func (s *auditSuite) TestClientReadsNotAudited(c *gc.C) {
+	client := s.APIState.Client()
+	_, err := client.Status(nil)
+	c.Assert(err, gc.IsNil)
+	_, err = client.EnvironmentGet()
+	c.Assert(err, gc.IsNil)
+
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(entries, gc.HasLen, 0)
+}
+
+func (s *auditSuite) TestOtherFacadeChangesAudited(c *gc.C) {
+	err := usermanager.NewClient(s.APIState).AddUser("bob", "Bob", "secret", "read")
+	c.Assert(err, gc.IsNil)
+
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Assert(entries[0].User, gc.Equals, "user-admin")
+	c.Assert(entries[0].Facade, gc.Equals, "UserManager")
+	c.Assert(entries[0].Method, gc.Equals, "AddUser")
+	c.Assert(entries[0].Args, gc.Equals, "<redacted>")
+}
+
+func (s *auditSuite) TestAgentRequestsNotAudited(c *gc.C) {
+	st, machine := s.OpenAPIAsNewMachine(c, state.JobHostUnits)
+	m, err := st.Machiner().Machine(names.NewMachineTag(machine.Id()))
+	c.Assert(err, gc.IsNil)
+	err = m.SetStatus(params.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
+
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(entries, gc.HasLen, 0)
+}
@@ -0,0 +1,107 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver/backups"
+	"github.com/juju/juju/state/apiserver/common"
+)
+
+// backupsHandler handles backup archive downloads and uploads through
+// HTTPS in the API server.
+type backupsHandler struct {
+	httpHandler
+}
+
+func (h *backupsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.authenticate(r, state.UserAccessAdmin); err != nil {
+		h.authError(w, h)
+		return
+	}
+	if err := h.validateEnvironUUID(r); err != nil {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		// Download a stored backup archive.
+		// Requires an "id" query identifying the backup.
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			h.sendError(w, http.StatusBadRequest, "expected id argument")
+			return
+		}
+		b, err := backups.NewBackups(h.state)
+		if err != nil {
+			h.sendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		metadata, archive, err := b.Get(id)
+		if errors.IsNotFound(err) {
+			h.sendError(w, http.StatusNotFound, err.Error())
+			return
+		} else if err != nil {
+			h.sendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		defer archive.Close()
+		w.Header().Set("Content-Type", "application/x-tar-gz")
+		w.Header().Set("Content-Length", fmt.Sprint(metadata.Size))
+		w.Header().Set("Digest", "SHA="+metadata.CheckSum)
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.Copy(w, archive); err != nil {
+			logger.Errorf("error sending backup archive %q: %v", id, err)
+		}
+	case "POST":
+		// Upload a backup archive, such as one downloaded from
+		// another state server, so that it can be restored.
+		// Requires a "checksum" query with the SHA-1 of the archive.
+		checksum := r.URL.Query().Get("checksum")
+		if checksum == "" {
+			h.sendError(w, http.StatusBadRequest, "expected checksum argument")
+			return
+		}
+		b, err := backups.NewBackups(h.state)
+		if err != nil {
+			h.sendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		metadata, err := b.Add(r.Body, checksum)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.sendJSON(w, http.StatusOK, backups.ResultFromMetadata(metadata))
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", r.Method))
+	}
+}
+
+// sendJSON sends a JSON-encoded response to the client.
+func (h *backupsHandler) sendJSON(w http.ResponseWriter, statusCode int, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	body, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	w.Write(body)
+	return nil
+}
+
+// sendError sends a JSON-encoded error response.
+func (h *backupsHandler) sendError(w http.ResponseWriter, statusCode int, message string) error {
+	err := common.ServerError(fmt.Errorf(message))
+	return h.sendJSON(w, statusCode, &params.ErrorResult{Error: err})
+}
@@ -0,0 +1,148 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver/common"
+	"github.com/juju/juju/state/backups"
+)
+
+var logger = loggo.GetLogger("juju.state.apiserver.backups")
+
+func init() {
+	common.RegisterStandardFacade("Backups", 0, NewAPI)
+}
+
+// API serves backup-specific API methods.
+type API struct {
+	st        *state.State
+	resources *common.Resources
+	backups   backups.Backups
+}
+
+// NewAPI creates a new instance of the Backups API facade.
+func NewAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	b, err := newBackups(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &API{st: st, resources: resources, backups: b}, nil
+}
+
+// newBackups returns the Backups that records metadata in state and
+// keeps archives in the environment's storage.
+var newBackups = func(st *state.State) (backups.Backups, error) {
+	return NewBackups(st)
+}
+
+// NewBackups returns the Backups for the environment of the given
+// State: metadata is recorded in state and archives are kept in the
+// environment's storage.
+func NewBackups(st *state.State) (backups.Backups, error) {
+	stor, err := environs.GetStorage(st)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot open environment storage")
+	}
+	return backups.NewBackups(state.NewBackupMetadataStorage(st), stor), nil
+}
+
+// dbInfo returns the connection details of the state server's
+// database.
+func (a *API) dbInfo() (backups.DBConnInfo, error) {
+	dbInfo, err := backups.NewDBConnInfoFromMongo(a.st.MongoConnectionInfo())
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get database connection details")
+	}
+	return dbInfo, nil
+}
+
+// Create runs a new backup on the state server and stores it.
+func (a *API) Create(args params.BackupsCreateArgs) (params.BackupsMetadataResult, error) {
+	var machine string
+	// State servers connect to mongo as their machine agent.
+	if tag, ok := a.st.MongoConnectionInfo().Tag.(names.MachineTag); ok {
+		machine = tag.Id()
+	}
+	dbInfo, err := a.dbInfo()
+	if err != nil {
+		return params.BackupsMetadataResult{}, errors.Trace(err)
+	}
+	origin := state.NewBackupOrigin(a.st, machine)
+
+	metadata, err := a.backups.Create(dbInfo, *origin, args.Notes)
+	if err != nil {
+		return params.BackupsMetadataResult{}, errors.Trace(err)
+	}
+	logger.Infof("created backup %q", metadata.ID)
+	return ResultFromMetadata(metadata), nil
+}
+
+// Info returns the metadata of the identified backup.
+func (a *API) Info(args params.BackupsInfoArgs) (params.BackupsMetadataResult, error) {
+	metadata, err := a.backups.Info(args.ID)
+	if err != nil {
+		return params.BackupsMetadataResult{}, errors.Trace(err)
+	}
+	return ResultFromMetadata(metadata), nil
+}
+
+// List returns the metadata of every stored backup.
+func (a *API) List(args params.BackupsListArgs) (params.BackupsListResult, error) {
+	metadataList, err := a.backups.List()
+	if err != nil {
+		return params.BackupsListResult{}, errors.Trace(err)
+	}
+	result := params.BackupsListResult{
+		List: make([]params.BackupsMetadataResult, len(metadataList)),
+	}
+	for i, metadata := range metadataList {
+		result.List[i] = ResultFromMetadata(metadata)
+	}
+	return result, nil
+}
+
+// LastGood returns the metadata of the most recent backup that
+// succeeded.
+func (a *API) LastGood(args params.BackupsLastGoodArgs) (params.BackupsMetadataResult, error) {
+	metadata, err := a.backups.LastGood()
+	if err != nil {
+		return params.BackupsMetadataResult{}, errors.Trace(err)
+	}
+	return ResultFromMetadata(metadata), nil
+}
+
+// Remove deletes the identified backup from storage.
+func (a *API) Remove(args params.BackupsRemoveArgs) error {
+	return errors.Trace(a.backups.Remove(args.ID))
+}
+
+// ResultFromMetadata converts backup metadata into its API form.
+func ResultFromMetadata(metadata *backups.Metadata) params.BackupsMetadataResult {
+	return params.BackupsMetadataResult{
+		ID:             metadata.ID,
+		Started:        metadata.Timestamp,
+		Finished:       metadata.Finished,
+		Checksum:       metadata.CheckSum,
+		ChecksumFormat: metadata.CheckSumFormat,
+		Size:           metadata.Size,
+		Stored:         metadata.Stored,
+		Notes:          metadata.Notes,
+		Error:          metadata.Error,
+
+		Environment: metadata.Origin.Environment,
+		Machine:     metadata.Origin.Machine,
+		Hostname:    metadata.Origin.Hostname,
+		Version:     metadata.Origin.Version,
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	gc "launchpad.net/gocheck"
+
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	backupsAPI "github.com/juju/juju/state/apiserver/backups"
+	apiservertesting "github.com/juju/juju/state/apiserver/testing"
+	"github.com/juju/juju/state/backups"
+)
+
+type backupsSuite struct {
+	jujutesting.JujuConnSuite
+	authorizer apiservertesting.FakeAuthorizer
+	api        *backupsAPI.API
+	fake       *fakeBackups
+}
+
+var _ = gc.Suite(&backupsSuite{})
+
+func (s *backupsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.fake = &fakeBackups{}
+	s.PatchValue(backupsAPI.NewBackupsFunc, func(*state.State) (backups.Backups, error) {
+		return s.fake, nil
+	})
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	var err error
+	s.api, err = backupsAPI.NewAPI(s.State, nil, s.authorizer)
+	c.Assert(err, gc.IsNil)
+}
+
+// fakeBackups is a backups.Backups that records the calls made to it.
+type fakeBackups struct {
+	calls    []string
+	dbInfo   backups.DBConnInfo
+	origin   backups.Origin
+	notes    string
+	id       string
+	archive  string
+	checksum string
+	args     backups.RestoreArgs
+	restored func()
+	metadata *backups.Metadata
+	err      error
+}
+
+func (f *fakeBackups) Create(dbInfo backups.DBConnInfo, origin backups.Origin, notes string) (*backups.Metadata, error) {
+	f.calls = append(f.calls, "Create")
+	f.dbInfo, f.origin, f.notes = dbInfo, origin, notes
+	return f.metadata, f.err
+}
+
+func (f *fakeBackups) Info(id string) (*backups.Metadata, error) {
+	f.calls = append(f.calls, "Info")
+	f.id = id
+	return f.metadata, f.err
+}
+
+func (f *fakeBackups) Get(id string) (*backups.Metadata, io.ReadCloser, error) {
+	f.calls = append(f.calls, "Get")
+	f.id = id
+	return f.metadata, nil, f.err
+}
+
+func (f *fakeBackups) List() ([]*backups.Metadata, error) {
+	f.calls = append(f.calls, "List")
+	if f.metadata == nil {
+		return nil, f.err
+	}
+	return []*backups.Metadata{f.metadata}, f.err
+}
+
+func (f *fakeBackups) Remove(id string) error {
+	f.calls = append(f.calls, "Remove")
+	f.id = id
+	return f.err
+}
+
+func (f *fakeBackups) Add(archive io.Reader, checksum string) (*backups.Metadata, error) {
+	f.calls = append(f.calls, "Add")
+	data, err := ioutil.ReadAll(archive)
+	if err != nil {
+		return nil, err
+	}
+	f.archive, f.checksum = string(data), checksum
+	return f.metadata, f.err
+}
+
+func (f *fakeBackups) Restore(id string, args backups.RestoreArgs) error {
+	f.calls = append(f.calls, "Restore")
+	f.id, f.args = id, args
+	if f.err == nil && f.restored != nil {
+		f.restored()
+	}
+	return f.err
+}
+
+func (f *fakeBackups) LastGood() (*backups.Metadata, error) {
+	f.calls = append(f.calls, "LastGood")
+	return f.metadata, f.err
+}
+
+func (s *backupsSuite) metadata(c *gc.C) *backups.Metadata {
+	origin := state.NewBackupOrigin(s.State, "0")
+	metadata := backups.NewMetadata("some-hash", 42, *origin, "some notes")
+	metadata.ID = "some-id"
+	metadata.Stored = true
+	return metadata
+}
+
+func (s *backupsSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	authorizer := s.authorizer
+	authorizer.Tag = names.NewMachineTag("1")
+	_, err := backupsAPI.NewAPI(s.State, nil, authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *backupsSuite) TestCreate(c *gc.C) {
+	s.fake.metadata = s.metadata(c)
+	result, err := s.api.Create(params.BackupsCreateArgs{Notes: "some notes"})
+	c.Assert(err, gc.IsNil)
+	c.Check(result, gc.DeepEquals, backupsAPI.ResultFromMetadata(s.fake.metadata))
+
+	c.Check(s.fake.calls, gc.DeepEquals, []string{"Create"})
+	c.Check(s.fake.notes, gc.Equals, "some notes")
+	c.Check(s.fake.origin.Environment, gc.Equals, s.State.EnvironTag().Id())
+	mgoInfo := s.State.MongoConnectionInfo()
+	c.Check(s.fake.dbInfo.Address(), gc.Equals, mgoInfo.Addrs[0])
+	c.Check(s.fake.dbInfo.Password(), gc.Equals, mgoInfo.Password)
+}
+
+func (s *backupsSuite) TestCreateError(c *gc.C) {
+	s.fake.err = fmt.Errorf("failed!")
+	_, err := s.api.Create(params.BackupsCreateArgs{})
+	c.Check(err, gc.ErrorMatches, "failed!")
+}
+
+func (s *backupsSuite) TestInfo(c *gc.C) {
+	s.fake.metadata = s.metadata(c)
+	result, err := s.api.Info(params.BackupsInfoArgs{ID: "some-id"})
+	c.Assert(err, gc.IsNil)
+	c.Check(s.fake.calls, gc.DeepEquals, []string{"Info"})
+	c.Check(s.fake.id, gc.Equals, "some-id")
+	c.Check(result.ID, gc.Equals, "some-id")
+	c.Check(result.Checksum, gc.Equals, "some-hash")
+	c.Check(result.Size, gc.Equals, int64(42))
+	c.Check(result.Notes, gc.Equals, "some notes")
+	c.Check(result.Machine, gc.Equals, "0")
+	c.Check(result.Stored, gc.Equals, true)
+}
+
+func (s *backupsSuite) TestInfoNotFound(c *gc.C) {
+	s.fake.err = errors.NotFoundf("backup metadata %q", "spam")
+	_, err := s.api.Info(params.BackupsInfoArgs{ID: "spam"})
+	c.Check(err, gc.ErrorMatches, `backup metadata "spam" not found`)
+}
+
+func (s *backupsSuite) TestList(c *gc.C) {
+	s.fake.metadata = s.metadata(c)
+	result, err := s.api.List(params.BackupsListArgs{})
+	c.Assert(err, gc.IsNil)
+	c.Check(s.fake.calls, gc.DeepEquals, []string{"List"})
+	c.Check(result.List, gc.DeepEquals, []params.BackupsMetadataResult{
+		backupsAPI.ResultFromMetadata(s.fake.metadata),
+	})
+}
+
+func (s *backupsSuite) TestListEmpty(c *gc.C) {
+	result, err := s.api.List(params.BackupsListArgs{})
+	c.Assert(err, gc.IsNil)
+	c.Check(result.List, gc.HasLen, 0)
+}
+
+func (s *backupsSuite) TestRemove(c *gc.C) {
+	err := s.api.Remove(params.BackupsRemoveArgs{ID: "some-id"})
+	c.Assert(err, gc.IsNil)
+	c.Check(s.fake.calls, gc.DeepEquals, []string{"Remove"})
+	c.Check(s.fake.id, gc.Equals, "some-id")
+}
+
+func (s *backupsSuite) TestRemoveError(c *gc.C) {
+	s.fake.err = fmt.Errorf("failed!")
+	err := s.api.Remove(params.BackupsRemoveArgs{ID: "some-id"})
+	c.Check(err, gc.ErrorMatches, "failed!")
+}
+
+func (s *backupsSuite) TestLastGood(c *gc.C) {
+	s.fake.metadata = s.metadata(c)
+	result, err := s.api.LastGood(params.BackupsLastGoodArgs{})
+	c.Assert(err, gc.IsNil)
+	c.Check(s.fake.calls, gc.DeepEquals, []string{"LastGood"})
+	c.Check(result, gc.DeepEquals, backupsAPI.ResultFromMetadata(s.fake.metadata))
+}
+
+func (s *backupsSuite) TestLastGoodNotFound(c *gc.C) {
+	s.fake.err = errors.NotFoundf("successful backup")
+	_, err := s.api.LastGood(params.BackupsLastGoodArgs{})
+	c.Check(err, gc.ErrorMatches, "successful backup not found")
+}
@@ -0,0 +1,120 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"os/exec"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver/common"
+	"github.com/juju/juju/state/backups"
+)
+
+// restartDelay is how long the machine agent is left running after a
+// restore, so the result can be returned to the client.
+const restartDelay = 5 * time.Second
+
+// stateServerTag returns the tag of the machine agent the API server
+// is running in.
+var stateServerTag = func(st *state.State) (names.MachineTag, error) {
+	// State servers connect to mongo as their machine agent.
+	tag, ok := st.MongoConnectionInfo().Tag.(names.MachineTag)
+	if !ok {
+		return names.MachineTag{}, errors.New("backups can only be restored by a state server")
+	}
+	return tag, nil
+}
+
+// reconnectAgent points the agent config of the state server's machine
+// agent, which was replaced by the one in the backup, at the current
+// API servers, and restarts the agent so it connects to the restored
+// state.
+var reconnectAgent = func(dataDir string, tag names.MachineTag, hostPorts [][]network.HostPort) error {
+	config, err := agent.ReadConfig(agent.ConfigPath(dataDir, tag))
+	if err != nil {
+		return errors.Annotate(err, "cannot read agent config")
+	}
+	config.SetAPIHostPorts(hostPorts)
+	if err := config.Write(); err != nil {
+		return errors.Annotate(err, "cannot write agent config")
+	}
+	service := "jujud-" + tag.String()
+	time.AfterFunc(restartDelay, func() {
+		logger.Infof("restarting %s", service)
+		if out, err := exec.Command("initctl", "restart", service).CombinedOutput(); err != nil {
+			logger.Errorf("cannot restart %s: %v (%s)", service, err, out)
+		}
+	})
+	return nil
+}
+
+// getDataDir returns the data directory of the API server's agent.
+func (a *API) getDataDir() string {
+	if a.resources == nil {
+		return ""
+	}
+	dataResource, ok := a.resources.Get("dataDir").(common.StringResource)
+	if !ok {
+		return ""
+	}
+	return dataResource.String()
+}
+
+// Restore replaces the state of the environment with the identified
+// backup. The state server keeps its own instance and addresses, and
+// the agents of the environment reconnect to it through the updated
+// API addresses.
+func (a *API) Restore(args params.BackupsRestoreArgs) error {
+	tag, err := stateServerTag(a.st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info, err := a.st.StateServerInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(info.MachineIds) > 1 {
+		return errors.Errorf("cannot restore a backup into an environment with %d state servers", len(info.MachineIds))
+	}
+	machine, err := a.st.Machine(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	instId, err := machine.InstanceId()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	hostPorts, err := a.st.APIHostPorts()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	dbInfo, err := a.dbInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = a.backups.Restore(args.ID, backups.RestoreArgs{
+		DBInfo:      dbInfo,
+		EnvironUUID: a.st.EnvironTag().Id(),
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("restored backup %q", args.ID)
+
+	if err := state.UpdateRestoredStateServer(a.st, tag.Id(), instId); err != nil {
+		return errors.Annotate(err, "cannot update restored state server")
+	}
+	if err := a.st.SetAPIHostPorts(hostPorts); err != nil {
+		return errors.Annotate(err, "cannot update API addresses")
+	}
+	return errors.Trace(reconnectAgent(a.getDataDir(), tag, hostPorts))
+}
@@ -0,0 +1,111 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"fmt"
+
+	"github.com/juju/names"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	backupsAPI "github.com/juju/juju/state/apiserver/backups"
+	"github.com/juju/juju/state/apiserver/common"
+)
+
+type restoreSuite struct {
+	backupsSuite
+	machine     *state.Machine
+	hostPorts   [][]network.HostPort
+	reconnected []string
+}
+
+var _ = gc.Suite(&restoreSuite{})
+
+func (s *restoreSuite) SetUpTest(c *gc.C) {
+	s.backupsSuite.SetUpTest(c)
+
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, gc.IsNil)
+	err = s.machine.SetProvisioned("instance-0", "fake_nonce", nil)
+	c.Assert(err, gc.IsNil)
+	s.hostPorts = [][]network.HostPort{
+		network.AddressesWithPort(network.NewAddresses("10.0.0.1"), 17070),
+	}
+	err = s.State.SetAPIHostPorts(s.hostPorts)
+	c.Assert(err, gc.IsNil)
+
+	s.PatchValue(backupsAPI.StateServerTag, func(*state.State) (names.MachineTag, error) {
+		return s.machine.Tag().(names.MachineTag), nil
+	})
+	s.reconnected = nil
+	s.PatchValue(backupsAPI.ReconnectAgent, func(dataDir string, tag names.MachineTag, hostPorts [][]network.HostPort) error {
+		c.Check(hostPorts, gc.DeepEquals, s.hostPorts)
+		s.reconnected = append(s.reconnected, dataDir, tag.String())
+		return nil
+	})
+
+	resources := common.NewResources()
+	resources.RegisterNamed("dataDir", common.StringResource("/var/lib/juju"))
+	s.api, err = backupsAPI.NewAPI(s.State, resources, s.authorizer)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *restoreSuite) TestRestore(c *gc.C) {
+	// The restored state knows the state server by its old instance.
+	s.fake.restored = func() {
+		err := state.UpdateRestoredStateServer(s.State, s.machine.Id(), "old-instance")
+		c.Assert(err, gc.IsNil)
+		err = s.State.SetAPIHostPorts(nil)
+		c.Assert(err, gc.IsNil)
+	}
+
+	err := s.api.Restore(params.BackupsRestoreArgs{ID: "some-id"})
+	c.Assert(err, gc.IsNil)
+
+	c.Check(s.fake.calls, gc.DeepEquals, []string{"Restore"})
+	c.Check(s.fake.id, gc.Equals, "some-id")
+	c.Check(s.fake.args.EnvironUUID, gc.Equals, s.State.EnvironTag().Id())
+	mgoInfo := s.State.MongoConnectionInfo()
+	c.Check(s.fake.args.DBInfo.Address(), gc.Equals, mgoInfo.Addrs[0])
+
+	err = s.machine.Refresh()
+	c.Assert(err, gc.IsNil)
+	instId, err := s.machine.InstanceId()
+	c.Assert(err, gc.IsNil)
+	c.Check(instId, gc.Equals, instance.Id("instance-0"))
+	hostPorts, err := s.State.APIHostPorts()
+	c.Assert(err, gc.IsNil)
+	c.Check(hostPorts, gc.DeepEquals, s.hostPorts)
+	c.Check(s.reconnected, gc.DeepEquals, []string{"/var/lib/juju", s.machine.Tag().String()})
+}
+
+func (s *restoreSuite) TestRestoreError(c *gc.C) {
+	s.fake.err = fmt.Errorf("failed!")
+	err := s.api.Restore(params.BackupsRestoreArgs{ID: "some-id"})
+	c.Check(err, gc.ErrorMatches, "failed!")
+	c.Check(s.reconnected, gc.HasLen, 0)
+}
+
+func (s *restoreSuite) TestRestoreRefusesHA(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, gc.IsNil)
+
+	err = s.api.Restore(params.BackupsRestoreArgs{ID: "some-id"})
+	c.Check(err, gc.ErrorMatches, "cannot restore a backup into an environment with 2 state servers")
+	c.Check(s.fake.calls, gc.HasLen, 0)
+}
+
+func (s *restoreSuite) TestRestoreNotOnStateServer(c *gc.C) {
+	s.PatchValue(backupsAPI.StateServerTag, func(*state.State) (names.MachineTag, error) {
+		return names.MachineTag{}, fmt.Errorf("backups can only be restored by a state server")
+	})
+	err := s.api.Restore(params.BackupsRestoreArgs{ID: "some-id"})
+	c.Check(err, gc.ErrorMatches, "backups can only be restored by a state server")
+	c.Check(s.fake.calls, gc.HasLen, 0)
+}
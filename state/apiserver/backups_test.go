@@ -0,0 +1,164 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/backups"
+)
+
+type backupsSuite struct {
+	authHttpSuite
+}
+
+var _ = gc.Suite(&backupsSuite{})
+
+func (s *backupsSuite) backupsURI(c *gc.C, query string) string {
+	uri := s.baseURL(c)
+	uri.Path += "/backups"
+	uri.RawQuery = query
+	return uri.String()
+}
+
+func (s *backupsSuite) addBackup(c *gc.C, content string) string {
+	origin := state.NewBackupOrigin(s.State, "0")
+	metadata := backups.NewMetadata("some-hash", int64(len(content)), *origin, "")
+	metaStorage := state.NewBackupMetadataStorage(s.State)
+	id, err := metaStorage.Add(metadata)
+	c.Assert(err, gc.IsNil)
+	stor, err := environs.GetStorage(s.State)
+	c.Assert(err, gc.IsNil)
+	name := backups.StorageName("juju-backup-" + id + ".tar.gz")
+	err = stor.Put(name, strings.NewReader(content), int64(len(content)))
+	c.Assert(err, gc.IsNil)
+	err = metaStorage.SetStored(id)
+	c.Assert(err, gc.IsNil)
+	return id
+}
+
+func (s *backupsSuite) assertErrorResponse(c *gc.C, resp *http.Response, expCode int, expError string) {
+	body := assertResponse(c, resp, expCode, "application/json")
+	var result params.ErrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Error, gc.NotNil)
+	c.Check(result.Error, gc.ErrorMatches, expError)
+}
+
+func (s *backupsSuite) TestRequiresAuth(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "GET", s.backupsURI(c, ""), "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
+func (s *backupsSuite) TestRequiresGETOrPOST(c *gc.C) {
+	resp, err := s.authRequest(c, "PUT", s.backupsURI(c, ""), "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "PUT"`)
+}
+
+func (s *backupsSuite) TestDownloadRequiresID(c *gc.C) {
+	resp, err := s.authRequest(c, "GET", s.backupsURI(c, ""), "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected id argument")
+}
+
+func (s *backupsSuite) TestDownloadNotFound(c *gc.C) {
+	resp, err := s.authRequest(c, "GET", s.backupsURI(c, "id=spam"), "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusNotFound, `backup metadata "spam" not found`)
+}
+
+func (s *backupsSuite) TestDownload(c *gc.C) {
+	id := s.addBackup(c, "archive data")
+	query := url.Values{"id": {id}}.Encode()
+	resp, err := s.authRequest(c, "GET", s.backupsURI(c, query), "", nil)
+	c.Assert(err, gc.IsNil)
+	c.Check(resp.Header.Get("Digest"), gc.Equals, "SHA=some-hash")
+	body := assertResponse(c, resp, http.StatusOK, "application/x-tar-gz")
+	c.Check(string(body), gc.Equals, "archive data")
+}
+
+func (s *backupsSuite) TestDownloadWithEnvUUID(c *gc.C) {
+	id := s.addBackup(c, "archive data")
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	uri := s.baseURL(c)
+	uri.Path = "/environment/" + env.UUID() + "/backups"
+	uri.RawQuery = url.Values{"id": {id}}.Encode()
+	resp, err := s.authRequest(c, "GET", uri.String(), "", nil)
+	c.Assert(err, gc.IsNil)
+	body := assertResponse(c, resp, http.StatusOK, "application/x-tar-gz")
+	c.Check(string(body), gc.Equals, "archive data")
+}
+
+// archive returns a minimal backup archive of the environment, along
+// with its checksum.
+func (s *backupsSuite) archive(c *gc.C) ([]byte, string) {
+	origin := state.NewBackupOrigin(s.State, "0")
+	data, err := json.Marshal(origin)
+	c.Assert(err, gc.IsNil)
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tarw := tar.NewWriter(gzw)
+	err = tarw.WriteHeader(&tar.Header{
+		Name: "juju-backup/metadata.json",
+		Mode: 0600,
+		Size: int64(len(data)),
+	})
+	c.Assert(err, gc.IsNil)
+	_, err = tarw.Write(data)
+	c.Assert(err, gc.IsNil)
+	c.Assert(tarw.Close(), gc.IsNil)
+	c.Assert(gzw.Close(), gc.IsNil)
+	hash := sha1.Sum(buf.Bytes())
+	return buf.Bytes(), base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func (s *backupsSuite) TestUploadRequiresChecksum(c *gc.C) {
+	resp, err := s.authRequest(c, "POST", s.backupsURI(c, ""), "application/x-tar-gz", strings.NewReader("archive"))
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected checksum argument")
+}
+
+func (s *backupsSuite) TestUploadChecksumMismatch(c *gc.C) {
+	data, _ := s.archive(c)
+	query := url.Values{"checksum": {"bogus"}}.Encode()
+	resp, err := s.authRequest(c, "POST", s.backupsURI(c, query), "application/x-tar-gz", bytes.NewReader(data))
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, `backup archive checksum mismatch: expected "bogus", got .*`)
+}
+
+func (s *backupsSuite) TestUpload(c *gc.C) {
+	data, checksum := s.archive(c)
+	query := url.Values{"checksum": {checksum}}.Encode()
+	resp, err := s.authRequest(c, "POST", s.backupsURI(c, query), "application/x-tar-gz", bytes.NewReader(data))
+	c.Assert(err, gc.IsNil)
+	body := assertResponse(c, resp, http.StatusOK, "application/json")
+	var result params.BackupsMetadataResult
+	err = json.Unmarshal(body, &result)
+	c.Assert(err, gc.IsNil)
+	c.Check(result.Checksum, gc.Equals, checksum)
+	c.Check(result.Size, gc.Equals, int64(len(data)))
+	c.Check(result.Stored, gc.Equals, true)
+	c.Check(result.Environment, gc.Equals, s.State.EnvironTag().Id())
+
+	metadata, err := state.NewBackupMetadataStorage(s.State).Get(result.ID)
+	c.Assert(err, gc.IsNil)
+	c.Check(metadata.CheckSum, gc.Equals, checksum)
+}
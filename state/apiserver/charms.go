@@ -26,6 +26,7 @@ import (
 	"gopkg.in/juju/charm.v3"
 
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api/params"
 )
 
@@ -40,7 +41,12 @@ type charmsHandler struct {
 type bundleContentSenderFunc func(w http.ResponseWriter, r *http.Request, bundle *charm.CharmArchive)
 
 func (h *charmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if err := h.authenticate(r); err != nil {
+	// Reading charms only needs read access; uploading them needs write.
+	access := state.UserAccessRead
+	if r.Method == "POST" {
+		access = state.UserAccessWrite
+	}
+	if err := h.authenticate(r, access); err != nil {
 		h.authError(w, h)
 		return
 	}
@@ -139,6 +139,26 @@ func (s *charmsSuite) TestAuthRequiresUser(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected url=CharmURL query argument")
 }
 
+func (s *charmsSuite) TestUploadRequiresWriteAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Password: "password",
+		Access:   state.UserAccessRead,
+	})
+	ch := charmtesting.Charms.CharmArchive(c.MkDir(), "dummy")
+	file, err := os.Open(ch.Path)
+	c.Assert(err, gc.IsNil)
+	defer file.Close()
+	resp, err := s.sendRequest(c, user.Tag().String(), "password", "POST",
+		s.charmsURI(c, "?series=quantal"), s.archiveContentType, file)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+
+	// Reading charms is allowed.
+	resp, err = s.sendRequest(c, user.Tag().String(), "password", "GET", s.charmsURI(c, ""), "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected url=CharmURL query argument")
+}
+
 func (s *charmsSuite) TestUploadRequiresSeries(c *gc.C) {
 	resp, err := s.authRequest(c, "POST", s.charmsURI(c, ""), "", nil)
 	c.Assert(err, gc.IsNil)
@@ -0,0 +1,175 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver/common"
+)
+
+// EnqueueActions queues the given Actions on their receivers, and
+// returns the tag and status of each Action that was queued.
+func (c *Client) EnqueueActions(args params.ActionsParams) (params.ActionStatusResults, error) {
+	results := params.ActionStatusResults{
+		Results: make([]params.ActionStatusResult, len(args.Actions)),
+	}
+	for i, arg := range args.Actions {
+		receiver, err := c.actionReceiver(arg.Receiver)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		var action *state.Action
+		if service, ok := receiver.(*state.Service); ok {
+			action, err = service.AddActionWithOptions(arg.Name, arg.Params, state.ServiceActionOptions{
+				FirstUnitOnly: arg.FirstUnitOnly,
+				BatchSize:     arg.BatchSize,
+			})
+		} else {
+			action, err = receiver.AddAction(arg.Name, arg.Params)
+		}
+		if verr, ok := err.(*state.ActionValidationError); ok {
+			results.Results[i].ValidationErrors = verr.Errors
+		}
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result, err := pendingActionStatus(action)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// ListActions returns the queued and completed Actions for each of the
+// given ActionReceivers.
+func (c *Client) ListActions(args params.Entities) (params.ReceiverActionsResults, error) {
+	results := params.ReceiverActionsResults{
+		Results: make([]params.ReceiverActionsResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		results.Results[i].Receiver = entity.Tag
+		receiver, err := c.actionReceiver(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		actions, err := receiver.Actions()
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		actionResults, err := receiver.ActionResults()
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		for _, action := range actions {
+			result, err := pendingActionStatus(action)
+			if err != nil {
+				results.Results[i].Error = common.ServerError(err)
+				break
+			}
+			results.Results[i].Actions = append(results.Results[i].Actions, result)
+		}
+		if results.Results[i].Error != nil {
+			continue
+		}
+		for _, result := range actionResults {
+			results.Results[i].Actions = append(results.Results[i].Actions, finishedActionStatus(result))
+		}
+	}
+	return results, nil
+}
+
+// ActionResults returns the current status of each of the Actions
+// identified by the given tags. An Action that is still queued is
+// reported as pending, otherwise its recorded result is returned.
+func (c *Client) ActionResults(args params.Entities) (params.ActionStatusResults, error) {
+	results := params.ActionStatusResults{
+		Results: make([]params.ActionStatusResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		result, err := c.actionStatus(entity.Tag)
+		if err != nil {
+			results.Results[i].ActionTag = entity.Tag
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// actionStatus looks up the Action with the given tag, falling back
+// to its ActionResult when it is no longer queued.
+func (c *Client) actionStatus(tag string) (params.ActionStatusResult, error) {
+	actionTag, err := names.ParseActionTag(tag)
+	if err != nil {
+		return params.ActionStatusResult{}, err
+	}
+	action, err := c.api.state.ActionByTag(actionTag)
+	if err == nil {
+		return pendingActionStatus(action)
+	}
+	if !errors.IsNotFound(err) {
+		return params.ActionStatusResult{}, err
+	}
+	result, err := c.api.state.ActionResultByActionTag(actionTag)
+	if errors.IsNotFound(err) {
+		return params.ActionStatusResult{}, errors.NotFoundf("action %q", actionTag.Id())
+	} else if err != nil {
+		return params.ActionStatusResult{}, err
+	}
+	return finishedActionStatus(result), nil
+}
+
+// actionReceiver returns the ActionReceiver identified by the given
+// unit or service tag.
+func (c *Client) actionReceiver(tag string) (state.ActionReceiver, error) {
+	if serviceTag, err := names.ParseServiceTag(tag); err == nil {
+		return c.api.state.Service(serviceTag.Id())
+	}
+	unitTag, err := names.ParseUnitTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	return c.api.state.Unit(unitTag.Id())
+}
+
+// pendingActionStatus reports a queued Action. For an Action queued on
+// a service, the Results hold the progress of the Action on each of the
+// service's units.
+func pendingActionStatus(action *state.Action) (params.ActionStatusResult, error) {
+	results, err := action.UnitResults()
+	if err != nil {
+		return params.ActionStatusResult{}, err
+	}
+	return params.ActionStatusResult{
+		ActionTag: action.ActionTag().String(),
+		Name:      action.Name(),
+		Params:    action.Payload(),
+		Status:    params.ActionPending,
+		Results:   results,
+	}, nil
+}
+
+func finishedActionStatus(result *state.ActionResult) params.ActionStatusResult {
+	return params.ActionStatusResult{
+		ActionTag: result.ActionTag().String(),
+		Name:      result.ActionName(),
+		Params:    result.Payload(),
+		Status:    string(result.Status()),
+		Results:   result.Results(),
+		Output:    result.Output(),
+	}
+}
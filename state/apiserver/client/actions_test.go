@@ -0,0 +1,228 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+)
+
+type actionsSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&actionsSuite{})
+
+var testActionsYaml = `
+actions:
+   snapshot:
+      description: Take a snapshot of the database.
+      params:
+         type: object
+         properties:
+            outfile:
+               description: The file to write out to.
+               type: string
+               default: foo.bz2
+   backup:
+      description: Back up the database.
+`
+
+func (s *actionsSuite) SetUpTest(c *gc.C) {
+	s.baseSuite.SetUpTest(c)
+	// Add the charms with the actions the tests queue before the
+	// scenario is set up, so they are used in place of the testing
+	// charms.
+	s.AddActionsCharm(c, "wordpress", testActionsYaml)
+	s.AddActionsCharm(c, "mysql", testActionsYaml)
+}
+
+func (s *actionsSuite) TestEnqueueActions(c *gc.C) {
+	s.setUpScenario(c)
+	results, err := s.APIState.Client().EnqueueActions(
+		params.ActionParams{
+			Receiver: "unit-wordpress-0",
+			Name:     "snapshot",
+			Params:   map[string]interface{}{"outfile": "out.tgz"},
+		},
+		params.ActionParams{
+			Receiver: "unit-unknown-0",
+			Name:     "snapshot",
+		},
+		params.ActionParams{
+			Receiver: "machine-1",
+			Name:     "snapshot",
+		},
+	)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0], jc.DeepEquals, params.ActionStatusResult{
+		ActionTag: "action-wordpress/0_a_0",
+		Name:      "snapshot",
+		Params:    map[string]interface{}{"outfile": "out.tgz"},
+		Status:    params.ActionPending,
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `unit "unknown/0" not found`)
+	c.Assert(results[2].Error, gc.ErrorMatches, `.*"machine-1" is not a valid unit tag`)
+
+	unit, err := s.State.Unit("wordpress/0")
+	c.Assert(err, gc.IsNil)
+	queued, err := unit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(queued, gc.HasLen, 1)
+	c.Assert(queued[0].Name(), gc.Equals, "snapshot")
+}
+
+func (s *actionsSuite) TestActionResults(c *gc.C) {
+	s.setUpScenario(c)
+	unit, err := s.State.Unit("wordpress/1")
+	c.Assert(err, gc.IsNil)
+	pending, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	done, err := unit.AddAction("backup", nil)
+	c.Assert(err, gc.IsNil)
+	err = done.Complete("backed up")
+	c.Assert(err, gc.IsNil)
+
+	results, err := s.APIState.Client().ActionResults(
+		pending.Tag().String(),
+		done.Tag().String(),
+		"action-wordpress/1_a_99",
+	)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0].Status, gc.Equals, params.ActionPending)
+	c.Assert(results[0].Name, gc.Equals, "snapshot")
+	c.Assert(results[1].Status, gc.Equals, params.ActionCompleted)
+	c.Assert(results[1].Output, gc.Equals, "backed up")
+	c.Assert(results[1].ActionTag, gc.Equals, done.Tag().String())
+	c.Assert(results[2].Error, gc.ErrorMatches, `action "wordpress/1_a_99" not found`)
+}
+
+func (s *actionsSuite) TestListActions(c *gc.C) {
+	s.setUpScenario(c)
+	unit, err := s.State.Unit("wordpress/0")
+	c.Assert(err, gc.IsNil)
+	_, err = unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	failed, err := unit.AddAction("backup", nil)
+	c.Assert(err, gc.IsNil)
+	err = failed.Fail("disk full")
+	c.Assert(err, gc.IsNil)
+
+	results, err := s.APIState.Client().ListActions("unit-wordpress-0", "unit-wordpress-1")
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[0].Actions, gc.HasLen, 2)
+	c.Assert(results[0].Actions[0].Name, gc.Equals, "snapshot")
+	c.Assert(results[0].Actions[0].Status, gc.Equals, params.ActionPending)
+	c.Assert(results[0].Actions[1].Name, gc.Equals, "backup")
+	c.Assert(results[0].Actions[1].Status, gc.Equals, params.ActionFailed)
+	c.Assert(results[0].Actions[1].Output, gc.Equals, "disk full")
+	c.Assert(results[1].Actions, gc.HasLen, 0)
+}
+
+func (s *actionsSuite) TestEnqueueServiceAction(c *gc.C) {
+	s.setUpScenario(c)
+	results, err := s.APIState.Client().EnqueueActions(
+		params.ActionParams{
+			Receiver:  "service-wordpress",
+			Name:      "snapshot",
+			Params:    map[string]interface{}{"outfile": "out.tgz"},
+			BatchSize: 1,
+		},
+		params.ActionParams{
+			Receiver:      "service-mysql",
+			Name:          "snapshot",
+			FirstUnitOnly: true,
+		},
+	)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 2)
+	unit, err := s.State.Unit("wordpress/0")
+	c.Assert(err, gc.IsNil)
+	queued, err := unit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(queued, gc.HasLen, 1)
+	c.Assert(results[0], jc.DeepEquals, params.ActionStatusResult{
+		ActionTag: "action-wordpress_a_0",
+		Name:      "snapshot",
+		Params:    map[string]interface{}{"outfile": "out.tgz"},
+		Status:    params.ActionPending,
+		Results: map[string]interface{}{
+			"wordpress/0": map[string]interface{}{
+				"status": "pending",
+				"id":     queued[0].Id(),
+			},
+			"wordpress/1": map[string]interface{}{
+				"status": "waiting",
+			},
+		},
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `service "mysql" has no live units`)
+
+	// finishing the first unit moves the action on to the next
+	err = queued[0].Finish(state.ActionCompleted, map[string]interface{}{"path": "out.tgz"}, "done")
+	c.Assert(err, gc.IsNil)
+
+	statuses, err := s.APIState.Client().ActionResults("action-wordpress_a_0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(statuses, gc.HasLen, 1)
+	c.Assert(statuses[0].Status, gc.Equals, params.ActionPending)
+	unit, err = s.State.Unit("wordpress/1")
+	c.Assert(err, gc.IsNil)
+	next, err := unit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(next, gc.HasLen, 1)
+	c.Assert(statuses[0].Results, jc.DeepEquals, map[string]interface{}{
+		"wordpress/0": map[string]interface{}{
+			"status":  "complete",
+			"id":      queued[0].Id(),
+			"results": map[string]interface{}{"path": "out.tgz"},
+			"output":  "done",
+		},
+		"wordpress/1": map[string]interface{}{
+			"status": "pending",
+			"id":     next[0].Id(),
+		},
+	})
+}
+
+func (s *actionsSuite) TestEnqueueActionsValidatesParams(c *gc.C) {
+	s.setUpScenario(c)
+	results, err := s.APIState.Client().EnqueueActions(
+		params.ActionParams{
+			Receiver: "unit-wordpress-0",
+			Name:     "snapshot",
+		},
+		params.ActionParams{
+			Receiver: "unit-wordpress-0",
+			Name:     "snapshop",
+		},
+		params.ActionParams{
+			Receiver: "unit-wordpress-0",
+			Name:     "snapshot",
+			Params:   map[string]interface{}{"outfile": 42},
+		},
+	)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 3)
+
+	// schema defaults are filled in
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[0].Params, jc.DeepEquals, map[string]interface{}{"outfile": "foo.bz2"})
+
+	c.Assert(results[1].Error, gc.ErrorMatches, `invalid action "snapshop": not defined by charm .*`)
+	c.Assert(results[1].Error.Code, gc.Equals, params.CodeActionNotValid)
+	c.Assert(results[1].ValidationErrors, gc.HasLen, 1)
+
+	c.Assert(results[2].Error, gc.ErrorMatches, `invalid action "snapshot": .*`)
+	c.Assert(results[2].Error.Code, gc.Equals, params.CodeActionNotValid)
+	c.Assert(results[2].ValidationErrors, gc.Not(gc.HasLen), 0)
+	c.Assert(results[2].ValidationErrors[0], gc.Matches, ".*outfile.*")
+}
@@ -0,0 +1,36 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/juju/state/api/params"
+)
+
+// StartAgentUpgrade starts upgrading the environment's agents to the
+// given version. If canary machines are given, only the state servers
+// and those machines are upgraded until ContinueAgentUpgrade is called.
+func (c *Client) StartAgentUpgrade(args params.StartAgentUpgrade) error {
+	_, err := c.api.state.StartAgentUpgrade(args.Version, args.Canaries)
+	return err
+}
+
+// ContinueAgentUpgrade upgrades all the environment's agents, once the
+// state servers and canary machines of a staged upgrade have upgraded.
+func (c *Client) ContinueAgentUpgrade() error {
+	au, err := c.api.state.AgentUpgrade()
+	if err != nil {
+		return err
+	}
+	return au.Continue()
+}
+
+// RollBackAgentUpgrade returns the agents that have not yet run
+// upgrade steps to the version they ran before the last upgrade.
+func (c *Client) RollBackAgentUpgrade() error {
+	au, err := c.api.state.AgentUpgrade()
+	if err != nil {
+		return err
+	}
+	return au.RollBack()
+}
@@ -0,0 +1,87 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/version"
+)
+
+type agentUpgradeSuite struct {
+	baseSuite
+	machine *state.Machine
+	newer   version.Number
+}
+
+var _ = gc.Suite(&agentUpgradeSuite{})
+
+func (s *agentUpgradeSuite) SetUpTest(c *gc.C) {
+	s.baseSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	err = s.machine.SetAgentVersion(version.Current)
+	c.Assert(err, gc.IsNil)
+	s.newer = version.Current.Number
+	s.newer.Patch++
+}
+
+func (s *agentUpgradeSuite) TestStartAgentUpgrade(c *gc.C) {
+	client := s.APIState.Client()
+	err := client.StartAgentUpgrade(s.newer, []string{s.machine.Id()})
+	c.Assert(err, gc.IsNil)
+
+	au, err := s.State.AgentUpgrade()
+	c.Assert(err, gc.IsNil)
+	c.Assert(au.TargetVersion(), gc.Equals, s.newer)
+	c.Assert(au.Canaries(), gc.DeepEquals, []string{s.machine.Id()})
+	c.Assert(au.InCanaryStage(), jc.IsTrue)
+
+	// The agent version cannot be set while only the canaries upgrade.
+	err = client.SetEnvironAgentVersion(s.newer)
+	c.Assert(err, gc.ErrorMatches, `agent upgrade to .* in progress`)
+}
+
+func (s *agentUpgradeSuite) TestContinueAgentUpgrade(c *gc.C) {
+	client := s.APIState.Client()
+	err := client.StartAgentUpgrade(s.newer, []string{s.machine.Id()})
+	c.Assert(err, gc.IsNil)
+	err = client.ContinueAgentUpgrade()
+	c.Assert(err, gc.ErrorMatches, `cannot continue agent upgrade to .*: machine \d+ not upgraded`)
+
+	err = s.machine.SetAgentVersion(version.Binary{Number: s.newer, Series: "quantal", Arch: "amd64"})
+	c.Assert(err, gc.IsNil)
+	err = client.ContinueAgentUpgrade()
+	c.Assert(err, gc.IsNil)
+	envConfig, err := s.State.EnvironConfig()
+	c.Assert(err, gc.IsNil)
+	agentVersion, _ := envConfig.AgentVersion()
+	c.Assert(agentVersion, gc.Equals, s.newer)
+}
+
+func (s *agentUpgradeSuite) TestRollBackAgentUpgrade(c *gc.C) {
+	client := s.APIState.Client()
+	err := client.StartAgentUpgrade(s.newer, []string{s.machine.Id()})
+	c.Assert(err, gc.IsNil)
+	err = client.RollBackAgentUpgrade()
+	c.Assert(err, gc.IsNil)
+
+	au, err := s.State.AgentUpgrade()
+	c.Assert(err, gc.IsNil)
+	c.Assert(au.RolledBack(), jc.IsTrue)
+
+	// Once rolled back, the agent version can be set again.
+	err = client.SetEnvironAgentVersion(s.newer)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *agentUpgradeSuite) TestNoAgentUpgrade(c *gc.C) {
+	err := s.APIState.Client().ContinueAgentUpgrade()
+	c.Assert(err, gc.ErrorMatches, "agent upgrade not found")
+	err = s.APIState.Client().RollBackAgentUpgrade()
+	c.Assert(err, gc.ErrorMatches, "agent upgrade not found")
+}
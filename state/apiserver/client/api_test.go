@@ -224,6 +224,7 @@ var scenarioStatus = &api.Status{
 					},
 					AgentState:     "down",
 					AgentStateInfo: "(error: blam)",
+					WorkloadStatus: "unknown",
 					Machine:        "1",
 					Subordinates: map[string]api.UnitStatus{
 						"logging/0": api.UnitStatus{
@@ -231,7 +232,8 @@ var scenarioStatus = &api.Status{
 								Status: "pending",
 								Data:   params.StatusData{},
 							},
-							AgentState: "pending",
+							AgentState:     "pending",
+							WorkloadStatus: "unknown",
 						},
 					},
 				},
@@ -240,15 +242,17 @@ var scenarioStatus = &api.Status{
 						Status: "pending",
 						Data:   params.StatusData{},
 					},
-					AgentState: "pending",
-					Machine:    "2",
+					AgentState:     "pending",
+					WorkloadStatus: "unknown",
+					Machine:        "2",
 					Subordinates: map[string]api.UnitStatus{
 						"logging/1": api.UnitStatus{
 							Agent: api.AgentStatus{
 								Status: "pending",
 								Data:   params.StatusData{},
 							},
-							AgentState: "pending",
+							AgentState:     "pending",
+							WorkloadStatus: "unknown",
 						},
 					},
 				},
@@ -0,0 +1,48 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+)
+
+// AuditLog returns the audit log entries that match the given filter,
+// oldest first.
+func (c *Client) AuditLog(args params.AuditLogFilter) (params.AuditLogResults, error) {
+	filter := state.AuditFilter{
+		Target: args.Entity,
+		Since:  args.Since,
+		Until:  args.Until,
+	}
+	if args.User != "" {
+		if !names.IsValidUser(args.User) {
+			return params.AuditLogResults{}, fmt.Errorf("invalid user name %q", args.User)
+		}
+		filter.User = names.NewUserTag(args.User).String()
+	}
+	entries, err := c.api.state.AuditEntries(filter)
+	if err != nil {
+		return params.AuditLogResults{}, err
+	}
+	results := params.AuditLogResults{
+		Entries: make([]params.AuditLogEntry, len(entries)),
+	}
+	for i, entry := range entries {
+		results.Entries[i] = params.AuditLogEntry{
+			Time:    entry.Time,
+			User:    entry.User,
+			Facade:  entry.Facade,
+			Method:  entry.Method,
+			Targets: entry.Targets,
+			Args:    entry.Args,
+			Error:   entry.Error,
+		}
+	}
+	return results, nil
+}
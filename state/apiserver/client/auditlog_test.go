@@ -0,0 +1,97 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+)
+
+type auditLogSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&auditLogSuite{})
+
+var auditLogTime = time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *auditLogSuite) SetUpTest(c *gc.C) {
+	s.baseSuite.SetUpTest(c)
+	for _, entry := range []state.AuditEntry{{
+		Time:    auditLogTime,
+		User:    "user-admin",
+		Facade:  "Client",
+		Method:  "ServiceDeploy",
+		Targets: []string{"wordpress"},
+		Args:    "<redacted>",
+	}, {
+		Time:    auditLogTime.Add(time.Minute),
+		User:    "user-bob",
+		Facade:  "Client",
+		Method:  "ServiceDestroy",
+		Targets: []string{"wordpress"},
+		Args:    `{"ServiceName":"wordpress"}`,
+	}} {
+		err := s.State.AddAuditEntry(entry)
+		c.Assert(err, gc.IsNil)
+	}
+}
+
+// auditMethods returns the user and method of each audit log entry.
+func auditMethods(entries []params.AuditLogEntry) []string {
+	methods := make([]string, len(entries))
+	for i, entry := range entries {
+		methods[i] = entry.User + " " + entry.Method
+	}
+	return methods
+}
+
+func (s *auditLogSuite) TestAuditLog(c *gc.C) {
+	// The calls made by the test are audited too.
+	err := s.APIState.Client().ServiceExpose("mysql")
+	c.Assert(err, gc.NotNil)
+
+	for i, test := range []struct {
+		filter   params.AuditLogFilter
+		expected []string
+	}{{
+		expected: []string{"user-admin ServiceDeploy", "user-bob ServiceDestroy", "user-admin ServiceExpose"},
+	}, {
+		filter:   params.AuditLogFilter{User: "bob"},
+		expected: []string{"user-bob ServiceDestroy"},
+	}, {
+		filter:   params.AuditLogFilter{Entity: "wordpress", Since: auditLogTime.Add(time.Second)},
+		expected: []string{"user-bob ServiceDestroy"},
+	}, {
+		filter:   params.AuditLogFilter{Entity: "mysql"},
+		expected: []string{"user-admin ServiceExpose"},
+	}, {
+		filter:   params.AuditLogFilter{Until: auditLogTime},
+		expected: []string{},
+	}} {
+		c.Logf("test %d: %+v", i, test.filter)
+		entries, err := s.APIState.Client().AuditLog(test.filter)
+		c.Check(err, gc.IsNil)
+		c.Check(auditMethods(entries), gc.DeepEquals, test.expected)
+	}
+}
+
+func (s *auditLogSuite) TestAuditLogEntry(c *gc.C) {
+	entries, err := s.APIState.Client().AuditLog(params.AuditLogFilter{User: "bob"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Assert(entries[0].Time.Equal(auditLogTime.Add(time.Minute)), gc.Equals, true)
+	c.Assert(entries[0].Facade, gc.Equals, "Client")
+	c.Assert(entries[0].Targets, gc.DeepEquals, []string{"wordpress"})
+	c.Assert(entries[0].Args, gc.Equals, `{"ServiceName":"wordpress"}`)
+}
+
+func (s *auditLogSuite) TestAuditLogInvalidUser(c *gc.C) {
+	_, err := s.APIState.Client().AuditLog(params.AuditLogFilter{User: "bob/1"})
+	c.Assert(err, gc.ErrorMatches, `invalid user name "bob/1"`)
+}
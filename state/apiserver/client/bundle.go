@@ -0,0 +1,480 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v3"
+
+	"github.com/juju/juju/bundle"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver/common"
+)
+
+// DeployBundle deploys the services, units and relations described by
+// a bundle. Anything the bundle describes that is already deployed is
+// left alone, so that a partly deployed bundle can be deployed again.
+// The steps taken are returned, along with the error that stopped the
+// deployment, if any.
+func (c *Client) DeployBundle(args params.DeployBundle) (params.DeployBundleResults, error) {
+	data, err := bundle.Parse([]byte(args.YAML))
+	if err != nil {
+		return params.DeployBundleResults{}, err
+	}
+	envConfig, err := c.api.state.EnvironConfig()
+	if err != nil {
+		return params.DeployBundleResults{}, err
+	}
+	d := &bundleDeployer{
+		client:    c,
+		data:      data,
+		envConfig: envConfig,
+		repo:      config.SpecializeCharmRepo(CharmStore, envConfig),
+	}
+	err = d.deploy()
+	return params.DeployBundleResults{
+		Steps: d.steps,
+		Error: common.ServerError(err),
+	}, nil
+}
+
+// bundleDeployer deploys a bundle, recording the steps taken.
+type bundleDeployer struct {
+	client    *Client
+	data      *bundle.Data
+	envConfig *config.Config
+	repo      charm.Repository
+	steps     []string
+}
+
+func (d *bundleDeployer) step(format string, args ...interface{}) {
+	d.steps = append(d.steps, fmt.Sprintf(format, args...))
+}
+
+func (d *bundleDeployer) deploy() error {
+	order, err := d.data.DeployOrder()
+	if err != nil {
+		return err
+	}
+	for _, name := range order {
+		if err := d.deployService(name, d.data.Services[name]); err != nil {
+			return errors.Annotatef(err, "cannot deploy service %q", name)
+		}
+	}
+	for _, endpoints := range d.data.Relations {
+		if err := d.addRelation(endpoints); err != nil {
+			return errors.Annotatef(err, "cannot add relation %v", endpoints)
+		}
+	}
+	return nil
+}
+
+func (d *bundleDeployer) deployService(name string, spec *bundle.ServiceSpec) error {
+	st := d.client.api.state
+	cons, err := constraints.Parse(spec.Constraints)
+	if err != nil {
+		return err
+	}
+	curl, err := d.charmURL(spec.Charm)
+	if err != nil {
+		return err
+	}
+	svc, err := st.Service(name)
+	if errors.IsNotFound(err) {
+		svc, err = d.addService(name, curl, spec, cons)
+	} else if err == nil {
+		err = d.updateService(svc, curl, spec, cons)
+	}
+	if err != nil {
+		return err
+	}
+	if err := d.addUnits(svc, spec); err != nil {
+		return err
+	}
+	if spec.Expose && !svc.IsExposed() {
+		if err := svc.SetExposed(); err != nil {
+			return err
+		}
+		d.step("exposed service %q", name)
+	}
+	if len(spec.Annotations) == 0 {
+		return nil
+	}
+	annotations, err := svc.Annotations()
+	if err != nil {
+		return err
+	}
+	for key, value := range spec.Annotations {
+		if annotations[key] != value {
+			if err := svc.SetAnnotations(spec.Annotations); err != nil {
+				return err
+			}
+			d.step("set annotations of service %q", name)
+			break
+		}
+	}
+	return nil
+}
+
+// charmURL returns the URL of the charm referred to by a bundle. The
+// revision is left unset when the reference has none.
+func (d *bundleDeployer) charmURL(charmRef string) (*charm.URL, error) {
+	ref, err := charm.ParseReference(charmRef)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Series == "" {
+		if defaultSeries, ok := d.envConfig.DefaultSeries(); ok {
+			ref.Series = defaultSeries
+		}
+	}
+	if ref.Series != "" {
+		return ref.URL("")
+	}
+	if ref.Schema != "cs" {
+		return nil, fmt.Errorf("cannot resolve series for charm %q", ref)
+	}
+	return d.client.resolveCharm(ref, d.repo)
+}
+
+// addService adds the charm of a new service to state if necessary,
+// and deploys the service without units.
+func (d *bundleDeployer) addService(name string, curl *charm.URL, spec *bundle.ServiceSpec, cons constraints.Value) (*state.Service, error) {
+	st := d.client.api.state
+	if curl.Revision < 0 {
+		if curl.Schema != "cs" {
+			return nil, fmt.Errorf("local charm %q must be added with its revision", curl)
+		}
+		revision, err := charm.Latest(d.repo, curl)
+		if err != nil {
+			return nil, err
+		}
+		curl = curl.WithRevision(revision)
+	}
+	ch, err := st.Charm(curl)
+	if errors.IsNotFound(err) && curl.Schema == "cs" {
+		if err := d.client.AddCharm(params.CharmURL{URL: curl.String()}); err != nil {
+			return nil, err
+		}
+		d.step("added charm %q", curl)
+		ch, err = st.Charm(curl)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ch.Meta().Subordinate && spec.NumUnits > 0 {
+		return nil, fmt.Errorf("subordinate service must be deployed without units")
+	}
+	svc, err := juju.DeployService(st, juju.DeployServiceParams{
+		ServiceName:    name,
+		ServiceOwner:   d.client.api.auth.GetAuthTag().String(),
+		Charm:          ch,
+		ConfigSettings: charm.Settings(spec.Options),
+		Constraints:    cons,
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.step("deployed service %q using charm %q", name, curl)
+	return svc, nil
+}
+
+// updateService checks that an existing service uses the bundle's
+// charm, and brings its options and constraints up to date.
+func (d *bundleDeployer) updateService(svc *state.Service, curl *charm.URL, spec *bundle.ServiceSpec, cons constraints.Value) error {
+	ch, _, err := svc.Charm()
+	if err != nil {
+		return err
+	}
+	if !charmMatches(ch.URL(), curl) {
+		return fmt.Errorf("service already deployed with charm %q", ch.URL())
+	}
+	if len(spec.Options) > 0 {
+		options, err := ch.Config().ValidateSettings(charm.Settings(spec.Options))
+		if err != nil {
+			return err
+		}
+		settings, err := svc.ConfigSettings()
+		if err != nil {
+			return err
+		}
+		changes := make(charm.Settings)
+		for name, value := range options {
+			if !reflect.DeepEqual(settings[name], value) {
+				changes[name] = value
+			}
+		}
+		if len(changes) > 0 {
+			if err := svc.UpdateConfigSettings(changes); err != nil {
+				return err
+			}
+			d.step("set options of service %q", svc.Name())
+		}
+	}
+	current, err := svc.Constraints()
+	if err != nil {
+		return err
+	}
+	if current.String() != cons.String() {
+		if err := svc.SetConstraints(cons); err != nil {
+			return err
+		}
+		d.step("set constraints of service %q to %q", svc.Name(), cons.String())
+	}
+	return nil
+}
+
+// charmMatches returns whether a service deployed with the charm with
+// the given URL uses the charm a bundle refers to. A bundle charm
+// without a revision matches any revision.
+func charmMatches(deployed, curl *charm.URL) bool {
+	if curl.Revision < 0 {
+		deployed = deployed.WithRevision(-1)
+	}
+	return *deployed == *curl
+}
+
+// addUnits adds units to a service until it has as many as the bundle
+// gives, placing each new unit as the bundle says.
+func (d *bundleDeployer) addUnits(svc *state.Service, spec *bundle.ServiceSpec) error {
+	units, err := svc.AllUnits()
+	if err != nil {
+		return err
+	}
+	for i := len(units); i < spec.NumUnits; i++ {
+		var machineSpec string
+		if i < len(spec.To) {
+			if machineSpec, err = d.machineSpec(spec.To[i]); err != nil {
+				return err
+			}
+		}
+		added, err := juju.AddUnits(d.client.api.state, svc, 1, machineSpec)
+		if err != nil {
+			return err
+		}
+		machineId, err := added[0].AssignedMachineId()
+		if err != nil {
+			return err
+		}
+		d.step("added unit %q to machine %q", added[0].Name(), machineId)
+	}
+	return nil
+}
+
+// machineSpec returns the machine specification, as accepted by
+// juju.AddUnits, for the given bundle placement. A unit placed with a
+// unit of another service goes to the machine that unit is assigned to.
+func (d *bundleDeployer) machineSpec(placement string) (string, error) {
+	st := d.client.api.state
+	p, err := bundle.ParsePlacement(placement)
+	if err != nil {
+		return "", err
+	}
+	machineId := p.Machine
+	if p.Unit != "" {
+		unit, err := st.Unit(p.Unit)
+		if err != nil {
+			return "", errors.Annotatef(err, "cannot place unit with %q", p.Unit)
+		}
+		if machineId, err = unit.AssignedMachineId(); err != nil {
+			return "", errors.Annotatef(err, "cannot place unit with %q", p.Unit)
+		}
+	} else if _, err := st.Machine(machineId); err != nil {
+		return "", errors.Annotatef(err, "cannot place unit on machine %s", machineId)
+	}
+	if p.ContainerType != "" {
+		return string(p.ContainerType) + ":" + machineId, nil
+	}
+	return machineId, nil
+}
+
+// addRelation adds the relation between the given endpoints unless it
+// already exists.
+func (d *bundleDeployer) addRelation(endpoints []string) error {
+	st := d.client.api.state
+	eps, err := st.InferEndpoints(endpoints)
+	if err != nil {
+		return err
+	}
+	if _, err := st.EndpointsRelation(eps...); err == nil {
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+	rel, err := st.AddRelation(eps...)
+	if err != nil {
+		return err
+	}
+	d.step("added relation %q", rel.String())
+	return nil
+}
+
+// ExportBundle returns a bundle, in YAML, that describes the services
+// in the environment: their charms, the options that differ from the
+// charm defaults, their constraints, exposure, annotations and units,
+// and the relations between them. Units that share a machine or whose
+// container is on the machine of another service's unit are placed
+// with that unit, so that deploying the bundle in another environment
+// reproduces the same topology.
+func (c *Client) ExportBundle() (params.StringResult, error) {
+	data, err := exportBundle(c.api.state)
+	if err != nil {
+		return params.StringResult{}, errors.Annotate(err, "cannot export bundle")
+	}
+	bundleYAML, err := data.YAML()
+	if err != nil {
+		return params.StringResult{}, err
+	}
+	return params.StringResult{Result: string(bundleYAML)}, nil
+}
+
+func exportBundle(st *state.State) (*bundle.Data, error) {
+	services, err := st.AllServices()
+	if err != nil {
+		return nil, err
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("environment has no services")
+	}
+	sort.Sort(servicesByName(services))
+	data := &bundle.Data{Services: make(map[string]*bundle.ServiceSpec)}
+	// hosts holds, for each machine, the unit in the bundle that
+	// units of other services placed on the machine are placed with.
+	hosts := make(map[string]string)
+	for _, svc := range services {
+		spec, err := exportService(svc, hosts)
+		if err != nil {
+			return nil, errors.Annotatef(err, "service %q", svc.Name())
+		}
+		data.Services[svc.Name()] = spec
+	}
+	relations, err := st.AllRelations()
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range relations {
+		eps := rel.Endpoints()
+		if len(eps) != 2 {
+			// Peer relations are added by deploying the service.
+			continue
+		}
+		data.Relations = append(data.Relations, []string{eps[0].String(), eps[1].String()})
+	}
+	sort.Sort(relationsByEndpoints(data.Relations))
+	if err := data.Verify(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func exportService(svc *state.Service, hosts map[string]string) (*bundle.ServiceSpec, error) {
+	ch, _, err := svc.Charm()
+	if err != nil {
+		return nil, err
+	}
+	spec := &bundle.ServiceSpec{
+		Charm:  ch.URL().String(),
+		Expose: svc.IsExposed(),
+	}
+	settings, err := svc.ConfigSettings()
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range settings {
+		if option, ok := ch.Config().Options[name]; ok && reflect.DeepEqual(option.Default, value) {
+			continue
+		}
+		if spec.Options == nil {
+			spec.Options = make(map[string]interface{})
+		}
+		spec.Options[name] = value
+	}
+	cons, err := svc.Constraints()
+	if err != nil {
+		return nil, err
+	}
+	spec.Constraints = cons.String()
+	annotations, err := svc.Annotations()
+	if err != nil {
+		return nil, err
+	}
+	if len(annotations) > 0 {
+		spec.Annotations = annotations
+	}
+	if !svc.IsPrincipal() {
+		return spec, nil
+	}
+	units, err := svc.AllUnits()
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(unitsByNumber(units))
+	// Units placed with units of other services come first, since
+	// the bundle gives placements for the first units only.
+	var placed, unplaced []string
+	var placements []string
+	for _, unit := range units {
+		machineId, err := unit.AssignedMachineId()
+		if state.IsNotAssigned(err) {
+			unplaced = append(unplaced, "")
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if host, ok := hosts[machineId]; ok {
+			placed = append(placed, machineId)
+			placements = append(placements, host)
+		} else if host, ok := hosts[state.ParentId(machineId)]; ok {
+			placed = append(placed, machineId)
+			placements = append(placements, string(state.ContainerTypeFromId(machineId))+":"+host)
+		} else {
+			unplaced = append(unplaced, machineId)
+		}
+	}
+	spec.NumUnits = len(units)
+	spec.To = placements
+	for i, machineId := range append(placed, unplaced...) {
+		if _, ok := hosts[machineId]; !ok && machineId != "" {
+			hosts[machineId] = svc.Name() + "/" + strconv.Itoa(i)
+		}
+	}
+	return spec, nil
+}
+
+type servicesByName []*state.Service
+
+func (s servicesByName) Len() int           { return len(s) }
+func (s servicesByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s servicesByName) Less(i, j int) bool { return s[i].Name() < s[j].Name() }
+
+type unitsByNumber []*state.Unit
+
+func (u unitsByNumber) Len() int      { return len(u) }
+func (u unitsByNumber) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u unitsByNumber) Less(i, j int) bool {
+	return unitNumber(u[i].Name()) < unitNumber(u[j].Name())
+}
+
+func unitNumber(unitName string) int {
+	n, _ := strconv.Atoi(unitName[strings.Index(unitName, "/")+1:])
+	return n
+}
+
+type relationsByEndpoints [][]string
+
+func (r relationsByEndpoints) Len() int      { return len(r) }
+func (r relationsByEndpoints) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r relationsByEndpoints) Less(i, j int) bool {
+	return strings.Join(r[i], " ") < strings.Join(r[j], " ")
+}
@@ -0,0 +1,272 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"fmt"
+
+	jc "github.com/juju/testing/checkers"
+	charmtesting "gopkg.in/juju/charm.v3/testing"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/bundle"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type bundleSuite struct {
+	baseSuite
+	store   *charmtesting.MockCharmStore
+	restore func()
+}
+
+var _ = gc.Suite(&bundleSuite{})
+
+func (s *bundleSuite) SetUpTest(c *gc.C) {
+	s.baseSuite.SetUpTest(c)
+	s.store, s.restore = makeMockCharmStore()
+}
+
+func (s *bundleSuite) TearDownTest(c *gc.C) {
+	s.restore()
+	s.baseSuite.TearDownTest(c)
+}
+
+const wordpressBundle = `
+services:
+  wordpress:
+    charm: cs:precise/wordpress
+    num_units: 1
+    options:
+      blog-title: Bundled
+    constraints: mem=2G
+    expose: true
+    annotations:
+      gui-x: "100"
+  mysql:
+    charm: cs:precise/mysql
+    num_units: 1
+    to: wordpress/0
+relations:
+  - [wordpress:db, mysql:server]
+`
+
+func (s *bundleSuite) assertUnitMachine(c *gc.C, unitName string) string {
+	unit, err := s.State.Unit(unitName)
+	c.Assert(err, gc.IsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, gc.IsNil)
+	return machineId
+}
+
+func (s *bundleSuite) TestDeployBundle(c *gc.C) {
+	wordpressURL, _ := addCharm(c, s.store, "wordpress")
+	mysqlURL, _ := addCharm(c, s.store, "mysql")
+
+	steps, err := s.APIState.Client().DeployBundle(wordpressBundle)
+	c.Assert(err, gc.IsNil)
+
+	machineId := s.assertUnitMachine(c, "wordpress/0")
+	c.Assert(s.assertUnitMachine(c, "mysql/0"), gc.Equals, machineId)
+	eps, err := s.State.InferEndpoints([]string{"wordpress", "mysql"})
+	c.Assert(err, gc.IsNil)
+	rel, err := s.State.EndpointsRelation(eps...)
+	c.Assert(err, gc.IsNil)
+	c.Assert(steps, jc.DeepEquals, []string{
+		fmt.Sprintf("added charm %q", wordpressURL),
+		fmt.Sprintf("deployed service \"wordpress\" using charm %q", wordpressURL),
+		fmt.Sprintf("added unit \"wordpress/0\" to machine %q", machineId),
+		`exposed service "wordpress"`,
+		`set annotations of service "wordpress"`,
+		fmt.Sprintf("added charm %q", mysqlURL),
+		fmt.Sprintf("deployed service \"mysql\" using charm %q", mysqlURL),
+		fmt.Sprintf("added unit \"mysql/0\" to machine %q", machineId),
+		fmt.Sprintf("added relation %q", rel.String()),
+	})
+
+	wordpress, err := s.State.Service("wordpress")
+	c.Assert(err, gc.IsNil)
+	settings, err := wordpress.ConfigSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings["blog-title"], gc.Equals, "Bundled")
+	cons, err := wordpress.Constraints()
+	c.Assert(err, gc.IsNil)
+	c.Assert(cons, gc.DeepEquals, constraints.MustParse("mem=2G"))
+	c.Assert(wordpress.IsExposed(), jc.IsTrue)
+	annotations, err := wordpress.Annotations()
+	c.Assert(err, gc.IsNil)
+	c.Assert(annotations, gc.DeepEquals, map[string]string{"gui-x": "100"})
+}
+
+func (s *bundleSuite) TestDeployBundleAgain(c *gc.C) {
+	addCharm(c, s.store, "wordpress")
+	addCharm(c, s.store, "mysql")
+	_, err := s.APIState.Client().DeployBundle(wordpressBundle)
+	c.Assert(err, gc.IsNil)
+
+	// Nothing is left to do once the bundle is deployed.
+	steps, err := s.APIState.Client().DeployBundle(wordpressBundle)
+	c.Assert(err, gc.IsNil)
+	c.Assert(steps, gc.HasLen, 0)
+
+	// Only the differences are deployed after the bundle changes.
+	steps, err = s.APIState.Client().DeployBundle(`
+services:
+  wordpress:
+    charm: cs:precise/wordpress
+    num_units: 2
+    options:
+      blog-title: Rebundled
+    constraints: mem=4G
+`)
+	c.Assert(err, gc.IsNil)
+	machineId := s.assertUnitMachine(c, "wordpress/1")
+	c.Assert(steps, jc.DeepEquals, []string{
+		`set options of service "wordpress"`,
+		`set constraints of service "wordpress" to "mem=4096M"`,
+		fmt.Sprintf("added unit \"wordpress/1\" to machine %q", machineId),
+	})
+}
+
+func (s *bundleSuite) TestDeployBundlePlacement(c *gc.C) {
+	addCharm(c, s.store, "wordpress")
+	machine, err := s.State.AddMachine("precise", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.APIState.Client().DeployBundle(fmt.Sprintf(`
+services:
+  wordpress:
+    charm: cs:precise/wordpress
+    num_units: 2
+    to: [%s]
+`, machine.Id()))
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.assertUnitMachine(c, "wordpress/0"), gc.Equals, machine.Id())
+	c.Assert(s.assertUnitMachine(c, "wordpress/1"), gc.Not(gc.Equals), machine.Id())
+}
+
+func (s *bundleSuite) TestDeployBundleUnknownMachine(c *gc.C) {
+	addCharm(c, s.store, "wordpress")
+	steps, err := s.APIState.Client().DeployBundle(`
+services:
+  wordpress:
+    charm: cs:precise/wordpress
+    num_units: 1
+    to: "42"
+`)
+	c.Assert(err, gc.ErrorMatches, `cannot deploy service "wordpress": cannot place unit on machine 42: machine 42 not found`)
+	// The steps taken before the error are reported.
+	c.Assert(steps, gc.HasLen, 2)
+}
+
+func (s *bundleSuite) TestDeployBundleServiceWithOtherCharm(c *gc.C) {
+	addCharm(c, s.store, "wordpress")
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "dummy"))
+
+	_, err := s.APIState.Client().DeployBundle(wordpressBundle)
+	c.Assert(err, gc.ErrorMatches, `cannot deploy service "wordpress": service already deployed with charm "local:quantal/dummy-1"`)
+}
+
+func (s *bundleSuite) TestDeployBundleSubordinateWithUnits(c *gc.C) {
+	addCharm(c, s.store, "logging")
+	_, err := s.APIState.Client().DeployBundle(`
+services:
+  logging:
+    charm: cs:precise/logging
+    num_units: 1
+`)
+	c.Assert(err, gc.ErrorMatches, `cannot deploy service "logging": subordinate service must be deployed without units`)
+}
+
+func (s *bundleSuite) TestDeployInvalidBundle(c *gc.C) {
+	_, err := s.APIState.Client().DeployBundle("services: {}")
+	c.Assert(err, gc.ErrorMatches, "invalid bundle: no services")
+}
+
+func (s *bundleSuite) TestExportBundle(c *gc.C) {
+	wordpressURL, _ := addCharm(c, s.store, "wordpress")
+	mysqlURL, _ := addCharm(c, s.store, "mysql")
+	_, err := s.APIState.Client().DeployBundle(wordpressBundle)
+	c.Assert(err, gc.IsNil)
+
+	exported, err := s.APIState.Client().ExportBundle()
+	c.Assert(err, gc.IsNil)
+	data, err := bundle.Parse([]byte(exported))
+	c.Assert(err, gc.IsNil)
+	c.Assert(data.Services, jc.DeepEquals, map[string]*bundle.ServiceSpec{
+		"wordpress": {
+			Charm:       wordpressURL.String(),
+			NumUnits:    1,
+			Options:     map[string]interface{}{"blog-title": "Bundled"},
+			Constraints: "mem=2048M",
+			Expose:      true,
+			Annotations: map[string]string{"gui-x": "100"},
+		},
+		"mysql": {
+			Charm:    mysqlURL.String(),
+			NumUnits: 1,
+			To:       []string{"wordpress/0"},
+		},
+	})
+	c.Assert(data.Relations, gc.HasLen, 1)
+	c.Assert(data.Relations[0], jc.SameContents, []string{"wordpress:db", "mysql:server"})
+
+	// The exported bundle describes what is deployed.
+	steps, err := s.APIState.Client().DeployBundle(exported)
+	c.Assert(err, gc.IsNil)
+	c.Assert(steps, gc.HasLen, 0)
+}
+
+func (s *bundleSuite) TestExportBundlePlacement(c *gc.C) {
+	dummy := s.AddTestingCharm(c, "dummy")
+	logging := s.AddTestingCharm(c, "logging")
+	app := s.AddTestingService(c, "app", dummy)
+	db := s.AddTestingService(c, "db", dummy)
+	s.AddTestingService(c, "logging", logging)
+	host, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, host.Id(), instance.LXC)
+	c.Assert(err, gc.IsNil)
+
+	// app/0 is on a machine of its own; app/1 shares the host with
+	// db/1, and db/0 is in a container on it.
+	for _, assign := range []struct {
+		service *state.Service
+		machine *state.Machine
+	}{
+		{app, nil},
+		{app, host},
+		{db, container},
+		{db, host},
+	} {
+		unit, err := assign.service.AddUnit()
+		c.Assert(err, gc.IsNil)
+		if assign.machine == nil {
+			err = s.State.AssignUnit(unit, state.AssignNew)
+		} else {
+			err = unit.AssignToMachine(assign.machine)
+		}
+		c.Assert(err, gc.IsNil)
+	}
+
+	exported, err := s.APIState.Client().ExportBundle()
+	c.Assert(err, gc.IsNil)
+	data, err := bundle.Parse([]byte(exported))
+	c.Assert(err, gc.IsNil)
+	c.Assert(data.Services["app"].NumUnits, gc.Equals, 2)
+	c.Assert(data.Services["app"].To, gc.HasLen, 0)
+	c.Assert(data.Services["db"].NumUnits, gc.Equals, 2)
+	c.Assert(data.Services["db"].To, gc.DeepEquals, []string{"lxc:app/1", "app/1"})
+	// Subordinate services have no units of their own.
+	c.Assert(data.Services["logging"].NumUnits, gc.Equals, 0)
+}
+
+func (s *bundleSuite) TestExportEmptyEnvironment(c *gc.C) {
+	_, err := s.APIState.Client().ExportBundle()
+	c.Assert(err, gc.ErrorMatches, "cannot export bundle: environment has no services")
+}
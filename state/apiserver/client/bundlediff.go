@@ -0,0 +1,192 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v3"
+
+	"github.com/juju/juju/bundle"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+)
+
+// DiffBundle compares a bundle with the environment, and returns the
+// services and relations that are only in one of them, and the
+// differences between the services in both. Only the options the
+// bundle sets are compared.
+func (c *Client) DiffBundle(args params.DiffBundle) (params.BundleDiff, error) {
+	data, err := bundle.Parse([]byte(args.YAML))
+	if err != nil {
+		return params.BundleDiff{}, err
+	}
+	envConfig, err := c.api.state.EnvironConfig()
+	if err != nil {
+		return params.BundleDiff{}, err
+	}
+	// The deployer is only used to resolve the bundle's charms, as
+	// DeployBundle would.
+	d := &bundleDeployer{
+		client:    c,
+		data:      data,
+		envConfig: envConfig,
+		repo:      config.SpecializeCharmRepo(CharmStore, envConfig),
+	}
+	diff, err := d.diff()
+	if err != nil {
+		return params.BundleDiff{}, errors.Annotate(err, "cannot compare bundle")
+	}
+	return diff, nil
+}
+
+func (d *bundleDeployer) diff() (params.BundleDiff, error) {
+	st := d.client.api.state
+	diff := params.BundleDiff{
+		Services: make(map[string]params.ServiceDiff),
+	}
+	services, err := st.AllServices()
+	if err != nil {
+		return params.BundleDiff{}, err
+	}
+	deployed := make(map[string]*state.Service)
+	for _, svc := range services {
+		deployed[svc.Name()] = svc
+		if _, ok := d.data.Services[svc.Name()]; !ok {
+			diff.ServicesToRemove = append(diff.ServicesToRemove, svc.Name())
+		}
+	}
+	for name, spec := range d.data.Services {
+		svc, ok := deployed[name]
+		if !ok {
+			diff.ServicesToAdd = append(diff.ServicesToAdd, name)
+			continue
+		}
+		serviceDiff, err := d.diffService(svc, spec)
+		if err != nil {
+			return params.BundleDiff{}, errors.Annotatef(err, "service %q", name)
+		}
+		if !reflect.DeepEqual(serviceDiff, params.ServiceDiff{}) {
+			diff.Services[name] = serviceDiff
+		}
+	}
+	sort.Strings(diff.ServicesToAdd)
+	sort.Strings(diff.ServicesToRemove)
+
+	relations, err := st.AllRelations()
+	if err != nil {
+		return params.BundleDiff{}, err
+	}
+	found := make(map[string]bool)
+	for _, endpoints := range d.data.Relations {
+		rel, err := d.findRelation(endpoints)
+		if err != nil {
+			return params.BundleDiff{}, err
+		}
+		if rel == nil {
+			diff.MissingRelations = append(diff.MissingRelations, endpoints)
+			continue
+		}
+		found[rel.String()] = true
+	}
+	for _, rel := range relations {
+		eps := rel.Endpoints()
+		if len(eps) != 2 || found[rel.String()] {
+			continue
+		}
+		diff.ExtraRelations = append(diff.ExtraRelations, []string{eps[0].String(), eps[1].String()})
+	}
+	sort.Sort(relationsByEndpoints(diff.MissingRelations))
+	sort.Sort(relationsByEndpoints(diff.ExtraRelations))
+	return diff, nil
+}
+
+func (d *bundleDeployer) diffService(svc *state.Service, spec *bundle.ServiceSpec) (params.ServiceDiff, error) {
+	var diff params.ServiceDiff
+	ch, _, err := svc.Charm()
+	if err != nil {
+		return diff, err
+	}
+	curl, err := d.charmURL(spec.Charm)
+	if err != nil {
+		return diff, err
+	}
+	if !charmMatches(ch.URL(), curl) {
+		diff.Charm = &params.ValueDiff{Bundle: curl.String(), Environment: ch.URL().String()}
+	}
+	if len(spec.Options) > 0 {
+		options, err := ch.Config().ValidateSettings(charm.Settings(spec.Options))
+		if err != nil {
+			return diff, err
+		}
+		settings, err := svc.ConfigSettings()
+		if err != nil {
+			return diff, err
+		}
+		for name, value := range options {
+			current, ok := settings[name]
+			if !ok {
+				current = ch.Config().Options[name].Default
+			}
+			if reflect.DeepEqual(current, value) {
+				continue
+			}
+			if diff.Options == nil {
+				diff.Options = make(map[string]params.ValueDiff)
+			}
+			diff.Options[name] = params.ValueDiff{Bundle: value, Environment: current}
+		}
+	}
+	bundleCons, err := constraints.Parse(spec.Constraints)
+	if err != nil {
+		return diff, err
+	}
+	cons, err := svc.Constraints()
+	if err != nil {
+		return diff, err
+	}
+	if bundleCons.String() != cons.String() {
+		diff.Constraints = &params.ValueDiff{Bundle: bundleCons.String(), Environment: cons.String()}
+	}
+	if svc.IsPrincipal() {
+		units, err := svc.AllUnits()
+		if err != nil {
+			return diff, err
+		}
+		if len(units) != spec.NumUnits {
+			diff.NumUnits = &params.ValueDiff{Bundle: spec.NumUnits, Environment: len(units)}
+		}
+	}
+	if svc.IsExposed() != spec.Expose {
+		diff.Expose = &params.ValueDiff{Bundle: spec.Expose, Environment: svc.IsExposed()}
+	}
+	return diff, nil
+}
+
+// findRelation returns the relation between the given bundle endpoints,
+// or nil if there is none.
+func (d *bundleDeployer) findRelation(endpoints []string) (*state.Relation, error) {
+	st := d.client.api.state
+	for _, endpoint := range endpoints {
+		if _, err := st.Service(strings.SplitN(endpoint, ":", 2)[0]); errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	eps, err := st.InferEndpoints(endpoints)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := st.EndpointsRelation(eps...)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	return rel, err
+}
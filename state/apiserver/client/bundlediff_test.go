@@ -0,0 +1,93 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state/api/params"
+)
+
+func (s *bundleSuite) TestDiffBundleNoDifferences(c *gc.C) {
+	addCharm(c, s.store, "wordpress")
+	addCharm(c, s.store, "mysql")
+	_, err := s.APIState.Client().DeployBundle(wordpressBundle)
+	c.Assert(err, gc.IsNil)
+
+	diff, err := s.APIState.Client().DiffBundle(wordpressBundle)
+	c.Assert(err, gc.IsNil)
+	c.Assert(diff.ServicesToAdd, gc.HasLen, 0)
+	c.Assert(diff.ServicesToRemove, gc.HasLen, 0)
+	c.Assert(diff.Services, gc.HasLen, 0)
+	c.Assert(diff.MissingRelations, gc.HasLen, 0)
+	c.Assert(diff.ExtraRelations, gc.HasLen, 0)
+}
+
+func (s *bundleSuite) TestDiffBundle(c *gc.C) {
+	addCharm(c, s.store, "wordpress")
+	addCharm(c, s.store, "mysql")
+	_, err := s.APIState.Client().DeployBundle(wordpressBundle)
+	c.Assert(err, gc.IsNil)
+	s.AddTestingService(c, "extra", s.AddTestingCharm(c, "dummy"))
+	eps, err := s.State.InferEndpoints([]string{"wordpress", "mysql"})
+	c.Assert(err, gc.IsNil)
+	rel, err := s.State.EndpointsRelation(eps...)
+	c.Assert(err, gc.IsNil)
+	relEps := rel.Endpoints()
+
+	diff, err := s.APIState.Client().DiffBundle(`
+services:
+  wordpress:
+    charm: cs:precise/wordpress
+    num_units: 3
+    options:
+      blog-title: Changed
+    constraints: mem=4G
+  mysql:
+    charm: cs:precise/mysql
+    num_units: 1
+    to: wordpress/0
+  varnish:
+    charm: cs:precise/varnish
+relations:
+  - [wordpress, varnish]
+`)
+	c.Assert(err, gc.IsNil)
+	c.Assert(diff.ServicesToAdd, gc.DeepEquals, []string{"varnish"})
+	c.Assert(diff.ServicesToRemove, gc.DeepEquals, []string{"extra"})
+	// Values are received as JSON, so numbers are floats.
+	c.Assert(diff.Services, gc.DeepEquals, map[string]params.ServiceDiff{
+		"wordpress": {
+			Options: map[string]params.ValueDiff{
+				"blog-title": {Bundle: "Changed", Environment: "Bundled"},
+			},
+			Constraints: &params.ValueDiff{Bundle: "mem=4096M", Environment: "mem=2048M"},
+			NumUnits:    &params.ValueDiff{Bundle: 3.0, Environment: 1.0},
+			Expose:      &params.ValueDiff{Bundle: false, Environment: true},
+		},
+	})
+	c.Assert(diff.MissingRelations, gc.DeepEquals, [][]string{{"wordpress", "varnish"}})
+	c.Assert(diff.ExtraRelations, gc.DeepEquals, [][]string{{relEps[0].String(), relEps[1].String()}})
+}
+
+func (s *bundleSuite) TestDiffBundleCharm(c *gc.C) {
+	addCharm(c, s.store, "wordpress")
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "dummy"))
+
+	diff, err := s.APIState.Client().DiffBundle(`
+services:
+  wordpress:
+    charm: cs:precise/wordpress
+`)
+	c.Assert(err, gc.IsNil)
+	c.Assert(diff.Services["wordpress"].Charm, gc.DeepEquals, &params.ValueDiff{
+		Bundle:      "cs:precise/wordpress",
+		Environment: "local:quantal/dummy-1",
+	})
+}
+
+func (s *bundleSuite) TestDiffInvalidBundle(c *gc.C) {
+	_, err := s.APIState.Client().DiffBundle("services: {}")
+	c.Assert(err, gc.ErrorMatches, "invalid bundle: no services")
+}
@@ -354,6 +354,13 @@ func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 
 // serviceSetCharm sets the charm for the given service.
 func (c *Client) serviceSetCharm(service *state.Service, url string, force bool) error {
+	// The charm of a service being upgraded a batch at a time may
+	// only be changed by aborting the rolling upgrade.
+	if _, err := c.api.state.RollingUpgrade(service.Name()); err == nil {
+		return fmt.Errorf("rolling upgrade of service %q in progress", service.Name())
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
 	curl, err := charm.ParseURL(url)
 	if err != nil {
 		return err
@@ -878,6 +885,12 @@ func (c *Client) EnvironmentUnset(args params.EnvironmentUnset) error {
 
 // SetEnvironAgentVersion sets the environment agent version.
 func (c *Client) SetEnvironAgentVersion(args params.SetEnvironAgentVersion) error {
+	// The agent version cannot change under a staged upgrade.
+	if au, err := c.api.state.AgentUpgrade(); err == nil && au.InCanaryStage() {
+		return fmt.Errorf("agent upgrade to %s in progress", au.TargetVersion())
+	} else if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
 	return c.api.state.SetEnvironAgentVersion(args.Version)
 }
 
@@ -0,0 +1,67 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+)
+
+// Metrics returns the metrics recorded by units that match the given
+// filter, ordered by time.
+func (c *Client) Metrics(args params.MetricsFilter) (params.MetricsResults, error) {
+	for _, unit := range args.Units {
+		if !names.IsValidUnit(unit) {
+			return params.MetricsResults{}, fmt.Errorf("invalid unit name %q", unit)
+		}
+	}
+	for _, service := range args.Services {
+		if !names.IsValidService(service) {
+			return params.MetricsResults{}, fmt.Errorf("invalid service name %q", service)
+		}
+	}
+	batches, err := c.api.state.MetricBatches(state.MetricFilter{
+		Units:    args.Units,
+		Services: args.Services,
+		Keys:     args.Keys,
+		Since:    args.Since,
+		Until:    args.Until,
+	})
+	if err != nil {
+		return params.MetricsResults{}, err
+	}
+	var results []params.MetricResult
+	for _, batch := range batches {
+		for _, metric := range batch.Metrics() {
+			results = append(results, params.MetricResult{
+				Unit:     batch.Unit(),
+				CharmURL: batch.CharmURL(),
+				Key:      metric.Key(),
+				Value:    metric.Value(),
+				Time:     metric.Time(),
+			})
+		}
+	}
+	sort.Sort(metricsByTime(results))
+	return params.MetricsResults{Metrics: results}, nil
+}
+
+type metricsByTime []params.MetricResult
+
+func (m metricsByTime) Len() int      { return len(m) }
+func (m metricsByTime) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+func (m metricsByTime) Less(i, j int) bool {
+	if !m[i].Time.Equal(m[j].Time) {
+		return m[i].Time.Before(m[j].Time)
+	}
+	if m[i].Unit != m[j].Unit {
+		return m[i].Unit < m[j].Unit
+	}
+	return m[i].Key < m[j].Key
+}
@@ -0,0 +1,126 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing/factory"
+)
+
+type metricsSuite struct {
+	baseSuite
+	factory *factory.Factory
+	now     time.Time
+}
+
+var _ = gc.Suite(&metricsSuite{})
+
+func (s *metricsSuite) SetUpTest(c *gc.C) {
+	s.baseSuite.SetUpTest(c)
+	s.factory = factory.NewFactory(s.State)
+	s.now = time.Now().Round(time.Second).UTC()
+
+	wordpress := s.factory.MakeService(c, &factory.ServiceParams{
+		Name:  "wordpress",
+		Charm: s.factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	mysql := s.factory.MakeService(c, &factory.ServiceParams{
+		Name:  "mysql",
+		Charm: s.factory.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	for _, svc := range []*state.Service{wordpress, wordpress, mysql} {
+		unit := s.factory.MakeUnit(c, &factory.UnitParams{Service: svc})
+		s.factory.MakeMetric(c, &factory.MetricParams{
+			Unit: unit,
+			Metrics: []*state.Metric{
+				state.NewMetric("pings", "5", s.now.Add(-time.Hour), nil),
+				state.NewMetric("users", "2", s.now, nil),
+			},
+		})
+	}
+}
+
+// metricKeys returns the unit and key of each metric.
+func metricKeys(metrics []params.MetricResult) []string {
+	keys := make([]string, len(metrics))
+	for i, metric := range metrics {
+		keys[i] = metric.Unit + " " + metric.Key
+	}
+	return keys
+}
+
+func (s *metricsSuite) TestMetricsAll(c *gc.C) {
+	metrics, err := s.APIState.Client().Metrics(params.MetricsFilter{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(metricKeys(metrics), gc.DeepEquals, []string{
+		"mysql/0 pings",
+		"wordpress/0 pings",
+		"wordpress/1 pings",
+		"mysql/0 users",
+		"wordpress/0 users",
+		"wordpress/1 users",
+	})
+	c.Assert(metrics[0].Value, gc.Equals, "5")
+	c.Assert(metrics[0].CharmURL, gc.Matches, ".*/mysql-.*")
+	c.Assert(metrics[0].Time.Equal(s.now.Add(-time.Hour)), gc.Equals, true)
+}
+
+func (s *metricsSuite) TestMetricsFilter(c *gc.C) {
+	for i, test := range []struct {
+		about  string
+		filter params.MetricsFilter
+		expect []string
+	}{{
+		about:  "by unit",
+		filter: params.MetricsFilter{Units: []string{"wordpress/1"}},
+		expect: []string{"wordpress/1 pings", "wordpress/1 users"},
+	}, {
+		about:  "by service",
+		filter: params.MetricsFilter{Services: []string{"mysql"}},
+		expect: []string{"mysql/0 pings", "mysql/0 users"},
+	}, {
+		about: "by unit and service",
+		filter: params.MetricsFilter{
+			Units:    []string{"wordpress/0"},
+			Services: []string{"wordpress"},
+		},
+		expect: []string{
+			"wordpress/0 pings", "wordpress/1 pings",
+			"wordpress/0 users", "wordpress/1 users",
+		},
+	}, {
+		about:  "by key",
+		filter: params.MetricsFilter{Keys: []string{"users"}},
+		expect: []string{"mysql/0 users", "wordpress/0 users", "wordpress/1 users"},
+	}, {
+		about: "by time",
+		filter: params.MetricsFilter{
+			Services: []string{"wordpress"},
+			Since:    s.now.Add(-2 * time.Hour),
+			Until:    s.now,
+		},
+		expect: []string{"wordpress/0 pings", "wordpress/1 pings"},
+	}, {
+		about:  "nothing matches",
+		filter: params.MetricsFilter{Units: []string{"mysql/1"}},
+		expect: []string{},
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		metrics, err := s.APIState.Client().Metrics(test.filter)
+		c.Check(err, gc.IsNil)
+		c.Check(metricKeys(metrics), gc.DeepEquals, test.expect)
+	}
+}
+
+func (s *metricsSuite) TestMetricsInvalidNames(c *gc.C) {
+	_, err := s.APIState.Client().Metrics(params.MetricsFilter{Units: []string{"wordpress"}})
+	c.Assert(err, gc.ErrorMatches, `invalid unit name "wordpress"`)
+	_, err = s.APIState.Client().Metrics(params.MetricsFilter{Services: []string{"wordpress/0"}})
+	c.Assert(err, gc.ErrorMatches, `invalid service name "wordpress/0"`)
+}
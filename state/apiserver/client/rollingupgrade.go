@@ -0,0 +1,93 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"gopkg.in/juju/charm.v3"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+)
+
+// ServiceRollingUpgrade sets the charm of a service, but upgrades its
+// units a batch at a time. The batches are released by the rolling
+// upgrade worker on the state servers.
+func (c *Client) ServiceRollingUpgrade(args params.ServiceRollingUpgrade) error {
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	curl, err := charm.ParseURL(args.CharmUrl)
+	if err != nil {
+		return err
+	}
+	ch, err := c.api.state.Charm(curl)
+	if err != nil {
+		return err
+	}
+	_, err = service.StartRollingUpgrade(state.RollingUpgradeParams{
+		Charm:      ch,
+		Force:      args.Force,
+		BatchSize:  args.BatchSize,
+		WaitActive: args.WaitActive,
+	})
+	return err
+}
+
+// RollingUpgradeStatus returns the progress of the rolling upgrade of
+// a service.
+func (c *Client) RollingUpgradeStatus(args params.RollingUpgrade) (params.RollingUpgradeStatus, error) {
+	ru, err := c.api.state.RollingUpgrade(args.ServiceName)
+	if err != nil {
+		return params.RollingUpgradeStatus{}, err
+	}
+	held, err := ru.HeldUnits()
+	if err != nil {
+		return params.RollingUpgradeStatus{}, err
+	}
+	batch, started := ru.Batch()
+	paused, reason := ru.Paused()
+	return params.RollingUpgradeStatus{
+		ServiceName:  ru.Service(),
+		FromCharmURL: ru.FromCharmURL().String(),
+		ToCharmURL:   ru.ToCharmURL().String(),
+		BatchSize:    ru.BatchSize(),
+		WaitActive:   ru.WaitActive(),
+		Batch:        batch,
+		BatchStarted: started,
+		HeldUnits:    held,
+		Paused:       paused,
+		PauseReason:  reason,
+	}, nil
+}
+
+// PauseRollingUpgrade stops further units of a service from being
+// upgraded.
+func (c *Client) PauseRollingUpgrade(args params.RollingUpgrade) error {
+	ru, err := c.api.state.RollingUpgrade(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	return ru.Pause("paused by user")
+}
+
+// ResumeRollingUpgrade continues the paused rolling upgrade of a
+// service.
+func (c *Client) ResumeRollingUpgrade(args params.RollingUpgrade) error {
+	ru, err := c.api.state.RollingUpgrade(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	return ru.Resume()
+}
+
+// AbortRollingUpgrade ends the rolling upgrade of a service, setting
+// its charm back to the one it used before.
+func (c *Client) AbortRollingUpgrade(args params.RollingUpgrade) error {
+	ru, err := c.api.state.RollingUpgrade(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	return ru.Abort()
+}
@@ -0,0 +1,97 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+)
+
+type rollingUpgradeSuite struct {
+	baseSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&rollingUpgradeSuite{})
+
+func (s *rollingUpgradeSuite) SetUpTest(c *gc.C) {
+	s.baseSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "service", s.AddTestingCharm(c, "dummy"))
+	for i := 0; i < 3; i++ {
+		_, err := s.service.AddUnit()
+		c.Assert(err, gc.IsNil)
+	}
+	s.AddTestingCharm(c, "wordpress")
+}
+
+func (s *rollingUpgradeSuite) TestServiceRollingUpgrade(c *gc.C) {
+	client := s.APIState.Client()
+	err := client.ServiceRollingUpgrade("service", "local:quantal/wordpress-3", false, 2, true)
+	c.Assert(err, gc.IsNil)
+
+	status, err := client.RollingUpgradeStatus("service")
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, jc.DeepEquals, params.RollingUpgradeStatus{
+		ServiceName:  "service",
+		FromCharmURL: "local:quantal/dummy-1",
+		ToCharmURL:   "local:quantal/wordpress-3",
+		BatchSize:    2,
+		WaitActive:   true,
+		HeldUnits:    []string{"service/0", "service/1", "service/2"},
+	})
+
+	// The charm cannot be set while the rolling upgrade is in progress.
+	err = client.ServiceSetCharm("service", "local:quantal/dummy-1", true)
+	c.Assert(err, gc.ErrorMatches, `rolling upgrade of service "service" in progress`)
+}
+
+func (s *rollingUpgradeSuite) TestServiceRollingUpgradeInvalidBatchSize(c *gc.C) {
+	err := s.APIState.Client().ServiceRollingUpgrade("service", "local:quantal/wordpress-3", false, 0, false)
+	c.Assert(err, gc.ErrorMatches, `cannot start rolling upgrade of service "service": invalid batch size 0`)
+}
+
+func (s *rollingUpgradeSuite) TestPauseResumeRollingUpgrade(c *gc.C) {
+	client := s.APIState.Client()
+	err := client.ServiceRollingUpgrade("service", "local:quantal/wordpress-3", false, 1, false)
+	c.Assert(err, gc.IsNil)
+
+	err = client.PauseRollingUpgrade("service")
+	c.Assert(err, gc.IsNil)
+	status, err := client.RollingUpgradeStatus("service")
+	c.Assert(err, gc.IsNil)
+	c.Assert(status.Paused, jc.IsTrue)
+	c.Assert(status.PauseReason, gc.Equals, "paused by user")
+
+	err = client.ResumeRollingUpgrade("service")
+	c.Assert(err, gc.IsNil)
+	status, err = client.RollingUpgradeStatus("service")
+	c.Assert(err, gc.IsNil)
+	c.Assert(status.Paused, jc.IsFalse)
+}
+
+func (s *rollingUpgradeSuite) TestAbortRollingUpgrade(c *gc.C) {
+	client := s.APIState.Client()
+	err := client.ServiceRollingUpgrade("service", "local:quantal/wordpress-3", false, 1, false)
+	c.Assert(err, gc.IsNil)
+	ru, err := s.State.RollingUpgrade("service")
+	c.Assert(err, gc.IsNil)
+	err = ru.ReleaseBatch([]string{"service/0"}, time.Now())
+	c.Assert(err, gc.IsNil)
+
+	err = client.AbortRollingUpgrade("service")
+	c.Assert(err, gc.IsNil)
+	err = s.service.Refresh()
+	c.Assert(err, gc.IsNil)
+	curl, _ := s.service.CharmURL()
+	c.Assert(curl.String(), gc.Equals, "local:quantal/dummy-1")
+
+	_, err = client.RollingUpgradeStatus("service")
+	c.Assert(err, gc.ErrorMatches, `rolling upgrade of service "service" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
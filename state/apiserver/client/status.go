@@ -34,18 +34,25 @@ func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
 		return noStatus, err
 	}
 	if context.services,
-		context.units, context.latestCharms, err = fetchAllServicesAndUnits(c.api.state, unitMatcher); err != nil {
+		context.units, context.latestCharms, err = fetchAllServicesAndUnits(c.api.state, unitMatcher, args.ErrorsOnly); err != nil {
 		return noStatus, err
 	}
 
 	// Filter machines by units in scope.
 	var machineIds *set.Strings
-	if !unitMatcher.matchesAny() {
+	if !unitMatcher.matchesAny() || args.ErrorsOnly {
 		machineIds, err = fetchUnitMachineIds(context.units)
 		if err != nil {
 			return noStatus, err
 		}
 	}
+	if args.ErrorsOnly && unitMatcher.matchesAny() {
+		// Machines in error are shown even when they host no units
+		// in error, unless the status is restricted to some units.
+		if err := addErrorMachineIds(c.api.state, machineIds); err != nil {
+			return noStatus, err
+		}
+	}
 	if context.machines, err = fetchMachines(c.api.state, machineIds); err != nil {
 		return noStatus, err
 	}
@@ -214,8 +221,10 @@ func fetchMachines(st *state.State, machineIds *set.Strings) (map[string][]*stat
 
 // fetchAllServicesAndUnits returns a map from service name to service,
 // a map from service name to unit name to unit, and a map from base charm URL to latest URL.
+// If errorsOnly is true, only units in error, or with subordinates in
+// error, and their services are returned.
 func fetchAllServicesAndUnits(
-	st *state.State, unitMatcher unitMatcher) (
+	st *state.State, unitMatcher unitMatcher, errorsOnly bool) (
 	map[string]*state.Service, map[string]map[string]*state.Unit, map[charm.URL]string, error) {
 
 	svcMap := make(map[string]*state.Service)
@@ -235,9 +244,18 @@ func fetchAllServicesAndUnits(
 			if !unitMatcher.matchUnit(u) {
 				continue
 			}
+			if errorsOnly {
+				inError, err := unitInError(st, u)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+				if !inError {
+					continue
+				}
+			}
 			svcUnitMap[u.Name()] = u
 		}
-		if unitMatcher.matchesAny() || len(svcUnitMap) > 0 {
+		if (unitMatcher.matchesAny() && !errorsOnly) || len(svcUnitMap) > 0 {
 			unitMap[s.Name()] = svcUnitMap
 			svcMap[s.Name()] = s
 			// Record the base URL for the service's charm so that
@@ -261,6 +279,63 @@ func fetchAllServicesAndUnits(
 	return svcMap, unitMap, latestCharms, nil
 }
 
+// unitInError reports whether the unit, or any of its subordinates, has
+// an error status.
+func unitInError(st *state.State, u *state.Unit) (bool, error) {
+	status, _, _, err := u.Status()
+	if errors.IsNotFound(err) {
+		// The unit has been removed.
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if status == params.StatusError {
+		return true, nil
+	}
+	for _, name := range u.SubordinateNames() {
+		sub, err := st.Unit(name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return false, err
+		}
+		status, _, _, err := sub.Status()
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return false, err
+		}
+		if status == params.StatusError {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// addErrorMachineIds adds the IDs of the machines with an error status,
+// and those machines' ancestors, to machineIds.
+func addErrorMachineIds(st *state.State, machineIds *set.Strings) error {
+	machines, err := st.AllMachines()
+	if err != nil {
+		return err
+	}
+	for _, m := range machines {
+		status, _, _, err := m.Status()
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if status != params.StatusError {
+			continue
+		}
+		for mid := m.Id(); mid != ""; mid = state.ParentId(mid) {
+			machineIds.Add(mid)
+		}
+	}
+	return nil
+}
+
 // fetchUnitMachineIds returns a set of IDs for machines that
 // the specified units reside on, and those machines' ancestors.
 func fetchUnitMachineIds(units map[string]map[string]*state.Unit) (*set.Strings, error) {
@@ -551,6 +626,11 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 	status.AgentVersion = status.Agent.Version
 	status.Life = status.Agent.Life
 	status.Err = status.Agent.Err
+	var err error
+	status.WorkloadStatus, status.WorkloadStatusInfo, err = unit.WorkloadStatus()
+	if err != nil && status.Err == nil {
+		status.Err = err
+	}
 	if subUnits := unit.SubordinateNames(); len(subUnits) > 0 {
 		status.Subordinates = make(map[string]api.UnitStatus)
 		for _, name := range subUnits {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
 )
 
 type statusSuite struct {
@@ -42,6 +43,45 @@ func (s *statusSuite) TestFullStatus(c *gc.C) {
 	c.Check(resultMachine.Series, gc.Equals, machine.Series())
 }
 
+func (s *statusSuite) TestFullStatusErrorsOnly(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	var units []*state.Unit
+	for i := 0; i < 2; i++ {
+		unit, err := wordpress.AddUnit()
+		c.Assert(err, gc.IsNil)
+		err = unit.AssignToMachine(s.addMachine(c))
+		c.Assert(err, gc.IsNil)
+		units = append(units, unit)
+	}
+	err := units[1].SetStatus(params.StatusError, "hook failed: install", nil)
+	c.Assert(err, gc.IsNil)
+	broken := s.addMachine(c)
+	err = broken.SetStatus(params.StatusError, "cannot start instance", nil)
+	c.Assert(err, gc.IsNil)
+	s.addMachine(c)
+
+	client := s.APIState.Client()
+	status, err := client.FullStatus(params.StatusParams{ErrorsOnly: true})
+	c.Assert(err, gc.IsNil)
+	c.Check(status.Services, gc.HasLen, 1)
+	c.Check(status.Services["wordpress"].Units, gc.HasLen, 1)
+	c.Check(status.Services["wordpress"].Units["wordpress/1"].Agent.Status, gc.Equals, params.StatusError)
+	c.Check(status.Machines, gc.HasLen, 2)
+	c.Check(status.Machines["1"].Id, gc.Equals, "1")
+	c.Check(status.Machines[broken.Id()].Id, gc.Equals, broken.Id())
+
+	// Patterns further restrict the status, and machines in error that
+	// host no matching units are left out.
+	status, err = client.FullStatus(params.StatusParams{
+		Patterns:   []string{"mysql"},
+		ErrorsOnly: true,
+	})
+	c.Assert(err, gc.IsNil)
+	c.Check(status.Services, gc.HasLen, 0)
+	c.Check(status.Machines, gc.HasLen, 0)
+}
+
 func (s *statusSuite) TestLegacyStatus(c *gc.C) {
 	machine := s.addMachine(c)
 	instanceId := "i-fakeinstance"
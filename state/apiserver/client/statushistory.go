@@ -0,0 +1,52 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+)
+
+// StatusHistory returns the statuses recently set for a unit or
+// machine, most recent first.
+func (c *Client) StatusHistory(args params.StatusHistory) (params.StatusHistoryResults, error) {
+	if args.Size <= 0 {
+		return params.StatusHistoryResults{}, fmt.Errorf("invalid history size %d", args.Size)
+	}
+	var history []state.StatusInfo
+	var err error
+	switch {
+	case names.IsValidUnit(args.Name):
+		var unit *state.Unit
+		if unit, err = c.api.state.Unit(args.Name); err == nil {
+			history, err = unit.StatusHistory(args.Size)
+		}
+	case names.IsValidMachine(args.Name):
+		var machine *state.Machine
+		if machine, err = c.api.state.Machine(args.Name); err == nil {
+			history, err = machine.StatusHistory(args.Size)
+		}
+	default:
+		return params.StatusHistoryResults{}, fmt.Errorf("%q is not a valid unit name or machine id", args.Name)
+	}
+	if err != nil {
+		return params.StatusHistoryResults{}, err
+	}
+	results := params.StatusHistoryResults{
+		Statuses: make([]params.StatusHistoryEntry, len(history)),
+	}
+	for i, status := range history {
+		results.Statuses[i] = params.StatusHistoryEntry{
+			Status: status.Status,
+			Info:   status.Message,
+			Data:   status.Data,
+			Since:  status.Since,
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing/factory"
+)
+
+type statusHistorySuite struct {
+	baseSuite
+	factory *factory.Factory
+}
+
+var _ = gc.Suite(&statusHistorySuite{})
+
+func (s *statusHistorySuite) SetUpTest(c *gc.C) {
+	s.baseSuite.SetUpTest(c)
+	s.factory = factory.NewFactory(s.State)
+}
+
+func (s *statusHistorySuite) TestUnitStatusHistory(c *gc.C) {
+	unit := s.factory.MakeUnit(c, nil)
+	err := unit.SetStatus(params.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
+	err = unit.SetStatus(params.StatusError, "hook failed", params.StatusData{"hook": "install"})
+	c.Assert(err, gc.IsNil)
+
+	history, err := s.APIState.Client().StatusHistory(unit.Name(), 10)
+	c.Assert(err, gc.IsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Status, gc.Equals, params.StatusError)
+	c.Assert(history[0].Info, gc.Equals, "hook failed")
+	c.Assert(history[0].Data, gc.DeepEquals, params.StatusData{"hook": "install"})
+	c.Assert(history[0].Since.IsZero(), gc.Equals, false)
+	c.Assert(history[1].Status, gc.Equals, params.StatusStarted)
+
+	history, err = s.APIState.Client().StatusHistory(unit.Name(), 1)
+	c.Assert(err, gc.IsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Status, gc.Equals, params.StatusError)
+}
+
+func (s *statusHistorySuite) TestMachineStatusHistory(c *gc.C) {
+	machine := s.factory.MakeMachine(c, nil)
+	err := machine.SetStatus(params.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
+
+	history, err := s.APIState.Client().StatusHistory(machine.Id(), 10)
+	c.Assert(err, gc.IsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Status, gc.Equals, params.StatusStarted)
+}
+
+func (s *statusHistorySuite) TestStatusHistoryErrors(c *gc.C) {
+	client := s.APIState.Client()
+	_, err := client.StatusHistory("wordpress", 10)
+	c.Assert(err, gc.ErrorMatches, `"wordpress" is not a valid unit name or machine id`)
+	_, err = client.StatusHistory("wordpress/0", 0)
+	c.Assert(err, gc.ErrorMatches, "invalid history size 0")
+	_, err = client.StatusHistory("wordpress/0", 10)
+	c.Assert(err, gc.ErrorMatches, `unit "wordpress/0" not found`)
+	_, err = client.StatusHistory("42", 10)
+	c.Assert(err, gc.ErrorMatches, "machine 42 not found")
+}
@@ -72,6 +72,7 @@ var (
 	ErrStoppedWatcher = stderrors.New("watcher has been stopped")
 	ErrBadRequest     = stderrors.New("invalid request")
 	ErrTryAgain       = stderrors.New("try again")
+	ErrLoginLockedOut = stderrors.New("too many failed logins, try again later")
 )
 
 var singletonErrorCodes = map[error]string{
@@ -86,6 +87,7 @@ var singletonErrorCodes = map[error]string{
 	ErrUnknownWatcher:            params.CodeNotFound,
 	ErrStoppedWatcher:            params.CodeStopped,
 	ErrTryAgain:                  params.CodeTryAgain,
+	ErrLoginLockedOut:            params.CodeUnauthorized,
 }
 
 func singletonCode(err error) (string, bool) {
@@ -123,6 +125,8 @@ func ServerError(err error) *params.Error {
 		code = params.CodeNoAddressSet
 	case state.IsNotProvisionedError(err):
 		code = params.CodeNotProvisioned
+	case state.IsActionValidationError(err):
+		code = params.CodeActionNotValid
 	case IsUnknownEnviromentError(err):
 		code = params.CodeNotFound
 	default:
@@ -99,6 +99,14 @@ var errorTransformTests = []struct {
 	err:        common.ErrTryAgain,
 	code:       params.CodeTryAgain,
 	helperFunc: params.IsCodeTryAgain,
+}, {
+	err:        common.ErrLoginLockedOut,
+	code:       params.CodeUnauthorized,
+	helperFunc: params.IsCodeUnauthorized,
+}, {
+	err:        &state.ActionValidationError{"snapshot", []string{"outfile: invalid type"}},
+	code:       params.CodeActionNotValid,
+	helperFunc: params.IsCodeActionNotValid,
 }, {
 	err:  stderrors.New("an error"),
 	code: "",
@@ -23,10 +23,15 @@ type EntityFinderEnvironConfigGetter interface {
 // ToolsGetter implements a common Tools method for use by various
 // facades.
 type ToolsGetter struct {
-	st         EntityFinderEnvironConfigGetter
-	getCanRead GetAuthFunc
+	st           EntityFinderEnvironConfigGetter
+	getCanRead   GetAuthFunc
+	agentVersion AgentVersionFunc
 }
 
+// AgentVersionFunc returns the agent version the entity with the given
+// tag should run, given the environment's agent version.
+type AgentVersionFunc func(tag string, global version.Number) (version.Number, error)
+
 // NewToolsGetter returns a new ToolsGetter. The GetAuthFunc will be
 // used on each invocation of Tools to determine current permissions.
 func NewToolsGetter(st EntityFinderEnvironConfigGetter, getCanRead GetAuthFunc) *ToolsGetter {
@@ -36,6 +41,17 @@ func NewToolsGetter(st EntityFinderEnvironConfigGetter, getCanRead GetAuthFunc)
 	}
 }
 
+// NewAgentVersionToolsGetter returns a new ToolsGetter that finds the
+// tools for the version returned by agentVersion, rather than for the
+// environment's agent version.
+func NewAgentVersionToolsGetter(st EntityFinderEnvironConfigGetter, getCanRead GetAuthFunc, agentVersion AgentVersionFunc) *ToolsGetter {
+	return &ToolsGetter{
+		st:           st,
+		getCanRead:   getCanRead,
+		agentVersion: agentVersion,
+	}
+}
+
 // Tools finds the tools necessary for the given agents.
 func (t *ToolsGetter) Tools(args params.Entities) (params.ToolsResults, error) {
 	result := params.ToolsResults{
@@ -86,6 +102,12 @@ func (t *ToolsGetter) oneAgentTools(canRead AuthFunc, tag string, agentVersion v
 	if !canRead(tag) {
 		return nil, ErrPerm
 	}
+	if t.agentVersion != nil {
+		var err error
+		if agentVersion, err = t.agentVersion(tag, agentVersion); err != nil {
+			return nil, err
+		}
+	}
 	entity, err := t.st.FindEntity(tag)
 	if err != nil {
 		return nil, err
@@ -9,23 +9,21 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
-	"path/filepath"
+	"regexp"
 	"strconv"
-	"strings"
+	"time"
 
 	"code.google.com/p/go.net/websocket"
 	"github.com/juju/loggo"
-	"github.com/juju/utils/tailer"
 	"launchpad.net/tomb"
 
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api/params"
 )
 
 // debugLogHandler takes requests to watch the debug log.
 type debugLogHandler struct {
 	httpHandler
-	logDir string
 }
 
 var maxLinesReached = fmt.Errorf("max lines reached")
@@ -46,11 +44,20 @@ var maxLinesReached = fmt.Errorf("max lines reached")
 //      - has no meaning if 'replay' is true
 //   level -> string one of [TRACE, DEBUG, INFO, WARNING, ERROR]
 //   replay -> string - one of [true, false], if true, start the file from the start
+//   since -> string - an RFC3339 time, only show lines logged at or after it
+//      - all the lines logged since then are shown, so backlog has no meaning
+//   until -> string - an RFC3339 time, only show lines logged at or before it
+//   grep -> string - a regular expression, only show lines whose message matches it
+//   noTail -> string - one of [true, false], if true, close the connection once
+//      the lines already logged have been sent, instead of waiting for more
+//
+// The lines are read from the log records the agents send to the state
+// servers, so all the state servers serve the same log.
 func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	server := websocket.Server{
 		Handler: func(socket *websocket.Conn) {
 			logger.Infof("debug log handler starting")
-			if err := h.authenticate(req); err != nil {
+			if err := h.authenticate(req, state.UserAccessRead); err != nil {
 				h.sendError(socket, fmt.Errorf("auth failed: %v", err))
 				socket.Close()
 				return
@@ -66,20 +73,13 @@ func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 				socket.Close()
 				return
 			}
-			// Open log file.
-			logLocation := filepath.Join(h.logDir, "all-machines.log")
-			logFile, err := os.Open(logLocation)
+			tailer, err := h.state.NewLogTailer(&stream.params)
 			if err != nil {
-				h.sendError(socket, fmt.Errorf("cannot open log file: %v", err))
-				socket.Close()
-				return
-			}
-			defer logFile.Close()
-			if err := stream.positionLogFile(logFile); err != nil {
-				h.sendError(socket, fmt.Errorf("cannot position log file: %v", err))
+				h.sendError(socket, err)
 				socket.Close()
 				return
 			}
+			defer tailer.Stop()
 
 			// If we get to here, no more errors to report, so we report a nil
 			// error.  This way the first line of the socket is always a json
@@ -90,11 +90,10 @@ func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 				return
 			}
 
-			stream.start(logFile, socket)
 			go func() {
 				defer stream.tomb.Done()
 				defer socket.Close()
-				stream.tomb.Kill(stream.loop())
+				stream.tomb.Kill(stream.loop(tailer, socket))
 			}()
 			if err := stream.tomb.Wait(); err != nil {
 				if err != maxLinesReached {
@@ -143,18 +142,66 @@ func newLogStream(queryMap url.Values) (*logStream, error) {
 		}
 	}
 
+	since, err := parseLogTime(queryMap, "since")
+	if err != nil {
+		return nil, err
+	}
+	until, err := parseLogTime(queryMap, "until")
+	if err != nil {
+		return nil, err
+	}
+	if !since.IsZero() && !until.IsZero() && until.Before(since) {
+		return nil, fmt.Errorf("until value %q is before since value %q", queryMap.Get("until"), queryMap.Get("since"))
+	}
+
+	grep := queryMap.Get("grep")
+	if grep != "" {
+		if _, err := regexp.Compile(grep); err != nil {
+			return nil, fmt.Errorf("grep value %q is not a valid regular expression: %v", grep, err)
+		}
+	}
+
+	noTail := false
+	if value := queryMap.Get("noTail"); value != "" {
+		var err error
+		noTail, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("noTail value %q is not a valid boolean", value)
+		}
+	}
+
 	return &logStream{
-		includeEntity: queryMap["includeEntity"],
-		includeModule: queryMap["includeModule"],
-		excludeEntity: queryMap["excludeEntity"],
-		excludeModule: queryMap["excludeModule"],
-		maxLines:      maxLines,
-		fromTheStart:  fromTheStart,
-		backlog:       backlog,
-		filterLevel:   level,
+		params: state.LogTailerParams{
+			MinLevel:      level,
+			IncludeEntity: queryMap["includeEntity"],
+			IncludeModule: queryMap["includeModule"],
+			ExcludeEntity: queryMap["excludeEntity"],
+			ExcludeModule: queryMap["excludeModule"],
+			Since:         since,
+			Until:         until,
+			MessageRegex:  grep,
+			InitialLines:  int(backlog),
+			FromTheStart:  fromTheStart,
+			NoTail:        noTail,
+		},
+		maxLines: maxLines,
 	}, nil
 }
 
+// parseLogTime parses the named time parameter, which is zero if it is
+// not set.
+func parseLogTime(queryMap url.Values, name string) (time.Time, error) {
+	value := queryMap.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s value %q is not a valid RFC3339 time", name, value)
+	}
+	return t, nil
+}
+
 // sendError sends a JSON-encoded error response.
 func (h *debugLogHandler) sendError(w io.Writer, err error) error {
 	response := &params.ErrorResult{}
@@ -172,154 +219,47 @@ func (h *debugLogHandler) sendError(w io.Writer, err error) error {
 	return err
 }
 
-type logLine struct {
-	line   string
-	agent  string
-	level  loggo.Level
-	module string
-}
-
-func parseLogLine(line string) *logLine {
-	const (
-		agentField  = 0
-		levelField  = 3
-		moduleField = 4
-	)
-	fields := strings.Fields(line)
-	result := &logLine{
-		line: line,
-	}
-	if len(fields) > agentField {
-		agent := fields[agentField]
-		if strings.HasSuffix(agent, ":") {
-			result.agent = agent[:len(agent)-1]
-		}
-	}
-	if len(fields) > moduleField {
-		if level, valid := loggo.ParseLevel(fields[levelField]); valid {
-			result.level = level
-			result.module = fields[moduleField]
-		}
-	}
-
-	return result
-}
-
-// logStream runs the tailer to read a log file and stream
-// it via a web socket.
+// logStream sends the log records selected by its parameters to a
+// web socket, one line each.
 type logStream struct {
-	tomb          tomb.Tomb
-	logTailer     *tailer.Tailer
-	filterLevel   loggo.Level
-	includeEntity []string
-	includeModule []string
-	excludeEntity []string
-	excludeModule []string
-	backlog       uint
-	maxLines      uint
-	lineCount     uint
-	fromTheStart  bool
-}
-
-// positionLogFile will update the internal read position of the logFile to be
-// at the end of the file or somewhere in the middle if backlog has been specified.
-func (stream *logStream) positionLogFile(logFile io.ReadSeeker) error {
-	// Seek to the end, or lines back from the end if we need to.
-	if !stream.fromTheStart {
-		return tailer.SeekLastLines(logFile, stream.backlog, stream.filterLine)
-	}
-	return nil
-}
-
-// start the tailer listening to the logFile, and sending the matching
-// lines to the writer.
-func (stream *logStream) start(logFile io.ReadSeeker, writer io.Writer) {
-	stream.logTailer = tailer.NewTailer(logFile, writer, stream.countedFilterLine)
-}
-
-// loop starts the tailer with the log file and the web socket.
-func (stream *logStream) loop() error {
-	select {
-	case <-stream.logTailer.Dead():
-		return stream.logTailer.Err()
-	case <-stream.tomb.Dying():
-		stream.logTailer.Stop()
-	}
-	return nil
-}
-
-// filterLine checks the received line for one of the confgured tags.
-func (stream *logStream) filterLine(line []byte) bool {
-	log := parseLogLine(string(line))
-	return stream.checkIncludeEntity(log) &&
-		stream.checkIncludeModule(log) &&
-		!stream.exclude(log) &&
-		stream.checkLevel(log)
-}
-
-// countedFilterLine checks the received line for one of the confgured tags,
-// and also checks to make sure the stream doesn't send more than the
-// specified number of lines.
-func (stream *logStream) countedFilterLine(line []byte) bool {
-	result := stream.filterLine(line)
-	if result && stream.maxLines > 0 {
-		stream.lineCount++
-		result = stream.lineCount <= stream.maxLines
-		if stream.lineCount == stream.maxLines {
-			stream.tomb.Kill(maxLinesReached)
-		}
-	}
-	return result
+	tomb      tomb.Tomb
+	params    state.LogTailerParams
+	maxLines  uint
+	lineCount uint
 }
 
-func (stream *logStream) checkIncludeEntity(line *logLine) bool {
-	if len(stream.includeEntity) == 0 {
-		return true
-	}
-	for _, value := range stream.includeEntity {
-		// special handling, if ends with '*', check prefix
-		if strings.HasSuffix(value, "*") {
-			if strings.HasPrefix(line.agent, value[:len(value)-1]) {
-				return true
+// loop sends the records received from the tailer to the writer until
+// the stream is stopped, maxLines records have been sent, or the tailer
+// has no more records to send.
+func (stream *logStream) loop(tailer state.LogTailer, writer io.Writer) error {
+	for {
+		select {
+		case <-stream.tomb.Dying():
+			return nil
+		case record, ok := <-tailer.Logs():
+			if !ok {
+				return tailer.Err()
 			}
-		} else if line.agent == value {
-			return true
-		}
-	}
-	return false
-}
-
-func (stream *logStream) checkIncludeModule(line *logLine) bool {
-	if len(stream.includeModule) == 0 {
-		return true
-	}
-	for _, value := range stream.includeModule {
-		if strings.HasPrefix(line.module, value) {
-			return true
-		}
-	}
-	return false
-}
-
-func (stream *logStream) exclude(line *logLine) bool {
-	for _, value := range stream.excludeEntity {
-		// special handling, if ends with '*', check prefix
-		if strings.HasSuffix(value, "*") {
-			if strings.HasPrefix(line.agent, value[:len(value)-1]) {
-				return true
+			if _, err := io.WriteString(writer, formatLogRecord(record)); err != nil {
+				return err
+			}
+			stream.lineCount++
+			if stream.maxLines > 0 && stream.lineCount >= stream.maxLines {
+				return maxLinesReached
 			}
-		} else if line.agent == value {
-			return true
-		}
-	}
-	for _, value := range stream.excludeModule {
-		if strings.HasPrefix(line.module, value) {
-			return true
 		}
 	}
-	return false
 }
 
-func (stream *logStream) checkLevel(line *logLine) bool {
-	return line.level >= stream.filterLevel
+// formatLogRecord returns the line showing the record, in the same
+// format as the agents' log files, prefixed by the entity's tag.
+func formatLogRecord(record *state.LogRecord) string {
+	return fmt.Sprintf("%s: %s %s %s %s %s\n",
+		record.Entity,
+		record.Time.UTC().Format("2006-01-02 15:04:05"),
+		record.Level,
+		record.Module,
+		record.Location,
+		record.Message,
+	)
 }
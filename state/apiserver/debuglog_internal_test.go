@@ -7,15 +7,16 @@ package apiserver
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"net/url"
-	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
 	gc "launchpad.net/gocheck"
 
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 )
 
@@ -25,292 +26,113 @@ type debugInternalSuite struct {
 
 var _ = gc.Suite(&debugInternalSuite{})
 
-func (s *debugInternalSuite) TestParseLogLine(c *gc.C) {
-	line := "machine-0: 2014-03-24 22:34:25 INFO juju.cmd.jujud machine.go:127 machine agent machine-0 start (1.17.7.1-trusty-amd64 [gc])"
-	logLine := parseLogLine(line)
-	c.Assert(logLine.line, gc.Equals, line)
-	c.Assert(logLine.agent, gc.Equals, "machine-0")
-	c.Assert(logLine.level, gc.Equals, loggo.INFO)
-	c.Assert(logLine.module, gc.Equals, "juju.cmd.jujud")
-}
-
-func (s *debugInternalSuite) TestParseLogLineMachineMultiline(c *gc.C) {
-	line := "machine-1: continuation line"
-	logLine := parseLogLine(line)
-	c.Assert(logLine.line, gc.Equals, line)
-	c.Assert(logLine.agent, gc.Equals, "machine-1")
-	c.Assert(logLine.level, gc.Equals, loggo.UNSPECIFIED)
-	c.Assert(logLine.module, gc.Equals, "")
-}
-
-func (s *debugInternalSuite) TestParseLogLineInvalid(c *gc.C) {
-	line := "not a full line"
-	logLine := parseLogLine(line)
-	c.Assert(logLine.line, gc.Equals, line)
-	c.Assert(logLine.agent, gc.Equals, "")
-	c.Assert(logLine.level, gc.Equals, loggo.UNSPECIFIED)
-	c.Assert(logLine.module, gc.Equals, "")
-}
-
-func checkLevel(logValue, streamValue loggo.Level) bool {
-	stream := &logStream{}
-	if streamValue != loggo.UNSPECIFIED {
-		stream.filterLevel = streamValue
+func (s *debugInternalSuite) TestFormatLogRecord(c *gc.C) {
+	line := formatLogRecord(&state.LogRecord{
+		Time:     time.Date(2014, 3, 24, 22, 34, 25, 0, time.UTC),
+		Entity:   "machine-0",
+		Module:   "juju.cmd.jujud",
+		Location: "machine.go:127",
+		Level:    loggo.INFO,
+		Message:  "machine agent machine-0 start",
+	})
+	c.Assert(line, gc.Equals, "machine-0: 2014-03-24 22:34:25 INFO juju.cmd.jujud machine.go:127 machine agent machine-0 start\n")
+}
+
+// fakeTailer is a state.LogTailer that sends the records written to
+// its channel.
+type fakeTailer struct {
+	logs  chan *state.LogRecord
+	dying chan struct{}
+	err   error
+}
+
+func newFakeTailer() *fakeTailer {
+	return &fakeTailer{
+		logs:  make(chan *state.LogRecord),
+		dying: make(chan struct{}),
 	}
-	line := &logLine{level: logValue}
-	return stream.checkLevel(line)
-}
-
-func (s *debugInternalSuite) TestCheckLevel(c *gc.C) {
-	c.Check(checkLevel(loggo.UNSPECIFIED, loggo.UNSPECIFIED), jc.IsTrue)
-	c.Check(checkLevel(loggo.TRACE, loggo.UNSPECIFIED), jc.IsTrue)
-	c.Check(checkLevel(loggo.DEBUG, loggo.UNSPECIFIED), jc.IsTrue)
-	c.Check(checkLevel(loggo.INFO, loggo.UNSPECIFIED), jc.IsTrue)
-	c.Check(checkLevel(loggo.WARNING, loggo.UNSPECIFIED), jc.IsTrue)
-	c.Check(checkLevel(loggo.ERROR, loggo.UNSPECIFIED), jc.IsTrue)
-	c.Check(checkLevel(loggo.CRITICAL, loggo.UNSPECIFIED), jc.IsTrue)
-
-	c.Check(checkLevel(loggo.UNSPECIFIED, loggo.TRACE), jc.IsFalse)
-	c.Check(checkLevel(loggo.TRACE, loggo.TRACE), jc.IsTrue)
-	c.Check(checkLevel(loggo.DEBUG, loggo.TRACE), jc.IsTrue)
-	c.Check(checkLevel(loggo.INFO, loggo.TRACE), jc.IsTrue)
-	c.Check(checkLevel(loggo.WARNING, loggo.TRACE), jc.IsTrue)
-	c.Check(checkLevel(loggo.ERROR, loggo.TRACE), jc.IsTrue)
-	c.Check(checkLevel(loggo.CRITICAL, loggo.TRACE), jc.IsTrue)
-
-	c.Check(checkLevel(loggo.UNSPECIFIED, loggo.INFO), jc.IsFalse)
-	c.Check(checkLevel(loggo.TRACE, loggo.INFO), jc.IsFalse)
-	c.Check(checkLevel(loggo.DEBUG, loggo.INFO), jc.IsFalse)
-	c.Check(checkLevel(loggo.INFO, loggo.INFO), jc.IsTrue)
-	c.Check(checkLevel(loggo.WARNING, loggo.INFO), jc.IsTrue)
-	c.Check(checkLevel(loggo.ERROR, loggo.INFO), jc.IsTrue)
-	c.Check(checkLevel(loggo.CRITICAL, loggo.INFO), jc.IsTrue)
-}
-
-func checkIncludeEntity(logValue string, agent ...string) bool {
-	stream := &logStream{includeEntity: agent}
-	line := &logLine{agent: logValue}
-	return stream.checkIncludeEntity(line)
-}
-
-func (s *debugInternalSuite) TestCheckIncludeEntity(c *gc.C) {
-	c.Check(checkIncludeEntity("machine-0"), jc.IsTrue)
-	c.Check(checkIncludeEntity("machine-0", "machine-0"), jc.IsTrue)
-	c.Check(checkIncludeEntity("machine-1", "machine-0"), jc.IsFalse)
-	c.Check(checkIncludeEntity("machine-1", "machine-0", "machine-1"), jc.IsTrue)
-	c.Check(checkIncludeEntity("machine-0-lxc-0", "machine-0"), jc.IsFalse)
-	c.Check(checkIncludeEntity("machine-0-lxc-0", "machine-0*"), jc.IsTrue)
-	c.Check(checkIncludeEntity("machine-0-lxc-0", "machine-0-lxc-*"), jc.IsTrue)
-}
-
-func checkIncludeModule(logValue string, module ...string) bool {
-	stream := &logStream{includeModule: module}
-	line := &logLine{module: logValue}
-	return stream.checkIncludeModule(line)
-}
-
-func (s *debugInternalSuite) TestCheckIncludeModule(c *gc.C) {
-	c.Check(checkIncludeModule("juju"), jc.IsTrue)
-	c.Check(checkIncludeModule("juju", "juju"), jc.IsTrue)
-	c.Check(checkIncludeModule("juju", "juju.environ"), jc.IsFalse)
-	c.Check(checkIncludeModule("juju.provisioner", "juju"), jc.IsTrue)
-	c.Check(checkIncludeModule("juju.provisioner", "juju*"), jc.IsFalse)
-	c.Check(checkIncludeModule("juju.provisioner", "juju.environ"), jc.IsFalse)
-	c.Check(checkIncludeModule("unit.mysql/1", "juju", "unit"), jc.IsTrue)
-}
-
-func checkExcludeEntity(logValue string, agent ...string) bool {
-	stream := &logStream{excludeEntity: agent}
-	line := &logLine{agent: logValue}
-	return stream.exclude(line)
-}
-
-func (s *debugInternalSuite) TestCheckExcludeEntity(c *gc.C) {
-	c.Check(checkExcludeEntity("machine-0"), jc.IsFalse)
-	c.Check(checkExcludeEntity("machine-0", "machine-0"), jc.IsTrue)
-	c.Check(checkExcludeEntity("machine-1", "machine-0"), jc.IsFalse)
-	c.Check(checkExcludeEntity("machine-1", "machine-0", "machine-1"), jc.IsTrue)
-	c.Check(checkExcludeEntity("machine-0-lxc-0", "machine-0"), jc.IsFalse)
-	c.Check(checkExcludeEntity("machine-0-lxc-0", "machine-0*"), jc.IsTrue)
-	c.Check(checkExcludeEntity("machine-0-lxc-0", "machine-0-lxc-*"), jc.IsTrue)
-}
-
-func checkExcludeModule(logValue string, module ...string) bool {
-	stream := &logStream{excludeModule: module}
-	line := &logLine{module: logValue}
-	return stream.exclude(line)
-}
-
-func (s *debugInternalSuite) TestCheckExcludeModule(c *gc.C) {
-	c.Check(checkExcludeModule("juju"), jc.IsFalse)
-	c.Check(checkExcludeModule("juju", "juju"), jc.IsTrue)
-	c.Check(checkExcludeModule("juju", "juju.environ"), jc.IsFalse)
-	c.Check(checkExcludeModule("juju.provisioner", "juju"), jc.IsTrue)
-	c.Check(checkExcludeModule("juju.provisioner", "juju*"), jc.IsFalse)
-	c.Check(checkExcludeModule("juju.provisioner", "juju.environ"), jc.IsFalse)
-	c.Check(checkExcludeModule("unit.mysql/1", "juju", "unit"), jc.IsTrue)
 }
 
-func (s *debugInternalSuite) TestFilterLine(c *gc.C) {
-	stream := &logStream{
-		filterLevel:   loggo.INFO,
-		includeEntity: []string{"machine-0", "unit-mysql*"},
-		includeModule: []string{"juju"},
-		excludeEntity: []string{"unit-mysql-2"},
-		excludeModule: []string{"juju.foo"},
-	}
-	c.Check(stream.filterLine([]byte(
-		"machine-0: date time WARNING juju")), jc.IsTrue)
-	c.Check(stream.filterLine([]byte(
-		"machine-1: date time WARNING juju")), jc.IsFalse)
-	c.Check(stream.filterLine([]byte(
-		"unit-mysql-0: date time WARNING juju")), jc.IsTrue)
-	c.Check(stream.filterLine([]byte(
-		"unit-mysql-1: date time WARNING juju")), jc.IsTrue)
-	c.Check(stream.filterLine([]byte(
-		"unit-mysql-2: date time WARNING juju")), jc.IsFalse)
-	c.Check(stream.filterLine([]byte(
-		"unit-wordpress-0: date time WARNING juju")), jc.IsFalse)
-	c.Check(stream.filterLine([]byte(
-		"machine-0: date time DEBUG juju")), jc.IsFalse)
-	c.Check(stream.filterLine([]byte(
-		"machine-0: date time WARNING juju.foo.bar")), jc.IsFalse)
-}
+func (t *fakeTailer) Logs() <-chan *state.LogRecord { return t.logs }
+func (t *fakeTailer) Dying() <-chan struct{}        { return t.dying }
+func (t *fakeTailer) Stop() error                   { return t.err }
+func (t *fakeTailer) Err() error                    { return t.err }
 
-func (s *debugInternalSuite) TestCountedFilterLineWithLimit(c *gc.C) {
-	stream := &logStream{
-		filterLevel: loggo.INFO,
-		maxLines:    5,
+func (t *fakeTailer) send(c *gc.C, messages ...string) {
+	for _, message := range messages {
+		select {
+		case t.logs <- &state.LogRecord{Entity: "machine-0", Level: loggo.INFO, Message: message}:
+		case <-time.After(testing.LongWait):
+			c.Fatalf("timed out sending %q", message)
+		}
 	}
-	line := []byte("machine-0: date time WARNING juju")
-	c.Check(stream.countedFilterLine(line), jc.IsTrue)
-	c.Check(stream.countedFilterLine(line), jc.IsTrue)
-	c.Check(stream.countedFilterLine(line), jc.IsTrue)
-	c.Check(stream.countedFilterLine(line), jc.IsTrue)
-	c.Check(stream.countedFilterLine(line), jc.IsTrue)
-	c.Check(stream.countedFilterLine(line), jc.IsFalse)
-	c.Check(stream.countedFilterLine(line), jc.IsFalse)
 }
 
-type chanWriter struct {
-	ch chan []byte
-}
-
-func (w *chanWriter) Write(buf []byte) (n int, err error) {
-	bufcopy := append([]byte{}, buf...)
-	w.ch <- bufcopy
-	return len(buf), nil
-}
-
-func (s *debugInternalSuite) testStreamInternal(c *gc.C, fromTheStart bool, backlog, maxLines uint, expected, errMatch string) {
-
-	dir := c.MkDir()
-	logPath := filepath.Join(dir, "logfile.txt")
-	logFile, err := os.Create(logPath)
-	c.Assert(err, gc.IsNil)
-	defer logFile.Close()
-	logFileReader, err := os.Open(logPath)
-	c.Assert(err, gc.IsNil)
-	defer logFileReader.Close()
-
-	logFile.WriteString(`line 1
-line 2
-line 3
-`)
-	stream := &logStream{
-		fromTheStart: fromTheStart,
-		backlog:      backlog,
-		maxLines:     maxLines,
-	}
-	err = stream.positionLogFile(logFileReader)
-	c.Assert(err, gc.IsNil)
+func (s *debugInternalSuite) testStreamInternal(c *gc.C, maxLines uint, messages []string, expected []string, errMatch string) {
+	tailer := newFakeTailer()
+	stream := &logStream{maxLines: maxLines}
 	var output bytes.Buffer
-	writer := &chanWriter{make(chan []byte)}
-	stream.start(logFileReader, writer)
-	defer stream.logTailer.Wait()
-
 	go func() {
 		defer stream.tomb.Done()
-		stream.tomb.Kill(stream.loop())
+		stream.tomb.Kill(stream.loop(tailer, &output))
 	}()
-
-	logFile.WriteString("line 4\n")
-	logFile.WriteString("line 5\n")
-
-	timeout := time.After(testing.LongWait)
-	for output.String() != expected {
-		select {
-		case buf := <-writer.ch:
-			output.Write(buf)
-		case <-timeout:
-			c.Fatalf("expected data didn't arrive:\n\tobtained: %#v\n\texpected: %#v", output.String(), expected)
-		}
+	tailer.send(c, messages...)
+	if errMatch == "" {
+		stream.tomb.Kill(nil)
 	}
-
-	stream.logTailer.Stop()
-
-	err = stream.tomb.Wait()
+	err := stream.tomb.Wait()
 	if errMatch == "" {
 		c.Assert(err, gc.IsNil)
 	} else {
 		c.Assert(err, gc.ErrorMatches, errMatch)
 	}
+	var expectedOutput string
+	for _, message := range expected {
+		expectedOutput += fmt.Sprintf("machine-0: 0001-01-01 00:00:00 INFO   %s\n", message)
+	}
+	c.Assert(output.String(), gc.Equals, expectedOutput)
 }
 
-func (s *debugInternalSuite) TestLogStreamLoopFromTheStart(c *gc.C) {
-	expected := `line 1
-line 2
-line 3
-line 4
-line 5
-`
-	s.testStreamInternal(c, true, 0, 0, expected, "")
-}
-
-func (s *debugInternalSuite) TestLogStreamLoopFromTheStartMaxLines(c *gc.C) {
-	expected := `line 1
-line 2
-line 3
-`
-	s.testStreamInternal(c, true, 0, 3, expected, "max lines reached")
+func (s *debugInternalSuite) TestLogStreamLoop(c *gc.C) {
+	messages := []string{"line 1", "line 2", "line 3"}
+	s.testStreamInternal(c, 0, messages, messages, "")
 }
 
-func (s *debugInternalSuite) TestLogStreamLoopJustTail(c *gc.C) {
-	expected := `line 4
-line 5
-`
-	s.testStreamInternal(c, false, 0, 0, expected, "")
+func (s *debugInternalSuite) TestLogStreamLoopMaxLines(c *gc.C) {
+	messages := []string{"line 1", "line 2", "line 3"}
+	s.testStreamInternal(c, 3, messages, messages, "max lines reached")
 }
 
-func (s *debugInternalSuite) TestLogStreamLoopBackOneLimitTwo(c *gc.C) {
-	expected := `line 3
-line 4
-`
-	s.testStreamInternal(c, false, 1, 2, expected, "max lines reached")
+func (s *debugInternalSuite) TestLogStreamLoopMaxLinesNotYetReached(c *gc.C) {
+	messages := []string{"line 1", "line 2"}
+	s.testStreamInternal(c, 3, messages, messages, "")
 }
 
-func (s *debugInternalSuite) TestLogStreamLoopTailMaxLinesNotYetReached(c *gc.C) {
-	expected := `line 4
-line 5
-`
-	s.testStreamInternal(c, false, 0, 3, expected, "")
+func (s *debugInternalSuite) TestLogStreamLoopTailerError(c *gc.C) {
+	tailer := newFakeTailer()
+	tailer.err = errors.New("boom")
+	close(tailer.logs)
+	stream := &logStream{}
+	var output bytes.Buffer
+	err := stream.loop(tailer, &output)
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(output.String(), gc.Equals, "")
 }
 
-func assertStreamParams(c *gc.C, obtained, expected *logStream) {
-	c.Check(obtained.includeEntity, jc.DeepEquals, expected.includeEntity)
-	c.Check(obtained.includeModule, jc.DeepEquals, expected.includeModule)
-	c.Check(obtained.excludeEntity, jc.DeepEquals, expected.excludeEntity)
-	c.Check(obtained.excludeModule, jc.DeepEquals, expected.excludeModule)
-	c.Check(obtained.maxLines, gc.Equals, expected.maxLines)
-	c.Check(obtained.fromTheStart, gc.Equals, expected.fromTheStart)
-	c.Check(obtained.filterLevel, gc.Equals, expected.filterLevel)
-	c.Check(obtained.backlog, gc.Equals, expected.backlog)
+func (s *debugInternalSuite) TestLogStreamLoopTailerDone(c *gc.C) {
+	tailer := newFakeTailer()
+	close(tailer.logs)
+	stream := &logStream{}
+	var output bytes.Buffer
+	err := stream.loop(tailer, &output)
+	c.Assert(err, gc.IsNil)
 }
 
 func (s *debugInternalSuite) TestNewLogStream(c *gc.C) {
 	obtained, err := newLogStream(nil)
 	c.Assert(err, gc.IsNil)
-	assertStreamParams(c, obtained, &logStream{})
+	c.Assert(obtained.params, jc.DeepEquals, state.LogTailerParams{})
+	c.Assert(obtained.maxLines, gc.Equals, uint(0))
 
 	values := url.Values{
 		"includeEntity": []string{"machine-1*", "machine-2"},
@@ -320,22 +142,29 @@ func (s *debugInternalSuite) TestNewLogStream(c *gc.C) {
 		"maxLines":      []string{"300"},
 		"backlog":       []string{"100"},
 		"level":         []string{"INFO"},
+		"since":         []string{"2014-09-01T02:10:00Z"},
+		"until":         []string{"2014-09-01T02:20:00Z"},
+		"grep":          []string{"hook failed"},
+		"noTail":        []string{"true"},
 		// OK, just a little nonsense
 		"replay": []string{"true"},
 	}
-	expected := &logStream{
-		includeEntity: []string{"machine-1*", "machine-2"},
-		includeModule: []string{"juju", "unit"},
-		excludeEntity: []string{"machine-1-lxc*"},
-		excludeModule: []string{"juju.provisioner"},
-		maxLines:      300,
-		backlog:       100,
-		filterLevel:   loggo.INFO,
-		fromTheStart:  true,
-	}
 	obtained, err = newLogStream(values)
 	c.Assert(err, gc.IsNil)
-	assertStreamParams(c, obtained, expected)
+	c.Assert(obtained.params, jc.DeepEquals, state.LogTailerParams{
+		MinLevel:      loggo.INFO,
+		IncludeEntity: []string{"machine-1*", "machine-2"},
+		IncludeModule: []string{"juju", "unit"},
+		ExcludeEntity: []string{"machine-1-lxc*"},
+		ExcludeModule: []string{"juju.provisioner"},
+		Since:         time.Date(2014, 9, 1, 2, 10, 0, 0, time.UTC),
+		Until:         time.Date(2014, 9, 1, 2, 20, 0, 0, time.UTC),
+		MessageRegex:  "hook failed",
+		InitialLines:  100,
+		FromTheStart:  true,
+		NoTail:        true,
+	})
+	c.Assert(obtained.maxLines, gc.Equals, uint(300))
 
 	_, err = newLogStream(url.Values{"maxLines": []string{"foo"}})
 	c.Assert(err, gc.ErrorMatches, `maxLines value "foo" is not a valid unsigned number`)
@@ -348,4 +177,22 @@ func (s *debugInternalSuite) TestNewLogStream(c *gc.C) {
 
 	_, err = newLogStream(url.Values{"level": []string{"foo"}})
 	c.Assert(err, gc.ErrorMatches, `level value "foo" is not one of "TRACE", "DEBUG", "INFO", "WARNING", "ERROR"`)
+
+	_, err = newLogStream(url.Values{"since": []string{"02:10"}})
+	c.Assert(err, gc.ErrorMatches, `since value "02:10" is not a valid RFC3339 time`)
+
+	_, err = newLogStream(url.Values{"until": []string{"foo"}})
+	c.Assert(err, gc.ErrorMatches, `until value "foo" is not a valid RFC3339 time`)
+
+	_, err = newLogStream(url.Values{
+		"since": []string{"2014-09-01T02:20:00Z"},
+		"until": []string{"2014-09-01T02:10:00Z"},
+	})
+	c.Assert(err, gc.ErrorMatches, `until value "2014-09-01T02:10:00Z" is before since value "2014-09-01T02:20:00Z"`)
+
+	_, err = newLogStream(url.Values{"grep": []string{"(foo"}})
+	c.Assert(err, gc.ErrorMatches, `grep value "\(foo" is not a valid regular expression: .*`)
+
+	_, err = newLogStream(url.Values{"noTail": []string{"foo"}})
+	c.Assert(err, gc.ErrorMatches, `noTail value "foo" is not a valid boolean`)
 }
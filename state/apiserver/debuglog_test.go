@@ -12,27 +12,33 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
 	"code.google.com/p/go.net/websocket"
+	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "launchpad.net/gocheck"
 
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api/params"
 	"github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
 )
 
 type debugLogSuite struct {
 	authHttpSuite
-	logFile *os.File
-	last    int
+	last int
 }
 
 var _ = gc.Suite(&debugLogSuite{})
 
+func (s *debugLogSuite) SetUpTest(c *gc.C) {
+	s.authHttpSuite.SetUpTest(c)
+	s.last = 0
+}
+
 func (s *debugLogSuite) TestWithHTTP(c *gc.C) {
 	uri := s.logURL(c, "http", nil).String()
 	_, err := s.sendRequest(c, "", "", "GET", uri, "", nil)
@@ -56,10 +62,14 @@ func (s *debugLogSuite) TestNoAuth(c *gc.C) {
 	s.assertWebsocketClosed(c, reader)
 }
 
-func (s *debugLogSuite) TestNoLogfile(c *gc.C) {
+func (s *debugLogSuite) TestReadAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Password: "password",
+		Access:   state.UserAccessRead,
+	})
+	s.userTag = user.Tag().String()
 	reader := s.openWebsocket(c, nil)
-	s.assertErrorResponse(c, reader, "cannot open log file: .*: no such file or directory")
-	s.assertWebsocketClosed(c, reader)
+	s.assertLogReader(c, reader)
 }
 
 func (s *debugLogSuite) TestBadParams(c *gc.C) {
@@ -77,7 +87,6 @@ func (s *debugLogSuite) assertLogReader(c *gc.C, reader *bufio.Reader) {
 }
 
 func (s *debugLogSuite) TestServesLog(c *gc.C) {
-	s.ensureLogFile(c)
 	reader := s.openWebsocket(c, nil)
 	s.assertLogReader(c, reader)
 }
@@ -85,7 +94,6 @@ func (s *debugLogSuite) TestServesLog(c *gc.C) {
 func (s *debugLogSuite) TestReadFromTopLevelPath(c *gc.C) {
 	// Backwards compatibility check, that we can read the log file at
 	// https://host:port/log
-	s.ensureLogFile(c)
 	reader := s.openWebsocketCustomPath(c, "/log")
 	s.assertLogReader(c, reader)
 }
@@ -94,14 +102,12 @@ func (s *debugLogSuite) TestReadFromEnvUUIDPath(c *gc.C) {
 	// Check that we can read the log at https://host:port/ENVUUID/log
 	environ, err := s.State.Environment()
 	c.Assert(err, gc.IsNil)
-	s.ensureLogFile(c)
 	reader := s.openWebsocketCustomPath(c, fmt.Sprintf("/environment/%s/log", environ.UUID()))
 	s.assertLogReader(c, reader)
 }
 
 func (s *debugLogSuite) TestReadRejectsWrongEnvUUIDPath(c *gc.C) {
 	// Check that we cannot upload charms to https://host:port/BADENVUUID/charms
-	s.ensureLogFile(c)
 	reader := s.openWebsocketCustomPath(c, "/environment/dead-beef-123456/log")
 	s.assertErrorResponse(c, reader, `unknown environment: "dead-beef-123456"`)
 	s.assertWebsocketClosed(c, reader)
@@ -165,8 +171,6 @@ func (s *debugLogSuite) TestBacklogWithMaxLines(c *gc.C) {
 }
 
 func (s *debugLogSuite) TestFilter(c *gc.C) {
-	s.ensureLogFile(c)
-
 	reader := s.openWebsocket(c, url.Values{
 		"includeEntity": {"machine-0", "unit-ubuntu-0"},
 		"includeModule": {"juju.cmd"},
@@ -180,6 +184,44 @@ func (s *debugLogSuite) TestFilter(c *gc.C) {
 	c.Assert(linesRead, jc.DeepEquals, expected)
 }
 
+func (s *debugLogSuite) TestTimeRangeNoTail(c *gc.C) {
+	s.writeLogLines(c, logLineCount)
+
+	reader := s.openWebsocket(c, url.Values{
+		"includeEntity": {"machine-1"},
+		"since":         {"2014-03-24T22:35:00Z"},
+		"until":         {"2014-03-24T22:36:28Z"},
+		"noTail":        {"true"},
+	})
+	s.assertLogFollowing(c, reader)
+
+	linesRead := s.readLogLines(c, reader, 13)
+	c.Assert(linesRead, jc.DeepEquals, logLines[27:40])
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogSuite) TestGrep(c *gc.C) {
+	s.writeLogLines(c, logLineCount)
+
+	reader := s.openWebsocket(c, url.Values{
+		"replay": {"true"},
+		"grep":   {`^worker: start "api"$`},
+		"noTail": {"true"},
+	})
+	s.assertLogFollowing(c, reader)
+
+	expected := []string{logLines[6], logLines[21], logLines[30], logLines[43]}
+	linesRead := s.readLogLines(c, reader, len(expected))
+	c.Assert(linesRead, jc.DeepEquals, expected)
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogSuite) TestBadGrep(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{"grep": {"(foo"}})
+	s.assertErrorResponse(c, reader, `grep value "\(foo" is not a valid regular expression: .*`)
+	s.assertWebsocketClosed(c, reader)
+}
+
 func (s *debugLogSuite) readLogLines(c *gc.C, reader *bufio.Reader, count int) (linesRead []string) {
 	for len(linesRead) < count {
 		line, err := reader.ReadString('\n')
@@ -207,26 +249,31 @@ func (s *debugLogSuite) openWebsocketCustomPath(c *gc.C, path string) *bufio.Rea
 	return bufio.NewReader(conn)
 }
 
-func (s *debugLogSuite) ensureLogFile(c *gc.C) {
-	if s.logFile != nil {
-		return
+func (s *debugLogSuite) writeLogLines(c *gc.C, count int) {
+	var records []state.LogRecord
+	for i := 0; i < count && s.last < logLineCount; i++ {
+		records = append(records, logRecord(c, logLines[s.last]))
+		s.last++
 	}
-	path := filepath.Join(s.LogDir, "all-machines.log")
-	var err error
-	s.logFile, err = os.Create(path)
+	err := s.State.AddLogs(records)
 	c.Assert(err, gc.IsNil)
-	s.AddCleanup(func(c *gc.C) {
-		s.logFile.Close()
-		s.logFile = nil
-		s.last = 0
-	})
 }
 
-func (s *debugLogSuite) writeLogLines(c *gc.C, count int) {
-	s.ensureLogFile(c)
-	for i := 0; i < count && s.last < logLineCount; i++ {
-		s.logFile.WriteString(logLines[s.last] + "\n")
-		s.last++
+// logRecord returns the log record shown as the given line.
+func logRecord(c *gc.C, line string) state.LogRecord {
+	fields := strings.SplitN(line, " ", 7)
+	c.Assert(fields, gc.HasLen, 7)
+	t, err := time.Parse("2006-01-02 15:04:05", fields[1]+" "+fields[2])
+	c.Assert(err, gc.IsNil)
+	level, ok := loggo.ParseLevel(fields[3])
+	c.Assert(ok, jc.IsTrue)
+	return state.LogRecord{
+		Time:     t,
+		Entity:   strings.TrimSuffix(fields[0], ":"),
+		Level:    level,
+		Module:   fields[4],
+		Location: fields[5],
+		Message:  fields[6],
 	}
 }
 
@@ -343,7 +390,6 @@ unit-ubuntu-0: 2014-03-24 22:36:28 DEBUG juju.worker.logger logger.go:35 initial
 unit-ubuntu-0: 2014-03-24 22:36:28 INFO juju runner.go:262 worker: start "uniter"
 unit-ubuntu-0: 2014-03-24 22:36:28 DEBUG juju.worker.logger logger.go:60 logger setup
 unit-ubuntu-0: 2014-03-24 22:36:28 INFO juju runner.go:262 worker: start "rsyslog"
-unit-ubuntu-0: 2014-03-24 22:36:28 DEBUG juju.worker.rsyslog worker.go:76 starting rsyslog worker mode 1 for "unit-ubuntu-0" "tim-local"
-`[1:], "\n")
+unit-ubuntu-0: 2014-03-24 22:36:28 DEBUG juju.worker.rsyslog worker.go:76 starting rsyslog worker mode 1 for "unit-ubuntu-0" "tim-local"`[1:], "\n")
 	logLineCount = len(logLines)
 )
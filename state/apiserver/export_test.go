@@ -12,10 +12,15 @@ import (
 )
 
 var (
-	RootType              = reflect.TypeOf(&srvRoot{})
-	NewPingTimeout        = newPingTimeout
-	MaxClientPingInterval = &maxClientPingInterval
-	MongoPingInterval     = &mongoPingInterval
+	RootType               = reflect.TypeOf(&srvRoot{})
+	NewPingTimeout         = newPingTimeout
+	MaxClientPingInterval  = &maxClientPingInterval
+	MongoPingInterval      = &mongoPingInterval
+	RequiredUserAccess     = requiredUserAccess
+	ReadOnlyClientMethods  = readOnlyClientMethods
+	LoginDelay             = loginDelay
+	LoginDelayFreeFailures = &loginDelayFreeFailures
+	LoginDelayBase         = &loginDelayBase
 )
 
 const LoginRateLimit = loginRateLimit
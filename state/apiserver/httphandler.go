@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/juju/names"
 
@@ -27,8 +28,9 @@ type httpHandler struct {
 }
 
 // authenticate parses HTTP basic authentication and authorizes the
-// request by looking up the provided tag and password against state.
-func (h *httpHandler) authenticate(r *http.Request) error {
+// request by looking up the provided tag and password against state,
+// and checking that the user has the required access.
+func (h *httpHandler) authenticate(r *http.Request, access state.UserAccess) error {
 	parts := strings.Fields(r.Header.Get("Authorization"))
 	if len(parts) != 2 || parts[0] != "Basic" {
 		// Invalid header format or no header provided.
@@ -48,12 +50,28 @@ func (h *httpHandler) authenticate(r *http.Request) error {
 	if _, err := names.ParseUserTag(tagPass[0]); err != nil {
 		return common.ErrBadCreds
 	}
-	// Ensure the credentials are correct.
-	_, err = checkCreds(h.state, params.Creds{
+	// Refuse the request if there have been too many failed logins,
+	// then ensure the credentials are correct.
+	throttle := &loginThrottle{h.state}
+	if err := throttle.check(tagPass[0], r.RemoteAddr, time.Now()); err != nil {
+		return err
+	}
+	entity, err := checkCreds(h.state, params.Creds{
 		AuthTag:  tagPass[0],
 		Password: tagPass[1],
 	})
-	return err
+	if err == common.ErrBadCreds {
+		if err := throttle.failed(tagPass[0], r.RemoteAddr, time.Now()); err != nil {
+			logger.Errorf("cannot record failed login: %v", err)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err := throttle.succeeded(tagPass[0], r.RemoteAddr); err != nil {
+		return err
+	}
+	return checkUserAccess(entity, access)
 }
 
 func (h *httpHandler) getEnvironUUID(r *http.Request) string {
@@ -4,6 +4,7 @@
 package logger
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 
 	"github.com/juju/juju/state"
@@ -25,6 +26,7 @@ func init() {
 type Logger interface {
 	WatchLoggingConfig(args params.Entities) params.NotifyWatchResults
 	LoggingConfig(args params.Entities) params.StringResults
+	WriteLogs(args params.LogRecords) error
 }
 
 // LoggerAPI implements the Logger interface and is the concrete
@@ -95,3 +97,25 @@ func (api *LoggerAPI) LoggingConfig(arg params.Entities) params.StringResults {
 	}
 	return params.StringResults{Results: results}
 }
+
+// WriteLogs records the log records sent by the authenticated agent,
+// so that they can be followed with debug-log from any state server.
+func (api *LoggerAPI) WriteLogs(args params.LogRecords) error {
+	entity := api.authorizer.GetAuthTag().String()
+	records := make([]state.LogRecord, len(args.Records))
+	for i, record := range args.Records {
+		level, ok := loggo.ParseLevel(record.Level)
+		if !ok {
+			return errors.Errorf("invalid log level %q", record.Level)
+		}
+		records[i] = state.LogRecord{
+			Time:     record.Time,
+			Entity:   entity,
+			Module:   record.Module,
+			Location: record.Location,
+			Level:    level,
+			Message:  record.Message,
+		}
+	}
+	return api.state.AddLogs(records)
+}
@@ -4,6 +4,9 @@
 package logger_test
 
 import (
+	"time"
+
+	"github.com/juju/loggo"
 	"github.com/juju/names"
 	gc "launchpad.net/gocheck"
 
@@ -14,6 +17,7 @@ import (
 	"github.com/juju/juju/state/apiserver/logger"
 	apiservertesting "github.com/juju/juju/state/apiserver/testing"
 	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
 )
 
 type loggerSuite struct {
@@ -143,3 +147,41 @@ func (s *loggerSuite) TestLoggingConfigForAgent(c *gc.C) {
 	c.Assert(result.Error, gc.IsNil)
 	c.Assert(result.Result, gc.Equals, newLoggingConfig)
 }
+
+func (s *loggerSuite) TestWriteLogs(c *gc.C) {
+	t := time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC)
+	err := s.logger.WriteLogs(params.LogRecords{
+		Records: []params.LogRecord{{
+			Time:     t,
+			Module:   "juju.worker",
+			Location: "worker.go:42",
+			Level:    "WARNING",
+			Message:  "hello",
+		}},
+	})
+	c.Assert(err, gc.IsNil)
+
+	tailer, err := s.State.NewLogTailer(&state.LogTailerParams{FromTheStart: true})
+	c.Assert(err, gc.IsNil)
+	defer tailer.Stop()
+	select {
+	case record := <-tailer.Logs():
+		c.Assert(*record, gc.DeepEquals, state.LogRecord{
+			Time:     t,
+			Entity:   s.rawMachine.Tag().String(),
+			Module:   "juju.worker",
+			Location: "worker.go:42",
+			Level:    loggo.WARNING,
+			Message:  "hello",
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for log record")
+	}
+}
+
+func (s *loggerSuite) TestWriteLogsInvalidLevel(c *gc.C) {
+	err := s.logger.WriteLogs(params.LogRecords{
+		Records: []params.LogRecord{{Level: "LOUD", Message: "hello"}},
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid log level "LOUD"`)
+}
@@ -0,0 +1,179 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/apiserver/common"
+)
+
+var (
+	// loginDelayFreeFailures is the number of failed logins that
+	// are not followed by a delay, so that a mistyped password does
+	// not get in the way.
+	loginDelayFreeFailures = 2
+
+	// loginDelayBase is the delay imposed after the first failed
+	// login that is not free. The delay doubles after each further
+	// failure, up to maxLoginDelay.
+	loginDelayBase = time.Second
+	maxLoginDelay  = time.Minute
+)
+
+// loginDelay returns how long logins are refused for after the given
+// number of failed logins.
+func loginDelay(failures int) time.Duration {
+	if failures <= loginDelayFreeFailures {
+		return 0
+	}
+	delay := loginDelayBase
+	for i := loginDelayFreeFailures + 1; i < failures && delay < maxLoginDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxLoginDelay {
+		delay = maxLoginDelay
+	}
+	return delay
+}
+
+// loginThrottle protects password logins as users against brute force
+// attacks. Failed logins are recorded in state, for the user at the
+// address they came from and for the address, so that they are counted
+// by all the state servers. After each failed login beyond the first
+// few, logins as the same user from the same address, or as any user
+// from that address, are refused for a delay that grows exponentially,
+// and once the environment's login-lockout-threshold is reached they
+// are refused for the login-lockout-duration. A user is never locked
+// out by failed logins from an address other than their own. Agents,
+// whose passwords are long and random, are not throttled.
+type loginThrottle struct {
+	st *state.State
+}
+
+// loginTarget is a user at an address, or an address, whose failed
+// logins are counted.
+type loginTarget struct {
+	key  string
+	name string
+}
+
+// targets returns the targets for a login with the given tag from the
+// given remote address, or nil if the login is not throttled.
+func (t *loginThrottle) targets(authTag, remoteAddr string) []loginTarget {
+	tag, err := names.ParseUserTag(authTag)
+	if err != nil {
+		return nil
+	}
+	remoteAddr = remoteHost(remoteAddr)
+	targets := []loginTarget{{
+		key:  state.UserLoginAttemptsKey(tag.Id(), remoteAddr),
+		name: tag.String(),
+	}}
+	if remoteAddr != "" {
+		targets = append(targets, loginTarget{
+			key:  state.AddressLoginAttemptsKey(remoteAddr),
+			name: remoteAddr,
+		})
+	}
+	return targets
+}
+
+// check returns an error if logins with the given tag from the given
+// remote address are currently refused.
+func (t *loginThrottle) check(authTag, remoteAddr string, now time.Time) error {
+	for _, target := range t.targets(authTag, remoteAddr) {
+		attempts, err := t.st.LoginAttempts(target.key)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if now.Before(attempts.LockedUntil) {
+			logger.Debugf("refusing login as %s from %s: locked out", authTag, remoteAddr)
+			return common.ErrLoginLockedOut
+		}
+		if now.Before(attempts.LastFailure.Add(loginDelay(attempts.Failures))) {
+			logger.Debugf("refusing login as %s from %s: delayed after failed logins", authTag, remoteAddr)
+			return common.ErrTryAgain
+		}
+	}
+	return nil
+}
+
+// remoteHost returns the host part of the given remote address.
+func remoteHost(remoteAddr string) string {
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
+
+// failed records a failed login with the given tag from the given
+// remote address, and locks out the user at that address or the
+// address when they reach the lockout threshold. Lockouts are recorded
+// in the audit log.
+func (t *loginThrottle) failed(authTag, remoteAddr string, now time.Time) error {
+	targets := t.targets(authTag, remoteAddr)
+	if len(targets) == 0 {
+		return nil
+	}
+	cfg, err := t.st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	opts := cfg.LoginLockoutOpts()
+	for _, target := range targets {
+		attempts, err := t.st.LoginAttempts(target.key)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if attempts.Failures > 0 && now.Sub(attempts.LastFailure) > opts.Duration {
+			// Failures this old are forgotten.
+			if err := t.st.ClearLoginAttempts(target.key); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		attempts, err = t.st.RecordLoginFailure(target.key, now, now.Add(opts.Duration))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if attempts.Failures < opts.Threshold {
+			continue
+		}
+		lockedUntil := now.Add(opts.Duration)
+		if err := t.st.LockOutLogins(target.key, lockedUntil); err != nil {
+			return errors.Trace(err)
+		}
+		logger.Warningf("locked out logins for %s until %v after %d failed logins", target.name, lockedUntil, attempts.Failures)
+		entry := state.AuditEntry{
+			Time:    now,
+			User:    authTag,
+			Facade:  "Admin",
+			Method:  "Login",
+			Targets: []string{target.name},
+			Error:   fmt.Sprintf("locked out until %s after %d failed logins", lockedUntil.UTC().Format(time.RFC3339), attempts.Failures),
+		}
+		if err := t.st.AddAuditEntry(entry); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// succeeded forgets the failed logins made as the user with the given
+// tag from the given remote address. The failures recorded for the
+// address are kept, so that logging in as one user does not reset the
+// count for guesses at other users' passwords.
+func (t *loginThrottle) succeeded(authTag, remoteAddr string) error {
+	tag, err := names.ParseUserTag(authTag)
+	if err != nil {
+		return nil
+	}
+	return t.st.ClearLoginAttempts(state.UserLoginAttemptsKey(tag.Id(), remoteHost(remoteAddr)))
+}
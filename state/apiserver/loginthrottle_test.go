@@ -0,0 +1,35 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state/apiserver"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type loginDelaySuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&loginDelaySuite{})
+
+func (s *loginDelaySuite) TestLoginDelay(c *gc.C) {
+	for failures, delay := range []time.Duration{
+		0, 0, 0,
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		16 * time.Second,
+		32 * time.Second,
+		time.Minute,
+		time.Minute,
+	} {
+		c.Check(apiserver.LoginDelay(failures), gc.Equals, delay, gc.Commentf("%d failures", failures))
+	}
+}
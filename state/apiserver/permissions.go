@@ -0,0 +1,80 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/apiserver/common"
+)
+
+// readOnlyFacades holds the facades that users with read access may
+// call all the methods of.
+var readOnlyFacades = set.NewStrings(
+	"AllWatcher",
+	"NotifyWatcher",
+	"Pinger",
+	"RelationUnitsWatcher",
+	"StringsWatcher",
+)
+
+// readOnlyMethods holds the methods, other than those of the Client
+// facade, that users with read access may call. A user may only change
+// their own password.
+var readOnlyMethods = set.NewStrings(
+	"KeyManager.ListKeys",
+	"UserManager.ListUsers",
+	"UserManager.SetPassword",
+	"UserManager.UserInfo",
+)
+
+// adminFacades holds the facades that only users with admin access
+// may call.
+var adminFacades = set.NewStrings(
+	"Backups",
+)
+
+// adminMethods holds the methods that only users with admin access
+// may call.
+var adminMethods = set.NewStrings(
+	"Client.DestroyEnvironment",
+	"KeyManager.AddKeys",
+	"KeyManager.DeleteKeys",
+	"KeyManager.ImportKeys",
+	"UserManager.AddUser",
+	"UserManager.ClearLoginLockouts",
+	"UserManager.DisableUser",
+	"UserManager.EnableUser",
+	"UserManager.RemoveUser",
+	"UserManager.SetAccess",
+)
+
+// requiredUserAccess returns the access a user needs to call the given
+// method. Users need write access for any method not listed above.
+func requiredUserAccess(rootName, methodName string) state.UserAccess {
+	fullName := rootName + "." + methodName
+	switch {
+	case adminFacades.Contains(rootName), adminMethods.Contains(fullName):
+		return state.UserAccessAdmin
+	case readOnlyFacades.Contains(rootName), readOnlyMethods.Contains(fullName):
+		return state.UserAccessRead
+	case rootName == "Client" && readOnlyClientMethods.Contains(methodName):
+		return state.UserAccessRead
+	}
+	return state.UserAccessWrite
+}
+
+// checkUserAccess returns common.ErrPerm if the entity is a user
+// without the required access. Agents are not restricted.
+func checkUserAccess(entity state.Entity, required state.UserAccess) error {
+	user, ok := entity.(*state.User)
+	if !ok {
+		return nil
+	}
+	if !user.Access().Allows(required) {
+		return common.ErrPerm
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/set"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/constraints"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/api/usermanager"
+	"github.com/juju/juju/state/apiserver"
+	"github.com/juju/juju/state/apiserver/common"
+	"github.com/juju/juju/testing/factory"
+)
+
+type permissionsSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&permissionsSuite{})
+
+func (s *permissionsSuite) openAPIWithAccess(c *gc.C, access state.UserAccess) *api.State {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Password: "password",
+		Access:   access,
+	})
+	return s.OpenAPIAs(c, user.Tag(), "password")
+}
+
+func (s *permissionsSuite) TestReadAccess(c *gc.C) {
+	st := s.openAPIWithAccess(c, state.UserAccessRead)
+	client := st.Client()
+
+	_, err := client.Status(nil)
+	c.Assert(err, gc.IsNil)
+	_, err = client.EnvironmentGet()
+	c.Assert(err, gc.IsNil)
+	watcher, err := client.WatchAll()
+	c.Assert(err, gc.IsNil)
+	_, err = watcher.Next()
+	c.Assert(err, gc.IsNil)
+	c.Assert(watcher.Stop(), gc.IsNil)
+
+	err = client.SetEnvironmentConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(err, jc.Satisfies, params.IsCodeUnauthorized)
+	err = client.ServiceDestroy("wordpress")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *permissionsSuite) TestWriteAccess(c *gc.C) {
+	st := s.openAPIWithAccess(c, state.UserAccessWrite)
+
+	err := st.Client().SetEnvironmentConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, gc.IsNil)
+
+	err = usermanager.NewClient(st).AddUser("foobar", "", "password", "")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(err, jc.Satisfies, params.IsCodeUnauthorized)
+}
+
+func (s *permissionsSuite) TestAdminAccess(c *gc.C) {
+	st := s.openAPIWithAccess(c, state.UserAccessAdmin)
+
+	err := usermanager.NewClient(st).AddUser("foobar", "", "password", "read")
+	c.Assert(err, gc.IsNil)
+	user, err := s.State.User("foobar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.Access(), gc.Equals, state.UserAccessRead)
+}
+
+func (s *permissionsSuite) TestAgentsAreNotRestricted(c *gc.C) {
+	st, machine := s.OpenAPIAsNewMachine(c, state.JobHostUnits)
+	_, _, err := st.Upgrader().Tools(machine.Tag().String())
+	c.Assert(err, gc.Not(jc.Satisfies), params.IsCodeUnauthorized)
+}
+
+func (s *permissionsSuite) TestRequiredUserAccess(c *gc.C) {
+	for i, test := range []struct {
+		rootName   string
+		methodName string
+		access     state.UserAccess
+	}{
+		{"Client", "FullStatus", state.UserAccessRead},
+		{"Client", "ServiceGet", state.UserAccessRead},
+		{"Client", "WatchAll", state.UserAccessRead},
+		{"Client", "ExportBundle", state.UserAccessRead},
+		{"Client", "DiffBundle", state.UserAccessRead},
+		{"Client", "RollingUpgradeStatus", state.UserAccessRead},
+		{"Client", "ServiceRollingUpgrade", state.UserAccessWrite},
+		{"Client", "StartAgentUpgrade", state.UserAccessWrite},
+		{"Client", "RollBackAgentUpgrade", state.UserAccessWrite},
+		{"AllWatcher", "Next", state.UserAccessRead},
+		{"Pinger", "Ping", state.UserAccessRead},
+		{"UserManager", "UserInfo", state.UserAccessRead},
+		{"UserManager", "SetPassword", state.UserAccessRead},
+		{"UserManager", "ListUsers", state.UserAccessRead},
+		{"Client", "ServiceDeploy", state.UserAccessWrite},
+		{"Client", "ServiceDestroy", state.UserAccessWrite},
+		{"Client", "ServiceSet", state.UserAccessWrite},
+		{"Client", "DestroyEnvironment", state.UserAccessAdmin},
+		{"UserManager", "AddUser", state.UserAccessAdmin},
+		{"UserManager", "SetAccess", state.UserAccessAdmin},
+		{"UserManager", "DisableUser", state.UserAccessAdmin},
+		{"UserManager", "EnableUser", state.UserAccessAdmin},
+		{"UserManager", "ClearLoginLockouts", state.UserAccessAdmin},
+		{"KeyManager", "AddKeys", state.UserAccessAdmin},
+		{"Backups", "Create", state.UserAccessAdmin},
+	} {
+		c.Logf("test %d: %s.%s", i, test.rootName, test.methodName)
+		c.Check(apiserver.RequiredUserAccess(test.rootName, test.methodName), gc.Equals, test.access)
+	}
+}
+
+func (s *permissionsSuite) TestReadOnlyClientMethodsRegistered(c *gc.C) {
+	clientType, err := common.Facades.GetType("Client", 0)
+	c.Assert(err, gc.IsNil)
+	methods := set.NewStrings(rpcreflect.ObjTypeOf(clientType).MethodNames()...)
+	for _, name := range apiserver.ReadOnlyClientMethods.SortedValues() {
+		c.Check(methods.Contains(name), jc.IsTrue, gc.Commentf("Client.%s", name))
+	}
+	// Methods that only look at the environment must be listed, or
+	// users with read access cannot call them and they are audited.
+	for _, name := range methods.SortedValues() {
+		for _, prefix := range []string{"Get", "List", "Find", "Watch"} {
+			if strings.HasPrefix(name, prefix) {
+				c.Check(apiserver.ReadOnlyClientMethods.Contains(name), jc.IsTrue, gc.Commentf("Client.%s", name))
+			}
+		}
+	}
+}
@@ -116,6 +116,7 @@ func (s *srvCaller) Call(objId string, arg reflect.Value) (reflect.Value, error)
 // and returns a MethodCaller that will be used by the RPC code to place calls on
 // that facade.
 // FindMethod uses the global registry state/apiserver/common.Facades.
+// Users are only allowed the methods their access level permits.
 // For more information about how FindMethod should work, see rpc/server.go and
 // rpc/rpcreflect/value.go
 func (r *srvRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
@@ -123,6 +124,9 @@ func (r *srvRoot) FindMethod(rootName string, version int, methodName string) (r
 	if err != nil {
 		return nil, err
 	}
+	if err := checkUserAccess(r.entity, requiredUserAccess(rootName, methodName)); err != nil {
+		return nil, err
+	}
 
 	creator := func(id string) (reflect.Value, error) {
 		objKey := objectKey{name: rootName, version: version, objId: id}
@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/environs/filestorage"
 	"github.com/juju/juju/environs/sync"
 	envtools "github.com/juju/juju/environs/tools"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api/params"
 	"github.com/juju/juju/state/apiserver/common"
 	"github.com/juju/juju/tools"
@@ -30,7 +31,7 @@ type toolsHandler struct {
 }
 
 func (h *toolsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if err := h.authenticate(r); err != nil {
+	if err := h.authenticate(r, state.UserAccessWrite); err != nil {
 		h.authError(w, h)
 		return
 	}
@@ -310,6 +310,30 @@ func (u *UniterAPI) HasSubordinates(args params.Entities) (params.BoolResults, e
 	return result, nil
 }
 
+// CharmUpgradeHeld returns whether each given unit is held back from
+// upgrading to its service's charm by a rolling upgrade.
+func (u *UniterAPI) CharmUpgradeHeld(args params.Entities) (params.BoolResults, error) {
+	result := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.BoolResults{}, err
+	}
+	for i, entity := range args.Entities {
+		err := common.ErrPerm
+		if canAccess(entity.Tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(entity.Tag)
+			if err == nil {
+				result.Results[i].Result = unit.CharmUpgradeHeld()
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // CharmURL returns the charm URL for all given units or services.
 func (u *UniterAPI) CharmURL(args params.Entities) (params.StringBoolResults, error) {
 	result := params.StringBoolResults{
@@ -417,6 +441,80 @@ func (u *UniterAPI) ClosePort(args params.EntitiesPorts) (params.ErrorResults, e
 	return result, nil
 }
 
+// AddMetrics adds a batch of metrics for each given unit. Each batch is
+// recorded against the charm the unit is running.
+func (u *UniterAPI) AddMetrics(args params.MetricsParams) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Metrics)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, batch := range args.Metrics {
+		err := common.ErrPerm
+		if canAccess(batch.Tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(batch.Tag)
+			if err == nil {
+				metrics := make([]*state.Metric, len(batch.Metrics))
+				for j, metric := range batch.Metrics {
+					metrics[j] = state.NewMetric(metric.Key, metric.Value, metric.Time, nil)
+				}
+				_, err = unit.AddMetrics(metrics)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetWorkloadStatus sets the workload status of each given unit.
+func (u *UniterAPI) SetWorkloadStatus(args params.SetWorkloadStatus) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		err := common.ErrPerm
+		if canAccess(entity.Tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(entity.Tag)
+			if err == nil {
+				err = unit.SetWorkloadStatus(entity.Status, entity.Info)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// WorkloadStatus returns the workload status of each given unit.
+func (u *UniterAPI) WorkloadStatus(args params.Entities) (params.WorkloadStatusResults, error) {
+	result := params.WorkloadStatusResults{
+		Results: make([]params.WorkloadStatusResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.WorkloadStatusResults{}, err
+	}
+	for i, entity := range args.Entities {
+		err := common.ErrPerm
+		if canAccess(entity.Tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(entity.Tag)
+			if err == nil {
+				result.Results[i].Status, result.Results[i].Info, err = unit.WorkloadStatus()
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 func (u *UniterAPI) watchOneUnitConfigSettings(tag string) (string, error) {
 	unit, err := u.getUnit(tag)
 	if err != nil {
@@ -802,16 +900,16 @@ func (u *UniterAPI) Actions(args params.Entities) (params.ActionsQueryResults, e
 func (u *UniterAPI) ActionComplete(args params.ActionResult) (params.BoolResult, error) {
 	action, err := u.actionIfPermitted(args.ActionTag)
 	if err == nil {
-		err = action.Complete(args.Output)
+		err = action.Finish(state.ActionCompleted, args.Results, args.Output)
 	}
 	return params.BoolResult{Error: common.ServerError(err), Result: err == nil}, err
 }
 
-// ActionFail saves the result of a completed Action
+// ActionFail saves the result of a failed Action
 func (u *UniterAPI) ActionFail(args params.ActionResult) (params.BoolResult, error) {
 	action, err := u.actionIfPermitted(args.ActionTag)
 	if err == nil {
-		err = action.Fail(args.Output)
+		err = action.Finish(state.ActionFailed, args.Results, args.Output)
 	}
 	return params.BoolResult{Error: common.ServerError(err), Result: err == nil}, err
 }
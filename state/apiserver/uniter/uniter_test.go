@@ -5,6 +5,7 @@ package uniter_test
 
 import (
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -50,10 +51,24 @@ type uniterSuite struct {
 
 var _ = gc.Suite(&uniterSuite{})
 
+// wordpressActionsYaml defines the actions queued on wordpress units
+// by these tests.
+var wordpressActionsYaml = `
+actions:
+   snapshot:
+      description: Take a snapshot of the database.
+   backup:
+      description: Back up the database.
+   frobz:
+      description: Frobnicate the service.
+   wgork:
+      description: Always fails.
+`
+
 func (s *uniterSuite) SetUpTest(c *gc.C) {
 	s.JujuConnSuite.SetUpTest(c)
 
-	s.wpCharm = s.AddTestingCharm(c, "wordpress")
+	s.wpCharm = s.AddActionsCharm(c, "wordpress", wordpressActionsYaml)
 	// Create two machines, two services and add a unit to each service.
 	var err error
 	s.machine0, err = s.State.AddMachine("quantal", state.JobHostUnits, state.JobManageEnviron)
@@ -524,6 +539,39 @@ func (s *uniterSuite) TestHasSubordinates(c *gc.C) {
 	})
 }
 
+func (s *uniterSuite) TestCharmUpgradeHeld(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.CharmUpgradeHeld(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: false},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	_, err = s.wordpress.StartRollingUpgrade(state.RollingUpgradeParams{
+		Charm:     s.AddTestingCharm(c, "dummy"),
+		BatchSize: 1,
+	})
+	c.Assert(err, gc.IsNil)
+
+	result, err = s.uniter.CharmUpgradeHeld(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: true},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *uniterSuite) TestDestroy(c *gc.C) {
 	c.Assert(s.wordpressUnit.Life(), gc.Equals, state.Alive)
 
@@ -714,6 +762,90 @@ func (s *uniterSuite) TestClosePort(c *gc.C) {
 	c.Assert(openedPorts, gc.HasLen, 0)
 }
 
+func (s *uniterSuite) TestAddMetrics(c *gc.C) {
+	err := s.wordpressUnit.SetCharmURL(s.wpCharm.URL())
+	c.Assert(err, gc.IsNil)
+	now := time.Now().Round(time.Second).UTC()
+
+	args := params.MetricsParams{Metrics: []params.MetricsParam{
+		{Tag: "unit-mysql-0", Metrics: []params.Metric{{Key: "pings", Value: "5", Time: now}}},
+		{Tag: "unit-wordpress-0", Metrics: []params.Metric{{Key: "pings", Value: "5", Time: now}, {Key: "users", Value: "2", Time: now}}},
+		{Tag: "unit-foo-42", Metrics: []params.Metric{{Key: "pings", Value: "5", Time: now}}},
+	}}
+	result, err := s.uniter.AddMetrics(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	batches, err := s.State.MetricBatches(state.MetricFilter{Units: []string{"wordpress/0"}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(batches, gc.HasLen, 1)
+	c.Assert(batches[0].CharmURL(), gc.Equals, s.wpCharm.URL().String())
+	metrics := batches[0].Metrics()
+	c.Assert(metrics, gc.HasLen, 2)
+	c.Assert(metrics[0].Key(), gc.Equals, "pings")
+	c.Assert(metrics[0].Value(), gc.Equals, "5")
+	c.Assert(metrics[0].Time().Equal(now), jc.IsTrue)
+	c.Assert(metrics[1].Key(), gc.Equals, "users")
+	c.Assert(metrics[1].Value(), gc.Equals, "2")
+
+	batches, err = s.State.MetricBatches(state.MetricFilter{Units: []string{"mysql/0"}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(batches, gc.HasLen, 0)
+}
+
+func (s *uniterSuite) TestSetWorkloadStatus(c *gc.C) {
+	args := params.SetWorkloadStatus{Entities: []params.EntityWorkloadStatus{
+		{Tag: "unit-mysql-0", Status: params.WorkloadActive},
+		{Tag: "unit-wordpress-0", Status: params.WorkloadWaiting, Info: "waiting for database relation"},
+		{Tag: "unit-wordpress-0", Status: params.WorkloadUnknown},
+		{Tag: "unit-foo-42", Status: params.WorkloadActive},
+	}}
+	result, err := s.uniter.SetWorkloadStatus(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{&params.Error{Message: `cannot set invalid workload status "unknown"`}},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	status, info, err := s.wordpressUnit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.WorkloadWaiting)
+	c.Assert(info, gc.Equals, "waiting for database relation")
+	status, _, err = s.mysqlUnit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.WorkloadUnknown)
+}
+
+func (s *uniterSuite) TestWorkloadStatus(c *gc.C) {
+	err := s.wordpressUnit.SetWorkloadStatus(params.WorkloadBlocked, "needs a licence key")
+	c.Assert(err, gc.IsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.WorkloadStatus(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.WorkloadStatusResults{
+		Results: []params.WorkloadStatusResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Status: params.WorkloadBlocked, Info: "needs a licence key"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *uniterSuite) TestWatchConfigSettings(c *gc.C) {
 	err := s.wordpressUnit.SetCharmURL(s.wpCharm.URL())
 	c.Assert(err, gc.IsNil)
@@ -4,6 +4,7 @@
 package upgrader
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/names"
 
 	"github.com/juju/juju/environs"
@@ -93,12 +94,40 @@ func (u *UnitUpgraderAPI) DesiredVersion(args params.Entities) (params.VersionRe
 		err := common.ErrPerm
 		if u.authorizer.AuthOwner(entity.Tag) {
 			result[i].Version, err = u.getMachineToolsVersion(entity.Tag)
+			if err == nil {
+				result[i].RollBack, err = u.isRolledBack(*result[i].Version)
+			}
 		}
 		result[i].Error = common.ServerError(err)
 	}
 	return params.VersionResults{Results: result}, nil
 }
 
+// isRolledBack returns whether a unit whose machine runs the given
+// version should return to it because a staged upgrade was rolled
+// back.
+func (u *UnitUpgraderAPI) isRolledBack(machineVersion version.Number) (bool, error) {
+	au, err := u.st.AgentUpgrade()
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return au.RolledBack() && machineVersion == au.PreviousVersion(), nil
+}
+
+// SetUpgradeStepsStarted is only of use to machine agents, which run
+// upgrade steps, so it always fails for units.
+func (u *UnitUpgraderAPI) SetUpgradeStepsStarted(args params.EntitiesVersion) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.AgentTools)),
+	}
+	for i := range args.AgentTools {
+		result.Results[i].Error = common.ServerError(common.ErrPerm)
+	}
+	return result, nil
+}
+
 // Tools finds the tools necessary for the given agents.
 func (u *UnitUpgraderAPI) Tools(args params.Entities) (params.ToolsResults, error) {
 	result := params.ToolsResults{
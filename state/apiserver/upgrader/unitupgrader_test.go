@@ -281,3 +281,24 @@ func (s *unitUpgraderSuite) TestDesiredVersionForAgent(c *gc.C) {
 	c.Assert(agentVersion, gc.NotNil)
 	c.Check(*agentVersion, gc.DeepEquals, version.Current.Number)
 }
+
+func (s *unitUpgraderSuite) TestDesiredVersionRolledBack(c *gc.C) {
+	err := s.rawMachine.SetAgentVersion(version.Current)
+	c.Assert(err, gc.IsNil)
+	err = s.rawUnit.SetAgentVersion(version.Current)
+	c.Assert(err, gc.IsNil)
+	newer := version.Current.Number
+	newer.Patch++
+	au, err := s.State.StartAgentUpgrade(newer, []string{s.rawMachine.Id()})
+	c.Assert(err, gc.IsNil)
+	err = au.RollBack()
+	c.Assert(err, gc.IsNil)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: s.rawUnit.Tag().String()}}}
+	results, err := s.upgrader.DesiredVersion(args)
+	c.Assert(err, gc.IsNil)
+	c.Check(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Check(*results.Results[0].Version, gc.Equals, version.Current.Number)
+	c.Check(results.Results[0].RollBack, jc.IsTrue)
+}
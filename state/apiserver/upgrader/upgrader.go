@@ -4,8 +4,7 @@
 package upgrader
 
 import (
-	"errors"
-
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 
@@ -53,6 +52,7 @@ type Upgrader interface {
 	DesiredVersion(args params.Entities) (params.VersionResults, error)
 	Tools(args params.Entities) (params.ToolsResults, error)
 	SetTools(args params.EntitiesVersion) (params.ErrorResults, error)
+	SetUpgradeStepsStarted(args params.EntitiesVersion) (params.ErrorResults, error)
 }
 
 // UpgraderAPI provides access to the Upgrader API facade.
@@ -77,13 +77,14 @@ func NewUpgraderAPI(
 	getCanReadWrite := func() (common.AuthFunc, error) {
 		return authorizer.AuthOwner, nil
 	}
-	return &UpgraderAPI{
-		ToolsGetter: common.NewToolsGetter(st, getCanReadWrite),
+	u := &UpgraderAPI{
 		ToolsSetter: common.NewToolsSetter(st, getCanReadWrite),
 		st:          st,
 		resources:   resources,
 		authorizer:  authorizer,
-	}, nil
+	}
+	u.ToolsGetter = common.NewAgentVersionToolsGetter(st, getCanReadWrite, u.toolsVersion)
+	return u, nil
 }
 
 // WatchAPIVersion starts a watcher to track if there is a new version
@@ -95,7 +96,7 @@ func (u *UpgraderAPI) WatchAPIVersion(args params.Entities) (params.NotifyWatchR
 	for i, agent := range args.Entities {
 		err := common.ErrPerm
 		if u.authorizer.AuthOwner(agent.Tag) {
-			watch := u.st.WatchForEnvironConfigChanges()
+			watch := u.st.WatchAgentVersion()
 			// Consume the initial event. Technically, API
 			// calls to Watch 'transmit' the initial event
 			// in the Watch response. But NotifyWatchers
@@ -141,6 +142,32 @@ func (u *UpgraderAPI) entityIsManager(tag string) bool {
 	}
 }
 
+// agentVersion returns the agent version the machine agent with the
+// given tag should run, taking any staged upgrade into account, and
+// whether it should return to that version because the upgrade was
+// rolled back.
+func (u *UpgraderAPI) agentVersion(tag string, global version.Number) (version.Number, bool, error) {
+	au, err := u.st.AgentUpgrade()
+	if errors.IsNotFound(err) {
+		return global, false, nil
+	} else if err != nil {
+		return version.Number{}, false, err
+	}
+	t, err := names.ParseMachineTag(tag)
+	if err != nil {
+		return version.Number{}, false, common.ErrPerm
+	}
+	vers, rollBack := au.AgentVersion(t.Id(), u.entityIsManager(tag), global)
+	return vers, rollBack, nil
+}
+
+// toolsVersion returns the version of the tools the machine agent with
+// the given tag should run.
+func (u *UpgraderAPI) toolsVersion(tag string, global version.Number) (version.Number, error) {
+	vers, _, err := u.agentVersion(tag, global)
+	return vers, err
+}
+
 // DesiredVersion reports the Agent Version that we want that agent to be running
 func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResults, error) {
 	results := make([]params.VersionResult, len(args.Entities))
@@ -151,12 +178,18 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 	if err != nil {
 		return params.VersionResults{}, common.ServerError(err)
 	}
-	// Is the desired version greater than the current API server version?
-	isNewerVersion := agentVersion.Compare(version.Current.Number) > 0
 	for i, entity := range args.Entities {
 		err := common.ErrPerm
 		if u.authorizer.AuthOwner(entity.Tag) {
-			// Only return the globally desired agent version if the
+			var desired version.Number
+			desired, results[i].RollBack, err = u.agentVersion(entity.Tag, agentVersion)
+			if err != nil {
+				results[i].Error = common.ServerError(err)
+				continue
+			}
+			// Is the desired version greater than the current API server version?
+			isNewerVersion := desired.Compare(version.Current.Number) > 0
+			// Only return the desired agent version if the
 			// asking entity is a machine agent with JobManageEnviron or
 			// if this API server is running the globally desired agent
 			// version. Otherwise report this API server's current
@@ -167,9 +200,9 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 			// new version other agents will start to see the new
 			// agent version.
 			if !isNewerVersion || u.entityIsManager(entity.Tag) {
-				results[i].Version = &agentVersion
+				results[i].Version = &desired
 			} else {
-				logger.Debugf("desired version is %s, but current version is %s and agent is not a manager node", agentVersion, version.Current.Number)
+				logger.Debugf("desired version is %s, but current version is %s and agent is not a manager node", desired, version.Current.Number)
 				results[i].Version = &version.Current.Number
 			}
 			err = nil
@@ -178,3 +211,24 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 	}
 	return params.VersionResults{Results: results}, nil
 }
+
+// SetUpgradeStepsStarted records that the given machine agents are
+// about to run the upgrade steps for the given versions. It fails for
+// an agent whose upgrade to that version has been rolled back.
+func (u *UpgraderAPI) SetUpgradeStepsStarted(args params.EntitiesVersion) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.AgentTools)),
+	}
+	for i, agent := range args.AgentTools {
+		err := common.ErrPerm
+		if u.authorizer.AuthOwner(agent.Tag) && agent.Tools != nil {
+			var t names.MachineTag
+			t, err = names.ParseMachineTag(agent.Tag)
+			if err == nil {
+				err = u.st.SetUpgradeStepsStarted(t.Id(), agent.Tools.Version.Number)
+			}
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
@@ -330,3 +330,78 @@ func (s *upgraderSuite) TestDesiredVersionRestrictedForNonAPIAgents(c *gc.C) {
 	c.Assert(agentVersion, gc.NotNil)
 	c.Check(*agentVersion, gc.DeepEquals, version.Current.Number)
 }
+
+func (s *upgraderSuite) desiredVersion(c *gc.C, m *state.Machine) params.VersionResult {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: m.Tag()}
+	upgraderAPI, err := upgrader.NewUpgraderAPI(s.State, s.resources, authorizer)
+	c.Assert(err, gc.IsNil)
+	args := params.Entities{Entities: []params.Entity{{Tag: m.Tag().String()}}}
+	results, err := upgraderAPI.DesiredVersion(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Version, gc.NotNil)
+	return results.Results[0]
+}
+
+func (s *upgraderSuite) startStagedUpgrade(c *gc.C) (*state.AgentUpgrade, *state.Machine, version.Number) {
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	for _, m := range []*state.Machine{s.apiMachine, s.rawMachine, other} {
+		err := m.SetAgentVersion(version.Current)
+		c.Assert(err, gc.IsNil)
+	}
+	newer := version.Current
+	newer.Patch++
+	au, err := s.State.StartAgentUpgrade(newer.Number, []string{s.rawMachine.Id()})
+	c.Assert(err, gc.IsNil)
+	return au, other, newer.Number
+}
+
+func (s *upgraderSuite) TestDesiredVersionStagedUpgrade(c *gc.C) {
+	au, other, newVersion := s.startStagedUpgrade(c)
+	previous := version.Current.Number
+	// The API server has already been upgraded.
+	s.PatchValue(&version.Current.Number, newVersion)
+
+	// Only the state server and the canary machine are upgraded.
+	result := s.desiredVersion(c, s.apiMachine)
+	c.Check(*result.Version, gc.Equals, newVersion)
+	result = s.desiredVersion(c, s.rawMachine)
+	c.Check(*result.Version, gc.Equals, newVersion)
+	c.Check(result.RollBack, jc.IsFalse)
+	result = s.desiredVersion(c, other)
+	c.Check(*result.Version, gc.Equals, previous)
+
+	// When the upgrade is rolled back, the canary machine returns to
+	// the previous version.
+	err := au.RollBack()
+	c.Assert(err, gc.IsNil)
+	result = s.desiredVersion(c, s.rawMachine)
+	c.Check(*result.Version, gc.Equals, previous)
+	c.Check(result.RollBack, jc.IsTrue)
+}
+
+func (s *upgraderSuite) TestSetUpgradeStepsStarted(c *gc.C) {
+	au, _, newVersion := s.startStagedUpgrade(c)
+	args := params.EntitiesVersion{AgentTools: []params.EntityVersion{{
+		Tag:   s.rawMachine.Tag().String(),
+		Tools: &params.Version{version.Binary{Number: newVersion}},
+	}, {
+		Tag:   s.apiMachine.Tag().String(),
+		Tools: &params.Version{version.Binary{Number: newVersion}},
+	}}}
+	results, err := s.upgrader.SetUpgradeStepsStarted(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	// The machine that started its upgrade steps is not rolled back.
+	err = au.RollBack()
+	c.Assert(err, gc.IsNil)
+	s.PatchValue(&version.Current.Number, newVersion)
+	result := s.desiredVersion(c, s.rawMachine)
+	c.Check(*result.Version, gc.Equals, newVersion)
+	c.Check(result.RollBack, jc.IsFalse)
+}
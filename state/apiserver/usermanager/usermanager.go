@@ -5,6 +5,7 @@ package usermanager
 
 import (
 	"fmt"
+	"net"
 	"time"
 
 	"github.com/juju/errors"
@@ -27,6 +28,11 @@ type UserManager interface {
 	AddUser(arg ModifyUsers) (params.ErrorResults, error)
 	RemoveUser(arg params.Entities) (params.ErrorResults, error)
 	SetPassword(args ModifyUsers) (params.ErrorResults, error)
+	SetAccess(args ModifyUsers) (params.ErrorResults, error)
+	ListUsers() (UserInfoResults, error)
+	DisableUser(args params.Entities) (params.ErrorResults, error)
+	EnableUser(args params.Entities) (params.ErrorResults, error)
+	ClearLoginLockouts(args LoginLockouts) (params.ErrorResults, error)
 }
 
 // UserInfo holds information on a user.
@@ -36,6 +42,8 @@ type UserInfo struct {
 	CreatedBy      string     `json:created-by`
 	DateCreated    time.Time  `json:date-created`
 	LastConnection *time.Time `json:last-connection`
+	Access         string     `json:access`
+	Disabled       bool       `json:disabled`
 }
 
 // UserInfoResult holds the result of a UserInfo call.
@@ -63,6 +71,22 @@ type ModifyUser struct {
 	Username    string
 	DisplayName string
 	Password    string
+	// Access is the user's access to the environment: "read",
+	// "write" or "admin". AddUser gives admin access if it is empty.
+	Access string
+}
+
+// LoginLockouts holds the parameters for a UserManager.ClearLoginLockouts
+// call.
+type LoginLockouts struct {
+	Lockouts []LoginLockout
+}
+
+// LoginLockout names a user or an address whose failed logins are to
+// be forgotten. Exactly one of Username and Address must be set.
+type LoginLockout struct {
+	Username string
+	Address  string
 }
 
 // UserManagerAPI implements the user manager interface and is the concrete
@@ -106,7 +130,11 @@ func (api *UserManagerAPI) AddUser(args ModifyUsers) (params.ErrorResults, error
 		if username == "" {
 			username = arg.Tag
 		}
-		_, err := api.state.AddUser(username, arg.DisplayName, arg.Password, user.Id())
+		access := state.UserAccess(arg.Access)
+		if access == "" {
+			access = state.UserAccessAdmin
+		}
+		_, err := api.state.AddUserWithAccess(username, arg.DisplayName, arg.Password, user.Id(), access)
 		if err != nil {
 			err = errors.Annotate(err, "failed to create user")
 			result.Results[i].Error = common.ServerError(err)
@@ -162,14 +190,7 @@ func (api *UserManagerAPI) UserInfo(args params.Entities) (UserInfoResults, erro
 				result.Error = common.ServerError(err)
 			}
 		} else {
-			info := UserInfo{
-				Username:       username,
-				DisplayName:    user.DisplayName(),
-				CreatedBy:      user.CreatedBy(),
-				DateCreated:    user.DateCreated(),
-				LastConnection: user.LastLogin(),
-			}
-			result.Result = &info
+			result.Result = userInfo(user)
 		}
 		results.Results[i] = result
 	}
@@ -216,6 +237,133 @@ func (api *UserManagerAPI) SetPassword(args ModifyUsers) (params.ErrorResults, e
 	return result, nil
 }
 
+// SetAccess sets the users' access to the environment.
+func (api *UserManagerAPI) SetAccess(args ModifyUsers) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	for i, arg := range args.Changes {
+		username := arg.Username
+		if username == "" {
+			username = arg.Tag
+		}
+		user, err := api.state.User(username)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if err := user.SetAccess(state.UserAccess(arg.Access)); err != nil {
+			err = errors.Annotate(err, "failed to set access")
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// ListUsers returns information on all the users, including the
+// disabled ones, ordered by name.
+func (api *UserManagerAPI) ListUsers() (UserInfoResults, error) {
+	users, err := api.state.AllUsers()
+	if err != nil {
+		return UserInfoResults{}, errors.Trace(err)
+	}
+	results := UserInfoResults{
+		Results: make([]UserInfoResult, len(users)),
+	}
+	for i, user := range users {
+		results.Results[i].Result = userInfo(user)
+	}
+	return results, nil
+}
+
+// DisableUser disables the given users, so that they cannot log in.
+// The API connections of the users are dropped. The admin user cannot
+// be disabled.
+func (api *UserManagerAPI) DisableUser(args params.Entities) (params.ErrorResults, error) {
+	return api.setDisabled(args, true)
+}
+
+// EnableUser enables the given users, so that they can log in again.
+func (api *UserManagerAPI) EnableUser(args params.Entities) (params.ErrorResults, error) {
+	return api.setDisabled(args, false)
+}
+
+func (api *UserManagerAPI) setDisabled(args params.Entities, disabled bool) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		tag, err := names.ParseUserTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if disabled && tag.Id() == state.AdminUser {
+			result.Results[i].Error = common.ServerError(errors.New("cannot disable admin user"))
+			continue
+		}
+		user, err := api.state.User(tag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if disabled {
+			err = user.Deactivate()
+		} else {
+			err = user.Activate()
+		}
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// ClearLoginLockouts forgets the failed logins made as the given users
+// or from the given addresses, ending any lockouts, so that they can
+// log in again at once.
+func (api *UserManagerAPI) ClearLoginLockouts(args LoginLockouts) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Lockouts)),
+	}
+	for i, arg := range args.Lockouts {
+		var err error
+		switch {
+		case arg.Username != "" && arg.Address == "":
+			if !names.IsValidUser(arg.Username) {
+				err = errors.NotValidf("user name %q", arg.Username)
+				break
+			}
+			err = api.state.ClearUserLoginAttempts(arg.Username)
+		case arg.Address != "" && arg.Username == "":
+			if net.ParseIP(arg.Address) == nil {
+				err = errors.NotValidf("address %q", arg.Address)
+				break
+			}
+			err = api.state.ClearLoginAttempts(state.AddressLoginAttemptsKey(arg.Address))
+		default:
+			err = errors.New("expected either a user name or an address")
+		}
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// userInfo returns the information on the given user.
+func userInfo(user *state.User) *UserInfo {
+	return &UserInfo{
+		Username:       user.Name(),
+		DisplayName:    user.DisplayName(),
+		CreatedBy:      user.CreatedBy(),
+		DateCreated:    user.DateCreated(),
+		LastConnection: user.LastLogin(),
+		Access:         string(user.Access()),
+		Disabled:       user.IsDeactivated(),
+	}
+}
+
 func (api *UserManagerAPI) getLoggedInUser() names.Tag {
 	switch tag := api.authorizer.GetAuthTag().(type) {
 	case names.UserTag:
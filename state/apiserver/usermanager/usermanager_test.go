@@ -4,6 +4,9 @@
 package usermanager_test
 
 import (
+	"time"
+
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "launchpad.net/gocheck"
@@ -65,6 +68,32 @@ func (s *userManagerSuite) TestAddUser(c *gc.C) {
 	c.Assert(user, gc.NotNil)
 	c.Assert(user.Name(), gc.Equals, "foobar")
 	c.Assert(user.DisplayName(), gc.Equals, "Foo Bar")
+	c.Assert(user.Access(), gc.Equals, state.UserAccessAdmin)
+}
+
+func (s *userManagerSuite) TestAddUserWithAccess(c *gc.C) {
+	args := usermanager.ModifyUsers{
+		Changes: []usermanager.ModifyUser{{
+			Username: "foobar",
+			Password: "password",
+			Access:   "read",
+		}, {
+			Username: "barfoo",
+			Password: "password",
+			Access:   "superuser",
+		}}}
+
+	result, err := s.usermanager.AddUser(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0], gc.DeepEquals, params.ErrorResult{Error: nil})
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `failed to create user: user access "superuser" not valid`)
+
+	user, err := s.State.User("foobar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.Access(), gc.Equals, state.UserAccessRead)
+	_, err = s.State.User("barfoo")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
 func (s *userManagerSuite) TestRemoveUser(c *gc.C) {
@@ -142,6 +171,7 @@ func (s *userManagerSuite) TestUserInfoUsersExist(c *gc.C) {
 					CreatedBy:      "admin",
 					DateCreated:    userFoo.DateCreated(),
 					LastConnection: userFoo.LastLogin(),
+					Access:         "admin",
 				},
 			}, {
 				Result: &usermanager.UserInfo{
@@ -150,6 +180,7 @@ func (s *userManagerSuite) TestUserInfoUsersExist(c *gc.C) {
 					CreatedBy:      "admin",
 					DateCreated:    userBar.DateCreated(),
 					LastConnection: userBar.LastLogin(),
+					Access:         "admin",
 				},
 			}},
 	}
@@ -176,6 +207,7 @@ func (s *userManagerSuite) TestUserInfoUserExists(c *gc.C) {
 					CreatedBy:      "admin",
 					DateCreated:    user.DateCreated(),
 					LastConnection: user.LastLogin(),
+					Access:         "admin",
 				},
 			},
 		},
@@ -328,3 +360,150 @@ func (s *userManagerSuite) TestSetPasswordOnDifferentUser(c *gc.C) {
 	expectedError := apiservertesting.ServerError("Can only change the password of the current user (admin)")
 	c.Assert(results.Results[0], gc.DeepEquals, params.ErrorResult{Error: expectedError})
 }
+
+func (s *userManagerSuite) TestSetAccess(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
+	args := usermanager.ModifyUsers{
+		Changes: []usermanager.ModifyUser{{
+			Username: "foobar",
+			Access:   "write",
+		}, {
+			Username: "admin",
+			Access:   "read",
+		}, {
+			Username: "foobar",
+			Access:   "superuser",
+		}, {
+			Username: "nobody",
+			Access:   "read",
+		}}}
+	results, err := s.usermanager.SetAccess(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Assert(results.Results[0], gc.DeepEquals, params.ErrorResult{Error: nil})
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "failed to set access: cannot change access of admin user")
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `failed to set access: user access "superuser" not valid`)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, "permission denied")
+
+	user, err := s.State.User("foobar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.Access(), gc.Equals, state.UserAccessWrite)
+}
+
+func (s *userManagerSuite) TestListUsers(c *gc.C) {
+	foo := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", DisplayName: "Foo Bar"})
+	bar := s.Factory.MakeUser(c, &factory.UserParams{Name: "barfoo", Access: state.UserAccessRead})
+	err := bar.Deactivate()
+	c.Assert(err, gc.IsNil)
+	admin, err := s.State.User("admin")
+	c.Assert(err, gc.IsNil)
+
+	results, err := s.usermanager.ListUsers()
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, jc.DeepEquals, usermanager.UserInfoResults{
+		Results: []usermanager.UserInfoResult{{
+			Result: &usermanager.UserInfo{
+				Username:       "admin",
+				DateCreated:    admin.DateCreated(),
+				LastConnection: admin.LastLogin(),
+				Access:         "admin",
+			},
+		}, {
+			Result: &usermanager.UserInfo{
+				Username:    "barfoo",
+				DisplayName: bar.DisplayName(),
+				CreatedBy:   "admin",
+				DateCreated: bar.DateCreated(),
+				Access:      "read",
+				Disabled:    true,
+			},
+		}, {
+			Result: &usermanager.UserInfo{
+				Username:    "foobar",
+				DisplayName: "Foo Bar",
+				CreatedBy:   "admin",
+				DateCreated: foo.DateCreated(),
+				Access:      "admin",
+			},
+		}},
+	})
+}
+
+func (s *userManagerSuite) TestDisableEnableUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: user.Tag().String()}},
+	}
+
+	results, err := s.usermanager.DisableUser(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{Error: nil}},
+	})
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.IsDeactivated(), jc.IsTrue)
+
+	results, err = s.usermanager.EnableUser(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{Error: nil}},
+	})
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.IsDeactivated(), jc.IsFalse)
+}
+
+func (s *userManagerSuite) TestDisableUserErrors(c *gc.C) {
+	args := params.Entities{
+		Entities: []params.Entity{
+			{Tag: "user-admin"},
+			{Tag: "user-nobody"},
+			{Tag: "machine-0"},
+		},
+	}
+	results, err := s.usermanager.DisableUser(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "cannot disable admin user")
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "permission denied")
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"machine-0" is not a valid user tag`)
+
+	admin, err := s.State.User("admin")
+	c.Assert(err, gc.IsNil)
+	c.Assert(admin.IsDeactivated(), jc.IsFalse)
+}
+
+func (s *userManagerSuite) TestClearLoginLockouts(c *gc.C) {
+	lockedUntil := time.Now().Add(time.Hour)
+	userKey := state.UserLoginAttemptsKey("foobar", "10.0.0.2")
+	addressKey := state.AddressLoginAttemptsKey("10.0.0.1")
+	for _, key := range []string{userKey, addressKey} {
+		err := s.State.LockOutLogins(key, lockedUntil)
+		c.Assert(err, gc.IsNil)
+	}
+
+	args := usermanager.LoginLockouts{
+		Lockouts: []usermanager.LoginLockout{
+			{Username: "foobar"},
+			{Address: "10.0.0.1"},
+			{Address: "not-an-address"},
+			{Username: "foobar", Address: "10.0.0.1"},
+			{},
+		},
+	}
+	results, err := s.usermanager.ClearLoginLockouts(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 5)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `address "not-an-address" not valid`)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, "expected either a user name or an address")
+	c.Assert(results.Results[4].Error, gc.ErrorMatches, "expected either a user name or an address")
+
+	for _, key := range []string{userKey, addressKey} {
+		attempts, err := s.State.LoginAttempts(key)
+		c.Assert(err, gc.IsNil)
+		c.Assert(attempts, gc.Equals, state.LoginAttempts{})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// AuditEntry records a change made to the environment through the API.
+type AuditEntry struct {
+	// Time is when the change was made.
+	Time time.Time
+
+	// User is the tag of the entity that made the change.
+	User string
+
+	// Facade and Method name the API call that made the change.
+	Facade string
+	Method string
+
+	// Targets holds the names of the entities the change was made
+	// to, such as services, units and machines.
+	Targets []string
+
+	// Args summarises the arguments of the call.
+	Args string
+
+	// Error holds the error returned by the call, if any.
+	Error string
+}
+
+// AuditFilter selects the entries returned by AuditEntries. Empty
+// fields do not restrict the selection.
+type AuditFilter struct {
+	// User selects the entries recorded for the user with this tag.
+	User string
+
+	// Target selects the entries for changes made to this entity.
+	Target string
+
+	// Since and Until select the entries recorded in the given time
+	// range, including Since and excluding Until.
+	Since time.Time
+	Until time.Time
+}
+
+type auditEntryDoc struct {
+	Id      bson.ObjectId `bson:"_id"`
+	Time    time.Time     `bson:"time"`
+	User    string        `bson:"user"`
+	Facade  string        `bson:"facade"`
+	Method  string        `bson:"method"`
+	Targets []string      `bson:"targets"`
+	Args    string        `bson:"args"`
+	Error   string        `bson:"error"`
+}
+
+// AddAuditEntry records the given change in the audit log.
+func (st *State) AddAuditEntry(entry AuditEntry) error {
+	if entry.User == "" {
+		return errors.New("cannot add audit entry without a user")
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	id := bson.NewObjectId()
+	ops := []txn.Op{{
+		C:      auditC,
+		Id:     id,
+		Assert: txn.DocMissing,
+		Insert: &auditEntryDoc{
+			Id:      id,
+			Time:    entry.Time.UTC(),
+			User:    entry.User,
+			Facade:  entry.Facade,
+			Method:  entry.Method,
+			Targets: entry.Targets,
+			Args:    entry.Args,
+			Error:   entry.Error,
+		},
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		return errors.Annotate(err, "cannot add audit entry")
+	}
+	return nil
+}
+
+// AuditEntries returns the audit log entries that match the given
+// filter, oldest first.
+func (st *State) AuditEntries(filter AuditFilter) ([]AuditEntry, error) {
+	audit, closer := st.getCollection(auditC)
+	defer closer()
+
+	query := bson.D{}
+	if filter.User != "" {
+		query = append(query, bson.DocElem{"user", filter.User})
+	}
+	if filter.Target != "" {
+		query = append(query, bson.DocElem{"targets", filter.Target})
+	}
+	if !filter.Since.IsZero() || !filter.Until.IsZero() {
+		timeRange := bson.D{}
+		if !filter.Since.IsZero() {
+			timeRange = append(timeRange, bson.DocElem{"$gte", filter.Since})
+		}
+		if !filter.Until.IsZero() {
+			timeRange = append(timeRange, bson.DocElem{"$lt", filter.Until})
+		}
+		query = append(query, bson.DocElem{"time", timeRange})
+	}
+	var docs []auditEntryDoc
+	if err := audit.Find(query).Sort("time", "_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get audit entries")
+	}
+	entries := make([]AuditEntry, len(docs))
+	for i, doc := range docs {
+		entries[i] = AuditEntry{
+			Time:    doc.Time.UTC(),
+			User:    doc.User,
+			Facade:  doc.Facade,
+			Method:  doc.Method,
+			Targets: doc.Targets,
+			Args:    doc.Args,
+			Error:   doc.Error,
+		}
+	}
+	return entries, nil
+}
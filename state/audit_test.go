@@ -0,0 +1,101 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+)
+
+type AuditSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&AuditSuite{})
+
+var auditTime = time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC)
+
+var auditEntries = []state.AuditEntry{{
+	Time:    auditTime,
+	User:    "user-admin",
+	Facade:  "Client",
+	Method:  "ServiceDeploy",
+	Targets: []string{"wordpress"},
+	Args:    `{"ServiceName":"wordpress"}`,
+}, {
+	Time:    auditTime.Add(time.Minute),
+	User:    "user-bob",
+	Facade:  "Client",
+	Method:  "ServiceDestroy",
+	Targets: []string{"wordpress"},
+	Args:    `{"ServiceName":"wordpress"}`,
+	Error:   `service "wordpress" not found`,
+}, {
+	Time:    auditTime.Add(2 * time.Minute),
+	User:    "user-admin",
+	Facade:  "Client",
+	Method:  "DestroyMachines",
+	Targets: []string{"1", "2"},
+	Args:    `{"MachineNames":["1","2"]}`,
+}}
+
+func (s *AuditSuite) addEntries(c *gc.C) {
+	// Add the entries out of order to check they are sorted.
+	for _, i := range []int{2, 0, 1} {
+		err := s.State.AddAuditEntry(auditEntries[i])
+		c.Assert(err, gc.IsNil)
+	}
+}
+
+func (s *AuditSuite) TestAuditEntries(c *gc.C) {
+	s.addEntries(c)
+	for i, test := range []struct {
+		about    string
+		filter   state.AuditFilter
+		expected []state.AuditEntry
+	}{{
+		about:    "all entries",
+		expected: auditEntries,
+	}, {
+		about:    "by user",
+		filter:   state.AuditFilter{User: "user-admin"},
+		expected: []state.AuditEntry{auditEntries[0], auditEntries[2]},
+	}, {
+		about:    "by target",
+		filter:   state.AuditFilter{Target: "2"},
+		expected: []state.AuditEntry{auditEntries[2]},
+	}, {
+		about:    "by time",
+		filter:   state.AuditFilter{Since: auditTime.Add(time.Minute), Until: auditTime.Add(2 * time.Minute)},
+		expected: []state.AuditEntry{auditEntries[1]},
+	}, {
+		about:    "no match",
+		filter:   state.AuditFilter{User: "user-admin", Target: "mysql"},
+		expected: []state.AuditEntry{},
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		entries, err := s.State.AuditEntries(test.filter)
+		c.Check(err, gc.IsNil)
+		c.Check(entries, jc.DeepEquals, test.expected)
+	}
+}
+
+func (s *AuditSuite) TestAddAuditEntryDefaultsTime(c *gc.C) {
+	before := time.Now().Add(-time.Second)
+	err := s.State.AddAuditEntry(state.AuditEntry{User: "user-admin", Method: "ServiceExpose"})
+	c.Assert(err, gc.IsNil)
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Assert(entries[0].Time.After(before), jc.IsTrue)
+}
+
+func (s *AuditSuite) TestAddAuditEntryWithoutUser(c *gc.C) {
+	err := s.State.AddAuditEntry(state.AuditEntry{Method: "ServiceExpose"})
+	c.Assert(err, gc.ErrorMatches, "cannot add audit entry without a user")
+}
@@ -14,6 +14,7 @@ import (
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state/backups"
 	"github.com/juju/juju/version"
 )
@@ -81,6 +82,7 @@ type backupMetadataDoc struct {
 	Size           int64  `bson:"size,minsize"`
 	Stored         bool   `bson:"stored"`
 	Notes          string `bson:"notes,omitempty"`
+	Error          string `bson:"error,omitempty"`
 
 	// origin
 	Environment string         `bson:"environment"`
@@ -107,6 +109,7 @@ func (doc *backupMetadataDoc) asMetadata() *backups.Metadata {
 		Origin:         origin,
 		Stored:         doc.Stored,
 		Notes:          doc.Notes,
+		Error:          doc.Error,
 	}
 	return &metadata
 }
@@ -122,6 +125,7 @@ func (doc *backupMetadataDoc) updateFromMetadata(metadata *backups.Metadata) {
 	doc.Size = metadata.Size
 	doc.Stored = metadata.Stored
 	doc.Notes = metadata.Notes
+	doc.Error = metadata.Error
 
 	doc.Environment = metadata.Origin.Environment
 	doc.Machine = metadata.Origin.Machine
@@ -208,3 +212,110 @@ func setBackupStored(st *State, id string) error {
 	}
 	return nil
 }
+
+// listBackupMetadata returns the metadata of every stored backup,
+// oldest first.
+func listBackupMetadata(st *State) ([]*backups.Metadata, error) {
+	collection, closer := st.getCollection(backupsMetaC)
+	defer closer()
+
+	var docs []backupMetadataDoc
+	if err := collection.Find(nil).Sort("started").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "error listing backup metadata")
+	}
+	list := make([]*backups.Metadata, len(docs))
+	for i, doc := range docs {
+		list[i] = doc.asMetadata()
+	}
+	return list, nil
+}
+
+// removeBackupMetadata removes the backup metadata associated with
+// "id".  If "id" does not match any stored records, an error
+// satisfying juju/errors.IsNotFound() is returned.
+func removeBackupMetadata(st *State, id string) error {
+	ops := []txn.Op{{
+		C:      backupsMetaC,
+		Id:     id,
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			return errors.NotFoundf("backup metadata %q", id)
+		}
+		return errors.Annotate(err, "error running transaction")
+	}
+	return nil
+}
+
+// UpdateRestoredStateServer brings the state restored from a backup
+// up to date with the state server it was restored on: the machine
+// keeps the given instance id, and it becomes the environment's only
+// state server.
+func UpdateRestoredStateServer(st *State, machineId string, instId instance.Id) error {
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     machineId,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"instanceid", instId}}}},
+	}, {
+		C:      instanceDataC,
+		Id:     machineId,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"instanceid", instId}}}},
+	}, {
+		C:  stateServersC,
+		Id: environGlobalKey,
+		Update: bson.D{{"$set", bson.D{
+			{"machineids", []string{machineId}},
+			{"votingmachineids", []string{machineId}},
+		}}},
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			return errors.NotFoundf("machine %q", machineId)
+		}
+		return errors.Annotate(err, "error running transaction")
+	}
+	return nil
+}
+
+//---------------------------
+// metadata storage
+
+// backupMetadataStorage stores backup metadata in the State's DB.
+type backupMetadataStorage struct {
+	st *State
+}
+
+// NewBackupMetadataStorage returns a backups.MetadataStorage that
+// keeps backup metadata in the State's DB.
+func NewBackupMetadataStorage(st *State) backups.MetadataStorage {
+	return &backupMetadataStorage{st}
+}
+
+// Add implements backups.MetadataStorage.
+func (s *backupMetadataStorage) Add(metadata *backups.Metadata) (string, error) {
+	return addBackupMetadata(s.st, metadata)
+}
+
+// Get implements backups.MetadataStorage.
+func (s *backupMetadataStorage) Get(id string) (*backups.Metadata, error) {
+	return getBackupMetadata(s.st, id)
+}
+
+// List implements backups.MetadataStorage.
+func (s *backupMetadataStorage) List() ([]*backups.Metadata, error) {
+	return listBackupMetadata(s.st)
+}
+
+// SetStored implements backups.MetadataStorage.
+func (s *backupMetadataStorage) SetStored(id string) error {
+	return setBackupStored(s.st, id)
+}
+
+// Remove implements backups.MetadataStorage.
+func (s *backupMetadataStorage) Remove(id string) error {
+	return removeBackupMetadata(s.st, id)
+}
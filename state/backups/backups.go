@@ -6,7 +6,284 @@
 package backups
 
 import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
+
+	"github.com/juju/juju/environs/storage"
 )
 
 var logger = loggo.GetLogger("juju.state.backups")
+
+var runBackup = backup
+
+// MetadataStorage is the persistence layer for backup metadata.
+type MetadataStorage interface {
+	// Add stores the metadata and returns the new ID assigned to it.
+	Add(metadata *Metadata) (string, error)
+	// Get returns the metadata associated with the ID.  If there is
+	// none, an error satisfying errors.IsNotFound is returned.
+	Get(id string) (*Metadata, error)
+	// List returns the metadata of every stored backup.
+	List() ([]*Metadata, error)
+	// SetStored records that the archive for the ID has been stored.
+	SetStored(id string) error
+	// Remove deletes the metadata associated with the ID.
+	Remove(id string) error
+}
+
+// Backups is an abstraction around all juju backup-related
+// functionality.
+type Backups interface {
+	// Create creates and stores a new juju backup archive and returns
+	// its associated metadata.
+	Create(dbInfo DBConnInfo, origin Origin, notes string) (*Metadata, error)
+	// Info returns the metadata associated with the ID.
+	Info(id string) (*Metadata, error)
+	// Get returns the metadata and archive file associated with the ID.
+	// The caller is responsible for closing the archive.
+	Get(id string) (*Metadata, io.ReadCloser, error)
+	// List returns the metadata for all stored backups.
+	List() ([]*Metadata, error)
+	// Remove deletes the backup from storage.
+	Remove(id string) error
+	// Add stores an existing backup archive, such as one uploaded by
+	// a client, and returns its associated metadata.  The archive
+	// must match the given checksum.
+	Add(archive io.Reader, checksum string) (*Metadata, error)
+	// Restore replaces the state of the running state server with
+	// the identified backup.
+	Restore(id string, args RestoreArgs) error
+	// LastGood returns the metadata of the most recent backup that
+	// succeeded.  If there is none, an error satisfying
+	// errors.IsNotFound is returned.
+	LastGood() (*Metadata, error)
+}
+
+type backups struct {
+	metadata MetadataStorage
+	archives storage.Storage
+}
+
+// NewBackups returns a new Backups that records metadata in the
+// provided metadata storage and keeps archives in the provided
+// environment storage.
+func NewBackups(metadata MetadataStorage, archives storage.Storage) Backups {
+	return &backups{
+		metadata: metadata,
+		archives: archives,
+	}
+}
+
+// archiveName returns the name under which the archive for the given
+// backup ID is kept in environment storage.
+func archiveName(id string) string {
+	return StorageName("juju-backup-" + id + ".tar.gz")
+}
+
+// Create creates and stores a new juju backup archive and returns
+// its associated metadata.  If the backup fails, that is recorded in
+// the metadata storage too.
+func (b *backups) Create(dbInfo DBConnInfo, origin Origin, notes string) (*Metadata, error) {
+	started := time.Now().UTC()
+	metadata, err := b.create(dbInfo, origin, notes, started)
+	if err != nil {
+		b.recordFailure(origin, notes, started, err)
+		return nil, errors.Trace(err)
+	}
+	return metadata, nil
+}
+
+// recordFailure records the metadata of a backup that failed.
+func (b *backups) recordFailure(origin Origin, notes string, started time.Time, failure error) {
+	metadata := NewMetadata("", 0, origin, notes)
+	metadata.Timestamp = started
+	metadata.Finished = time.Now().UTC()
+	metadata.Error = failure.Error()
+	if _, err := b.metadata.Add(metadata); err != nil {
+		logger.Errorf("cannot record failed backup: %v", err)
+	}
+}
+
+func (b *backups) create(dbInfo DBConnInfo, origin Origin, notes string, started time.Time) (*Metadata, error) {
+	tempDir, err := ioutil.TempDir("", "jujuBackupArchive")
+	if err != nil {
+		return nil, errors.Annotate(err, "error creating temp directory")
+	}
+	defer os.RemoveAll(tempDir)
+
+	filename, checksum, err := runBackup(dbInfo, tempDir, &origin)
+	if err != nil {
+		return nil, errors.Annotate(err, "error creating backup archive")
+	}
+	archive, err := os.Open(filepath.Join(tempDir, filename))
+	if err != nil {
+		return nil, errors.Annotate(err, "error opening backup archive")
+	}
+	defer archive.Close()
+	stat, err := archive.Stat()
+	if err != nil {
+		return nil, errors.Annotate(err, "error reading backup archive")
+	}
+
+	metadata := NewMetadata(checksum, stat.Size(), origin, notes)
+	metadata.Timestamp = started
+	metadata.Finished = time.Now().UTC()
+	id, err := b.metadata.Add(metadata)
+	if err != nil {
+		return nil, errors.Annotate(err, "error storing backup metadata")
+	}
+	metadata.ID = id
+
+	logger.Infof("storing backup archive %q", id)
+	if err := b.archives.Put(archiveName(id), archive, stat.Size()); err != nil {
+		// The failure is recorded separately, so drop the
+		// metadata of the archive that was never stored.
+		if err := b.metadata.Remove(id); err != nil {
+			logger.Errorf("cannot remove metadata of unstored backup %q: %v", id, err)
+		}
+		return nil, errors.Annotate(err, "error storing backup archive")
+	}
+	if err := b.metadata.SetStored(id); err != nil {
+		return nil, errors.Annotate(err, "error updating backup metadata")
+	}
+	metadata.Stored = true
+	return metadata, nil
+}
+
+// Info returns the metadata associated with the ID.
+func (b *backups) Info(id string) (*Metadata, error) {
+	metadata, err := b.metadata.Get(id)
+	return metadata, errors.Trace(err)
+}
+
+// Get returns the metadata and archive file associated with the ID.
+func (b *backups) Get(id string) (*Metadata, io.ReadCloser, error) {
+	metadata, err := b.metadata.Get(id)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if !metadata.Stored {
+		return nil, nil, errors.NotFoundf("backup archive %q", id)
+	}
+	archive, err := b.archives.Get(archiveName(id))
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "error opening backup archive")
+	}
+	return metadata, archive, nil
+}
+
+// List returns the metadata for all stored backups.
+func (b *backups) List() ([]*Metadata, error) {
+	metadataList, err := b.metadata.List()
+	return metadataList, errors.Trace(err)
+}
+
+// Remove deletes the backup archive and its metadata.
+func (b *backups) Remove(id string) error {
+	metadata, err := b.metadata.Get(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if metadata.Stored {
+		if err := b.archives.Remove(archiveName(id)); err != nil {
+			return errors.Annotate(err, "error removing backup archive")
+		}
+	}
+	return errors.Trace(b.metadata.Remove(id))
+}
+
+// LastGood returns the metadata of the most recent backup that
+// succeeded.
+func (b *backups) LastGood() (*Metadata, error) {
+	metadataList, err := b.metadata.List()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var last *Metadata
+	for _, metadata := range metadataList {
+		if metadata.Error != "" || !metadata.Stored {
+			continue
+		}
+		if last == nil || metadata.Timestamp.After(last.Timestamp) {
+			last = metadata
+		}
+	}
+	if last == nil {
+		return nil, errors.NotFoundf("successful backup")
+	}
+	return last, nil
+}
+
+// Add stores an existing backup archive and returns its associated
+// metadata.  The origin of the backup is read from the archive itself.
+func (b *backups) Add(archive io.Reader, checksum string) (*Metadata, error) {
+	tempDir, err := ioutil.TempDir("", "jujuBackupArchive")
+	if err != nil {
+		return nil, errors.Annotate(err, "error creating temp directory")
+	}
+	defer os.RemoveAll(tempDir)
+
+	filename, actual, err := copyArchive(archive, tempDir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if actual != checksum {
+		return nil, errors.Errorf("backup archive checksum mismatch: expected %q, got %q", checksum, actual)
+	}
+	contentdir, err := unpackArchive(filename, tempDir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	origin, err := readOrigin(contentdir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.Annotate(err, "error opening backup archive")
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, errors.Annotate(err, "error reading backup archive")
+	}
+
+	metadata := NewMetadata(checksum, stat.Size(), *origin, "")
+	id, err := b.metadata.Add(metadata)
+	if err != nil {
+		return nil, errors.Annotate(err, "error storing backup metadata")
+	}
+	metadata.ID = id
+
+	logger.Infof("storing uploaded backup archive %q", id)
+	if err := b.archives.Put(archiveName(id), file, stat.Size()); err != nil {
+		if err := b.metadata.Remove(id); err != nil {
+			logger.Errorf("cannot remove metadata of unstored backup %q: %v", id, err)
+		}
+		return nil, errors.Annotate(err, "error storing backup archive")
+	}
+	if err := b.metadata.SetStored(id); err != nil {
+		return nil, errors.Annotate(err, "error updating backup metadata")
+	}
+	metadata.Stored = true
+	return metadata, nil
+}
+
+// Restore replaces the state of the running state server with the
+// identified backup.
+func (b *backups) Restore(id string, args RestoreArgs) error {
+	metadata, archive, err := b.Get(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+	logger.Infof("restoring backup %q", id)
+	return errors.Trace(restore(archive, metadata, args))
+}
@@ -0,0 +1,244 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&backupsSuite{})
+
+type backupsSuite struct {
+	testing.BaseSuite
+	metadata *fakeMetadataStorage
+	archives storage.Storage
+	backups  backups.Backups
+}
+
+func (s *backupsSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.metadata = &fakeMetadataStorage{docs: make(map[string]*backups.Metadata)}
+	archives, err := filestorage.NewFileStorageWriter(c.MkDir())
+	c.Assert(err, gc.IsNil)
+	s.archives = archives
+	s.backups = backups.NewBackups(s.metadata, s.archives)
+}
+
+// fakeMetadataStorage is an in-memory backups.MetadataStorage.
+type fakeMetadataStorage struct {
+	docs   map[string]*backups.Metadata
+	nextID int
+}
+
+func (f *fakeMetadataStorage) Add(metadata *backups.Metadata) (string, error) {
+	id := fmt.Sprintf("backup-%d", f.nextID)
+	f.nextID++
+	stored := *metadata
+	stored.ID = id
+	f.docs[id] = &stored
+	return id, nil
+}
+
+func (f *fakeMetadataStorage) Get(id string) (*backups.Metadata, error) {
+	metadata, ok := f.docs[id]
+	if !ok {
+		return nil, errors.NotFoundf("backup metadata %q", id)
+	}
+	result := *metadata
+	return &result, nil
+}
+
+func (f *fakeMetadataStorage) List() ([]*backups.Metadata, error) {
+	var list []*backups.Metadata
+	for id := range f.docs {
+		metadata, _ := f.Get(id)
+		list = append(list, metadata)
+	}
+	return list, nil
+}
+
+func (f *fakeMetadataStorage) SetStored(id string) error {
+	metadata, ok := f.docs[id]
+	if !ok {
+		return errors.NotFoundf("backup metadata %q", id)
+	}
+	metadata.Stored = true
+	return nil
+}
+
+func (f *fakeMetadataStorage) Remove(id string) error {
+	if _, ok := f.docs[id]; !ok {
+		return errors.NotFoundf("backup metadata %q", id)
+	}
+	delete(f.docs, id)
+	return nil
+}
+
+func (s *backupsSuite) patchBackup(c *gc.C, content string) {
+	s.PatchValue(backups.RunBackup, func(dbInfo backups.DBConnInfo, outputFolder string, origin *backups.Origin) (string, string, error) {
+		c.Check(dbInfo.Password(), gc.Equals, "secret")
+		c.Check(dbInfo.Username(), gc.Equals, "machine-0")
+		c.Check(dbInfo.Address(), gc.Equals, "localhost:37017")
+		c.Check(origin.Machine, gc.Equals, "0")
+		filename := "juju-backup_20140101000000.tar.gz"
+		err := ioutil.WriteFile(filepath.Join(outputFolder, filename), []byte(content), 0600)
+		c.Assert(err, gc.IsNil)
+		return filename, "some-hash", nil
+	})
+}
+
+func (s *backupsSuite) create(c *gc.C, notes string) *backups.Metadata {
+	dbInfo := backups.NewDBConnInfo("localhost:37017", "machine-0", "secret")
+	origin := backups.Origin{Environment: "env", Machine: "0", Hostname: "host"}
+	metadata, err := s.backups.Create(dbInfo, origin, notes)
+	c.Assert(err, gc.IsNil)
+	return metadata
+}
+
+func (s *backupsSuite) TestCreate(c *gc.C) {
+	s.patchBackup(c, "archive data")
+	metadata := s.create(c, "some notes")
+
+	c.Check(metadata.ID, gc.Equals, "backup-0")
+	c.Check(metadata.CheckSum, gc.Equals, "some-hash")
+	c.Check(metadata.Size, gc.Equals, int64(len("archive data")))
+	c.Check(metadata.Notes, gc.Equals, "some notes")
+	c.Check(metadata.Origin.Machine, gc.Equals, "0")
+	c.Check(metadata.Stored, jc.IsTrue)
+	c.Check(metadata.Finished.Before(metadata.Timestamp), jc.IsFalse)
+
+	stored, err := s.metadata.Get(metadata.ID)
+	c.Assert(err, gc.IsNil)
+	c.Check(stored.Stored, jc.IsTrue)
+	names, err := s.archives.List("backups/")
+	c.Assert(err, gc.IsNil)
+	c.Check(names, gc.DeepEquals, []string{"backups/juju-backup-backup-0.tar.gz"})
+}
+
+func (s *backupsSuite) TestCreateFailure(c *gc.C) {
+	s.PatchValue(backups.RunBackup, func(backups.DBConnInfo, string, *backups.Origin) (string, string, error) {
+		return "", "", fmt.Errorf("mongodump failed")
+	})
+	dbInfo := backups.NewDBConnInfo("localhost:37017", "machine-0", "secret")
+	_, err := s.backups.Create(dbInfo, backups.Origin{}, "")
+	c.Check(err, gc.ErrorMatches, "error creating backup archive: mongodump failed")
+
+	// The failure is recorded.
+	c.Assert(s.metadata.docs, gc.HasLen, 1)
+	failed := s.metadata.docs["backup-0"]
+	c.Check(failed.Error, gc.Equals, "error creating backup archive: mongodump failed")
+	c.Check(failed.Stored, jc.IsFalse)
+	c.Check(failed.Finished.Before(failed.Timestamp), jc.IsFalse)
+}
+
+func (s *backupsSuite) TestInfo(c *gc.C) {
+	s.patchBackup(c, "archive data")
+	created := s.create(c, "")
+
+	metadata, err := s.backups.Info(created.ID)
+	c.Assert(err, gc.IsNil)
+	c.Check(metadata, jc.DeepEquals, created)
+
+	_, err = s.backups.Info("spam")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *backupsSuite) TestGet(c *gc.C) {
+	s.patchBackup(c, "archive data")
+	created := s.create(c, "")
+
+	metadata, archive, err := s.backups.Get(created.ID)
+	c.Assert(err, gc.IsNil)
+	defer archive.Close()
+	c.Check(metadata, jc.DeepEquals, created)
+	data, err := ioutil.ReadAll(archive)
+	c.Assert(err, gc.IsNil)
+	c.Check(string(data), gc.Equals, "archive data")
+}
+
+func (s *backupsSuite) TestGetNotFound(c *gc.C) {
+	_, _, err := s.backups.Get("spam")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *backupsSuite) TestGetNotStored(c *gc.C) {
+	id, err := s.metadata.Add(&backups.Metadata{})
+	c.Assert(err, gc.IsNil)
+	_, _, err = s.backups.Get(id)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(err, gc.ErrorMatches, `backup archive "backup-0" not found`)
+}
+
+func (s *backupsSuite) TestList(c *gc.C) {
+	s.patchBackup(c, "archive data")
+	created := s.create(c, "")
+
+	list, err := s.backups.List()
+	c.Assert(err, gc.IsNil)
+	c.Check(list, jc.DeepEquals, []*backups.Metadata{created})
+}
+
+func (s *backupsSuite) TestRemove(c *gc.C) {
+	s.patchBackup(c, "archive data")
+	created := s.create(c, "")
+
+	err := s.backups.Remove(created.ID)
+	c.Assert(err, gc.IsNil)
+	c.Check(s.metadata.docs, gc.HasLen, 0)
+	names, err := s.archives.List("backups/")
+	c.Assert(err, gc.IsNil)
+	c.Check(names, gc.HasLen, 0)
+}
+
+func (s *backupsSuite) TestRemoveNotFound(c *gc.C) {
+	err := s.backups.Remove("spam")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *backupsSuite) TestRemoveFailed(c *gc.C) {
+	id, err := s.metadata.Add(&backups.Metadata{Error: "mongodump failed"})
+	c.Assert(err, gc.IsNil)
+
+	err = s.backups.Remove(id)
+	c.Assert(err, gc.IsNil)
+	c.Check(s.metadata.docs, gc.HasLen, 0)
+}
+
+func (s *backupsSuite) TestLastGood(c *gc.C) {
+	s.patchBackup(c, "archive data")
+	first := s.create(c, "first")
+	second := s.create(c, "second")
+	second.Timestamp = first.Timestamp.Add(time.Minute)
+	s.metadata.docs[second.ID].Timestamp = second.Timestamp
+	_, err := s.metadata.Add(&backups.Metadata{
+		Timestamp: first.Timestamp.Add(time.Hour),
+		Error:     "mongodump failed",
+	})
+	c.Assert(err, gc.IsNil)
+
+	metadata, err := s.backups.LastGood()
+	c.Assert(err, gc.IsNil)
+	c.Check(metadata, jc.DeepEquals, second)
+}
+
+func (s *backupsSuite) TestLastGoodNotFound(c *gc.C) {
+	_, err := s.metadata.Add(&backups.Metadata{Error: "mongodump failed"})
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.backups.LastGood()
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(err, gc.ErrorMatches, "successful backup not found")
+}
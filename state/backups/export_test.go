@@ -4,7 +4,11 @@
 package backups
 
 var (
-	GetMongodumpPath = &getMongodumpPath
-	GetFilesToBackup = &getFilesToBackup
-	RunCommand       = &runCommand
+	GetMongodumpPath    = &getMongodumpPath
+	GetFilesToBackup    = &getFilesToBackup
+	RunCommand          = &runCommand
+	RunBackup           = &runBackup
+	RestoreRoot         = &restoreRoot
+	GetMongorestorePath = &getMongorestorePath
+	Untar               = untar
 )
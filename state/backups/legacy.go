@@ -26,6 +26,13 @@ import (
 // Between the two, this is all that is necessary to later restore the
 // juju agent on another machine.
 func Backup(password string, username string, outputFolder string, addr string) (filename string, sha1sum string, err error) {
+	return backup(NewDBConnInfo(addr, username, password), outputFolder, nil)
+}
+
+// backup creates a backup archive as Backup does.  If origin is not
+// nil it is also written to the archive, as juju-backup/metadata.json,
+// so that the archive can be validated when it is restored.
+func backup(dbinfo DBConnInfo, outputFolder string, origin *Origin) (filename string, sha1sum string, err error) {
 	// YYYYMMDDHHMMSS
 	formattedDate := time.Now().Format("20060102150405")
 	bkpFile := fmt.Sprintf("juju-backup_%s.tar.gz", formattedDate)
@@ -37,6 +44,13 @@ func Backup(password string, username string, outputFolder string, addr string)
 	}
 	defer os.RemoveAll(root)
 
+	// Record where the backup came from.
+	if origin != nil {
+		if err := writeOrigin(contentdir, origin); err != nil {
+			return "", "", errors.Trace(err)
+		}
+	}
+
 	// Dump the files.
 	logger.Infof("dumping state-related files")
 	err = dumpFiles(contentdir)
@@ -46,7 +60,6 @@ func Backup(password string, username string, outputFolder string, addr string)
 
 	// Dump the database.
 	logger.Infof("dumping database")
-	dbinfo := NewDBConnInfo(addr, username, password)
 	err = dumpDatabase(dbinfo, dumpdir)
 	if err != nil {
 		return "", "", errors.Trace(err)
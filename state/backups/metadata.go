@@ -43,6 +43,9 @@ type Metadata struct {
 	Stored bool
 	// Notes (optional) contains any user-supplied annotations for the archive.
 	Notes string
+	// Error records why the backup failed, if it did.  Failed backups
+	// have no archive.
+	Error string
 }
 
 // NewMetadata returns a new Metadata for a state backup archive.  The
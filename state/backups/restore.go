@@ -0,0 +1,312 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/hash"
+
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/version"
+)
+
+const (
+	metadataFile = "metadata.json"
+	restoreName  = "mongorestore"
+)
+
+// restoreRoot is the directory under which the state-related files in
+// a backup archive are restored.
+var restoreRoot = "/"
+
+// writeOrigin records the origin of a backup in the archive's content
+// directory.
+func writeOrigin(contentdir string, origin *Origin) error {
+	data, err := json.Marshal(origin)
+	if err != nil {
+		return errors.Annotate(err, "error marshalling backup metadata")
+	}
+	err = ioutil.WriteFile(filepath.Join(contentdir, metadataFile), data, 0600)
+	return errors.Annotate(err, "error writing backup metadata")
+}
+
+// readOrigin returns the origin recorded in an unpacked backup archive.
+func readOrigin(contentdir string) (*Origin, error) {
+	data, err := ioutil.ReadFile(filepath.Join(contentdir, metadataFile))
+	if os.IsNotExist(err) {
+		return nil, errors.Errorf("backup archive has no metadata")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "error reading backup metadata")
+	}
+	var origin Origin
+	if err := json.Unmarshal(data, &origin); err != nil {
+		return nil, errors.Annotate(err, "error unmarshalling backup metadata")
+	}
+	return &origin, nil
+}
+
+// ValidateOrigin checks that a backup taken at the given origin may be
+// restored into the environment with the given UUID by this version of
+// juju.
+func ValidateOrigin(origin Origin, envUUID string) error {
+	if origin.Environment != envUUID {
+		return errors.Errorf("backup is of environment %q, not %q", origin.Environment, envUUID)
+	}
+	current := version.Current.Number
+	if origin.Version.Major != current.Major || origin.Version.Minor != current.Minor {
+		return errors.Errorf("backup was made by juju %s, cannot restore it with juju %s", origin.Version, current)
+	}
+	return nil
+}
+
+// RestoreArgs holds what is needed to restore a backup into the state
+// server it is run on.
+type RestoreArgs struct {
+	// DBInfo identifies the state server's database.
+	DBInfo DBConnInfo
+	// EnvironUUID identifies the environment being restored; only
+	// backups of that environment are accepted.
+	EnvironUUID string
+}
+
+// copyArchive copies the archive into a new file in dir, and returns
+// the path of the file and the archive's checksum.
+func copyArchive(archive io.Reader, dir string) (string, string, error) {
+	file, err := ioutil.TempFile(dir, "archive")
+	if err != nil {
+		return "", "", errors.Annotate(err, "error creating archive file")
+	}
+	defer file.Close()
+	hasher := hash.NewHashingWriter(file, sha1.New())
+	if _, err := io.Copy(hasher, archive); err != nil {
+		return "", "", errors.Annotate(err, "error reading backup archive")
+	}
+	return file.Name(), hasher.Base64Sum(), nil
+}
+
+// restore restores the backup archive described by metadata into the
+// running state server: the state-related files are put back in place
+// and the database is replaced with the dump in the archive.
+func restore(archive io.Reader, metadata *Metadata, args RestoreArgs) error {
+	if err := ValidateOrigin(metadata.Origin, args.EnvironUUID); err != nil {
+		return errors.Trace(err)
+	}
+	tempDir, err := ioutil.TempDir("", "jujuRestore")
+	if err != nil {
+		return errors.Annotate(err, "error creating temp directory")
+	}
+	defer os.RemoveAll(tempDir)
+
+	filename, checksum, err := copyArchive(archive, tempDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if checksum != metadata.CheckSum {
+		return errors.Errorf("backup archive checksum mismatch: expected %q, got %q", metadata.CheckSum, checksum)
+	}
+	contentdir, err := unpackArchive(filename, tempDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	logger.Infof("restoring state-related files")
+	rootTar, err := os.Open(filepath.Join(contentdir, "root.tar"))
+	if err != nil {
+		return errors.Annotate(err, "error opening state-related files")
+	}
+	defer rootTar.Close()
+	if err := untar(rootTar, restoreRoot); err != nil {
+		return errors.Annotate(err, "cannot restore state-related files")
+	}
+
+	logger.Infof("restoring database")
+	if err := restoreDatabase(args.DBInfo, filepath.Join(contentdir, "dump")); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// unpackArchive unpacks the backup archive file into dir, and returns
+// the path of the archive's content directory.
+func unpackArchive(filename, dir string) (string, error) {
+	archive, err := os.Open(filename)
+	if err != nil {
+		return "", errors.Annotate(err, "error opening backup archive")
+	}
+	defer archive.Close()
+	gzr, err := gzip.NewReader(archive)
+	if err != nil {
+		return "", errors.Annotate(err, "error uncompressing backup archive")
+	}
+	defer gzr.Close()
+	unpackDir := filepath.Join(dir, "unpacked")
+	if err := untar(gzr, unpackDir); err != nil {
+		return "", errors.Annotate(err, "error unpacking backup archive")
+	}
+	return filepath.Join(unpackDir, "juju-backup"), nil
+}
+
+// untar extracts the tar stream into dir, refusing any entry that
+// would be written outside of it.
+func untar(r io.Reader, dir string) error {
+	tarr := tar.NewReader(r)
+	for {
+		hdr, err := tarr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		name, err := entryName(dir, hdr.Name)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, name)
+		mode := os.FileMode(hdr.Mode).Perm()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := checkNoSymlinks(dir, name); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(target, mode); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			// Links are only followed within the archive's own
+			// tree, and are never written through.
+			if filepath.IsAbs(hdr.Linkname) || hasDotDot(hdr.Linkname) {
+				return fmt.Errorf("invalid link target %q of archive entry %q", hdr.Linkname, hdr.Name)
+			}
+			if err := checkNoSymlinks(dir, filepath.Dir(name)); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			os.Remove(target)
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := checkNoSymlinks(dir, name); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, tarr)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			logger.Debugf("skipping archive entry %q of type %c", hdr.Name, hdr.Typeflag)
+		}
+	}
+}
+
+// entryName returns the cleaned path, relative to dir, of the archive
+// entry with the given name, or an error if it is not within dir.
+func entryName(dir, entry string) (string, error) {
+	name := filepath.Clean(entry)
+	if filepath.IsAbs(name) || hasDotDot(name) {
+		return "", fmt.Errorf("invalid archive entry %q", entry)
+	}
+	rel, err := filepath.Rel(dir, filepath.Join(dir, name))
+	if err != nil || hasDotDot(rel) {
+		return "", fmt.Errorf("invalid archive entry %q", entry)
+	}
+	return name, nil
+}
+
+// hasDotDot returns whether any element of path is "..".
+func hasDotDot(path string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(path), "/") {
+		if part == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNoSymlinks returns an error if any existing element of the
+// path name within dir is a symbolic link, so that no archive entry
+// is written elsewhere through a link.
+func checkNoSymlinks(dir, name string) error {
+	path := dir
+	for _, part := range strings.Split(name, string(os.PathSeparator)) {
+		if part == "." {
+			continue
+		}
+		path = filepath.Join(path, part)
+		info, err := os.Lstat(path)
+		if os.IsNotExist(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("invalid archive entry: %q is a symlink", path)
+		}
+	}
+	return nil
+}
+
+var getMongorestorePath = func() (string, error) {
+	mongod, err := mongo.Path()
+	if err != nil {
+		return "", errors.Annotate(err, "failed to get mongod path")
+	}
+	mongoRestorePath := filepath.Join(filepath.Dir(mongod), restoreName)
+
+	if _, err := os.Stat(mongoRestorePath); err == nil {
+		// It already exists so no need to continue.
+		return mongoRestorePath, nil
+	}
+
+	path, err := exec.LookPath(restoreName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return path, nil
+}
+
+func restoreDatabase(info DBConnInfo, dumpdir string) error {
+	mongorestorePath, err := getMongorestorePath()
+	if err != nil {
+		return errors.Annotate(err, "mongorestore not available")
+	}
+
+	err = runCommand(
+		mongorestorePath,
+		"--drop",
+		"--oplogReplay",
+		"--ssl",
+		"--host", info.Address(),
+		"--username", info.Username(),
+		"--password", info.Password(),
+		dumpdir,
+	)
+	if err != nil {
+		return errors.Annotate(err, "failed to restore database")
+	}
+
+	return nil
+}
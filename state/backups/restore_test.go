@@ -0,0 +1,308 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+var _ = gc.Suite(&restoreSuite{})
+
+type restoreSuite struct {
+	testing.BaseSuite
+	metadata    *fakeMetadataStorage
+	backups     backups.Backups
+	cwd         string
+	restoreRoot string
+	commands    [][]string
+}
+
+func (s *restoreSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.metadata = &fakeMetadataStorage{docs: make(map[string]*backups.Metadata)}
+	archives, err := filestorage.NewFileStorageWriter(c.MkDir())
+	c.Assert(err, gc.IsNil)
+	s.backups = backups.NewBackups(s.metadata, archives)
+
+	s.cwd = c.MkDir()
+	err = ioutil.WriteFile(filepath.Join(s.cwd, "agent.conf"), []byte("agent config"), 0600)
+	c.Assert(err, gc.IsNil)
+	s.PatchValue(backups.GetFilesToBackup, func(string) ([]string, error) {
+		return []string{filepath.Join(s.cwd, "agent.conf")}, nil
+	})
+	s.PatchValue(backups.GetMongodumpPath, func() (string, error) {
+		return "bogusmongodump", nil
+	})
+	s.PatchValue(backups.GetMongorestorePath, func() (string, error) {
+		return "bogusmongorestore", nil
+	})
+	s.commands = nil
+	s.PatchValue(backups.RunCommand, func(command string, args ...string) error {
+		s.commands = append(s.commands, append([]string{command}, args...))
+		return nil
+	})
+	s.restoreRoot = c.MkDir()
+	s.PatchValue(backups.RestoreRoot, s.restoreRoot)
+}
+
+func (s *restoreSuite) dbInfo() backups.DBConnInfo {
+	return backups.NewDBConnInfo("localhost:37017", "machine-0", "secret")
+}
+
+func (s *restoreSuite) origin() backups.Origin {
+	return backups.Origin{
+		Environment: "env-uuid",
+		Machine:     "0",
+		Hostname:    "host",
+		Version:     version.Current.Number,
+	}
+}
+
+func (s *restoreSuite) create(c *gc.C, origin backups.Origin) *backups.Metadata {
+	metadata, err := s.backups.Create(s.dbInfo(), origin, "")
+	c.Assert(err, gc.IsNil)
+	s.commands = nil
+	return metadata
+}
+
+func (s *restoreSuite) restoreArgs() backups.RestoreArgs {
+	return backups.RestoreArgs{
+		DBInfo:      s.dbInfo(),
+		EnvironUUID: "env-uuid",
+	}
+}
+
+func (s *restoreSuite) TestRestore(c *gc.C) {
+	metadata := s.create(c, s.origin())
+
+	err := s.backups.Restore(metadata.ID, s.restoreArgs())
+	c.Assert(err, gc.IsNil)
+
+	data, err := ioutil.ReadFile(filepath.Join(s.restoreRoot, s.cwd, "agent.conf"))
+	c.Assert(err, gc.IsNil)
+	c.Check(string(data), gc.Equals, "agent config")
+
+	c.Assert(s.commands, gc.HasLen, 1)
+	command := s.commands[0]
+	c.Check(command[0], gc.Equals, "bogusmongorestore")
+	c.Check(command[1:len(command)-1], gc.DeepEquals, []string{
+		"--drop",
+		"--oplogReplay",
+		"--ssl",
+		"--host", "localhost:37017",
+		"--username", "machine-0",
+		"--password", "secret",
+	})
+	c.Check(filepath.Base(command[len(command)-1]), gc.Equals, "dump")
+}
+
+func (s *restoreSuite) TestRestoreNotFound(c *gc.C) {
+	err := s.backups.Restore("spam", s.restoreArgs())
+	c.Check(err, gc.ErrorMatches, `backup metadata "spam" not found`)
+}
+
+func (s *restoreSuite) TestRestoreWrongEnvironment(c *gc.C) {
+	metadata := s.create(c, s.origin())
+	args := s.restoreArgs()
+	args.EnvironUUID = "other-uuid"
+
+	err := s.backups.Restore(metadata.ID, args)
+	c.Check(err, gc.ErrorMatches, `backup is of environment "env-uuid", not "other-uuid"`)
+	c.Check(s.commands, gc.HasLen, 0)
+}
+
+func (s *restoreSuite) TestRestoreWrongVersion(c *gc.C) {
+	origin := s.origin()
+	origin.Version.Minor++
+	metadata := s.create(c, origin)
+
+	err := s.backups.Restore(metadata.ID, s.restoreArgs())
+	c.Check(err, gc.ErrorMatches, `backup was made by juju .*, cannot restore it with juju .*`)
+	c.Check(s.commands, gc.HasLen, 0)
+}
+
+func (s *restoreSuite) TestRestoreChecksumMismatch(c *gc.C) {
+	metadata := s.create(c, s.origin())
+	s.metadata.docs[metadata.ID].CheckSum = "bogus"
+
+	err := s.backups.Restore(metadata.ID, s.restoreArgs())
+	c.Check(err, gc.ErrorMatches, `backup archive checksum mismatch: expected "bogus", got .*`)
+	c.Check(s.commands, gc.HasLen, 0)
+	_, err = os.Stat(filepath.Join(s.restoreRoot, s.cwd, "agent.conf"))
+	c.Check(os.IsNotExist(err), jc.IsTrue)
+}
+
+// archive returns the content of the archive of the given backup,
+// along with its checksum.
+func (s *restoreSuite) archive(c *gc.C, id string) ([]byte, string) {
+	_, archive, err := s.backups.Get(id)
+	c.Assert(err, gc.IsNil)
+	defer archive.Close()
+	data, err := ioutil.ReadAll(archive)
+	c.Assert(err, gc.IsNil)
+	hash := sha1.Sum(data)
+	return data, base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func (s *restoreSuite) TestAdd(c *gc.C) {
+	created := s.create(c, s.origin())
+	data, checksum := s.archive(c, created.ID)
+	c.Assert(checksum, gc.Equals, created.CheckSum)
+
+	metadata, err := s.backups.Add(bytes.NewReader(data), checksum)
+	c.Assert(err, gc.IsNil)
+	c.Check(metadata.ID, gc.Not(gc.Equals), created.ID)
+	c.Check(metadata.CheckSum, gc.Equals, checksum)
+	c.Check(metadata.Size, gc.Equals, int64(len(data)))
+	c.Check(metadata.Origin, gc.Equals, s.origin())
+	c.Check(metadata.Stored, jc.IsTrue)
+
+	_, added := s.archive(c, metadata.ID)
+	c.Check(added, gc.Equals, checksum)
+}
+
+func (s *restoreSuite) TestAddChecksumMismatch(c *gc.C) {
+	created := s.create(c, s.origin())
+	data, _ := s.archive(c, created.ID)
+
+	_, err := s.backups.Add(bytes.NewReader(data), "bogus")
+	c.Check(err, gc.ErrorMatches, `backup archive checksum mismatch: expected "bogus", got .*`)
+	c.Check(s.metadata.docs, gc.HasLen, 1)
+}
+
+// failingStorage is a storage.Storage that cannot store files.
+type failingStorage struct {
+	storage.Storage
+}
+
+func (failingStorage) Put(name string, r io.Reader, length int64) error {
+	return fmt.Errorf("disk full")
+}
+
+func (s *restoreSuite) TestAddStoreFailure(c *gc.C) {
+	created := s.create(c, s.origin())
+	data, checksum := s.archive(c, created.ID)
+
+	b := backups.NewBackups(s.metadata, failingStorage{})
+	_, err := b.Add(bytes.NewReader(data), checksum)
+	c.Check(err, gc.ErrorMatches, "error storing backup archive: disk full")
+
+	// The metadata of the unstored archive is removed.
+	c.Check(s.metadata.docs, gc.HasLen, 1)
+	c.Check(s.metadata.docs[created.ID], gc.NotNil)
+}
+
+func (s *restoreSuite) TestAddWithoutMetadata(c *gc.C) {
+	// Archives made by the legacy backup do not record their origin.
+	filename, checksum, err := backups.Backup("secret", "machine-0", s.cwd, "localhost:37017")
+	c.Assert(err, gc.IsNil)
+	data, err := ioutil.ReadFile(filepath.Join(s.cwd, filename))
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.backups.Add(bytes.NewReader(data), checksum)
+	c.Check(err, gc.ErrorMatches, "backup archive has no metadata")
+	c.Check(s.metadata.docs, gc.HasLen, 0)
+}
+
+type tarEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	content  string
+}
+
+func makeTar(c *gc.C, entries ...tarEntry) *bytes.Buffer {
+	var buf bytes.Buffer
+	tarw := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		err := tarw.WriteHeader(&tar.Header{
+			Name:     entry.name,
+			Typeflag: entry.typeflag,
+			Linkname: entry.linkname,
+			Mode:     0644,
+			Size:     int64(len(entry.content)),
+		})
+		c.Assert(err, gc.IsNil)
+		_, err = tarw.Write([]byte(entry.content))
+		c.Assert(err, gc.IsNil)
+	}
+	c.Assert(tarw.Close(), gc.IsNil)
+	return &buf
+}
+
+func (s *restoreSuite) TestUntar(c *gc.C) {
+	dir := c.MkDir()
+	err := backups.Untar(makeTar(c,
+		tarEntry{name: "etc/", typeflag: tar.TypeDir},
+		tarEntry{name: "etc/agent.conf", typeflag: tar.TypeReg, content: "config"},
+		tarEntry{name: "etc/current", typeflag: tar.TypeSymlink, linkname: "agent.conf"},
+	), dir)
+	c.Assert(err, gc.IsNil)
+	data, err := ioutil.ReadFile(filepath.Join(dir, "etc", "current"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, "config")
+}
+
+var untarErrorTests = []struct {
+	about   string
+	entries []tarEntry
+	err     string
+}{{
+	about:   "absolute path",
+	entries: []tarEntry{{name: "/etc/passwd", typeflag: tar.TypeReg}},
+	err:     `invalid archive entry "/etc/passwd"`,
+}, {
+	about:   "path outside the root",
+	entries: []tarEntry{{name: "a/../../etc/passwd", typeflag: tar.TypeReg}},
+	err:     `invalid archive entry "a/../../etc/passwd"`,
+}, {
+	about:   "absolute link target",
+	entries: []tarEntry{{name: "etc", typeflag: tar.TypeSymlink, linkname: "/etc"}},
+	err:     `invalid link target "/etc" of archive entry "etc"`,
+}, {
+	about:   "link target outside the root",
+	entries: []tarEntry{{name: "etc", typeflag: tar.TypeSymlink, linkname: "a/../../etc"}},
+	err:     `invalid link target "a/../../etc" of archive entry "etc"`,
+}, {
+	about: "file within a link",
+	entries: []tarEntry{
+		{name: "a", typeflag: tar.TypeDir},
+		{name: "b", typeflag: tar.TypeSymlink, linkname: "a"},
+		{name: "b/passwd", typeflag: tar.TypeReg},
+	},
+	err: `invalid archive entry: ".*/b" is a symlink`,
+}, {
+	about: "file replacing a link",
+	entries: []tarEntry{
+		{name: "a", typeflag: tar.TypeReg},
+		{name: "b", typeflag: tar.TypeSymlink, linkname: "a"},
+		{name: "b", typeflag: tar.TypeReg},
+	},
+	err: `invalid archive entry: ".*/b" is a symlink`,
+}}
+
+func (s *restoreSuite) TestUntarErrors(c *gc.C) {
+	for i, test := range untarErrorTests {
+		c.Logf("test %d: %s", i, test.about)
+		err := backups.Untar(makeTar(c, test.entries...), c.MkDir())
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
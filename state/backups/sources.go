@@ -139,6 +139,20 @@ func NewDBConnInfo(addr, user, pw string) DBConnInfo {
 	return &dbinfo
 }
 
+// NewDBConnInfoFromMongo returns the DBConnInfo for the database
+// described by the given mongo connection info, using its first
+// address.
+func NewDBConnInfoFromMongo(mgoInfo *authentication.MongoInfo) (DBConnInfo, error) {
+	if len(mgoInfo.Addrs) == 0 {
+		return nil, errors.New("no database address")
+	}
+	var username string
+	if mgoInfo.Tag != nil {
+		username = mgoInfo.Tag.String()
+	}
+	return NewDBConnInfo(mgoInfo.Addrs[0], username, mgoInfo.Password), nil
+}
+
 // Address returns the connection address.
 func (ci *dbConnInfo) Address() string {
 	return ci.address
@@ -7,9 +7,12 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "launchpad.net/gocheck"
 
+	"github.com/juju/juju/environmentserver/authentication"
+	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/state/backups"
 	"github.com/juju/juju/testing"
 )
@@ -85,3 +88,22 @@ func (s *sourcesSuite) TestGetFilesToBackup(c *gc.C) {
 		filepath.Join(s.root, "/var/log/juju/machine-0.log"),
 	})
 }
+
+func (s *sourcesSuite) TestNewDBConnInfoFromMongo(c *gc.C) {
+	mgoInfo := &authentication.MongoInfo{
+		Info: mongo.Info{
+			Addrs: []string{"10.0.0.1:37017", "10.0.0.2:37017"},
+		},
+		Tag:      names.NewMachineTag("0"),
+		Password: "secret",
+	}
+	dbInfo, err := backups.NewDBConnInfoFromMongo(mgoInfo)
+	c.Assert(err, gc.IsNil)
+	c.Check(dbInfo.Address(), gc.Equals, "10.0.0.1:37017")
+	c.Check(dbInfo.Username(), gc.Equals, "machine-0")
+	c.Check(dbInfo.Password(), gc.Equals, "secret")
+
+	mgoInfo.Addrs = nil
+	_, err = backups.NewDBConnInfoFromMongo(mgoInfo)
+	c.Assert(err, gc.ErrorMatches, "no database address")
+}
@@ -4,12 +4,15 @@
 package state_test
 
 import (
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "launchpad.net/gocheck"
 
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/backups"
 	"github.com/juju/juju/version"
@@ -40,6 +43,7 @@ func (s *backupSuite) checkMetadata(
 	c.Check(metadata.Size, gc.Equals, expected.Size)
 	c.Check(metadata.Origin, gc.DeepEquals, expected.Origin)
 	c.Check(metadata.Stored, gc.DeepEquals, expected.Stored)
+	c.Check(metadata.Error, gc.Equals, expected.Error)
 }
 
 //---------------------------
@@ -58,7 +62,7 @@ func (s *backupSuite) TestBackupsNewBackupOrigin(c *gc.C) {
 }
 
 //---------------------------
-// getBackupMetadata()
+// GetBackupMetadata()
 
 func (s *backupSuite) TestBackupsGetBackupMetadataFound(c *gc.C) {
 	expected := s.metadata(c)
@@ -77,7 +81,7 @@ func (s *backupSuite) TestBackupsGetBackupMetadataNotFound(c *gc.C) {
 }
 
 //---------------------------
-// addBackupMetadata()
+// AddBackupMetadata()
 
 func (s *backupSuite) TestBackupsAddBackupMetadataSuccess(c *gc.C) {
 	expected := s.metadata(c)
@@ -90,6 +94,17 @@ func (s *backupSuite) TestBackupsAddBackupMetadataSuccess(c *gc.C) {
 	s.checkMetadata(c, metadata, expected, id)
 }
 
+func (s *backupSuite) TestBackupsAddBackupMetadataFailed(c *gc.C) {
+	expected := s.metadata(c)
+	expected.Error = "mongodump failed"
+	id, err := state.AddBackupMetadata(s.State, expected)
+	c.Assert(err, gc.IsNil)
+
+	metadata, err := state.GetBackupMetadata(s.State, id)
+	c.Assert(err, gc.IsNil)
+	s.checkMetadata(c, metadata, expected, id)
+}
+
 func (s *backupSuite) TestBackupsAddBackupMetadataGeneratedID(c *gc.C) {
 	expected := s.metadata(c)
 	expected.ID = "spam"
@@ -119,7 +134,7 @@ func (s *backupSuite) TestBackupsAddBackupMetadataAlreadyExists(c *gc.C) {
 }
 
 //---------------------------
-// setBackupStored()
+// SetBackupStored()
 
 func (s *backupSuite) TestBackupsSetBackupStoredSuccess(c *gc.C) {
 	original := s.metadata(c)
@@ -142,3 +157,107 @@ func (s *backupSuite) TestBackupsSetBackupStoredNotFound(c *gc.C) {
 
 	c.Check(err, jc.Satisfies, errors.IsNotFound)
 }
+
+//---------------------------
+// ListBackupMetadata()
+
+func (s *backupSuite) TestBackupsListBackupMetadata(c *gc.C) {
+	first := s.metadata(c)
+	first.Timestamp = first.Timestamp.Add(-time.Hour)
+	firstID, err := state.AddBackupMetadata(s.State, first)
+	c.Assert(err, gc.IsNil)
+	second := s.metadata(c)
+	secondID, err := state.AddBackupMetadata(s.State, second)
+	c.Assert(err, gc.IsNil)
+
+	list, err := state.ListBackupMetadata(s.State)
+	c.Assert(err, gc.IsNil)
+	c.Assert(list, gc.HasLen, 2)
+	s.checkMetadata(c, list[0], first, firstID)
+	s.checkMetadata(c, list[1], second, secondID)
+}
+
+func (s *backupSuite) TestBackupsListBackupMetadataEmpty(c *gc.C) {
+	list, err := state.ListBackupMetadata(s.State)
+	c.Assert(err, gc.IsNil)
+	c.Check(list, gc.HasLen, 0)
+}
+
+//---------------------------
+// RemoveBackupMetadata()
+
+func (s *backupSuite) TestBackupsRemoveBackupMetadataSuccess(c *gc.C) {
+	id, err := state.AddBackupMetadata(s.State, s.metadata(c))
+	c.Assert(err, gc.IsNil)
+
+	err = state.RemoveBackupMetadata(s.State, id)
+	c.Check(err, gc.IsNil)
+
+	_, err = state.GetBackupMetadata(s.State, id)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *backupSuite) TestBackupsRemoveBackupMetadataNotFound(c *gc.C) {
+	err := state.RemoveBackupMetadata(s.State, "spam")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+//---------------------------
+// NewBackupMetadataStorage()
+
+func (s *backupSuite) TestBackupsMetadataStorage(c *gc.C) {
+	storage := state.NewBackupMetadataStorage(s.State)
+	expected := s.metadata(c)
+	id, err := storage.Add(expected)
+	c.Assert(err, gc.IsNil)
+
+	err = storage.SetStored(id)
+	c.Assert(err, gc.IsNil)
+	expected.Stored = true
+	metadata, err := storage.Get(id)
+	c.Assert(err, gc.IsNil)
+	s.checkMetadata(c, metadata, expected, id)
+
+	list, err := storage.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(list, gc.HasLen, 1)
+	s.checkMetadata(c, list[0], expected, id)
+
+	err = storage.Remove(id)
+	c.Assert(err, gc.IsNil)
+	_, err = storage.Get(id)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+//---------------------------
+// UpdateRestoredStateServer()
+
+func (s *backupSuite) TestBackupsUpdateRestoredStateServer(c *gc.C) {
+	var machines []*state.Machine
+	for i := 0; i < 2; i++ {
+		m, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+		c.Assert(err, gc.IsNil)
+		err = m.SetProvisioned(instance.Id(fmt.Sprintf("old-%d", i)), "fake_nonce", nil)
+		c.Assert(err, gc.IsNil)
+		machines = append(machines, m)
+	}
+	m := machines[1]
+
+	err := state.UpdateRestoredStateServer(s.State, m.Id(), "new-instance")
+	c.Assert(err, gc.IsNil)
+
+	err = m.Refresh()
+	c.Assert(err, gc.IsNil)
+	instId, err := m.InstanceId()
+	c.Assert(err, gc.IsNil)
+	c.Check(instId, gc.Equals, instance.Id("new-instance"))
+	info, err := s.State.StateServerInfo()
+	c.Assert(err, gc.IsNil)
+	c.Check(info.MachineIds, gc.DeepEquals, []string{m.Id()})
+	c.Check(info.VotingMachineIds, gc.DeepEquals, []string{m.Id()})
+}
+
+func (s *backupSuite) TestBackupsUpdateRestoredStateServerNotFound(c *gc.C) {
+	err := state.UpdateRestoredStateServer(s.State, "42", "new-instance")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -309,7 +309,7 @@ func (s *CleanupSuite) TestCleanupActions(c *gc.C) {
 	s.assertDoesNotNeedCleanup(c)
 
 	// Create a service with a unit.
-	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	mysql := s.AddTestingService(c, "mysql", s.AddActionsCharm(c, "mysql", testActionsYaml, 1))
 	unit, err := mysql.AddUnit()
 	c.Assert(err, gc.IsNil)
 
@@ -317,9 +317,9 @@ func (s *CleanupSuite) TestCleanupActions(c *gc.C) {
 	s.assertDoesNotNeedCleanup(c)
 
 	// Add a couple actions to the unit
-	_, err = unit.AddAction("action1", nil)
+	_, err = unit.AddAction("snapshot", nil)
 	c.Assert(err, gc.IsNil)
-	_, err = unit.AddAction("action2", nil)
+	_, err = unit.AddAction("backup", nil)
 	c.Assert(err, gc.IsNil)
 
 	// make sure unit still has actions
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net/url"
 	"path/filepath"
+	"time"
 
 	"github.com/juju/names"
 	jujutxn "github.com/juju/txn"
@@ -24,10 +25,12 @@ import (
 )
 
 var (
-	GetBackupMetadata   = getBackupMetadata
-	AddBackupMetadata   = addBackupMetadata
-	AddBackupMetadataID = addBackupMetadataID
-	SetBackupStored     = setBackupStored
+	GetBackupMetadata    = getBackupMetadata
+	AddBackupMetadata    = addBackupMetadata
+	AddBackupMetadataID  = addBackupMetadataID
+	SetBackupStored      = setBackupStored
+	ListBackupMetadata   = listBackupMetadata
+	RemoveBackupMetadata = removeBackupMetadata
 )
 
 func SetTestHooks(c *gc.C, st *State, hooks ...jujutxn.TestHook) txntesting.TransactionChecker {
@@ -152,6 +155,42 @@ func ClearInstanceDocId(c *gc.C, m *Machine) {
 	c.Assert(err, gc.IsNil)
 }
 
+// SCHEMACHANGE
+// RemoveWorkloadStatus removes the workload status document of the unit,
+// as for units added before workload status was recorded.
+func RemoveWorkloadStatus(c *gc.C, u *Unit) {
+	err := u.st.runTransaction([]txn.Op{removeWorkloadStatusOp(u.st, u.globalKey())})
+	c.Assert(err, gc.IsNil)
+}
+
+// SCHEMACHANGE
+// RemoveMetricBatchCreated removes the creation time of the metric
+// batch, as for batches recorded before it was stored.
+func RemoveMetricBatchCreated(c *gc.C, st *State, uuid string) {
+	ops := []txn.Op{{
+		C:      metricsC,
+		Id:     uuid,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$unset", bson.D{{"created", 1}}}},
+	}}
+	err := st.runTransaction(ops)
+	c.Assert(err, gc.IsNil)
+}
+
+// AgeStatusHistory makes all the statuses recorded in the status
+// history appear to have been set the given duration earlier.
+func AgeStatusHistory(c *gc.C, st *State, d time.Duration) {
+	history, closer := st.getCollection(statusHistoryC)
+	defer closer()
+	var docs []statusHistoryDoc
+	err := history.Find(nil).All(&docs)
+	c.Assert(err, gc.IsNil)
+	for _, doc := range docs {
+		err := history.UpdateId(doc.Id, bson.D{{"$set", bson.D{{"updated", doc.Updated.Add(-d)}}}})
+		c.Assert(err, gc.IsNil)
+	}
+}
+
 // SCHEMACHANGE
 // This method is used to reset the ownertag attribute
 func SetServiceOwnerTag(s *Service, ownerTag string) {
@@ -183,6 +222,7 @@ func GetPasswordHash(e Authenticator) string {
 
 func init() {
 	logSize = logSizeTests
+	agentLogsSize = agentLogsSizeTests
 }
 
 // TxnRevno returns the txn-revno field of the document
@@ -254,3 +294,7 @@ var (
 	GetPorts         = getPorts
 	NowToTheSecond   = nowToTheSecond
 )
+
+var LogTailTimeout = &logTailTimeout
+
+var NewLogId = &newLogId
@@ -0,0 +1,152 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// LoginAttempts records the failed password logins made as a user from
+// an address, or from an address as any user.
+type LoginAttempts struct {
+	// Failures is the number of failed logins since the attempts
+	// were last cleared or locked out.
+	Failures int
+
+	// LastFailure is when the most recent login failed.
+	LastFailure time.Time
+
+	// LockedUntil is when a lockout ends, if logins are locked out.
+	LockedUntil time.Time
+}
+
+// UserLoginAttemptsKey returns the key under which the failed logins
+// made as the named user from the given address are recorded. They
+// are kept apart from those made from other addresses, so that failed
+// logins from one address cannot lock the user out everywhere.
+func UserLoginAttemptsKey(name, addr string) string {
+	return userLoginAttemptsPrefix(name) + addr
+}
+
+func userLoginAttemptsPrefix(name string) string {
+	return "user#" + name + "#"
+}
+
+// AddressLoginAttemptsKey returns the key under which the failed
+// logins made from the given address are recorded.
+func AddressLoginAttemptsKey(addr string) string {
+	return "addr#" + addr
+}
+
+type loginAttemptsDoc struct {
+	Id          string    `bson:"_id"`
+	Failures    int       `bson:"failures"`
+	LastFailure time.Time `bson:"lastfailure"`
+	LockedUntil time.Time `bson:"lockeduntil"`
+
+	// Expires holds when the document is removed by the TTL index
+	// on the collection, once the failures and any lockout it
+	// records no longer matter.
+	Expires time.Time `bson:"expires"`
+}
+
+func (doc *loginAttemptsDoc) attempts() LoginAttempts {
+	return LoginAttempts{
+		Failures:    doc.Failures,
+		LastFailure: doc.LastFailure.UTC(),
+		LockedUntil: doc.LockedUntil.UTC(),
+	}
+}
+
+// LoginAttempts returns the failed logins recorded under the given
+// key. No failures are returned if none have been recorded.
+func (st *State) LoginAttempts(key string) (LoginAttempts, error) {
+	attempts, closer := st.getCollection(loginAttemptsC)
+	defer closer()
+
+	var doc loginAttemptsDoc
+	err := attempts.FindId(key).One(&doc)
+	if err == mgo.ErrNotFound {
+		return LoginAttempts{}, nil
+	}
+	if err != nil {
+		return LoginAttempts{}, errors.Annotatef(err, "cannot get login attempts for %q", key)
+	}
+	return doc.attempts(), nil
+}
+
+// RecordLoginFailure records a failed login under the given key, and
+// returns the failed logins recorded so far. The record is forgotten
+// at the expires time, unless more failures are recorded. Login
+// attempts are not written in transactions, so that concurrent
+// failures are all counted.
+func (st *State) RecordLoginFailure(key string, when, expires time.Time) (LoginAttempts, error) {
+	attempts, closer := st.getCollection(loginAttemptsC)
+	defer closer()
+
+	change := mgo.Change{
+		Update: bson.D{
+			{"$inc", bson.D{{"failures", 1}}},
+			{"$set", bson.D{
+				{"lastfailure", when.UTC()},
+				{"expires", expires.UTC()},
+			}},
+		},
+		Upsert:    true,
+		ReturnNew: true,
+	}
+	var doc loginAttemptsDoc
+	if _, err := attempts.FindId(key).Apply(change, &doc); err != nil {
+		return LoginAttempts{}, errors.Annotatef(err, "cannot record login failure for %q", key)
+	}
+	return doc.attempts(), nil
+}
+
+// LockOutLogins refuses logins under the given key until the given
+// time. The failures recorded so far are forgotten.
+func (st *State) LockOutLogins(key string, until time.Time) error {
+	attempts, closer := st.getCollection(loginAttemptsC)
+	defer closer()
+
+	update := bson.D{{"$set", bson.D{
+		{"failures", 0},
+		{"lockeduntil", until.UTC()},
+		{"expires", until.UTC()},
+	}}}
+	if _, err := attempts.UpsertId(key, update); err != nil {
+		return errors.Annotatef(err, "cannot lock out logins for %q", key)
+	}
+	return nil
+}
+
+// ClearLoginAttempts forgets the failed logins and any lockout
+// recorded under the given key.
+func (st *State) ClearLoginAttempts(key string) error {
+	attempts, closer := st.getCollection(loginAttemptsC)
+	defer closer()
+
+	err := attempts.RemoveId(key)
+	if err != nil && err != mgo.ErrNotFound {
+		return errors.Annotatef(err, "cannot clear login attempts for %q", key)
+	}
+	return nil
+}
+
+// ClearUserLoginAttempts forgets the failed logins and any lockouts
+// recorded for the named user, from all addresses.
+func (st *State) ClearUserLoginAttempts(name string) error {
+	attempts, closer := st.getCollection(loginAttemptsC)
+	defer closer()
+
+	sel := bson.D{{"_id", bson.RegEx{Pattern: "^" + regexp.QuoteMeta(userLoginAttemptsPrefix(name))}}}
+	if _, err := attempts.RemoveAll(sel); err != nil {
+		return errors.Annotatef(err, "cannot clear login attempts for user %q", name)
+	}
+	return nil
+}
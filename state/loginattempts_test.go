@@ -0,0 +1,103 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+)
+
+type LoginAttemptsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&LoginAttemptsSuite{})
+
+// loginTime is recent, so that the records are not expired by mongo
+// during the tests.
+var loginTime = time.Now().Truncate(time.Second).UTC()
+
+func (s *LoginAttemptsSuite) TestNoAttempts(c *gc.C) {
+	attempts, err := s.State.LoginAttempts(state.UserLoginAttemptsKey("bob", "10.0.0.1"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(attempts, gc.Equals, state.LoginAttempts{})
+}
+
+func (s *LoginAttemptsSuite) TestRecordLoginFailure(c *gc.C) {
+	key := state.UserLoginAttemptsKey("bob", "10.0.0.1")
+	attempts, err := s.State.RecordLoginFailure(key, loginTime, loginTime.Add(time.Hour))
+	c.Assert(err, gc.IsNil)
+	c.Assert(attempts, gc.Equals, state.LoginAttempts{
+		Failures:    1,
+		LastFailure: loginTime,
+	})
+	attempts, err = s.State.RecordLoginFailure(key, loginTime.Add(time.Second), loginTime.Add(time.Hour))
+	c.Assert(err, gc.IsNil)
+	c.Assert(attempts.Failures, gc.Equals, 2)
+	c.Assert(attempts.LastFailure, gc.Equals, loginTime.Add(time.Second))
+
+	stored, err := s.State.LoginAttempts(key)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored, gc.Equals, attempts)
+
+	// Failures are recorded separately for each key.
+	attempts, err = s.State.LoginAttempts(state.AddressLoginAttemptsKey("10.0.0.1"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(attempts.Failures, gc.Equals, 0)
+}
+
+func (s *LoginAttemptsSuite) TestLockOutLogins(c *gc.C) {
+	key := state.AddressLoginAttemptsKey("10.0.0.1")
+	_, err := s.State.RecordLoginFailure(key, loginTime, loginTime.Add(time.Hour))
+	c.Assert(err, gc.IsNil)
+	err = s.State.LockOutLogins(key, loginTime.Add(time.Hour))
+	c.Assert(err, gc.IsNil)
+
+	attempts, err := s.State.LoginAttempts(key)
+	c.Assert(err, gc.IsNil)
+	c.Assert(attempts, gc.Equals, state.LoginAttempts{
+		LastFailure: loginTime,
+		LockedUntil: loginTime.Add(time.Hour),
+	})
+}
+
+func (s *LoginAttemptsSuite) TestClearLoginAttempts(c *gc.C) {
+	key := state.UserLoginAttemptsKey("bob", "10.0.0.1")
+	err := s.State.ClearLoginAttempts(key)
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.State.RecordLoginFailure(key, loginTime, loginTime.Add(time.Hour))
+	c.Assert(err, gc.IsNil)
+	err = s.State.LockOutLogins(key, loginTime.Add(time.Hour))
+	c.Assert(err, gc.IsNil)
+	err = s.State.ClearLoginAttempts(key)
+	c.Assert(err, gc.IsNil)
+
+	attempts, err := s.State.LoginAttempts(key)
+	c.Assert(err, gc.IsNil)
+	c.Assert(attempts, gc.Equals, state.LoginAttempts{})
+}
+
+func (s *LoginAttemptsSuite) TestClearUserLoginAttempts(c *gc.C) {
+	keys := []string{
+		state.UserLoginAttemptsKey("bob", "10.0.0.1"),
+		state.UserLoginAttemptsKey("bob", "10.0.0.2"),
+		state.UserLoginAttemptsKey("bobby", "10.0.0.1"),
+	}
+	for _, key := range keys {
+		err := s.State.LockOutLogins(key, loginTime.Add(time.Hour))
+		c.Assert(err, gc.IsNil)
+	}
+	err := s.State.ClearUserLoginAttempts("bob")
+	c.Assert(err, gc.IsNil)
+
+	for i, key := range keys {
+		attempts, err := s.State.LoginAttempts(key)
+		c.Assert(err, gc.IsNil)
+		c.Assert(attempts.LockedUntil.IsZero(), gc.Equals, i < 2, gc.Commentf("key %q", key))
+	}
+}
@@ -0,0 +1,414 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"launchpad.net/tomb"
+)
+
+// LogRecord holds a log message written by an agent.
+type LogRecord struct {
+	Time     time.Time
+	Entity   string
+	Module   string
+	Location string
+	Level    loggo.Level
+	Message  string
+}
+
+// logDoc is the representation of a LogRecord in the logs
+// collection. The field names are kept short because the collection
+// is capped, so its size bounds the bytes stored, not the records.
+type logDoc struct {
+	Id       bson.ObjectId `bson:"_id"`
+	Time     time.Time     `bson:"t"`
+	Entity   string        `bson:"e"`
+	Module   string        `bson:"m"`
+	Location string        `bson:"x"`
+	Level    loggo.Level   `bson:"l"`
+	Message  string        `bson:"msg"`
+}
+
+// newLogId returns the id of a new log record. The ids follow the
+// clock of the state server adding the record, so they are not
+// ordered across state servers.
+var newLogId = bson.NewObjectId
+
+// AddLogs records the given log records. The logs collection is
+// capped, so the oldest records are discarded as new ones are added.
+// Log records are not written in transactions: they are never
+// updated, and losing some is preferable to slowing down the agents.
+func (st *State) AddLogs(records []LogRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	logs, closer := st.getCollection(logsC)
+	defer closer()
+
+	docs := make([]interface{}, len(records))
+	for i, record := range records {
+		if record.Entity == "" {
+			return errors.New("cannot add log record without an entity")
+		}
+		t := record.Time
+		if t.IsZero() {
+			t = time.Now()
+		}
+		docs[i] = &logDoc{
+			Id:       newLogId(),
+			Time:     t.UTC(),
+			Entity:   record.Entity,
+			Module:   record.Module,
+			Location: record.Location,
+			Level:    record.Level,
+			Message:  record.Message,
+		}
+	}
+	if err := logs.Insert(docs...); err != nil {
+		return errors.Annotate(err, "cannot add log records")
+	}
+	return nil
+}
+
+// LogTailerParams selects the log records sent by a LogTailer.
+type LogTailerParams struct {
+	// MinLevel excludes the records logged below this level.
+	MinLevel loggo.Level
+
+	// IncludeEntity and ExcludeEntity hold entity tags. A tag ending
+	// with '*' matches all the entities with that prefix. If
+	// IncludeEntity is empty, all entities are included.
+	IncludeEntity []string
+	ExcludeEntity []string
+
+	// IncludeModule and ExcludeModule hold logging module names,
+	// which match the modules with that prefix. If IncludeModule is
+	// empty, all modules are included.
+	IncludeModule []string
+	ExcludeModule []string
+
+	// Since and Until exclude the records logged before and after
+	// the given times, if they are not zero. When Since is set, all
+	// the existing records logged since then are sent. When Until is
+	// set, the tailer stops once a record logged after it is added.
+	Since time.Time
+	Until time.Time
+
+	// MessageRegex, if not empty, excludes the records whose message
+	// does not match the regular expression.
+	MessageRegex string
+
+	// InitialLines is the number of existing records sent before
+	// following the new ones. It is ignored if FromTheStart or Since
+	// is set.
+	InitialLines int
+
+	// FromTheStart sends all the existing records before following
+	// the new ones.
+	FromTheStart bool
+
+	// NoTail stops the tailer once the existing records are sent,
+	// instead of following the new ones.
+	NoTail bool
+}
+
+// LogTailer sends the log records selected by its parameters as they
+// are added.
+type LogTailer interface {
+	// Logs returns the channel on which the records are sent. It is
+	// closed when the tailer stops.
+	Logs() <-chan *LogRecord
+
+	// Dying returns a channel that is closed when the tailer starts
+	// to stop.
+	Dying() <-chan struct{}
+
+	// Stop stops the tailer and returns any error it encountered.
+	Stop() error
+
+	// Err returns the error that stopped the tailer, if any.
+	Err() error
+}
+
+// logTailTimeout is how long the tailer waits for new records before
+// checking whether it has been stopped.
+var logTailTimeout = time.Second
+
+// NewLogTailer returns a LogTailer that follows the logs collection
+// of st. The records added after NewLogTailer returns are always sent.
+// The tailer uses its own session, so it does not hold up other users
+// of st.
+func (st *State) NewLogTailer(params *LogTailerParams) (LogTailer, error) {
+	session := st.db.Session.Copy()
+	t := &logTailer{
+		logs:   st.db.C(logsC).With(session),
+		params: params,
+		out:    make(chan *LogRecord),
+	}
+	from, err := t.initialPosition()
+	if err != nil {
+		session.Close()
+		return nil, errors.Annotate(err, "cannot get initial log records")
+	}
+	go func() {
+		defer t.tomb.Done()
+		defer close(t.out)
+		defer session.Close()
+		t.tomb.Kill(t.loop(from))
+	}()
+	return t, nil
+}
+
+// logPosition identifies the record in the logs collection from which
+// the tailer sends records, in $natural order. The position is kept by
+// record rather than by id or time, because the records are added by
+// all the state servers, whose clocks may disagree.
+type logPosition struct {
+	// Id holds the id of the record, or is empty to send all the
+	// records from the start of the collection.
+	Id bson.ObjectId
+
+	// Inclusive holds whether the record itself is sent.
+	Inclusive bool
+}
+
+type logTailer struct {
+	tomb   tomb.Tomb
+	logs   *mgo.Collection
+	params *LogTailerParams
+	out    chan *LogRecord
+}
+
+// Logs implements LogTailer.
+func (t *logTailer) Logs() <-chan *LogRecord {
+	return t.out
+}
+
+// Dying implements LogTailer.
+func (t *logTailer) Dying() <-chan struct{} {
+	return t.tomb.Dying()
+}
+
+// Stop implements LogTailer.
+func (t *logTailer) Stop() error {
+	t.tomb.Kill(nil)
+	return t.tomb.Wait()
+}
+
+// Err implements LogTailer.
+func (t *logTailer) Err() error {
+	return t.tomb.Err()
+}
+
+// initialPosition returns the position of the first record to send,
+// given the existing records requested by the tailer's parameters.
+func (t *logTailer) initialPosition() (logPosition, error) {
+	if t.params.FromTheStart || !t.params.Since.IsZero() {
+		return logPosition{}, nil
+	}
+	var doc logDoc
+	if t.params.InitialLines > 0 {
+		// Start from the oldest of the last InitialLines records.
+		err := t.logs.Find(t.query()).Sort("-$natural").Skip(t.params.InitialLines - 1).Select(bson.D{{"_id", 1}}).One(&doc)
+		if err == mgo.ErrNotFound {
+			// There are fewer records, so all are sent.
+			return logPosition{}, nil
+		}
+		return logPosition{Id: doc.Id, Inclusive: true}, err
+	}
+	err := t.logs.Find(nil).Sort("-$natural").Select(bson.D{{"_id", 1}}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return logPosition{}, nil
+	}
+	return logPosition{Id: doc.Id}, err
+}
+
+func (t *logTailer) loop(from logPosition) error {
+	for {
+		var err error
+		from, err = t.sendFrom(from)
+		if err != nil || t.params.NoTail {
+			return err
+		}
+		if done, err := t.pastUntil(); err != nil || done {
+			return err
+		}
+		// A tailable cursor dies when it has no records to start
+		// from, or when the records it points to are overwritten,
+		// so it is recreated from the last record sent.
+		select {
+		case <-t.tomb.Dying():
+			return tomb.ErrDying
+		case <-time.After(logTailTimeout):
+		}
+	}
+}
+
+// sendFrom sends the records from the given position until there are
+// no more, or, when tailing, until the cursor dies. It returns the
+// position following the last record sent.
+func (t *logTailer) sendFrom(from logPosition) (logPosition, error) {
+	query := t.query()
+	skipping := false
+	if from.Id != "" {
+		n, err := t.logs.FindId(from.Id).Count()
+		if err != nil {
+			return from, errors.Annotate(err, "cannot tail logs")
+		}
+		// If the record has been overwritten, all the records left
+		// follow it.
+		if skipping = n > 0; skipping {
+			query = bson.M{"$or": []bson.M{{"_id": from.Id}, query}}
+		}
+	}
+	q := t.logs.Find(query).Sort("$natural")
+	var iter *mgo.Iter
+	if t.params.NoTail {
+		iter = q.Iter()
+	} else {
+		iter = q.Tail(logTailTimeout)
+	}
+	var doc logDoc
+	for {
+		if iter.Next(&doc) {
+			if skipping {
+				// Skip the records up to the position.
+				if doc.Id != from.Id {
+					continue
+				}
+				skipping = false
+				if !from.Inclusive {
+					continue
+				}
+			}
+			if err := t.send(&doc); err != nil {
+				iter.Close()
+				return from, err
+			}
+			from = logPosition{Id: doc.Id}
+			continue
+		}
+		if iter.Err() != nil || !iter.Timeout() {
+			break
+		}
+		if done, err := t.pastUntil(); err != nil || done {
+			break
+		}
+		select {
+		case <-t.tomb.Dying():
+			iter.Close()
+			return from, tomb.ErrDying
+		default:
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return from, errors.Annotate(err, "cannot tail logs")
+	}
+	return from, nil
+}
+
+// pastUntil returns whether the newest record was logged after the
+// tailer's Until time, after which no more records are sent.
+func (t *logTailer) pastUntil() (bool, error) {
+	if t.params.Until.IsZero() {
+		return false, nil
+	}
+	var doc logDoc
+	err := t.logs.Find(nil).Sort("-$natural").Select(bson.D{{"t", 1}}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Annotate(err, "cannot get last log record")
+	}
+	return doc.Time.After(t.params.Until), nil
+}
+
+func (t *logTailer) send(doc *logDoc) error {
+	record := &LogRecord{
+		Time:     doc.Time.UTC(),
+		Entity:   doc.Entity,
+		Module:   doc.Module,
+		Location: doc.Location,
+		Level:    doc.Level,
+		Message:  doc.Message,
+	}
+	select {
+	case <-t.tomb.Dying():
+		return tomb.ErrDying
+	case t.out <- record:
+	}
+	return nil
+}
+
+// query returns the selector matching the records allowed by the
+// tailer's parameters.
+func (t *logTailer) query() bson.M {
+	query := bson.M{}
+	if t.params.MinLevel > loggo.UNSPECIFIED {
+		query["l"] = bson.D{{"$gte", t.params.MinLevel}}
+	}
+	if entity := matchAny(t.params.IncludeEntity, t.params.ExcludeEntity, entityPattern); entity != nil {
+		query["e"] = entity
+	}
+	if module := matchAny(t.params.IncludeModule, t.params.ExcludeModule, modulePattern); module != nil {
+		query["m"] = module
+	}
+	var times bson.D
+	if !t.params.Since.IsZero() {
+		times = append(times, bson.DocElem{"$gte", t.params.Since.UTC()})
+	}
+	if !t.params.Until.IsZero() {
+		times = append(times, bson.DocElem{"$lte", t.params.Until.UTC()})
+	}
+	if times != nil {
+		query["t"] = times
+	}
+	if t.params.MessageRegex != "" {
+		query["msg"] = bson.RegEx{Pattern: t.params.MessageRegex}
+	}
+	return query
+}
+
+// matchAny returns the condition matching the values matched by one
+// of include, if any are given, and none of exclude, or nil if there
+// are no values.
+func matchAny(include, exclude []string, pattern func(string) interface{}) bson.D {
+	var cond bson.D
+	patterns := func(values []string) []interface{} {
+		result := make([]interface{}, len(values))
+		for i, value := range values {
+			result[i] = pattern(value)
+		}
+		return result
+	}
+	if len(include) > 0 {
+		cond = append(cond, bson.DocElem{"$in", patterns(include)})
+	}
+	if len(exclude) > 0 {
+		cond = append(cond, bson.DocElem{"$nin", patterns(exclude)})
+	}
+	return cond
+}
+
+// entityPattern matches an entity tag, or all the tags with the given
+// prefix when it ends with '*'.
+func entityPattern(value string) interface{} {
+	if strings.HasSuffix(value, "*") {
+		return bson.RegEx{Pattern: "^" + regexp.QuoteMeta(value[:len(value)-1])}
+	}
+	return value
+}
+
+// modulePattern matches all the modules with the given prefix.
+func modulePattern(value string) interface{} {
+	return bson.RegEx{Pattern: "^" + regexp.QuoteMeta(value)}
+}
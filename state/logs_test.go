@@ -0,0 +1,254 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/loggo"
+	"gopkg.in/mgo.v2/bson"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type LogsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&LogsSuite{})
+
+func (s *LogsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.PatchValue(state.LogTailTimeout, 50*time.Millisecond)
+}
+
+var logTime = time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func logRecord(entity, module string, level loggo.Level, message string) state.LogRecord {
+	return state.LogRecord{
+		Time:     logTime,
+		Entity:   entity,
+		Module:   module,
+		Location: "file.go:42",
+		Level:    level,
+		Message:  message,
+	}
+}
+
+func (s *LogsSuite) addLogs(c *gc.C, records ...state.LogRecord) {
+	err := s.State.AddLogs(records)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *LogsSuite) startTailer(c *gc.C, params *state.LogTailerParams) state.LogTailer {
+	tailer, err := s.State.NewLogTailer(params)
+	c.Assert(err, gc.IsNil)
+	s.AddCleanup(func(c *gc.C) {
+		c.Check(tailer.Stop(), gc.IsNil)
+	})
+	return tailer
+}
+
+func (s *LogsSuite) assertMessages(c *gc.C, tailer state.LogTailer, expect ...string) {
+	timeout := time.After(testing.LongWait)
+	for _, message := range expect {
+		select {
+		case record, ok := <-tailer.Logs():
+			c.Assert(ok, gc.Equals, true)
+			c.Assert(record.Message, gc.Equals, message)
+		case <-timeout:
+			c.Fatalf("timed out waiting for %q", message)
+		}
+	}
+	select {
+	case record := <-tailer.Logs():
+		c.Fatalf("unexpected log record %#v", record)
+	case <-time.After(testing.ShortWait):
+	}
+}
+
+func (s *LogsSuite) TestAddLogs(c *gc.C) {
+	s.addLogs(c, logRecord("machine-0", "juju.worker", loggo.INFO, "hello"))
+	tailer := s.startTailer(c, &state.LogTailerParams{FromTheStart: true})
+	select {
+	case record := <-tailer.Logs():
+		c.Assert(*record, gc.DeepEquals, logRecord("machine-0", "juju.worker", loggo.INFO, "hello"))
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for log record")
+	}
+}
+
+func (s *LogsSuite) TestAddLogsWithoutEntity(c *gc.C) {
+	err := s.State.AddLogs([]state.LogRecord{logRecord("", "juju", loggo.INFO, "hello")})
+	c.Assert(err, gc.ErrorMatches, "cannot add log record without an entity")
+}
+
+func (s *LogsSuite) TestTailerFollowsNewRecords(c *gc.C) {
+	s.addLogs(c, logRecord("machine-0", "juju", loggo.INFO, "old"))
+	tailer := s.startTailer(c, &state.LogTailerParams{})
+	s.assertMessages(c, tailer)
+
+	s.addLogs(c,
+		logRecord("machine-0", "juju", loggo.INFO, "new 1"),
+		logRecord("machine-0", "juju", loggo.INFO, "new 2"),
+	)
+	s.assertMessages(c, tailer, "new 1", "new 2")
+}
+
+func (s *LogsSuite) TestTailerFollowsRecordsFromSkewedClocks(c *gc.C) {
+	tailer := s.startTailer(c, &state.LogTailerParams{})
+	s.addLogs(c, logRecord("machine-0", "juju", loggo.INFO, "first"))
+	s.assertMessages(c, tailer, "first")
+
+	// Another state server, whose clock is behind, adds a record.
+	s.PatchValue(state.NewLogId, func() bson.ObjectId {
+		return bson.NewObjectIdWithTime(time.Now().Add(-time.Hour))
+	})
+	s.addLogs(c, logRecord("machine-1", "juju", loggo.INFO, "second"))
+	s.assertMessages(c, tailer, "second")
+}
+
+func (s *LogsSuite) TestTailerEmptyCollection(c *gc.C) {
+	tailer := s.startTailer(c, &state.LogTailerParams{FromTheStart: true})
+	s.assertMessages(c, tailer)
+
+	s.addLogs(c, logRecord("machine-0", "juju", loggo.INFO, "first"))
+	s.assertMessages(c, tailer, "first")
+	s.addLogs(c, logRecord("machine-0", "juju", loggo.INFO, "second"))
+	s.assertMessages(c, tailer, "second")
+}
+
+func (s *LogsSuite) TestTailerInitialLines(c *gc.C) {
+	for i := 0; i < 5; i++ {
+		s.addLogs(c, logRecord("machine-0", "juju", loggo.INFO, fmt.Sprint(i)))
+	}
+	tailer := s.startTailer(c, &state.LogTailerParams{InitialLines: 2})
+	s.assertMessages(c, tailer, "3", "4")
+
+	s.addLogs(c, logRecord("machine-0", "juju", loggo.INFO, "5"))
+	s.assertMessages(c, tailer, "5")
+}
+
+func (s *LogsSuite) TestTailerFromTheStart(c *gc.C) {
+	for i := 0; i < 3; i++ {
+		s.addLogs(c, logRecord("machine-0", "juju", loggo.INFO, fmt.Sprint(i)))
+	}
+	tailer := s.startTailer(c, &state.LogTailerParams{FromTheStart: true, InitialLines: 1})
+	s.assertMessages(c, tailer, "0", "1", "2")
+}
+
+func (s *LogsSuite) TestTailerFilters(c *gc.C) {
+	tailer := s.startTailer(c, &state.LogTailerParams{
+		MinLevel:      loggo.INFO,
+		IncludeEntity: []string{"machine-0", "unit-mysql*"},
+		ExcludeEntity: []string{"unit-mysql-2"},
+		IncludeModule: []string{"juju"},
+		ExcludeModule: []string{"juju.foo"},
+	})
+	s.assertMessages(c, tailer)
+	s.addLogs(c,
+		logRecord("machine-0", "juju", loggo.WARNING, "machine-0"),
+		logRecord("machine-1", "juju", loggo.WARNING, "machine-1"),
+		logRecord("machine-0-lxc-0", "juju", loggo.WARNING, "machine-0-lxc-0"),
+		logRecord("unit-mysql-0", "juju", loggo.WARNING, "unit-mysql-0"),
+		logRecord("unit-mysql-2", "juju", loggo.WARNING, "unit-mysql-2"),
+		logRecord("unit-wordpress-0", "juju", loggo.WARNING, "unit-wordpress-0"),
+		logRecord("machine-0", "juju", loggo.DEBUG, "debug"),
+		logRecord("machine-0", "juju.foo.bar", loggo.WARNING, "juju.foo.bar"),
+		logRecord("machine-0", "juju.worker", loggo.ERROR, "juju.worker"),
+		logRecord("machine-0", "unit.mysql/0", loggo.ERROR, "unit.mysql/0"),
+	)
+	s.assertMessages(c, tailer, "machine-0", "unit-mysql-0", "juju.worker")
+}
+
+func (s *LogsSuite) TestTailerStop(c *gc.C) {
+	tailer, err := s.State.NewLogTailer(&state.LogTailerParams{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(tailer.Stop(), gc.IsNil)
+	select {
+	case _, ok := <-tailer.Logs():
+		c.Assert(ok, gc.Equals, false)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("log channel not closed")
+	}
+}
+
+func (s *LogsSuite) TestTailerTimeRange(c *gc.C) {
+	for i := 0; i < 5; i++ {
+		record := logRecord("machine-0", "juju", loggo.INFO, fmt.Sprint(i))
+		record.Time = logTime.Add(time.Duration(i) * time.Minute)
+		s.addLogs(c, record)
+	}
+	tailer := s.startTailer(c, &state.LogTailerParams{
+		Since: logTime.Add(time.Minute),
+		Until: logTime.Add(3 * time.Minute),
+	})
+	s.assertMessages(c, tailer, "1", "2", "3")
+
+	record := logRecord("machine-0", "juju", loggo.INFO, "late")
+	record.Time = logTime.Add(2 * time.Minute)
+	s.addLogs(c, record, logRecord("machine-0", "juju", loggo.INFO, "now"))
+	s.assertMessages(c, tailer, "late")
+}
+
+func (s *LogsSuite) TestTailerMessageRegex(c *gc.C) {
+	s.addLogs(c,
+		logRecord("machine-0", "juju", loggo.INFO, "worker: start \"api\""),
+		logRecord("machine-0", "juju", loggo.INFO, "connection established"),
+		logRecord("machine-0", "juju", loggo.INFO, "worker: exited \"api\""),
+	)
+	tailer := s.startTailer(c, &state.LogTailerParams{
+		FromTheStart: true,
+		MessageRegex: `^worker: (start|stop) `,
+	})
+	s.assertMessages(c, tailer, "worker: start \"api\"")
+}
+
+func (s *LogsSuite) TestTailerStopsAfterUntil(c *gc.C) {
+	s.addLogs(c,
+		logRecord("machine-0", "juju", loggo.INFO, "before"),
+	)
+	later := logRecord("machine-0", "juju", loggo.INFO, "after")
+	later.Time = logTime.Add(time.Hour)
+	s.addLogs(c, later)
+
+	tailer, err := s.State.NewLogTailer(&state.LogTailerParams{
+		FromTheStart: true,
+		Until:        logTime.Add(time.Minute),
+	})
+	c.Assert(err, gc.IsNil)
+	var messages []string
+	timeout := time.After(testing.LongWait)
+	for done := false; !done; {
+		select {
+		case record, ok := <-tailer.Logs():
+			if !ok {
+				done = true
+				break
+			}
+			messages = append(messages, record.Message)
+		case <-timeout:
+			c.Fatalf("tailer did not stop")
+		}
+	}
+	c.Assert(messages, gc.DeepEquals, []string{"before"})
+	c.Assert(tailer.Err(), gc.IsNil)
+}
+
+func (s *LogsSuite) TestTailerNoTail(c *gc.C) {
+	for i := 0; i < 3; i++ {
+		s.addLogs(c, logRecord("machine-0", "juju", loggo.INFO, fmt.Sprint(i)))
+	}
+	tailer, err := s.State.NewLogTailer(&state.LogTailerParams{InitialLines: 2, NoTail: true})
+	c.Assert(err, gc.IsNil)
+	var messages []string
+	for record := range tailer.Logs() {
+		messages = append(messages, record.Message)
+	}
+	c.Assert(messages, gc.DeepEquals, []string{"1", "2"})
+	c.Assert(tailer.Err(), gc.IsNil)
+}
@@ -1263,6 +1263,7 @@ func (m *Machine) SetStatus(status params.Status, info string, data params.Statu
 		Assert: notDeadDoc,
 	},
 		updateStatusOp(m.st, m.globalKey(), doc),
+		addStatusHistoryOp(m.st, m.globalKey(), doc),
 	}
 	if err := m.st.runTransaction(ops); err != nil {
 		return fmt.Errorf("cannot set status of machine %q: %v", m, onAbort(err, errNotAlive))
@@ -1270,6 +1271,12 @@ func (m *Machine) SetStatus(status params.Status, info string, data params.Statu
 	return nil
 }
 
+// StatusHistory returns at most size statuses recently set for the
+// machine, most recent first.
+func (m *Machine) StatusHistory(size int) ([]StatusInfo, error) {
+	return statusHistory(m.st, m.globalKey(), size)
+}
+
 // Clean returns true if the machine does not have any deployed units or containers.
 func (m *Machine) Clean() bool {
 	return m.doc.Clean
@@ -106,11 +106,21 @@ func (u *backingUnit) updated(st *State, store *multiwatcher.Store, id interface
 		}
 		info.Status = sdoc.Status
 		info.StatusInfo = sdoc.StatusInfo
+		wdoc, err := getWorkloadStatus(st, unitGlobalKey(u.Name))
+		if errors.IsNotFound(err) {
+			wdoc.Status = params.WorkloadUnknown
+		} else if err != nil {
+			return err
+		}
+		info.WorkloadStatus = wdoc.Status
+		info.WorkloadStatusInfo = wdoc.StatusInfo
 	} else {
 		// The entry already exists, so preserve the current status.
 		oldInfo := oldInfo.(*params.UnitInfo)
 		info.Status = oldInfo.Status
 		info.StatusInfo = oldInfo.StatusInfo
+		info.WorkloadStatus = oldInfo.WorkloadStatus
+		info.WorkloadStatusInfo = oldInfo.WorkloadStatusInfo
 	}
 	publicAddress, privateAddress, err := getUnitAddresses(st, u.Name)
 	if err != nil {
@@ -315,6 +325,40 @@ func (a *backingStatus) mongoId() interface{} {
 	panic("cannot find mongo id from status document")
 }
 
+type backingWorkloadStatus workloadStatusDoc
+
+func (s *backingWorkloadStatus) updated(st *State, store *multiwatcher.Store, id interface{}) error {
+	parentId, ok := backingEntityIdForGlobalKey(id.(string))
+	if !ok {
+		return nil
+	}
+	info0 := store.Get(parentId)
+	switch info := info0.(type) {
+	case nil:
+		// The parent info doesn't exist. Ignore the status until it does.
+		return nil
+	case *params.UnitInfo:
+		newInfo := *info
+		newInfo.WorkloadStatus = s.Status
+		newInfo.WorkloadStatusInfo = s.StatusInfo
+		info0 = &newInfo
+	default:
+		panic(fmt.Errorf("workload status for unexpected entity with id %q; type %T", id, info))
+	}
+	store.Update(info0)
+	return nil
+}
+
+func (s *backingWorkloadStatus) removed(st *State, store *multiwatcher.Store, id interface{}) error {
+	// If the status is removed, the parent will follow not long after,
+	// so do nothing.
+	return nil
+}
+
+func (a *backingWorkloadStatus) mongoId() interface{} {
+	panic("cannot find mongo id from workload status document")
+}
+
 type backingConstraints constraintsDoc
 
 func (s *backingConstraints) updated(st *State, store *multiwatcher.Store, id interface{}) error {
@@ -495,6 +539,10 @@ func newAllWatcherStateBacking(st *State) multiwatcher.Backing {
 		Collection: st.db.C(statusesC),
 		infoType:   reflect.TypeOf(backingStatus{}),
 		subsidiary: true,
+	}, {
+		Collection: st.db.C(workloadStatusesC),
+		infoType:   reflect.TypeOf(backingWorkloadStatus{}),
+		subsidiary: true,
 	}, {
 		Collection: st.db.C(constraintsC),
 		infoType:   reflect.TypeOf(backingConstraints{}),
@@ -154,12 +154,13 @@ func (s *storeManagerStateSuite) setUpScenario(c *gc.C) (entities entityInfoSlic
 		c.Assert(m.Tag().String(), gc.Equals, fmt.Sprintf("machine-%d", i+1))
 
 		add(&params.UnitInfo{
-			Name:      fmt.Sprintf("wordpress/%d", i),
-			Service:   wordpress.Name(),
-			Series:    m.Series(),
-			MachineId: m.Id(),
-			Ports:     []network.Port{},
-			Status:    params.StatusPending,
+			Name:           fmt.Sprintf("wordpress/%d", i),
+			Service:        wordpress.Name(),
+			Series:         m.Series(),
+			MachineId:      m.Id(),
+			Ports:          []network.Port{},
+			Status:         params.StatusPending,
+			WorkloadStatus: params.WorkloadUnknown,
 		})
 		pairs := map[string]string{"name": fmt.Sprintf("bar %d", i)}
 		err = wu.SetAnnotations(pairs)
@@ -208,11 +209,12 @@ func (s *storeManagerStateSuite) setUpScenario(c *gc.C) (entities entityInfoSlic
 		c.Assert(ok, gc.Equals, true)
 		c.Assert(deployer, gc.Equals, names.NewUnitTag(fmt.Sprintf("wordpress/%d", i)))
 		add(&params.UnitInfo{
-			Name:    fmt.Sprintf("logging/%d", i),
-			Service: "logging",
-			Series:  "quantal",
-			Ports:   []network.Port{},
-			Status:  params.StatusPending,
+			Name:           fmt.Sprintf("logging/%d", i),
+			Service:        "logging",
+			Series:         "quantal",
+			Ports:          []network.Port{},
+			Status:         params.StatusPending,
+			WorkloadStatus: params.WorkloadUnknown,
 		})
 	}
 	return
@@ -379,13 +381,14 @@ var allWatcherChangedTests = []struct {
 		},
 		expectContents: []params.EntityInfo{
 			&params.UnitInfo{
-				Name:       "wordpress/0",
-				Service:    "wordpress",
-				Series:     "quantal",
-				MachineId:  "0",
-				Ports:      []network.Port{{"tcp", 12345}},
-				Status:     params.StatusError,
-				StatusInfo: "failure",
+				Name:           "wordpress/0",
+				Service:        "wordpress",
+				Series:         "quantal",
+				MachineId:      "0",
+				Ports:          []network.Port{{"tcp", 12345}},
+				Status:         params.StatusError,
+				StatusInfo:     "failure",
+				WorkloadStatus: params.WorkloadUnknown,
 			},
 		},
 	}, {
@@ -455,6 +458,7 @@ var allWatcherChangedTests = []struct {
 				Ports:          []network.Port{{"tcp", 12345}},
 				Status:         params.StatusError,
 				StatusInfo:     "failure",
+				WorkloadStatus: params.WorkloadUnknown,
 			},
 		},
 	},
@@ -747,6 +751,41 @@ var allWatcherChangedTests = []struct {
 			},
 		},
 	},
+	// Workload status changes
+	{
+		about: "no unit in state -> do nothing",
+		setUp: func(c *gc.C, st *State) {},
+		change: watcher.Change{
+			C:  "workloadstatuses",
+			Id: "u#wordpress/0",
+		},
+	}, {
+		about: "workload status is changed if the unit exists in the store",
+		add: []params.EntityInfo{&params.UnitInfo{
+			Name:           "wordpress/0",
+			Status:         params.StatusStarted,
+			WorkloadStatus: params.WorkloadUnknown,
+		}},
+		setUp: func(c *gc.C, st *State) {
+			wordpress := AddTestingService(c, st, "wordpress", AddTestingCharm(c, st, "wordpress"))
+			u, err := wordpress.AddUnit()
+			c.Assert(err, gc.IsNil)
+			err = u.SetWorkloadStatus(params.WorkloadWaiting, "waiting for database relation")
+			c.Assert(err, gc.IsNil)
+		},
+		change: watcher.Change{
+			C:  "workloadstatuses",
+			Id: "u#wordpress/0",
+		},
+		expectContents: []params.EntityInfo{
+			&params.UnitInfo{
+				Name:               "wordpress/0",
+				Status:             params.StatusStarted,
+				WorkloadStatus:     params.WorkloadWaiting,
+				WorkloadStatusInfo: "waiting for database relation",
+			},
+		},
+	},
 	// Machine status changes
 	{
 		about: "no machine in state -> do nothing",
@@ -4,6 +4,7 @@
 package state
 
 import (
+	"regexp"
 	"time"
 
 	"github.com/juju/errors"
@@ -29,6 +30,7 @@ type metricBatchDoc struct {
 	Unit     string      `bson:"unit"`
 	CharmUrl string      `bson:"charmurl"`
 	Sent     bool        `bson:"sent"`
+	Created  time.Time   `bson:"created"`
 	Metrics  []metricDoc `bson:"metrics"`
 }
 
@@ -76,6 +78,7 @@ func (st *State) addMetrics(unitTag names.UnitTag, charmUrl *charm.URL, metrics
 			Unit:     unitTag.Id(),
 			CharmUrl: charmUrl.String(),
 			Sent:     false,
+			Created:  nowToTheSecond(),
 			Metrics:  metricDocs,
 		}}
 	buildTxn := func(attempt int) ([]txn.Op, error) {
@@ -117,6 +120,160 @@ func (st *State) MetricBatch(id string) (*MetricBatch, error) {
 	return &MetricBatch{st: st, doc: doc}, nil
 }
 
+// MetricFilter selects the metric batches returned by MetricBatches,
+// and the metrics within them. Empty fields do not restrict the
+// selection.
+type MetricFilter struct {
+	// Units and Services select the batches reported by the named
+	// units and by the units of the named services.
+	Units    []string
+	Services []string
+
+	// Keys selects the metrics with the given keys.
+	Keys []string
+
+	// Since and Until select the metrics recorded in the given time
+	// range, including Since and excluding Until.
+	Since time.Time
+	Until time.Time
+}
+
+// MetricBatches returns the metric batches that match the given filter,
+// oldest first. Each batch holds only those of its metrics that match
+// the filter; batches without any such metrics are omitted.
+func (st *State) MetricBatches(filter MetricFilter) ([]*MetricBatch, error) {
+	c, closer := st.getCollection(metricsC)
+	defer closer()
+
+	match := bson.D{}
+	if len(filter.Units) > 0 || len(filter.Services) > 0 {
+		var units []interface{}
+		for _, unit := range filter.Units {
+			units = append(units, unit)
+		}
+		for _, service := range filter.Services {
+			units = append(units, bson.RegEx{Pattern: "^" + regexp.QuoteMeta(service+"/")})
+		}
+		match = append(match, bson.DocElem{"unit", bson.D{{"$in", units}}})
+	}
+	metricMatch := bson.D{}
+	if len(filter.Keys) > 0 {
+		metricMatch = append(metricMatch, bson.DocElem{"key", bson.D{{"$in", filter.Keys}}})
+	}
+	if !filter.Since.IsZero() || !filter.Until.IsZero() {
+		timeRange := bson.D{}
+		if !filter.Since.IsZero() {
+			timeRange = append(timeRange, bson.DocElem{"$gte", filter.Since})
+		}
+		if !filter.Until.IsZero() {
+			timeRange = append(timeRange, bson.DocElem{"$lt", filter.Until})
+		}
+		metricMatch = append(metricMatch, bson.DocElem{"time", timeRange})
+	}
+	if len(metricMatch) == 0 {
+		return st.metricBatches(match)
+	}
+
+	// Select the batches holding any matching metric, then drop the
+	// other metrics from those batches.
+	match = append(match, bson.DocElem{"metrics", bson.D{{"$elemMatch", metricMatch}}})
+	unwoundMatch := make(bson.D, len(metricMatch))
+	for i, elem := range metricMatch {
+		unwoundMatch[i] = bson.DocElem{"metrics." + elem.Name, elem.Value}
+	}
+	pipeline := []bson.D{
+		{{"$match", match}},
+		{{"$unwind", "$metrics"}},
+		{{"$match", unwoundMatch}},
+		{{"$group", bson.D{
+			{"_id", "$_id"},
+			{"unit", bson.D{{"$first", "$unit"}}},
+			{"charmurl", bson.D{{"$first", "$charmurl"}}},
+			{"sent", bson.D{{"$first", "$sent"}}},
+			{"created", bson.D{{"$first", "$created"}}},
+			{"metrics", bson.D{{"$push", "$metrics"}}},
+		}}},
+		{{"$sort", bson.D{{"created", 1}, {"_id", 1}}}},
+	}
+	var docs []metricBatchDoc
+	if err := c.Pipe(pipeline).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get metric batches")
+	}
+	return st.newMetricBatches(docs), nil
+}
+
+// metricBatches returns the metric batches matching the given query,
+// oldest first.
+func (st *State) metricBatches(query bson.D) ([]*MetricBatch, error) {
+	c, closer := st.getCollection(metricsC)
+	defer closer()
+	var docs []metricBatchDoc
+	if err := c.Find(query).Sort("created").All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.newMetricBatches(docs), nil
+}
+
+// newMetricBatches returns MetricBatches for the given documents.
+func (st *State) newMetricBatches(docs []metricBatchDoc) []*MetricBatch {
+	batches := make([]*MetricBatch, len(docs))
+	for i, doc := range docs {
+		batches[i] = &MetricBatch{st: st, doc: doc}
+	}
+	return batches
+}
+
+// MetricsToSend returns at most batchSize metric batches that have not
+// been sent to the metrics collector, oldest first.
+func (st *State) MetricsToSend(batchSize int) ([]*MetricBatch, error) {
+	c, closer := st.getCollection(metricsC)
+	defer closer()
+	var docs []metricBatchDoc
+	err := c.Find(bson.M{"sent": false}).Sort("created").Limit(batchSize).All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.newMetricBatches(docs), nil
+}
+
+// CleanupOldMetrics removes the metric batches that were sent to the
+// metrics collector and were created before the given time.
+func (st *State) CleanupOldMetrics(before time.Time) error {
+	c, closer := st.getCollection(metricsC)
+	defer closer()
+	var docs []struct {
+		UUID string `bson:"_id"`
+	}
+	// Batches recorded before their creation time was stored have
+	// no created field, and are old enough to be removed.
+	err := c.Find(bson.M{
+		"sent": true,
+		"$or": []bson.M{
+			{"created": bson.M{"$lt": before}},
+			{"created": bson.M{"$exists": false}},
+		},
+	}).Select(bson.M{"_id": 1}).All(&docs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      metricsC,
+			Id:     doc.UUID,
+			Remove: true,
+		}
+	}
+	if err := st.runTransaction(ops); err != nil {
+		return errors.Annotate(err, "cannot remove old metrics")
+	}
+	logger.Debugf("removed %d old metric batches", len(docs))
+	return nil
+}
+
 // UUID returns to uuid of the metric.
 func (m *MetricBatch) UUID() string {
 	return m.doc.UUID
@@ -132,6 +289,11 @@ func (m *MetricBatch) CharmURL() string {
 	return m.doc.CharmUrl
 }
 
+// Created returns the time the metric batch was recorded.
+func (m *MetricBatch) Created() time.Time {
+	return m.doc.Created
+}
+
 // Sent returns a flag to tell us if this metric has been sent to the metric
 // collection service
 func (m *MetricBatch) Sent() bool {
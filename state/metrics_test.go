@@ -4,6 +4,8 @@
 package state_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "launchpad.net/gocheck"
 
@@ -52,6 +54,148 @@ func (s *MetricSuite) TestAddMetric(c *gc.C) {
 	c.Assert(metric.Credentials(), gc.DeepEquals, []byte("creds"))
 }
 
+func (s *MetricSuite) TestMetricBatchesFilter(c *gc.C) {
+	unit := s.assertAddUnit(c)
+	now := state.NowToTheSecond()
+	added, err := unit.AddMetrics([]*state.Metric{
+		state.NewMetric("pings", "5", now.Add(-time.Hour), nil),
+		state.NewMetric("users", "2", now.Add(-time.Hour), nil),
+		state.NewMetric("pings", "7", now, nil),
+	})
+	c.Assert(err, gc.IsNil)
+
+	metricValues := func(batches []*state.MetricBatch) []string {
+		var values []string
+		for _, batch := range batches {
+			c.Check(batch.UUID(), gc.Equals, added.UUID())
+			c.Check(batch.Unit(), gc.Equals, unit.Name())
+			for _, metric := range batch.Metrics() {
+				values = append(values, metric.Key()+"="+metric.Value())
+			}
+		}
+		return values
+	}
+	for i, test := range []struct {
+		about  string
+		filter state.MetricFilter
+		expect []string
+	}{{
+		about:  "everything",
+		expect: []string{"pings=5", "users=2", "pings=7"},
+	}, {
+		about:  "by unit",
+		filter: state.MetricFilter{Units: []string{unit.Name()}},
+		expect: []string{"pings=5", "users=2", "pings=7"},
+	}, {
+		about:  "by another unit",
+		filter: state.MetricFilter{Units: []string{"mysql/0"}},
+	}, {
+		about:  "by service",
+		filter: state.MetricFilter{Services: []string{"wordpress"}},
+		expect: []string{"pings=5", "users=2", "pings=7"},
+	}, {
+		about:  "services are matched by name, not by prefix",
+		filter: state.MetricFilter{Services: []string{"word"}},
+	}, {
+		about:  "by key",
+		filter: state.MetricFilter{Keys: []string{"pings"}},
+		expect: []string{"pings=5", "pings=7"},
+	}, {
+		about: "by time",
+		filter: state.MetricFilter{
+			Since: now.Add(-time.Hour),
+			Until: now,
+		},
+		expect: []string{"pings=5", "users=2"},
+	}, {
+		about: "by key and time",
+		filter: state.MetricFilter{
+			Keys:  []string{"pings"},
+			Since: now,
+		},
+		expect: []string{"pings=7"},
+	}, {
+		about:  "no matching metrics",
+		filter: state.MetricFilter{Keys: []string{"errors"}},
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		batches, err := s.State.MetricBatches(test.filter)
+		c.Check(err, gc.IsNil)
+		c.Check(metricValues(batches), gc.DeepEquals, test.expect)
+	}
+}
+
+func (s *MetricSuite) TestMetricsToSend(c *gc.C) {
+	unit := s.assertAddUnit(c)
+	now := state.NowToTheSecond()
+	m := state.NewMetric("item", "5", now, nil)
+	var added []*state.MetricBatch
+	for i := 0; i < 3; i++ {
+		batch, err := unit.AddMetrics([]*state.Metric{m})
+		c.Assert(err, gc.IsNil)
+		added = append(added, batch)
+	}
+	err := added[0].SetSent()
+	c.Assert(err, gc.IsNil)
+
+	batches, err := s.State.MetricsToSend(10)
+	c.Assert(err, gc.IsNil)
+	c.Assert(batches, gc.HasLen, 2)
+	for _, batch := range batches {
+		c.Assert(batch.Sent(), jc.IsFalse)
+		c.Assert(batch.UUID(), gc.Not(gc.Equals), added[0].UUID())
+	}
+
+	batches, err = s.State.MetricsToSend(1)
+	c.Assert(err, gc.IsNil)
+	c.Assert(batches, gc.HasLen, 1)
+}
+
+func (s *MetricSuite) TestCleanupOldMetrics(c *gc.C) {
+	unit := s.assertAddUnit(c)
+	now := state.NowToTheSecond()
+	m := state.NewMetric("item", "5", now, nil)
+	sent, err := unit.AddMetrics([]*state.Metric{m})
+	c.Assert(err, gc.IsNil)
+	err = sent.SetSent()
+	c.Assert(err, gc.IsNil)
+	c.Assert(sent.Created().IsZero(), jc.IsFalse)
+	unsent, err := unit.AddMetrics([]*state.Metric{m})
+	c.Assert(err, gc.IsNil)
+
+	// Batches created after the given time are kept.
+	err = s.State.CleanupOldMetrics(now.Add(-time.Hour))
+	c.Assert(err, gc.IsNil)
+	batches, err := s.State.MetricBatches(state.MetricFilter{Units: []string{unit.Name()}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(batches, gc.HasLen, 2)
+
+	// Only sent batches are removed.
+	err = s.State.CleanupOldMetrics(now.Add(time.Hour))
+	c.Assert(err, gc.IsNil)
+	batches, err = s.State.MetricBatches(state.MetricFilter{Units: []string{unit.Name()}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(batches, gc.HasLen, 1)
+	c.Assert(batches[0].UUID(), gc.Equals, unsent.UUID())
+}
+
+func (s *MetricSuite) TestCleanupOldMetricsWithoutCreated(c *gc.C) {
+	unit := s.assertAddUnit(c)
+	now := state.NowToTheSecond()
+	m := state.NewMetric("item", "5", now, nil)
+	sent, err := unit.AddMetrics([]*state.Metric{m})
+	c.Assert(err, gc.IsNil)
+	err = sent.SetSent()
+	c.Assert(err, gc.IsNil)
+	state.RemoveMetricBatchCreated(c, s.State, sent.UUID())
+
+	err = s.State.CleanupOldMetrics(now.Add(-time.Hour))
+	c.Assert(err, gc.IsNil)
+	batches, err := s.State.MetricBatches(state.MetricFilter{Units: []string{unit.Name()}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(batches, gc.HasLen, 0)
+}
+
 func assertUnitRemoved(c *gc.C, unit *state.Unit) {
 	assertUnitDead(c, unit)
 	err := unit.Remove()
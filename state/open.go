@@ -6,6 +6,7 @@ package state
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -127,6 +128,8 @@ var indexes = []struct {
 	{networkInterfacesC, []string{"macaddress", "networkname"}, true},
 	{networkInterfacesC, []string{"networkname"}, false},
 	{networkInterfacesC, []string{"machineid"}, false},
+	{statusHistoryC, []string{"entityid", "updated"}, false},
+	{auditC, []string{"time"}, false},
 }
 
 // The capped collection used for transaction logs defaults to 10MB.
@@ -137,6 +140,13 @@ var (
 	logSizeTests = 1000000
 )
 
+// The capped collection holding agent logs defaults to 100MB. It is
+// also tweaked in export_test.go, to 1MB.
+var (
+	agentLogsSize      = 100000000
+	agentLogsSizeTests = 1000000
+)
+
 func maybeUnauthorized(err error, msg string) error {
 	if err == nil {
 		return nil
@@ -196,6 +206,11 @@ func newState(session *mgo.Session, mongoInfo *authentication.MongoInfo, policy
 	if err != nil && err.Error() != "collection already exists" {
 		return nil, maybeUnauthorized(err, "cannot create log collection")
 	}
+	logs := db.C(logsC)
+	err = logs.Create(&mgo.CollectionInfo{Capped: true, MaxBytes: agentLogsSize})
+	if err != nil && err.Error() != "collection already exists" {
+		return nil, maybeUnauthorized(err, "cannot create agent logs collection")
+	}
 	txns := db.C(txnsC)
 	err = txns.Create(&mgo.CollectionInfo{})
 	if err != nil && err.Error() != "collection already exists" {
@@ -225,6 +240,11 @@ func newState(session *mgo.Session, mongoInfo *authentication.MongoInfo, policy
 			return nil, errors.Annotate(err, "cannot create database index")
 		}
 	}
+	// Login attempts are removed by mongo once they expire.
+	expiry := mgo.Index{Key: []string{"expires"}, ExpireAfter: time.Second}
+	if err := db.C(loginAttemptsC).EnsureIndex(expiry); err != nil {
+		return nil, errors.Annotate(err, "cannot create database index")
+	}
 
 	return st, nil
 }
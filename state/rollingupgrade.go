@@ -0,0 +1,346 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v3"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// RollingUpgrade represents the upgrade of the charm of a service's
+// units a batch at a time. While it is in progress, the units that
+// have not been released to upgrade keep the charm they have, although
+// the service already uses the new charm. The rolling upgrade is
+// advanced by a worker on the state servers, which releases the next
+// batch once the units of the current one have upgraded.
+type RollingUpgrade struct {
+	st  *State
+	doc rollingUpgradeDoc
+}
+
+type rollingUpgradeDoc struct {
+	Service      string     `bson:"_id"`
+	FromCharmURL *charm.URL `bson:"fromcharmurl"`
+	ToCharmURL   *charm.URL `bson:"tocharmurl"`
+	BatchSize    int        `bson:"batchsize"`
+	WaitActive   bool       `bson:"waitactive"`
+	Batch        []string   `bson:"batch"`
+	BatchStarted time.Time  `bson:"batchstarted"`
+	Paused       bool       `bson:"paused"`
+	PauseReason  string     `bson:"pausereason"`
+}
+
+// RollingUpgradeParams holds the parameters of a rolling upgrade.
+type RollingUpgradeParams struct {
+	// Charm is the charm the service is upgraded to.
+	Charm *Charm
+
+	// Force upgrades units even if they are in an error state.
+	Force bool
+
+	// BatchSize is the number of units upgraded at a time.
+	BatchSize int
+
+	// WaitActive requires the workload status of each upgraded unit
+	// to be active, as set by its charm, before the next batch is
+	// upgraded.
+	WaitActive bool
+}
+
+// StartRollingUpgrade holds back the upgrade of the service's units,
+// then sets the service's charm, so that the units can be released to
+// upgrade a batch at a time.
+func (s *Service) StartRollingUpgrade(p RollingUpgradeParams) (*RollingUpgrade, error) {
+	if p.BatchSize < 1 {
+		return nil, fmt.Errorf("cannot start rolling upgrade of service %q: invalid batch size %d", s, p.BatchSize)
+	}
+	if *p.Charm.URL() == *s.doc.CharmURL {
+		return nil, fmt.Errorf("cannot start rolling upgrade of service %q: service already uses charm %q", s, p.Charm.URL())
+	}
+	doc := rollingUpgradeDoc{
+		Service:      s.doc.Name,
+		FromCharmURL: s.doc.CharmURL,
+		ToCharmURL:   p.Charm.URL(),
+		BatchSize:    p.BatchSize,
+		WaitActive:   p.WaitActive,
+	}
+	units, err := s.AllUnits()
+	if err != nil {
+		return nil, err
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.Name,
+		Assert: bson.D{{"life", Alive}, {"charmurl", s.doc.CharmURL}},
+	}, {
+		C:      rollingUpgradesC,
+		Id:     doc.Service,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	for _, unit := range units {
+		ops = append(ops, holdCharmUpgradeOp(unit.Name(), true))
+	}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		if _, err := s.st.RollingUpgrade(s.doc.Name); err == nil {
+			return nil, fmt.Errorf("cannot start rolling upgrade of service %q: rolling upgrade already in progress", s)
+		}
+		return nil, fmt.Errorf("cannot start rolling upgrade of service %q: service or units changed", s)
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot start rolling upgrade of service %q: %v", s, err)
+	}
+	ru := &RollingUpgrade{st: s.st, doc: doc}
+	if err := s.SetCharm(p.Charm, p.Force); err != nil {
+		if err := ru.release(); err != nil {
+			logger.Errorf("cannot release units of service %q: %v", s, err)
+		}
+		return nil, err
+	}
+	return ru, nil
+}
+
+func holdCharmUpgradeOp(unitName string, held bool) txn.Op {
+	update := bson.D{{"$set", bson.D{{"charmupgradeheld", true}}}}
+	if !held {
+		update = bson.D{{"$unset", bson.D{{"charmupgradeheld", nil}}}}
+	}
+	return txn.Op{
+		C:      unitsC,
+		Id:     unitName,
+		Assert: txn.DocExists,
+		Update: update,
+	}
+}
+
+// RollingUpgrade returns the rolling upgrade of the named service.
+func (st *State) RollingUpgrade(service string) (*RollingUpgrade, error) {
+	upgrades, closer := st.getCollection(rollingUpgradesC)
+	defer closer()
+
+	ru := &RollingUpgrade{st: st}
+	err := upgrades.FindId(service).One(&ru.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("rolling upgrade of service %q", service)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot get rolling upgrade of service %q: %v", service, err)
+	}
+	return ru, nil
+}
+
+// AllRollingUpgrades returns the rolling upgrades in progress.
+func (st *State) AllRollingUpgrades() ([]*RollingUpgrade, error) {
+	upgrades, closer := st.getCollection(rollingUpgradesC)
+	defer closer()
+
+	var docs []rollingUpgradeDoc
+	if err := upgrades.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, fmt.Errorf("cannot get rolling upgrades: %v", err)
+	}
+	result := make([]*RollingUpgrade, len(docs))
+	for i, doc := range docs {
+		result[i] = &RollingUpgrade{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// Service returns the name of the upgraded service.
+func (ru *RollingUpgrade) Service() string {
+	return ru.doc.Service
+}
+
+// FromCharmURL returns the URL of the charm the service used before
+// the upgrade.
+func (ru *RollingUpgrade) FromCharmURL() *charm.URL {
+	return ru.doc.FromCharmURL
+}
+
+// ToCharmURL returns the URL of the charm the service is upgraded to.
+func (ru *RollingUpgrade) ToCharmURL() *charm.URL {
+	return ru.doc.ToCharmURL
+}
+
+// BatchSize returns the number of units upgraded at a time.
+func (ru *RollingUpgrade) BatchSize() int {
+	return ru.doc.BatchSize
+}
+
+// WaitActive returns whether upgraded units must report an active
+// workload before the next batch is upgraded.
+func (ru *RollingUpgrade) WaitActive() bool {
+	return ru.doc.WaitActive
+}
+
+// Batch returns the names of the units of the current batch, and when
+// they were released to upgrade.
+func (ru *RollingUpgrade) Batch() ([]string, time.Time) {
+	return ru.doc.Batch, ru.doc.BatchStarted
+}
+
+// Paused returns whether the rolling upgrade is paused, and why.
+func (ru *RollingUpgrade) Paused() (bool, string) {
+	return ru.doc.Paused, ru.doc.PauseReason
+}
+
+// Refresh refreshes the contents of the rolling upgrade from state.
+func (ru *RollingUpgrade) Refresh() error {
+	fresh, err := ru.st.RollingUpgrade(ru.doc.Service)
+	if err != nil {
+		return err
+	}
+	ru.doc = fresh.doc
+	return nil
+}
+
+// HeldUnits returns the names of the units that have not yet been
+// released to upgrade, in order of their number.
+func (ru *RollingUpgrade) HeldUnits() ([]string, error) {
+	units, closer := ru.st.getCollection(unitsC)
+	defer closer()
+
+	var docs []struct {
+		Name string `bson:"_id"`
+	}
+	sel := bson.D{{"service", ru.doc.Service}, {"charmupgradeheld", true}}
+	if err := units.Find(sel).Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+		return nil, fmt.Errorf("cannot get held units of service %q: %v", ru.doc.Service, err)
+	}
+	names := make([]string, len(docs))
+	for i, doc := range docs {
+		names[i] = doc.Name
+	}
+	sort.Sort(unitNamesByNumber(names))
+	return names, nil
+}
+
+type unitNamesByNumber []string
+
+func (u unitNamesByNumber) Len() int      { return len(u) }
+func (u unitNamesByNumber) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u unitNamesByNumber) Less(i, j int) bool {
+	return unitNameNumber(u[i]) < unitNameNumber(u[j])
+}
+
+func unitNameNumber(name string) int {
+	n, _ := strconv.Atoi(name[strings.Index(name, "/")+1:])
+	return n
+}
+
+// ReleaseBatch releases the given units to upgrade, as the current
+// batch.
+func (ru *RollingUpgrade) ReleaseBatch(unitNames []string, now time.Time) error {
+	ops := []txn.Op{{
+		C:      rollingUpgradesC,
+		Id:     ru.doc.Service,
+		Assert: bson.D{{"paused", false}},
+		Update: bson.D{{"$set", bson.D{
+			{"batch", unitNames},
+			{"batchstarted", now.UTC()},
+		}}},
+	}}
+	for _, name := range unitNames {
+		ops = append(ops, holdCharmUpgradeOp(name, false))
+	}
+	if err := ru.st.runTransaction(ops); err != nil {
+		return fmt.Errorf("cannot release units of service %q: %v", ru.doc.Service, onAbort(err, errors.New("rolling upgrade paused or units removed")))
+	}
+	ru.doc.Batch = unitNames
+	ru.doc.BatchStarted = now.UTC()
+	return nil
+}
+
+// Pause stops further batches from being released, recording why.
+func (ru *RollingUpgrade) Pause(reason string) error {
+	return ru.setPaused(true, reason)
+}
+
+// Resume continues a paused rolling upgrade.
+func (ru *RollingUpgrade) Resume() error {
+	return ru.setPaused(false, "")
+}
+
+func (ru *RollingUpgrade) setPaused(paused bool, reason string) error {
+	ops := []txn.Op{{
+		C:      rollingUpgradesC,
+		Id:     ru.doc.Service,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{
+			{"paused", paused},
+			{"pausereason", reason},
+		}}},
+	}}
+	if err := ru.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("rolling upgrade of service %q", ru.doc.Service)
+	} else if err != nil {
+		return fmt.Errorf("cannot update rolling upgrade of service %q: %v", ru.doc.Service, err)
+	}
+	ru.doc.Paused = paused
+	ru.doc.PauseReason = reason
+	return nil
+}
+
+// Finish ends a rolling upgrade whose units have all been released.
+func (ru *RollingUpgrade) Finish() error {
+	held, err := ru.HeldUnits()
+	if err != nil {
+		return err
+	}
+	if len(held) > 0 {
+		return fmt.Errorf("cannot finish rolling upgrade of service %q: units %s not upgraded", ru.doc.Service, strings.Join(held, ", "))
+	}
+	return ru.release()
+}
+
+// Abort ends a rolling upgrade, setting the service's charm back to
+// the charm it used before. Units that were already upgraded are
+// upgraded to that charm again.
+func (ru *RollingUpgrade) Abort() error {
+	svc, err := ru.st.Service(ru.doc.Service)
+	if err != nil {
+		return err
+	}
+	ch, err := ru.st.Charm(ru.doc.FromCharmURL)
+	if err != nil {
+		return err
+	}
+	if err := svc.SetCharm(ch, true); err != nil {
+		return fmt.Errorf("cannot abort rolling upgrade of service %q: %v", ru.doc.Service, err)
+	}
+	return ru.release()
+}
+
+// release removes the rolling upgrade, releasing any units still held.
+func (ru *RollingUpgrade) release() error {
+	held, err := ru.HeldUnits()
+	if err != nil {
+		return err
+	}
+	ops := []txn.Op{{
+		C:      rollingUpgradesC,
+		Id:     ru.doc.Service,
+		Remove: true,
+	}}
+	for _, name := range held {
+		ops = append(ops, holdCharmUpgradeOp(name, false))
+	}
+	if err := ru.st.runTransaction(ops); err != nil {
+		return fmt.Errorf("cannot end rolling upgrade of service %q: %v", ru.doc.Service, err)
+	}
+	return nil
+}
+
+// CharmUpgradeHeld returns whether the unit is held back from
+// upgrading to its service's charm by a rolling upgrade.
+func (u *Unit) CharmUpgradeHeld() bool {
+	return u.doc.CharmUpgradeHeld
+}
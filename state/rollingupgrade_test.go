@@ -0,0 +1,195 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+)
+
+type RollingUpgradeSuite struct {
+	ConnSuite
+	oldCharm *state.Charm
+	newCharm *state.Charm
+	service  *state.Service
+	units    []*state.Unit
+}
+
+var _ = gc.Suite(&RollingUpgradeSuite{})
+
+func (s *RollingUpgradeSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.oldCharm = s.AddTestingCharm(c, "wordpress")
+	s.newCharm = s.AddConfigCharm(c, "wordpress", "options: {}", 42)
+	s.service = s.AddTestingService(c, "wordpress", s.oldCharm)
+	s.units = nil
+	for i := 0; i < 3; i++ {
+		unit, err := s.service.AddUnit()
+		c.Assert(err, gc.IsNil)
+		err = unit.SetCharmURL(s.oldCharm.URL())
+		c.Assert(err, gc.IsNil)
+		s.units = append(s.units, unit)
+	}
+}
+
+func (s *RollingUpgradeSuite) start(c *gc.C) *state.RollingUpgrade {
+	ru, err := s.service.StartRollingUpgrade(state.RollingUpgradeParams{
+		Charm:      s.newCharm,
+		BatchSize:  2,
+		WaitActive: true,
+	})
+	c.Assert(err, gc.IsNil)
+	return ru
+}
+
+func (s *RollingUpgradeSuite) assertHeld(c *gc.C, held ...bool) {
+	for i, unit := range s.units {
+		err := unit.Refresh()
+		c.Assert(err, gc.IsNil)
+		c.Check(unit.CharmUpgradeHeld(), gc.Equals, held[i], gc.Commentf("unit %s", unit))
+	}
+}
+
+func (s *RollingUpgradeSuite) TestStartRollingUpgrade(c *gc.C) {
+	ru := s.start(c)
+	c.Assert(ru.Service(), gc.Equals, "wordpress")
+	c.Assert(ru.FromCharmURL(), gc.DeepEquals, s.oldCharm.URL())
+	c.Assert(ru.ToCharmURL(), gc.DeepEquals, s.newCharm.URL())
+	c.Assert(ru.BatchSize(), gc.Equals, 2)
+	c.Assert(ru.WaitActive(), jc.IsTrue)
+	batch, _ := ru.Batch()
+	c.Assert(batch, gc.HasLen, 0)
+
+	// The service uses the new charm, but its units are held back.
+	err := s.service.Refresh()
+	c.Assert(err, gc.IsNil)
+	curl, _ := s.service.CharmURL()
+	c.Assert(curl, gc.DeepEquals, s.newCharm.URL())
+	s.assertHeld(c, true, true, true)
+
+	held, err := ru.HeldUnits()
+	c.Assert(err, gc.IsNil)
+	c.Assert(held, gc.DeepEquals, []string{"wordpress/0", "wordpress/1", "wordpress/2"})
+
+	stored, err := s.State.RollingUpgrade("wordpress")
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored.ToCharmURL(), gc.DeepEquals, s.newCharm.URL())
+	all, err := s.State.AllRollingUpgrades()
+	c.Assert(err, gc.IsNil)
+	c.Assert(all, gc.HasLen, 1)
+}
+
+func (s *RollingUpgradeSuite) TestStartRollingUpgradeErrors(c *gc.C) {
+	_, err := s.service.StartRollingUpgrade(state.RollingUpgradeParams{
+		Charm:     s.newCharm,
+		BatchSize: 0,
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot start rolling upgrade of service "wordpress": invalid batch size 0`)
+
+	_, err = s.service.StartRollingUpgrade(state.RollingUpgradeParams{
+		Charm:     s.oldCharm,
+		BatchSize: 1,
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot start rolling upgrade of service "wordpress": service already uses charm "local:quantal/quantal-wordpress-3"`)
+
+	s.start(c)
+	_, err = s.service.StartRollingUpgrade(state.RollingUpgradeParams{
+		Charm:     s.oldCharm,
+		BatchSize: 1,
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot start rolling upgrade of service "wordpress": rolling upgrade already in progress`)
+}
+
+func (s *RollingUpgradeSuite) TestStartRollingUpgradeSetCharmFails(c *gc.C) {
+	other := s.AddSeriesCharm(c, "wordpress", "precise")
+	_, err := s.service.StartRollingUpgrade(state.RollingUpgradeParams{
+		Charm:     other,
+		BatchSize: 1,
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot change a service's series")
+
+	// The units are not left held back.
+	_, err = s.State.RollingUpgrade("wordpress")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	s.assertHeld(c, false, false, false)
+}
+
+func (s *RollingUpgradeSuite) TestReleaseBatch(c *gc.C) {
+	ru := s.start(c)
+	now := time.Date(2014, 10, 1, 12, 0, 0, 0, time.UTC)
+	err := ru.ReleaseBatch([]string{"wordpress/0", "wordpress/1"}, now)
+	c.Assert(err, gc.IsNil)
+	s.assertHeld(c, false, false, true)
+
+	err = ru.Refresh()
+	c.Assert(err, gc.IsNil)
+	batch, started := ru.Batch()
+	c.Assert(batch, gc.DeepEquals, []string{"wordpress/0", "wordpress/1"})
+	c.Assert(started.Equal(now), jc.IsTrue)
+	held, err := ru.HeldUnits()
+	c.Assert(err, gc.IsNil)
+	c.Assert(held, gc.DeepEquals, []string{"wordpress/2"})
+
+	// The rolling upgrade cannot finish while units are held back.
+	err = ru.Finish()
+	c.Assert(err, gc.ErrorMatches, `cannot finish rolling upgrade of service "wordpress": units wordpress/2 not upgraded`)
+
+	err = ru.ReleaseBatch([]string{"wordpress/2"}, now)
+	c.Assert(err, gc.IsNil)
+	err = ru.Finish()
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.RollingUpgrade("wordpress")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RollingUpgradeSuite) TestPauseResume(c *gc.C) {
+	ru := s.start(c)
+	err := ru.Pause("unit wordpress/0: hook failed")
+	c.Assert(err, gc.IsNil)
+	paused, reason := ru.Paused()
+	c.Assert(paused, jc.IsTrue)
+	c.Assert(reason, gc.Equals, "unit wordpress/0: hook failed")
+
+	// No batch is released while the rolling upgrade is paused.
+	err = ru.ReleaseBatch([]string{"wordpress/0"}, time.Now())
+	c.Assert(err, gc.ErrorMatches, `cannot release units of service "wordpress": rolling upgrade paused or units removed`)
+	s.assertHeld(c, true, true, true)
+
+	err = ru.Resume()
+	c.Assert(err, gc.IsNil)
+	err = ru.Refresh()
+	c.Assert(err, gc.IsNil)
+	paused, reason = ru.Paused()
+	c.Assert(paused, jc.IsFalse)
+	c.Assert(reason, gc.Equals, "")
+	err = ru.ReleaseBatch([]string{"wordpress/0"}, time.Now())
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *RollingUpgradeSuite) TestAbort(c *gc.C) {
+	ru := s.start(c)
+	err := ru.ReleaseBatch([]string{"wordpress/0"}, time.Now())
+	c.Assert(err, gc.IsNil)
+	err = s.units[0].SetCharmURL(s.newCharm.URL())
+	c.Assert(err, gc.IsNil)
+
+	err = ru.Abort()
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.RollingUpgrade("wordpress")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	s.assertHeld(c, false, false, false)
+
+	// The service uses the old charm again, so the upgraded unit is
+	// upgraded back to it.
+	err = s.service.Refresh()
+	c.Assert(err, gc.IsNil)
+	curl, force := s.service.CharmURL()
+	c.Assert(curl, gc.DeepEquals, s.oldCharm.URL())
+	c.Assert(force, jc.IsTrue)
+}
@@ -589,6 +589,9 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 			Insert: udoc,
 		},
 		createStatusOp(s.st, globalKey, sdoc),
+		createWorkloadStatusOp(s.st, globalKey, workloadStatusDoc{
+			Status: params.WorkloadUnknown,
+		}),
 		{
 			C:      servicesC,
 			Id:     s.doc.Name,
@@ -674,6 +677,7 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 	},
 		removeConstraintsOp(s.st, u.globalKey()),
 		removeStatusOp(s.st, u.globalKey()),
+		removeWorkloadStatusOp(s.st, u.globalKey()),
 		annotationRemoveOp(s.st, u.globalKey()),
 		s.st.newCleanupOp(cleanupRemovedUnit, u.doc.Name),
 	)
@@ -748,6 +752,93 @@ func allUnits(st *State, service string) (units []*Unit, err error) {
 	return units, nil
 }
 
+// AddAction queues an action with the given name and payload on every
+// live unit of the service, and returns the service action that tracks
+// its progress on those units.
+func (s *Service) AddAction(name string, payload map[string]interface{}) (*Action, error) {
+	return s.AddActionWithOptions(name, payload, ServiceActionOptions{})
+}
+
+// AddActionWithOptions is like AddAction, but opts controls which of
+// the service's units the action is queued on, and how many at once.
+func (s *Service) AddActionWithOptions(name string, payload map[string]interface{}, opts ServiceActionOptions) (*Action, error) {
+	if opts.BatchSize < 0 {
+		return nil, fmt.Errorf("cannot add action; invalid batch size %d", opts.BatchSize)
+	}
+	ch, _, err := s.Charm()
+	if err != nil {
+		return nil, fmt.Errorf("cannot add action; %v", err)
+	}
+	if payload, err = validateActionPayload(ch, name, payload); err != nil {
+		return nil, err
+	}
+	doc, err := newActionDoc(s.st, s, name, payload)
+	if err != nil {
+		return nil, fmt.Errorf("cannot add action; %v", err)
+	}
+	doc.BatchSize = opts.BatchSize
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if notDead, err := isNotDead(s.st.db, servicesC, s.doc.Name); err != nil {
+			return nil, err
+		} else if !notDead {
+			return nil, fmt.Errorf("service %q is dead", s)
+		}
+		units, err := s.actionUnitNames(opts.FirstUnitOnly)
+		if err != nil {
+			return nil, err
+		}
+		doc.Units, doc.Children, doc.Finished = units, nil, 0
+		unitOps, err := s.st.queueUnitActionOps(&doc)
+		if err != nil {
+			return nil, err
+		}
+		if doc.Finished == len(doc.Units) {
+			return nil, fmt.Errorf("service %q has no live units", s)
+		}
+		ops := []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.Name,
+			Assert: notDeadDoc,
+		}}
+		ops = append(ops, unitOps...)
+		return append(ops, txn.Op{
+			C:      actionsC,
+			Id:     doc.Id,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		}), nil
+	}
+	if err = s.st.run(buildTxn); err == nil {
+		return newAction(s.st, doc), nil
+	}
+	return nil, err
+}
+
+// Actions returns the actions queued on the service that have not yet
+// finished on all of its units.
+func (s *Service) Actions() ([]*Action, error) {
+	return s.st.matchingActions(s)
+}
+
+// ActionResults returns the results of the actions queued on the
+// service that have finished on all of its units.
+func (s *Service) ActionResults() ([]*ActionResult, error) {
+	return s.st.matchingActionResults(s)
+}
+
+// WatchActions starts and returns a StringsWatcher that notifies when
+// actions are queued on the service or finish on all of its units.
+func (s *Service) WatchActions() StringsWatcher {
+	return s.st.WatchActionsFilteredBy(s)
+}
+
+// WatchActionResults starts and returns a StringsWatcher that notifies
+// when the results of actions queued on the service are recorded.
+func (s *Service) WatchActionResults() StringsWatcher {
+	return s.st.WatchActionResultsFilteredBy(s)
+}
+
 // Relations returns a Relation for every relation the service is in.
 func (s *Service) Relations() (relations []*Relation, err error) {
 	return serviceRelations(s.st, s.doc.Name)
@@ -0,0 +1,262 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// ServiceActionOptions controls how an action queued on a service is
+// fanned out to the service's units.
+type ServiceActionOptions struct {
+	// FirstUnitOnly restricts the action to the lowest numbered live
+	// unit of the service.
+	FirstUnitOnly bool
+
+	// BatchSize limits how many units the action is pending on at
+	// once; the action is queued on the next unit as each one
+	// finishes. Zero queues the action on every unit immediately.
+	BatchSize int
+}
+
+// queueUnitActionOps returns the operations needed to queue the service
+// action described by pdoc on as many of its waiting units as its batch
+// size allows. The payload is validated against each unit's own charm,
+// which may differ from the service's during an upgrade. Units that are
+// no longer alive, or whose charm does not accept the action, are
+// skipped. The Children and Finished fields of pdoc are updated to
+// match.
+func (st *State) queueUnitActionOps(pdoc *actionDoc) ([]txn.Op, error) {
+	var ops []txn.Op
+	for len(pdoc.Children) < len(pdoc.Units) {
+		if pdoc.BatchSize > 0 && len(pdoc.Children)-pdoc.Finished >= pdoc.BatchSize {
+			break
+		}
+		unitName := pdoc.Units[len(pdoc.Children)]
+		unit, err := st.Unit(unitName)
+		if errors.IsNotFound(err) || err == nil && unit.Life() == Dead {
+			pdoc.Children = append(pdoc.Children, "")
+			pdoc.Finished++
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		ch, err := unit.actionCharm()
+		if err != nil {
+			return nil, err
+		}
+		payload, err := validateActionPayload(ch, pdoc.Name, pdoc.Payload)
+		if IsActionValidationError(err) {
+			logger.Warningf("skipping action %q on unit %q: %v", pdoc.Name, unitName, err)
+			pdoc.Children = append(pdoc.Children, "")
+			pdoc.Finished++
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		doc, err := newActionDoc(st, unit, pdoc.Name, payload)
+		if err != nil {
+			return nil, err
+		}
+		doc.Parent = pdoc.Id
+		pdoc.Children = append(pdoc.Children, doc.Id)
+		ops = append(ops, txn.Op{
+			C:      unitsC,
+			Id:     unitName,
+			Assert: notDeadDoc,
+		}, txn.Op{
+			C:      actionsC,
+			Id:     doc.Id,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		})
+	}
+	return ops, nil
+}
+
+// finishChildOps returns the operations needed to record, on the service
+// action that queued a, that a has finished with the given result. The
+// action is queued on the next waiting unit, if any, and once every unit
+// has finished the service action is itself finished, with the results
+// of a and of the other units.
+func (a *Action) finishChildOps(result *actionResultDoc) ([]txn.Op, error) {
+	parent, err := a.st.Action(a.doc.Parent)
+	if errors.IsNotFound(err) {
+		// The service action was finished directly; there is
+		// nothing left to record on it.
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	pdoc := parent.doc
+	finished := pdoc.Finished
+	pdoc.Finished++
+	ops, err := a.st.queueUnitActionOps(&pdoc)
+	if err != nil {
+		return nil, err
+	}
+	if pdoc.Finished < len(pdoc.Units) {
+		return append(ops, txn.Op{
+			C:      actionsC,
+			Id:     pdoc.Id,
+			Assert: bson.D{{"finished", finished}},
+			Update: bson.D{{"$set", bson.D{
+				{"children", pdoc.Children},
+				{"finished", pdoc.Finished},
+			}}},
+		}), nil
+	}
+	unitResults := make(map[string]unitActionResult)
+	for i, childId := range pdoc.Children {
+		if childId == a.doc.Id {
+			unitResults[pdoc.Units[i]] = unitActionResult{
+				Id:      childId,
+				Status:  result.Status,
+				Results: result.Results,
+				Output:  result.Output,
+			}
+			continue
+		}
+		unitResult, err := a.st.childActionResult(childId)
+		if err != nil {
+			return nil, err
+		}
+		unitResults[pdoc.Units[i]] = unitResult
+	}
+	status, results, output := summarizeUnitResults(unitResults)
+	doc := newActionResultDoc(newAction(a.st, pdoc), status, results, output)
+	return append(ops, txn.Op{
+		C:      actionsC,
+		Id:     pdoc.Id,
+		Assert: bson.D{{"finished", finished}},
+		Remove: true,
+	}, addActionResultOp(a.st, &doc)), nil
+}
+
+// childActionStatus returns the status of the unit action with the given
+// id, which was queued by a service action.
+func (st *State) childActionStatus(id string) (ActionStatus, error) {
+	result, err := st.childActionResult(id)
+	return result.Status, err
+}
+
+// childActionResult returns the outcome so far of the unit action with
+// the given id, which was queued by a service action.
+func (st *State) childActionResult(id string) (unitActionResult, error) {
+	if id == "" {
+		return unitActionResult{Status: ActionSkipped}, nil
+	}
+	if _, err := st.Action(id); err == nil {
+		return unitActionResult{Id: id, Status: ActionPending}, nil
+	} else if !errors.IsNotFound(err) {
+		return unitActionResult{}, err
+	}
+	resultId, ok := convertActionIdToActionResultId(id)
+	if !ok {
+		return unitActionResult{}, fmt.Errorf("cannot convert actionId to actionResultId: %v", id)
+	}
+	result, err := st.ActionResult(resultId)
+	if err != nil {
+		return unitActionResult{}, err
+	}
+	return unitActionResult{
+		Id:      id,
+		Status:  result.Status(),
+		Results: result.Results(),
+		Output:  result.Output(),
+	}, nil
+}
+
+// unitActionResult holds the outcome of a service action on one of the
+// service's units.
+type unitActionResult struct {
+	// Id holds the id of the unit action queued by the service
+	// action, or is empty if the unit was skipped.
+	Id string
+
+	Status  ActionStatus
+	Results map[string]interface{}
+	Output  string
+}
+
+// entry returns the entry for the unit in the results of the service
+// action.
+func (r unitActionResult) entry() map[string]interface{} {
+	entry := map[string]interface{}{
+		"status": string(r.Status),
+	}
+	if r.Id != "" {
+		entry["id"] = r.Id
+	}
+	if r.Results != nil {
+		entry["results"] = r.Results
+	}
+	if r.Output != "" {
+		entry["output"] = r.Output
+	}
+	return entry
+}
+
+// summarizeUnitResults computes the final status, results and output
+// of a service action from its outcome on each of the service's units.
+// The results hold an entry for each unit giving the status, id,
+// results and output of the unit action. The service action only
+// completes if it completed on every unit.
+func summarizeUnitResults(unitResults map[string]unitActionResult) (ActionStatus, map[string]interface{}, string) {
+	results := make(map[string]interface{})
+	completed := 0
+	for unitName, unitResult := range unitResults {
+		results[unitName] = unitResult.entry()
+		if unitResult.Status == ActionCompleted {
+			completed++
+		}
+	}
+	status := ActionCompleted
+	if completed < len(unitResults) {
+		status = ActionFailed
+	}
+	return status, results, fmt.Sprintf("%d of %d units completed", completed, len(unitResults))
+}
+
+// actionUnitNames returns the names of the live units of the service,
+// ordered by unit number, that an action queued on the service should
+// be run on.
+func (s *Service) actionUnitNames(firstUnitOnly bool) ([]string, error) {
+	units, err := s.AllUnits()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, unit := range units {
+		if unit.Life() != Dead {
+			names = append(names, unit.Name())
+		}
+	}
+	sort.Sort(unitNamesByNumber(names))
+	if firstUnitOnly && len(names) > 1 {
+		names = names[:1]
+	}
+	return names, nil
+}
+
+// unitNamesByNumber sorts unit names of a single service by unit number.
+type unitNamesByNumber []string
+
+func (u unitNamesByNumber) Len() int      { return len(u) }
+func (u unitNamesByNumber) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u unitNamesByNumber) Less(i, j int) bool {
+	return unitNumber(u[i]) < unitNumber(u[j])
+}
+
+func unitNumber(unitName string) int {
+	n, _ := strconv.Atoi(unitName[strings.LastIndex(unitName, "/")+1:])
+	return n
+}
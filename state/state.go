@@ -67,6 +67,15 @@ const (
 	stateServersC      = "stateServers"
 	openedPortsC       = "openedPorts"
 	metricsC           = "metrics"
+	workloadStatusesC  = "workloadstatuses"
+	statusHistoryC     = "statushistory"
+	auditC             = "auditlog"
+	loginAttemptsC     = "loginattempts"
+	rollingUpgradesC   = "rollingupgrades"
+	agentUpgradesC     = "agentupgrades"
+
+	// This capped collection holds the log records sent by agents.
+	logsC = "logs"
 
 	// This collection is used just for storing metadata.
 	backupsMetaC = "backupsmetadata"
@@ -244,6 +253,15 @@ func (st *State) checkCanUpgrade(currentVersion, newVersion string) error {
 	db, closer := st.newDB()
 	defer closer()
 
+	// Machines that stayed at the target version of a rolled back
+	// agent upgrade, because they had started its upgrade steps, may
+	// be upgraded again.
+	rolledBackVersion, stayed, err := st.agentsLeftByRollBack()
+	if err != nil {
+		return err
+	}
+	matchRolledBack := rolledBackVersion.String() + "-"
+
 	matchCurrent := "^" + regexp.QuoteMeta(currentVersion) + "-"
 	matchNew := "^" + regexp.QuoteMeta(newVersion) + "-"
 	// Get all machines and units with a different or empty version.
@@ -259,12 +277,18 @@ func (st *State) checkCanUpgrade(currentVersion, newVersion string) error {
 		collection := db.C(name)
 
 		var doc struct {
-			Id string `bson:"_id"`
+			Id    string `bson:"_id"`
+			Tools *struct {
+				Version string `bson:"version"`
+			} `bson:"tools"`
 		}
-		iter := collection.Find(sel).Select(bson.D{{"_id", 1}}).Iter()
+		iter := collection.Find(sel).Select(bson.D{{"_id", 1}, {"tools.version", 1}}).Iter()
 		for iter.Next(&doc) {
 			switch name {
 			case machinesC:
+				if doc.Tools != nil && strings.HasPrefix(doc.Tools.Version, matchRolledBack) && containsString(stayed, doc.Id) {
+					continue
+				}
 				agentTags = append(agentTags, names.NewMachineTag(doc.Id).String())
 			case unitsC:
 				agentTags = append(agentTags, names.NewUnitTag(doc.Id).String())
@@ -1555,6 +1579,17 @@ func (st *State) ActionResult(id string) (*ActionResult, error) {
 	return newActionResult(st, doc), nil
 }
 
+// ActionResultByActionTag returns the ActionResult recorded when the
+// Action identified by the given tag was completed or failed.
+func (st *State) ActionResultByActionTag(tag names.ActionTag) (*ActionResult, error) {
+	actionId := actionIdFromTag(tag)
+	id, ok := convertActionIdToActionResultId(actionId)
+	if !ok {
+		return nil, errors.NotFoundf("action result for action %q", actionId)
+	}
+	return st.ActionResult(id)
+}
+
 // matchingActionResults finds actions that match name
 func (st *State) matchingActionResults(ar ActionReceiver) ([]*ActionResult, error) {
 	var doc actionResultDoc
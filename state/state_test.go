@@ -2311,7 +2311,7 @@ var entityTypes = map[string]interface{}{
 func (s *StateSuite) TestFindEntity(c *gc.C) {
 	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, gc.IsNil)
-	svc := s.AddTestingService(c, "ser-vice2", s.AddTestingCharm(c, "mysql"))
+	svc := s.AddTestingService(c, "ser-vice2", s.AddActionsCharm(c, "mysql", testActionsYaml, 1))
 	unit, err := svc.AddUnit()
 	c.Assert(err, gc.IsNil)
 	_, err = unit.AddAction("fakeaction", nil)
@@ -2399,7 +2399,7 @@ func (s *StateSuite) TestParseUnitTag(c *gc.C) {
 }
 
 func (s *StateSuite) TestParseActionTag(c *gc.C) {
-	svc := s.AddTestingService(c, "service2", s.AddTestingCharm(c, "dummy"))
+	svc := s.AddTestingService(c, "service2", s.AddActionsCharm(c, "dummy", testActionsYaml, 1))
 	u, err := svc.AddUnit()
 	c.Assert(err, gc.IsNil)
 	f, err := u.AddAction("fakeaction", nil)
@@ -3366,7 +3366,7 @@ func (s *StateSuite) TestWatchAPIHostPorts(c *gc.C) {
 
 func (s *StateSuite) TestUnitActionsFindsRightActions(c *gc.C) {
 	// Add simple service and two units
-	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	mysql := s.AddTestingService(c, "mysql", s.AddActionsCharm(c, "mysql", testActionsYaml, 1))
 
 	unit1, err := mysql.AddUnit()
 	c.Assert(err, gc.IsNil)
@@ -3375,16 +3375,16 @@ func (s *StateSuite) TestUnitActionsFindsRightActions(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 
 	// Add 3 actions to first unit, and 2 to the second unit
-	_, err = unit1.AddAction("action1.1", nil)
+	_, err = unit1.AddAction("snapshot", nil)
 	c.Assert(err, gc.IsNil)
-	_, err = unit1.AddAction("action1.2", nil)
+	_, err = unit1.AddAction("backup", nil)
 	c.Assert(err, gc.IsNil)
-	_, err = unit1.AddAction("action1.3", nil)
+	_, err = unit1.AddAction("fakeaction", nil)
 	c.Assert(err, gc.IsNil)
 
-	_, err = unit2.AddAction("action2.1", nil)
+	_, err = unit2.AddAction("snapshot", nil)
 	c.Assert(err, gc.IsNil)
-	_, err = unit2.AddAction("action2.2", nil)
+	_, err = unit2.AddAction("backup", nil)
 	c.Assert(err, gc.IsNil)
 
 	// Verify that calling Actions on unit1 returns only
@@ -3393,7 +3393,7 @@ func (s *StateSuite) TestUnitActionsFindsRightActions(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	c.Assert(len(actions1), gc.Equals, 3)
 	for _, action := range actions1 {
-		c.Assert(action.Name(), gc.Matches, "^action1\\..")
+		c.Assert(action.Prefix(), gc.Equals, unit1.Name())
 	}
 
 	// Verify that calling Actions on unit2 returns only
@@ -3402,12 +3402,12 @@ func (s *StateSuite) TestUnitActionsFindsRightActions(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	c.Assert(len(actions2), gc.Equals, 2)
 	for _, action := range actions2 {
-		c.Assert(action.Name(), gc.Matches, "^action2\\..")
+		c.Assert(action.Prefix(), gc.Equals, unit2.Name())
 	}
 }
 
 func (s *StateSuite) TestWatchActions(c *gc.C) {
-	svc := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	svc := s.AddTestingService(c, "mysql", s.AddActionsCharm(c, "mysql", testActionsYaml, 1))
 	u, err := svc.AddUnit()
 	c.Assert(err, gc.IsNil)
 
@@ -3418,11 +3418,11 @@ func (s *StateSuite) TestWatchActions(c *gc.C) {
 	wc.AssertNoChange()
 
 	// add 3 actions
-	_, err = u.AddAction("fakeaction1", nil)
+	_, err = u.AddAction("snapshot", nil)
 	c.Assert(err, gc.IsNil)
-	fa2, err := u.AddAction("fakeaction2", nil)
+	fa2, err := u.AddAction("backup", nil)
 	c.Assert(err, gc.IsNil)
-	_, err = u.AddAction("fakeaction3", nil)
+	_, err = u.AddAction("fakeaction", nil)
 	c.Assert(err, gc.IsNil)
 
 	// fail the middle one
@@ -0,0 +1,126 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/state/api/params"
+)
+
+// statusHistoryDoc records a status set for an entity, so that past
+// statuses are still known after the entity's statusDoc is updated.
+// EntityId is the global key of the entity.
+type statusHistoryDoc struct {
+	Id         bson.ObjectId     `bson:"_id"`
+	EntityId   string            `bson:"entityid"`
+	Status     params.Status     `bson:"status"`
+	StatusInfo string            `bson:"statusinfo"`
+	StatusData params.StatusData `bson:"statusdata"`
+	Updated    time.Time         `bson:"updated"`
+}
+
+// StatusInfo holds a status recorded in the status history of an
+// entity, and the time it was set.
+type StatusInfo struct {
+	Status  params.Status
+	Message string
+	Data    params.StatusData
+	Since   time.Time
+}
+
+// addStatusHistoryOp returns the operation needed to record the given
+// status in the history of the entity with the given globalKey.
+func addStatusHistoryOp(st *State, globalKey string, doc statusDoc) txn.Op {
+	id := bson.NewObjectId()
+	return txn.Op{
+		C:      statusHistoryC,
+		Id:     id,
+		Assert: txn.DocMissing,
+		Insert: &statusHistoryDoc{
+			Id:         id,
+			EntityId:   globalKey,
+			Status:     doc.Status,
+			StatusInfo: doc.StatusInfo,
+			StatusData: doc.StatusData,
+			Updated:    time.Now().UTC(),
+		},
+	}
+}
+
+// statusHistory returns at most size statuses recorded for the entity
+// with the given globalKey, most recent first.
+func statusHistory(st *State, globalKey string, size int) ([]StatusInfo, error) {
+	if size <= 0 {
+		return nil, errors.Errorf("invalid status history size %d", size)
+	}
+	history, closer := st.getCollection(statusHistoryC)
+	defer closer()
+
+	var docs []statusHistoryDoc
+	err := history.Find(bson.D{{"entityid", globalKey}}).Sort("-updated", "-_id").Limit(size).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get status history of %q", globalKey)
+	}
+	results := make([]StatusInfo, len(docs))
+	for i, doc := range docs {
+		results[i] = StatusInfo{
+			Status:  doc.Status,
+			Message: doc.StatusInfo,
+			Data:    doc.StatusData,
+			Since:   doc.Updated,
+		}
+	}
+	return results, nil
+}
+
+// PruneStatusHistory removes the statuses recorded more than maxAge
+// ago, and all but the maxPerEntity most recent statuses of each
+// entity. A zero value of either disables that limit.
+func (st *State) PruneStatusHistory(maxAge time.Duration, maxPerEntity int) error {
+	history, closer := st.getCollection(statusHistoryC)
+	defer closer()
+
+	if maxAge > 0 {
+		before := time.Now().Add(-maxAge)
+		if _, err := history.RemoveAll(bson.D{{"updated", bson.D{{"$lt", before}}}}); err != nil {
+			return errors.Annotate(err, "cannot remove old status history")
+		}
+	}
+	if maxPerEntity <= 0 {
+		return nil
+	}
+	var entityIds []string
+	if err := history.Find(nil).Distinct("entityid", &entityIds); err != nil {
+		return errors.Annotate(err, "cannot get status history entities")
+	}
+	for _, entityId := range entityIds {
+		var docs []struct {
+			Id bson.ObjectId `bson:"_id"`
+		}
+		err := history.Find(bson.D{{"entityid", entityId}}).
+			Sort("-updated", "-_id").
+			Skip(maxPerEntity).
+			Select(bson.D{{"_id", 1}}).
+			All(&docs)
+		if err != nil {
+			return errors.Annotatef(err, "cannot get status history of %q", entityId)
+		}
+		if len(docs) == 0 {
+			continue
+		}
+		ids := make([]bson.ObjectId, len(docs))
+		for i, doc := range docs {
+			ids[i] = doc.Id
+		}
+		if _, err := history.RemoveAll(bson.D{{"_id", bson.D{{"$in", ids}}}}); err != nil {
+			return errors.Annotatef(err, "cannot prune status history of %q", entityId)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+)
+
+type StatusHistorySuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&StatusHistorySuite{})
+
+func (s *StatusHistorySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var err error
+	s.unit, err = svc.AddUnit()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *StatusHistorySuite) setStatuses(c *gc.C, infos ...string) {
+	for _, info := range infos {
+		err := s.unit.SetStatus(params.StatusError, info, params.StatusData{"info": info})
+		c.Assert(err, gc.IsNil)
+	}
+}
+
+func (s *StatusHistorySuite) assertHistory(c *gc.C, size int, expect ...string) {
+	history, err := s.unit.StatusHistory(size)
+	c.Assert(err, gc.IsNil)
+	c.Assert(history, gc.HasLen, len(expect))
+	for i, info := range expect {
+		c.Check(history[i].Status, gc.Equals, params.StatusError)
+		c.Check(history[i].Message, gc.Equals, info)
+		c.Check(history[i].Data, gc.DeepEquals, params.StatusData{"info": info})
+	}
+}
+
+func (s *StatusHistorySuite) TestUnitStatusHistory(c *gc.C) {
+	s.assertHistory(c, 10)
+
+	before := time.Now()
+	s.setStatuses(c, "first", "second", "third")
+	s.assertHistory(c, 10, "third", "second", "first")
+	s.assertHistory(c, 2, "third", "second")
+
+	history, err := s.unit.StatusHistory(1)
+	c.Assert(err, gc.IsNil)
+	c.Assert(history[0].Since.Before(before.Add(-time.Second)), gc.Equals, false)
+	c.Assert(history[0].Since.After(time.Now()), gc.Equals, false)
+}
+
+func (s *StatusHistorySuite) TestStatusHistoryInvalidSize(c *gc.C) {
+	_, err := s.unit.StatusHistory(0)
+	c.Assert(err, gc.ErrorMatches, "invalid status history size 0")
+}
+
+func (s *StatusHistorySuite) TestStatusHistoryNotRecordedOnFailure(c *gc.C) {
+	err := s.unit.SetStatus(params.StatusDown, "", nil)
+	c.Assert(err, gc.NotNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, gc.IsNil)
+	err = s.unit.SetStatus(params.StatusStarted, "", nil)
+	c.Assert(err, gc.NotNil)
+	s.assertHistory(c, 10)
+}
+
+func (s *StatusHistorySuite) TestMachineStatusHistory(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	err = machine.SetStatus(params.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
+	err = machine.SetStatus(params.StatusStopped, "", nil)
+	c.Assert(err, gc.IsNil)
+
+	history, err := machine.StatusHistory(10)
+	c.Assert(err, gc.IsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Status, gc.Equals, params.StatusStopped)
+	c.Assert(history[1].Status, gc.Equals, params.StatusStarted)
+
+	// The unit's history is kept apart.
+	s.assertHistory(c, 10)
+}
+
+func (s *StatusHistorySuite) TestPruneStatusHistoryBySize(c *gc.C) {
+	s.setStatuses(c, "first", "second", "third")
+	err := s.State.PruneStatusHistory(0, 2)
+	c.Assert(err, gc.IsNil)
+	s.assertHistory(c, 10, "third", "second")
+}
+
+func (s *StatusHistorySuite) TestPruneStatusHistoryByAge(c *gc.C) {
+	s.setStatuses(c, "first", "second")
+	state.AgeStatusHistory(c, s.State, 2*time.Hour)
+	s.setStatuses(c, "third")
+
+	err := s.State.PruneStatusHistory(time.Hour, 0)
+	c.Assert(err, gc.IsNil)
+	s.assertHistory(c, 10, "third")
+}
+
+func (s *StatusHistorySuite) TestPruneStatusHistoryNoLimits(c *gc.C) {
+	s.setStatuses(c, "first", "second")
+	err := s.State.PruneStatusHistory(0, 0)
+	c.Assert(err, gc.IsNil)
+	s.assertHistory(c, 10, "second", "first")
+}
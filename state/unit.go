@@ -80,6 +80,10 @@ type unitDoc struct {
 	TxnRevno     int64 `bson:"txn-revno"`
 	PasswordHash string
 
+	// CharmUpgradeHeld is set while a rolling upgrade of the unit's
+	// service holds the unit back from upgrading its charm.
+	CharmUpgradeHeld bool `bson:",omitempty"`
+
 	// No longer used - to be removed.
 	PublicAddress  string
 	PrivateAddress string
@@ -721,6 +725,7 @@ func (u *Unit) SetStatus(status params.Status, info string, data params.StatusDa
 		Assert: notDeadDoc,
 	},
 		updateStatusOp(u.st, u.globalKey(), doc),
+		addStatusHistoryOp(u.st, u.globalKey(), doc),
 	}
 	err := u.st.runTransaction(ops)
 	if err != nil {
@@ -729,6 +734,64 @@ func (u *Unit) SetStatus(status params.Status, info string, data params.StatusDa
 	return nil
 }
 
+// StatusHistory returns at most size statuses recently set for the
+// unit, most recent first.
+func (u *Unit) StatusHistory(size int) ([]StatusInfo, error) {
+	return statusHistory(u.st, u.globalKey(), size)
+}
+
+// WorkloadStatus returns the status of the unit's workload, as last set
+// by its charm, and the accompanying message.
+func (u *Unit) WorkloadStatus() (status params.WorkloadStatus, info string, err error) {
+	doc, err := getWorkloadStatus(u.st, u.globalKey())
+	if errors.IsNotFound(err) {
+		return params.WorkloadUnknown, "", nil
+	} else if err != nil {
+		return "", "", err
+	}
+	return doc.Status, doc.StatusInfo, nil
+}
+
+// SetWorkloadStatus sets the status of the unit's workload, along with
+// a message for the operator.
+func (u *Unit) SetWorkloadStatus(status params.WorkloadStatus, info string) error {
+	if !status.Valid() {
+		return fmt.Errorf("cannot set invalid workload status %q", status)
+	}
+	doc := workloadStatusDoc{
+		Status:     status,
+		StatusInfo: info,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if notDead, err := isNotDead(u.st.db, unitsC, u.doc.Name); err != nil {
+				return nil, err
+			} else if !notDead {
+				return nil, errDead
+			}
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.Name,
+			Assert: notDeadDoc,
+		}}
+		_, err := getWorkloadStatus(u.st, u.globalKey())
+		if errors.IsNotFound(err) {
+			// The unit was added before workload status was recorded.
+			ops = append(ops, createWorkloadStatusOp(u.st, u.globalKey(), doc))
+		} else if err != nil {
+			return nil, err
+		} else {
+			ops = append(ops, updateWorkloadStatusOp(u.st, u.globalKey(), doc))
+		}
+		return ops, nil
+	}
+	if err := u.st.run(buildTxn); err != nil {
+		return fmt.Errorf("cannot set workload status of unit %q: %v", u, err)
+	}
+	return nil
+}
+
 // OpenPort sets the policy of the port with protocol and number to be opened.
 func (u *Unit) OpenPort(protocol string, number int) (err error) {
 	ports, err := NewPortRange(u.Name(), number, number, protocol)
@@ -1612,6 +1675,13 @@ func (u *Unit) UnassignFromMachine() (err error) {
 // AddAction adds a new Action of type name and using arguments payload to
 // this Unit, and returns its ID
 func (u *Unit) AddAction(name string, payload map[string]interface{}) (*Action, error) {
+	ch, err := u.actionCharm()
+	if err != nil {
+		return nil, fmt.Errorf("cannot add action; %v", err)
+	}
+	if payload, err = validateActionPayload(ch, name, payload); err != nil {
+		return nil, err
+	}
 	doc, err := newActionDoc(u.st, u, name, payload)
 	if err != nil {
 		return nil, fmt.Errorf("cannot add action; %v", err)
@@ -1641,6 +1711,20 @@ func (u *Unit) AddAction(name string, payload map[string]interface{}) (*Action,
 	return nil, err
 }
 
+// actionCharm returns the charm whose actions may be queued on the unit:
+// the unit's own charm if it has one, otherwise its service's charm.
+func (u *Unit) actionCharm() (*Charm, error) {
+	if u.doc.CharmURL != nil {
+		return u.st.Charm(u.doc.CharmURL)
+	}
+	svc, err := u.Service()
+	if err != nil {
+		return nil, err
+	}
+	ch, _, err := svc.Charm()
+	return ch, err
+}
+
 // Actions returns a list of actions for this unit
 func (u *Unit) Actions() ([]*Action, error) {
 	return u.st.matchingActions(u)
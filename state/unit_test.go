@@ -580,6 +580,58 @@ func (s *UnitSuite) TestGetSetStatusWhileNotAlive(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "status not found")
 }
 
+func (s *UnitSuite) TestGetSetWorkloadStatus(c *gc.C) {
+	status, info, err := s.unit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.WorkloadUnknown)
+	c.Assert(info, gc.Equals, "")
+
+	err = s.unit.SetWorkloadStatus(params.WorkloadUnknown, "")
+	c.Assert(err, gc.ErrorMatches, `cannot set invalid workload status "unknown"`)
+	err = s.unit.SetWorkloadStatus(params.WorkloadStatus("vliegkat"), "orville")
+	c.Assert(err, gc.ErrorMatches, `cannot set invalid workload status "vliegkat"`)
+
+	err = s.unit.SetWorkloadStatus(params.WorkloadWaiting, "waiting for database relation")
+	c.Assert(err, gc.IsNil)
+	status, info, err = s.unit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.WorkloadWaiting)
+	c.Assert(info, gc.Equals, "waiting for database relation")
+
+	err = s.unit.SetWorkloadStatus(params.WorkloadActive, "")
+	c.Assert(err, gc.IsNil)
+	status, info, err = s.unit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.WorkloadActive)
+	c.Assert(info, gc.Equals, "")
+
+	// The agent status is not affected.
+	agentStatus, _, _, err := s.unit.Status()
+	c.Assert(err, gc.IsNil)
+	c.Assert(agentStatus, gc.Equals, params.StatusPending)
+}
+
+func (s *UnitSuite) TestSetWorkloadStatusWithoutDocument(c *gc.C) {
+	state.RemoveWorkloadStatus(c, s.unit)
+	status, _, err := s.unit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.WorkloadUnknown)
+
+	err = s.unit.SetWorkloadStatus(params.WorkloadBlocked, "needs a licence key")
+	c.Assert(err, gc.IsNil)
+	status, info, err := s.unit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.WorkloadBlocked)
+	c.Assert(info, gc.Equals, "needs a licence key")
+}
+
+func (s *UnitSuite) TestSetWorkloadStatusWhenDead(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, gc.IsNil)
+	err = s.unit.SetWorkloadStatus(params.WorkloadActive, "")
+	c.Assert(err, gc.ErrorMatches, `cannot set workload status of unit "wordpress/0": not found or dead`)
+}
+
 func (s *UnitSuite) TestGetSetStatusDataStandard(c *gc.C) {
 	err := s.unit.SetStatus(params.StatusStarted, "", nil)
 	c.Assert(err, gc.IsNil)
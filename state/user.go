@@ -40,11 +40,60 @@ func (st *State) AddAdminUser(password string) (*User, error) {
 	return st.AddUser(AdminUser, "", password, "")
 }
 
-// AddUser adds a user to the database.
+// UserAccess is the level of access a user has to the environment
+// through the API.
+type UserAccess string
+
+const (
+	// UserAccessRead allows a user to see the environment, but not
+	// to change it.
+	UserAccessRead UserAccess = "read"
+
+	// UserAccessWrite additionally allows a user to change the
+	// environment.
+	UserAccessWrite UserAccess = "write"
+
+	// UserAccessAdmin additionally allows a user to manage the
+	// environment's users, keys and backups.
+	UserAccessAdmin UserAccess = "admin"
+)
+
+// userAccessRanks orders the access levels; each level allows all
+// that the lower ones do.
+var userAccessRanks = map[UserAccess]int{
+	UserAccessRead:  1,
+	UserAccessWrite: 2,
+	UserAccessAdmin: 3,
+}
+
+// Validate returns an error if the access level is not known.
+func (access UserAccess) Validate() error {
+	if _, ok := userAccessRanks[access]; !ok {
+		return errors.NotValidf("user access %q", string(access))
+	}
+	return nil
+}
+
+// Allows returns whether the access level includes the given one.
+func (access UserAccess) Allows(required UserAccess) bool {
+	rank, ok := userAccessRanks[access]
+	return ok && rank >= userAccessRanks[required]
+}
+
+// AddUser adds a user to the database, with admin access.
 func (st *State) AddUser(name, displayName, password, creator string) (*User, error) {
+	return st.AddUserWithAccess(name, displayName, password, creator, UserAccessAdmin)
+}
+
+// AddUserWithAccess adds a user to the database, with the given
+// access to the environment.
+func (st *State) AddUserWithAccess(name, displayName, password, creator string, access UserAccess) (*User, error) {
 	if !names.IsValidUser(name) {
 		return nil, errors.Errorf("invalid user name %q", name)
 	}
+	if err := access.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
 	salt, err := utils.RandomSalt()
 	if err != nil {
 		return nil, err
@@ -58,6 +107,7 @@ func (st *State) AddUser(name, displayName, password, creator string) (*User, er
 			PasswordSalt: salt,
 			CreatedBy:    creator,
 			DateCreated:  nowToTheSecond(),
+			Access:       access,
 		},
 	}
 	ops := []txn.Op{{
@@ -98,6 +148,23 @@ func (st *State) User(name string) (*User, error) {
 	return user, nil
 }
 
+// AllUsers returns all the users in the database, including the
+// deactivated ones, ordered by name.
+func (st *State) AllUsers() ([]*User, error) {
+	users, closer := st.getCollection(usersC)
+	defer closer()
+
+	var docs []userDoc
+	if err := users.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get all users")
+	}
+	result := make([]*User, len(docs))
+	for i, doc := range docs {
+		result[i] = &User{st: st, doc: doc}
+	}
+	return result, nil
+}
+
 // User represents a local user in the database.
 type User struct {
 	st  *State
@@ -114,6 +181,9 @@ type userDoc struct {
 	CreatedBy    string     `bson:"createdby"`
 	DateCreated  time.Time  `bson:"datecreated"`
 	LastLogin    *time.Time `bson:"lastlogin"`
+	// Users added before access levels were introduced have none,
+	// and keep the full access they had.
+	Access UserAccess `bson:"access,omitempty"`
 }
 
 // String returns "<name>@local" where <name> is the Name of the user.
@@ -159,6 +229,39 @@ func (u *User) LastLogin() *time.Time {
 	return &result
 }
 
+// Access returns the level of access the User has to the environment.
+func (u *User) Access() UserAccess {
+	if u.doc.Access == "" {
+		return UserAccessAdmin
+	}
+	return u.doc.Access
+}
+
+// SetAccess sets the level of access the User has to the environment.
+// The admin user always keeps admin access.
+func (u *User) SetAccess(access UserAccess) error {
+	if err := access.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if u.doc.Name == AdminUser && access != UserAccessAdmin {
+		return errors.Unauthorizedf("cannot change access of admin user")
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     u.Name(),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"access", access}}}},
+	}}
+	if err := u.st.runTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = fmt.Errorf("user no longer exists")
+		}
+		return errors.Annotatef(err, "cannot set access of user %q", u.Name())
+	}
+	u.doc.Access = access
+	return nil
+}
+
 // nowToTheSecond returns the current time in UTC to the nearest second.
 func nowToTheSecond() time.Time {
 	return time.Now().Round(time.Second).UTC()
@@ -7,11 +7,13 @@ import (
 	"regexp"
 	"time"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "launchpad.net/gocheck"
 
 	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/testing/factory"
 )
 
@@ -197,3 +199,113 @@ func (s *UserSuite) TestCantDeactivateAdmin(c *gc.C) {
 	err = user.Deactivate()
 	c.Assert(err, gc.ErrorMatches, "cannot deactivate admin user")
 }
+
+func (s *UserSuite) TestAddUserHasAdminAccess(c *gc.C) {
+	user, err := s.State.AddUser("bob", "", "password", "admin")
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.Access(), gc.Equals, state.UserAccessAdmin)
+}
+
+func (s *UserSuite) TestAddUserWithAccess(c *gc.C) {
+	user, err := s.State.AddUserWithAccess("bob", "", "password", "admin", state.UserAccessRead)
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.Access(), gc.Equals, state.UserAccessRead)
+
+	user, err = s.State.User("bob")
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.Access(), gc.Equals, state.UserAccessRead)
+}
+
+func (s *UserSuite) TestAddUserWithInvalidAccess(c *gc.C) {
+	user, err := s.State.AddUserWithAccess("bob", "", "password", "admin", "superuser")
+	c.Assert(err, gc.ErrorMatches, `user access "superuser" not valid`)
+	c.Assert(user, gc.IsNil)
+	_, err = s.State.User("bob")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UserSuite) TestSetAccess(c *gc.C) {
+	user := s.factory.MakeUser(c, nil)
+	c.Assert(user.Access(), gc.Equals, state.UserAccessAdmin)
+
+	err := user.SetAccess(state.UserAccessWrite)
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.Access(), gc.Equals, state.UserAccessWrite)
+
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.Access(), gc.Equals, state.UserAccessWrite)
+
+	err = user.SetAccess("superuser")
+	c.Assert(err, gc.ErrorMatches, `user access "superuser" not valid`)
+	c.Assert(user.Access(), gc.Equals, state.UserAccessWrite)
+}
+
+func (s *UserSuite) TestCantChangeAdminAccess(c *gc.C) {
+	user, err := s.State.User(state.AdminUser)
+	c.Assert(err, gc.IsNil)
+	err = user.SetAccess(state.UserAccessRead)
+	c.Assert(err, gc.ErrorMatches, "cannot change access of admin user")
+	c.Assert(user.Access(), gc.Equals, state.UserAccessAdmin)
+}
+
+func (s *UserSuite) TestUserAccessAllows(c *gc.C) {
+	for i, test := range []struct {
+		access   state.UserAccess
+		required state.UserAccess
+		allowed  bool
+	}{
+		{state.UserAccessRead, state.UserAccessRead, true},
+		{state.UserAccessRead, state.UserAccessWrite, false},
+		{state.UserAccessRead, state.UserAccessAdmin, false},
+		{state.UserAccessWrite, state.UserAccessRead, true},
+		{state.UserAccessWrite, state.UserAccessWrite, true},
+		{state.UserAccessWrite, state.UserAccessAdmin, false},
+		{state.UserAccessAdmin, state.UserAccessRead, true},
+		{state.UserAccessAdmin, state.UserAccessWrite, true},
+		{state.UserAccessAdmin, state.UserAccessAdmin, true},
+		{"", state.UserAccessRead, false},
+	} {
+		c.Logf("test %d: %q requires %q", i, test.access, test.required)
+		c.Check(test.access.Allows(test.required), gc.Equals, test.allowed)
+	}
+}
+
+func (s *UserSuite) TestAllUsers(c *gc.C) {
+	s.factory.MakeUser(c, &factory.UserParams{Name: "zoe"})
+	bob := s.factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	err := bob.Deactivate()
+	c.Assert(err, gc.IsNil)
+
+	users, err := s.State.AllUsers()
+	c.Assert(err, gc.IsNil)
+	var names []string
+	for _, user := range users {
+		names = append(names, user.Name())
+	}
+	c.Assert(names, jc.DeepEquals, []string{"admin", "bob", "zoe"})
+	c.Assert(users[1].IsDeactivated(), jc.IsTrue)
+}
+
+func (s *UserSuite) TestWatch(c *gc.C) {
+	user := s.factory.MakeUser(c, nil)
+	w := user.Watch()
+	defer statetesting.AssertStop(c, w)
+
+	// Initial event.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := user.Deactivate()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	err = user.Activate()
+	c.Assert(err, gc.IsNil)
+	err = user.SetAccess(state.UserAccessRead)
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
@@ -1197,6 +1197,11 @@ func (e *Environment) Watch() NotifyWatcher {
 	return newEntityWatcher(e.st, environmentsC, e.doc.UUID)
 }
 
+// Watch returns a watcher for observing changes to a user.
+func (u *User) Watch() NotifyWatcher {
+	return newEntityWatcher(u.st, usersC, u.doc.Name)
+}
+
 // WatchForEnvironConfigChanges returns a NotifyWatcher waiting for the Environ
 // Config to change. This differs from WatchEnvironConfig in that the watcher
 // is a NotifyWatcher that does not give content during Changes()
@@ -1224,7 +1229,28 @@ func (u *Unit) WatchConfigSettings() (NotifyWatcher, error) {
 	return newEntityWatcher(u.st, settingsC, settingsKey), nil
 }
 
+// WatchAgentVersion returns a NotifyWatcher that notifies when the
+// environment's agent version, or the staged agent upgrade, changes.
+func (st *State) WatchAgentVersion() NotifyWatcher {
+	return newDocsWatcher(st,
+		docKey{settingsC, environGlobalKey},
+		docKey{agentUpgradesC, environGlobalKey},
+	)
+}
+
 func newEntityWatcher(st *State, collName string, key string) NotifyWatcher {
+	return newDocsWatcher(st, docKey{collName, key})
+}
+
+// docKey identifies a document watched by an entityWatcher.
+type docKey struct {
+	collName string
+	key      string
+}
+
+// newDocsWatcher returns a NotifyWatcher that notifies when any of the
+// given documents changes.
+func newDocsWatcher(st *State, docs ...docKey) NotifyWatcher {
 	w := &entityWatcher{
 		commonWatcher: commonWatcher{st: st},
 		out:           make(chan struct{}),
@@ -1232,7 +1258,7 @@ func newEntityWatcher(st *State, collName string, key string) NotifyWatcher {
 	go func() {
 		defer w.tomb.Done()
 		defer close(w.out)
-		w.tomb.Kill(w.loop(collName, key))
+		w.tomb.Kill(w.loop(docs))
 	}()
 	return w
 }
@@ -1259,16 +1285,18 @@ func getTxnRevno(coll *mgo.Collection, key string) (int64, error) {
 	return doc.TxnRevno, nil
 }
 
-func (w *entityWatcher) loop(collName string, key string) error {
-	coll, closer := w.st.getCollection(collName)
-	txnRevno, err := getTxnRevno(coll, key)
-	closer()
-	if err != nil {
-		return err
-	}
+func (w *entityWatcher) loop(docs []docKey) error {
 	in := make(chan watcher.Change)
-	w.st.watcher.Watch(coll.Name, key, txnRevno, in)
-	defer w.st.watcher.Unwatch(coll.Name, key, in)
+	for _, doc := range docs {
+		coll, closer := w.st.getCollection(doc.collName)
+		txnRevno, err := getTxnRevno(coll, doc.key)
+		closer()
+		if err != nil {
+			return err
+		}
+		w.st.watcher.Watch(coll.Name, doc.key, txnRevno, in)
+		defer w.st.watcher.Unwatch(coll.Name, doc.key, in)
+	}
 	out := w.out
 	for {
 		select {
@@ -0,0 +1,73 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/state/api/params"
+)
+
+// workloadStatusDoc represents the workload status of a unit, as set
+// by its charm, in Mongodb. The _id field is the global key of the
+// unit. Units created before workload status was recorded have no
+// document until their charm first sets it.
+type workloadStatusDoc struct {
+	Status     params.WorkloadStatus `bson:"status"`
+	StatusInfo string                `bson:"statusinfo"`
+}
+
+// getWorkloadStatus returns the workload status document associated
+// with the given globalKey.
+func getWorkloadStatus(st *State, globalKey string) (workloadStatusDoc, error) {
+	statuses, closer := st.getCollection(workloadStatusesC)
+	defer closer()
+
+	var doc workloadStatusDoc
+	err := statuses.FindId(globalKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return workloadStatusDoc{}, errors.NotFoundf("workload status")
+	}
+	if err != nil {
+		return workloadStatusDoc{}, fmt.Errorf("cannot get workload status %q: %v", globalKey, err)
+	}
+	return doc, nil
+}
+
+// createWorkloadStatusOp returns the operation needed to create the
+// workload status document associated with the given globalKey.
+func createWorkloadStatusOp(st *State, globalKey string, doc workloadStatusDoc) txn.Op {
+	return txn.Op{
+		C:      workloadStatusesC,
+		Id:     globalKey,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}
+}
+
+// updateWorkloadStatusOp returns the operation needed to update the
+// workload status document associated with the given globalKey.
+func updateWorkloadStatusOp(st *State, globalKey string, doc workloadStatusDoc) txn.Op {
+	return txn.Op{
+		C:      workloadStatusesC,
+		Id:     globalKey,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", doc}},
+	}
+}
+
+// removeWorkloadStatusOp returns the operation needed to remove the
+// workload status document associated with the given globalKey.
+func removeWorkloadStatusOp(st *State, globalKey string) txn.Op {
+	return txn.Op{
+		C:      workloadStatusesC,
+		Id:     globalKey,
+		Remove: true,
+	}
+}
@@ -36,6 +36,7 @@ type UserParams struct {
 	DisplayName string
 	Password    string
 	Creator     string
+	Access      state.UserAccess
 }
 
 // CharmParams defines the parameters for creating a charm.
@@ -123,8 +124,11 @@ func (factory *Factory) MakeUser(c *gc.C, params *UserParams) *state.User {
 	if params.Creator == "" {
 		params.Creator = "admin"
 	}
-	user, err := factory.st.AddUser(
-		params.Name, params.DisplayName, params.Password, params.Creator)
+	if params.Access == "" {
+		params.Access = state.UserAccessAdmin
+	}
+	user, err := factory.st.AddUserWithAccess(
+		params.Name, params.DisplayName, params.Password, params.Creator, params.Access)
 	c.Assert(err, gc.IsNil)
 	return user
 }
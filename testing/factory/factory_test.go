@@ -83,6 +83,7 @@ func (s *factorySuite) TestMakeUserNil(c *gc.C) {
 	c.Assert(saved.DateCreated(), gc.Equals, user.DateCreated())
 	c.Assert(saved.LastLogin(), gc.Equals, user.LastLogin())
 	c.Assert(saved.IsDeactivated(), gc.Equals, user.IsDeactivated())
+	c.Assert(saved.Access(), gc.Equals, user.Access())
 }
 
 func (s *factorySuite) TestMakeUserParams(c *gc.C) {
@@ -95,9 +96,11 @@ func (s *factorySuite) TestMakeUserParams(c *gc.C) {
 		DisplayName: displayName,
 		Creator:     creator,
 		Password:    password,
+		Access:      state.UserAccessRead,
 	})
 	c.Assert(user.IsDeactivated(), jc.IsFalse)
 	c.Assert(user.Name(), gc.Equals, username)
+	c.Assert(user.Access(), gc.Equals, state.UserAccessRead)
 	c.Assert(user.DisplayName(), gc.Equals, displayName)
 	c.Assert(user.CreatedBy(), gc.Equals, creator)
 	c.Assert(user.PasswordValid(password), jc.IsTrue)
@@ -111,6 +114,7 @@ func (s *factorySuite) TestMakeUserParams(c *gc.C) {
 	c.Assert(saved.DateCreated(), gc.Equals, user.DateCreated())
 	c.Assert(saved.LastLogin(), gc.Equals, user.LastLogin())
 	c.Assert(saved.IsDeactivated(), gc.Equals, user.IsDeactivated())
+	c.Assert(saved.Access(), gc.Equals, user.Access())
 }
 
 func (s *factorySuite) TestMakeMachineNil(c *gc.C) {
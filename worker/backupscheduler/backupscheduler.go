@@ -0,0 +1,239 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The backupscheduler package implements the state server worker that
+// takes backups of juju state on the schedule set by the environment
+// configuration, and removes the backups that the configured retention
+// policy no longer keeps.
+package backupscheduler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/watcher"
+)
+
+var logger = loggo.GetLogger("juju.worker.backupscheduler")
+
+// ScheduledNotes are the notes recorded with the backups taken by the
+// worker.
+const ScheduledNotes = "scheduled backup"
+
+// Backups holds the backup operations used by the worker.
+type Backups interface {
+	Create(dbInfo backups.DBConnInfo, origin backups.Origin, notes string) (*backups.Metadata, error)
+	List() ([]*backups.Metadata, error)
+	Remove(id string) error
+}
+
+// newBackups returns the Backups that records metadata in state and
+// keeps archives in the environment's storage.
+var newBackups = func(st *state.State) (Backups, error) {
+	stor, err := environs.GetStorage(st)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot open environment storage")
+	}
+	return backups.NewBackups(state.NewBackupMetadataStorage(st), stor), nil
+}
+
+// BackupScheduler takes scheduled backups of juju state.
+type BackupScheduler struct {
+	tomb      tomb.Tomb
+	st        *state.State
+	machineId string
+	backups   Backups
+}
+
+// NewBackupScheduler returns a worker that takes backups of juju state
+// on the state server machine with the given id, on the schedule set
+// by the environment configuration.
+func NewBackupScheduler(st *state.State, machineId string) *BackupScheduler {
+	bs := &BackupScheduler{
+		st:        st,
+		machineId: machineId,
+	}
+	go func() {
+		defer bs.tomb.Done()
+		bs.tomb.Kill(bs.loop())
+	}()
+	return bs
+}
+
+func (bs *BackupScheduler) String() string {
+	return "backup scheduler"
+}
+
+func (bs *BackupScheduler) Kill() {
+	bs.tomb.Kill(nil)
+}
+
+func (bs *BackupScheduler) Stop() error {
+	bs.tomb.Kill(nil)
+	return bs.tomb.Wait()
+}
+
+func (bs *BackupScheduler) Wait() error {
+	return bs.tomb.Wait()
+}
+
+func (bs *BackupScheduler) loop() error {
+	var err error
+	bs.backups, err = newBackups(bs.st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	lastRun, err := bs.lastRun()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if lastRun.IsZero() {
+		// Wait a full interval before the first backup.
+		lastRun = time.Now()
+	}
+
+	environWatcher := bs.st.WatchForEnvironConfigChanges()
+	defer watcher.Stop(environWatcher, &bs.tomb)
+
+	var opts config.BackupsOpts
+	var timer *time.Timer
+	var due <-chan time.Time
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	schedule := func() {
+		if timer != nil {
+			timer.Stop()
+			timer, due = nil, nil
+		}
+		if opts.Interval == 0 {
+			return
+		}
+		timer = time.NewTimer(lastRun.Add(opts.Interval).Sub(time.Now()))
+		due = timer.C
+	}
+	for {
+		select {
+		case <-bs.tomb.Dying():
+			return tomb.ErrDying
+		case _, ok := <-environWatcher.Changes():
+			if !ok {
+				return watcher.MustErr(environWatcher)
+			}
+			cfg, err := bs.st.EnvironConfig()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if newOpts := cfg.BackupsOpts(); newOpts != opts {
+				logger.Debugf("backups interval %v, max count %d, max age %v",
+					newOpts.Interval, newOpts.MaxCount, newOpts.MaxAge)
+				opts = newOpts
+				schedule()
+			}
+		case <-due:
+			lastRun = time.Now()
+			bs.backup()
+			if err := bs.prune(opts, time.Now()); err != nil {
+				logger.Errorf("cannot prune backups: %v", err)
+			}
+			schedule()
+		}
+	}
+}
+
+// lastRun returns when the most recent scheduled backup was started, or
+// the zero time if there is none.
+func (bs *BackupScheduler) lastRun() (time.Time, error) {
+	metadataList, err := bs.backups.List()
+	if err != nil {
+		return time.Time{}, errors.Annotate(err, "cannot list backups")
+	}
+	var last time.Time
+	for _, metadata := range metadataList {
+		if metadata.Notes == ScheduledNotes && metadata.Timestamp.After(last) {
+			last = metadata.Timestamp
+		}
+	}
+	return last, nil
+}
+
+// backup takes a backup of juju state.  Failures are recorded with the
+// backup metadata, so they do not stop the worker.
+func (bs *BackupScheduler) backup() {
+	dbInfo, err := backups.NewDBConnInfoFromMongo(bs.st.MongoConnectionInfo())
+	if err != nil {
+		logger.Errorf("scheduled backup failed: cannot get database connection details: %v", err)
+		return
+	}
+	origin := state.NewBackupOrigin(bs.st, bs.machineId)
+
+	logger.Infof("taking scheduled backup")
+	metadata, err := bs.backups.Create(dbInfo, *origin, ScheduledNotes)
+	if err != nil {
+		logger.Errorf("scheduled backup failed: %v", err)
+		return
+	}
+	logger.Infof("scheduled backup %q done", metadata.ID)
+}
+
+// prune removes the backups that the retention policy does not keep.
+func (bs *BackupScheduler) prune(opts config.BackupsOpts, now time.Time) error {
+	metadataList, err := bs.backups.List()
+	if err != nil {
+		return errors.Annotate(err, "cannot list backups")
+	}
+	for _, id := range Expired(metadataList, opts, now) {
+		logger.Infof("removing expired backup %q", id)
+		if err := bs.backups.Remove(id); err != nil {
+			return errors.Annotate(err, "cannot remove backup")
+		}
+	}
+	return nil
+}
+
+type byTimestamp []*backups.Metadata
+
+func (b byTimestamp) Len() int           { return len(b) }
+func (b byTimestamp) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byTimestamp) Less(i, j int) bool { return b[i].Timestamp.Before(b[j].Timestamp) }
+
+// Expired returns the ids of the scheduled backups that the retention
+// policy in opts does not keep at the given time: all but the newest
+// MaxCount scheduled backups, and those older than MaxAge.  The most
+// recent successful scheduled backup is always kept.  Backups taken by
+// hand are left for their owners to remove.
+func Expired(metadataList []*backups.Metadata, opts config.BackupsOpts, now time.Time) []string {
+	var sorted []*backups.Metadata
+	for _, metadata := range metadataList {
+		if metadata.Notes == ScheduledNotes {
+			sorted = append(sorted, metadata)
+		}
+	}
+	sort.Sort(sort.Reverse(byTimestamp(sorted)))
+
+	var expired []string
+	keptGood := false
+	for i, metadata := range sorted {
+		good := metadata.Stored && metadata.Error == ""
+		if good && !keptGood {
+			keptGood = true
+			continue
+		}
+		tooMany := opts.MaxCount > 0 && i >= opts.MaxCount
+		tooOld := opts.MaxAge > 0 && now.Sub(metadata.Timestamp) > opts.MaxAge
+		if tooMany || tooOld {
+			expired = append(expired, metadata.ID)
+		}
+	}
+	return expired
+}
@@ -0,0 +1,286 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"fmt"
+	"sync"
+	stdtesting "testing"
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/backupscheduler"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type BackupSchedulerSuite struct {
+	testing.JujuConnSuite
+	fake *fakeBackups
+}
+
+var _ = gc.Suite(&BackupSchedulerSuite{})
+
+func (s *BackupSchedulerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.fake = &fakeBackups{
+		created: make(chan *backups.Metadata, 100),
+		removed: make(chan string, 100),
+	}
+	s.PatchValue(backupscheduler.NewBackups, func(*state.State) (backupscheduler.Backups, error) {
+		return s.fake, nil
+	})
+}
+
+// fakeBackups is an in-memory backupscheduler.Backups.
+type fakeBackups struct {
+	mu       sync.Mutex
+	list     []*backups.Metadata
+	nextID   int
+	fail     bool
+	created  chan *backups.Metadata
+	removed  chan string
+	machines []string
+}
+
+func (f *fakeBackups) Create(dbInfo backups.DBConnInfo, origin backups.Origin, notes string) (*backups.Metadata, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	metadata := backups.NewMetadata("some-hash", 42, origin, notes)
+	metadata.ID = fmt.Sprintf("backup-%d", f.nextID)
+	f.nextID++
+	f.machines = append(f.machines, origin.Machine)
+	var err error
+	if f.fail {
+		metadata.Error = "mongodump failed"
+		err = fmt.Errorf("mongodump failed")
+	} else {
+		metadata.Stored = true
+	}
+	f.list = append(f.list, metadata)
+	select {
+	case f.created <- metadata:
+	default:
+	}
+	return metadata, err
+}
+
+func (f *fakeBackups) List() ([]*backups.Metadata, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	list := make([]*backups.Metadata, len(f.list))
+	copy(list, f.list)
+	return list, nil
+}
+
+func (f *fakeBackups) Remove(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, metadata := range f.list {
+		if metadata.ID == id {
+			f.list = append(f.list[:i], f.list[i+1:]...)
+			select {
+			case f.removed <- id:
+			default:
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("backup metadata %q not found", id)
+}
+
+func (s *BackupSchedulerSuite) setConfig(c *gc.C, attrs map[string]interface{}) {
+	err := s.State.UpdateEnvironConfig(attrs, nil, nil)
+	c.Assert(err, gc.IsNil)
+	s.State.StartSync()
+}
+
+func (s *BackupSchedulerSuite) waitCreated(c *gc.C) *backups.Metadata {
+	select {
+	case metadata := <-s.fake.created:
+		return metadata
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for a backup")
+	}
+	panic("unreachable")
+}
+
+func (s *BackupSchedulerSuite) assertNoBackup(c *gc.C) {
+	select {
+	case metadata := <-s.fake.created:
+		c.Fatalf("unexpected backup %q", metadata.ID)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *BackupSchedulerSuite) TestNoScheduleByDefault(c *gc.C) {
+	bs := backupscheduler.NewBackupScheduler(s.State, "0")
+	defer func() { c.Assert(bs.Stop(), gc.IsNil) }()
+	s.assertNoBackup(c)
+}
+
+func (s *BackupSchedulerSuite) TestScheduledBackups(c *gc.C) {
+	s.setConfig(c, map[string]interface{}{"backups-interval": "10ms"})
+	bs := backupscheduler.NewBackupScheduler(s.State, "0")
+	defer func() { c.Assert(bs.Stop(), gc.IsNil) }()
+
+	for i := 0; i < 3; i++ {
+		metadata := s.waitCreated(c)
+		c.Check(metadata.Notes, gc.Equals, backupscheduler.ScheduledNotes)
+		c.Check(metadata.Origin.Machine, gc.Equals, "0")
+		c.Check(metadata.Origin.Environment, gc.Equals, s.State.EnvironTag().Id())
+	}
+
+}
+
+func (s *BackupSchedulerSuite) TestScheduleChange(c *gc.C) {
+	bs := backupscheduler.NewBackupScheduler(s.State, "0")
+	defer func() { c.Assert(bs.Stop(), gc.IsNil) }()
+	s.assertNoBackup(c)
+
+	s.setConfig(c, map[string]interface{}{"backups-interval": "10ms"})
+	s.waitCreated(c)
+}
+
+func (s *BackupSchedulerSuite) TestScheduledBackupFailure(c *gc.C) {
+	s.fake.fail = true
+	s.setConfig(c, map[string]interface{}{"backups-interval": "10ms"})
+	bs := backupscheduler.NewBackupScheduler(s.State, "0")
+	defer func() { c.Assert(bs.Stop(), gc.IsNil) }()
+
+	// Failures do not stop the worker.
+	for i := 0; i < 2; i++ {
+		metadata := s.waitCreated(c)
+		c.Check(metadata.Error, gc.Equals, "mongodump failed")
+	}
+}
+
+func (s *BackupSchedulerSuite) TestPrunesBackups(c *gc.C) {
+	s.setConfig(c, map[string]interface{}{
+		"backups-interval":  "10ms",
+		"backups-max-count": 2,
+	})
+	bs := backupscheduler.NewBackupScheduler(s.State, "0")
+	defer func() { c.Assert(bs.Stop(), gc.IsNil) }()
+
+	first := s.waitCreated(c)
+	s.waitCreated(c)
+	s.waitCreated(c)
+	select {
+	case id := <-s.fake.removed:
+		c.Check(id, gc.Equals, first.ID)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for a backup to be pruned")
+	}
+}
+
+var (
+	now  = time.Date(2014, 8, 1, 12, 0, 0, 0, time.UTC)
+	hour = time.Hour
+	day  = 24 * time.Hour
+)
+
+// metadata returns the metadata of a scheduled backup taken at the
+// given time before now.
+func metadata(id string, age time.Duration, failed bool) *backups.Metadata {
+	m := &backups.Metadata{ID: id, Timestamp: now.Add(-age), Notes: backupscheduler.ScheduledNotes}
+	if failed {
+		m.Error = "mongodump failed"
+	} else {
+		m.Stored = true
+	}
+	return m
+}
+
+// manualMetadata returns the metadata of a backup taken by hand at the
+// given time before now.
+func manualMetadata(id string, age time.Duration) *backups.Metadata {
+	m := metadata(id, age, false)
+	m.Notes = "before upgrade"
+	return m
+}
+
+var expiredTests = []struct {
+	about   string
+	list    []*backups.Metadata
+	opts    config.BackupsOpts
+	expired []string
+}{{
+	about: "no policy",
+	list: []*backups.Metadata{
+		metadata("a", 3*day, false),
+		metadata("b", 2*day, false),
+	},
+}, {
+	about: "max count",
+	list: []*backups.Metadata{
+		metadata("a", 3*day, false),
+		metadata("b", 2*day, false),
+		metadata("c", day, false),
+	},
+	opts:    config.BackupsOpts{MaxCount: 2},
+	expired: []string{"a"},
+}, {
+	about: "max age",
+	list: []*backups.Metadata{
+		metadata("a", 3*day, false),
+		metadata("b", 2*day, false),
+		metadata("c", hour, false),
+	},
+	opts:    config.BackupsOpts{MaxAge: day},
+	expired: []string{"b", "a"},
+}, {
+	about: "failures count towards max count",
+	list: []*backups.Metadata{
+		metadata("a", 3*day, false),
+		metadata("b", 2*day, true),
+		metadata("c", day, false),
+	},
+	opts:    config.BackupsOpts{MaxCount: 2},
+	expired: []string{"a"},
+}, {
+	about: "the last good backup is kept",
+	list: []*backups.Metadata{
+		metadata("a", 3*day, false),
+		metadata("b", 2*day, true),
+		metadata("c", day, true),
+	},
+	opts:    config.BackupsOpts{MaxCount: 1, MaxAge: hour},
+	expired: []string{"c", "b"},
+}, {
+	about: "manual backups are kept",
+	list: []*backups.Metadata{
+		manualMetadata("a", 3*day),
+		metadata("b", 2*day, false),
+		manualMetadata("c", 2*hour),
+		metadata("d", hour, false),
+	},
+	opts:    config.BackupsOpts{MaxCount: 1, MaxAge: day},
+	expired: []string{"b"},
+}, {
+	about: "unsorted",
+	list: []*backups.Metadata{
+		metadata("c", day, false),
+		metadata("a", 3*day, false),
+		metadata("b", 2*day, false),
+	},
+	opts:    config.BackupsOpts{MaxCount: 1},
+	expired: []string{"b", "a"},
+}}
+
+func (s *BackupSchedulerSuite) TestExpired(c *gc.C) {
+	for i, test := range expiredTests {
+		c.Logf("test %d: %s", i, test.about)
+		expired := backupscheduler.Expired(test.list, test.opts, now)
+		c.Check(expired, gc.DeepEquals, test.expired)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The logsender package implements the agent worker that sends the
+// agent's log messages to the state servers, which store them so
+// that debug-log can follow the logs of all the agents.
+package logsender
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/worker"
+)
+
+// DefaultBufferSize is the number of log records an agent holds
+// while they cannot be sent.
+const DefaultBufferSize = 1000
+
+// maxBatchSize is the largest number of log records sent in one call.
+var maxBatchSize = 100
+
+// excludedModules holds the logging modules whose messages are not
+// sent. The RPC codec traces every message on the wire, including
+// those carrying log records, so sending its messages would make the
+// log sender feed itself.
+var excludedModules = []string{
+	"juju.rpc.jsoncodec",
+}
+
+// LogRecord holds a log message written by the agent.
+type LogRecord struct {
+	Time     time.Time
+	Module   string
+	Location string
+	Level    loggo.Level
+	Message  string
+}
+
+// BufferedLogWriter is a loggo.Writer that holds the log records
+// written until the log sender takes them. When its buffer is full,
+// new records are dropped rather than blocking the agent.
+type BufferedLogWriter struct {
+	in      chan *LogRecord
+	dropped uint64
+}
+
+var _ loggo.Writer = (*BufferedLogWriter)(nil)
+
+// NewBufferedLogWriter returns a BufferedLogWriter holding up to size
+// log records.
+func NewBufferedLogWriter(size int) *BufferedLogWriter {
+	return &BufferedLogWriter{in: make(chan *LogRecord, size)}
+}
+
+// Write implements loggo.Writer.
+func (w *BufferedLogWriter) Write(level loggo.Level, module, filename string, line int, timestamp time.Time, message string) {
+	for _, excluded := range excludedModules {
+		if module == excluded || strings.HasPrefix(module, excluded+".") {
+			return
+		}
+	}
+	record := &LogRecord{
+		Time:     timestamp,
+		Module:   module,
+		Location: fmt.Sprintf("%s:%d", filepath.Base(filename), line),
+		Level:    level,
+		Message:  message,
+	}
+	select {
+	case w.in <- record:
+	default:
+		atomic.AddUint64(&w.dropped, 1)
+	}
+}
+
+// Logs returns the channel on which the written log records are
+// received.
+func (w *BufferedLogWriter) Logs() <-chan *LogRecord {
+	return w.in
+}
+
+// Dropped returns the number of log records dropped because the
+// buffer was full, and resets it.
+func (w *BufferedLogWriter) Dropped() uint64 {
+	return atomic.SwapUint64(&w.dropped, 0)
+}
+
+// LogWriter sends log records to the state servers.
+type LogWriter interface {
+	WriteLogs(records []params.LogRecord) error
+}
+
+type logSender struct {
+	tomb tomb.Tomb
+	logs *BufferedLogWriter
+	api  LogWriter
+}
+
+// New returns a worker that sends the log records written to logs
+// using api.
+func New(logs *BufferedLogWriter, api LogWriter) worker.Worker {
+	s := &logSender{logs: logs, api: api}
+	go func() {
+		defer s.tomb.Done()
+		s.tomb.Kill(s.loop())
+	}()
+	return s
+}
+
+func (s *logSender) String() string {
+	return "log sender"
+}
+
+func (s *logSender) Kill() {
+	s.tomb.Kill(nil)
+}
+
+func (s *logSender) Wait() error {
+	return s.tomb.Wait()
+}
+
+func (s *logSender) loop() error {
+	for {
+		var batch []params.LogRecord
+		select {
+		case <-s.tomb.Dying():
+			return tomb.ErrDying
+		case record := <-s.logs.Logs():
+			batch = append(batch, logRecordParams(record))
+		}
+	collect:
+		for len(batch) < maxBatchSize {
+			select {
+			case record := <-s.logs.Logs():
+				batch = append(batch, logRecordParams(record))
+			default:
+				break collect
+			}
+		}
+		// Nothing is logged while sending, so that the sender does
+		// not keep itself busy with its own messages.
+		if dropped := s.logs.Dropped(); dropped > 0 {
+			batch = append(batch, params.LogRecord{
+				Time:    time.Now(),
+				Module:  "juju.worker.logsender",
+				Level:   loggo.WARNING.String(),
+				Message: fmt.Sprintf("%d log messages dropped due to lack of buffer space", dropped),
+			})
+		}
+		if err := s.api.WriteLogs(batch); err != nil {
+			return errors.Annotate(err, "cannot send log records")
+		}
+	}
+}
+
+func logRecordParams(record *LogRecord) params.LogRecord {
+	return params.LogRecord{
+		Time:     record.Time,
+		Module:   record.Module,
+		Location: record.Location,
+		Level:    record.Level.String(),
+		Message:  record.Message,
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsender_test
+
+import (
+	"errors"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/loggo"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state/api/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/logsender"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type logSenderSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&logSenderSuite{})
+
+type fakeLogWriter struct {
+	batches chan []params.LogRecord
+	err     error
+}
+
+func (w *fakeLogWriter) WriteLogs(records []params.LogRecord) error {
+	w.batches <- records
+	return w.err
+}
+
+func (s *logSenderSuite) nextBatch(c *gc.C, api *fakeLogWriter) []params.LogRecord {
+	select {
+	case batch := <-api.batches:
+		return batch
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for log records")
+	}
+	panic("unreachable")
+}
+
+func (s *logSenderSuite) TestBufferedLogWriter(c *gc.C) {
+	logs := logsender.NewBufferedLogWriter(2)
+	t := time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC)
+	logs.Write(loggo.INFO, "juju.worker", "/path/to/worker.go", 42, t, "one")
+	logs.Write(loggo.DEBUG, "juju.worker", "/path/to/worker.go", 43, t, "two")
+	logs.Write(loggo.DEBUG, "juju.worker", "/path/to/worker.go", 44, t, "three")
+
+	c.Assert(<-logs.Logs(), gc.DeepEquals, &logsender.LogRecord{
+		Time:     t,
+		Module:   "juju.worker",
+		Location: "worker.go:42",
+		Level:    loggo.INFO,
+		Message:  "one",
+	})
+	c.Assert((<-logs.Logs()).Message, gc.Equals, "two")
+	c.Assert(logs.Dropped(), gc.Equals, uint64(1))
+	c.Assert(logs.Dropped(), gc.Equals, uint64(0))
+}
+
+func (s *logSenderSuite) TestBufferedLogWriterExcludesCodecTraces(c *gc.C) {
+	logs := logsender.NewBufferedLogWriter(2)
+	logs.Write(loggo.TRACE, "juju.rpc.jsoncodec", "codec.go", 1, time.Now(), "<- {}")
+	logs.Write(loggo.DEBUG, "juju.rpc", "server.go", 1, time.Now(), "one")
+	c.Assert((<-logs.Logs()).Message, gc.Equals, "one")
+	select {
+	case record := <-logs.Logs():
+		c.Fatalf("unexpected log record %#v", record)
+	default:
+	}
+}
+
+func (s *logSenderSuite) TestSendsBatches(c *gc.C) {
+	s.PatchValue(logsender.MaxBatchSize, 2)
+	logs := logsender.NewBufferedLogWriter(10)
+	for _, message := range []string{"one", "two", "three"} {
+		logs.Write(loggo.INFO, "juju", "file.go", 1, time.Now(), message)
+	}
+	api := &fakeLogWriter{batches: make(chan []params.LogRecord, 10)}
+	w := logsender.New(logs, api)
+	defer func() {
+		w.Kill()
+		c.Assert(w.Wait(), gc.IsNil)
+	}()
+
+	batch := s.nextBatch(c, api)
+	c.Assert(batch, gc.HasLen, 2)
+	c.Assert(batch[0].Message, gc.Equals, "one")
+	c.Assert(batch[0].Level, gc.Equals, "INFO")
+	c.Assert(batch[0].Location, gc.Equals, "file.go:1")
+	c.Assert(batch[1].Message, gc.Equals, "two")
+	batch = s.nextBatch(c, api)
+	c.Assert(batch, gc.HasLen, 1)
+	c.Assert(batch[0].Message, gc.Equals, "three")
+
+	logs.Write(loggo.ERROR, "juju", "file.go", 2, time.Now(), "four")
+	batch = s.nextBatch(c, api)
+	c.Assert(batch, gc.HasLen, 1)
+	c.Assert(batch[0].Message, gc.Equals, "four")
+}
+
+func (s *logSenderSuite) TestReportsDropped(c *gc.C) {
+	logs := logsender.NewBufferedLogWriter(1)
+	logs.Write(loggo.INFO, "juju", "file.go", 1, time.Now(), "one")
+	logs.Write(loggo.INFO, "juju", "file.go", 1, time.Now(), "two")
+	api := &fakeLogWriter{batches: make(chan []params.LogRecord, 10)}
+	w := logsender.New(logs, api)
+	defer func() {
+		w.Kill()
+		c.Assert(w.Wait(), gc.IsNil)
+	}()
+
+	batch := s.nextBatch(c, api)
+	c.Assert(batch, gc.HasLen, 2)
+	c.Assert(batch[0].Message, gc.Equals, "one")
+	c.Assert(batch[1].Level, gc.Equals, "WARNING")
+	c.Assert(batch[1].Message, gc.Equals, "1 log messages dropped due to lack of buffer space")
+}
+
+func (s *logSenderSuite) TestSendError(c *gc.C) {
+	logs := logsender.NewBufferedLogWriter(10)
+	logs.Write(loggo.INFO, "juju", "file.go", 1, time.Now(), "one")
+	api := &fakeLogWriter{
+		batches: make(chan []params.LogRecord, 10),
+		err:     errors.New("boom"),
+	}
+	w := logsender.New(logs, api)
+	s.nextBatch(c, api)
+	c.Assert(w.Wait(), gc.ErrorMatches, "cannot send log records: boom")
+}
@@ -0,0 +1,11 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender
+
+var (
+	SendInterval   = &sendInterval
+	MaxRetryDelay  = &maxRetryDelay
+	BatchesPerPost = &batchesPerPost
+	PostTimeout    = &postTimeout
+)
@@ -0,0 +1,205 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The metricsender package implements the state server worker that
+// sends the metrics recorded by units to the HTTP collector set by the
+// metrics-collector-url environment setting, and removes sent metrics
+// once they are older than the metrics-retention setting.
+//
+// Unsent metric batches are posted to the collector as a JSON array,
+// with a Content-Type of application/json:
+//
+//	[{
+//		"uuid": "0c4d9f2c-...",
+//		"env-uuid": "a1a7bd6e-...",
+//		"unit": "wordpress/0",
+//		"charm-url": "cs:precise/wordpress-3",
+//		"created": "2014-09-01T12:00:00Z",
+//		"metrics": [
+//			{"key": "pings", "value": "5", "time": "2014-09-01T11:59:58Z"}
+//		]
+//	}]
+//
+// The batches are marked as sent when the collector replies with a 2xx
+// status. Otherwise they are sent again later, after a delay that
+// doubles with each failure.
+package metricsender
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.worker.metricsender")
+
+var (
+	// sendInterval is how often unsent metrics are sent.
+	sendInterval = 5 * time.Minute
+
+	// maxRetryDelay is the longest delay between attempts to send
+	// metrics after failures.
+	maxRetryDelay = time.Hour
+
+	// batchesPerPost is the largest number of metric batches posted
+	// to the collector in one request.
+	batchesPerPost = 1000
+
+	// postTimeout is how long a post to the collector may take
+	// before it is abandoned, so that an unresponsive collector does
+	// not stall the worker.
+	postTimeout = time.Minute
+)
+
+// MetricBatch is the representation of a batch of metrics posted to
+// the collector.
+type MetricBatch struct {
+	UUID     string    `json:"uuid"`
+	EnvUUID  string    `json:"env-uuid"`
+	Unit     string    `json:"unit"`
+	CharmURL string    `json:"charm-url"`
+	Created  time.Time `json:"created"`
+	Metrics  []Metric  `json:"metrics"`
+}
+
+// Metric is the representation of a single metric posted to the
+// collector.
+type Metric struct {
+	Key   string    `json:"key"`
+	Value string    `json:"value"`
+	Time  time.Time `json:"time"`
+}
+
+// MetricSender sends recorded metrics to the collector.
+type MetricSender struct {
+	tomb tomb.Tomb
+	st   *state.State
+}
+
+// NewMetricSender returns a worker that periodically sends the unsent
+// metrics in state to the metrics collector.
+func NewMetricSender(st *state.State) *MetricSender {
+	ms := &MetricSender{st: st}
+	go func() {
+		defer ms.tomb.Done()
+		ms.tomb.Kill(ms.loop())
+	}()
+	return ms
+}
+
+func (ms *MetricSender) String() string {
+	return "metric sender"
+}
+
+func (ms *MetricSender) Kill() {
+	ms.tomb.Kill(nil)
+}
+
+func (ms *MetricSender) Stop() error {
+	ms.tomb.Kill(nil)
+	return ms.tomb.Wait()
+}
+
+func (ms *MetricSender) Wait() error {
+	return ms.tomb.Wait()
+}
+
+func (ms *MetricSender) loop() error {
+	delay := sendInterval
+	for {
+		select {
+		case <-ms.tomb.Dying():
+			return tomb.ErrDying
+		case <-time.After(delay):
+		}
+		cfg, err := ms.st.EnvironConfig()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if collectorURL, ok := cfg.MetricsCollectorURL(); ok {
+			if err := ms.send(collectorURL); err != nil {
+				delay *= 2
+				if delay > maxRetryDelay {
+					delay = maxRetryDelay
+				}
+				logger.Errorf("cannot send metrics, retrying in %v: %v", delay, err)
+			} else {
+				delay = sendInterval
+			}
+		}
+		if err := ms.st.CleanupOldMetrics(time.Now().Add(-cfg.MetricsRetention())); err != nil {
+			logger.Errorf("cannot remove old metrics: %v", err)
+		}
+	}
+}
+
+// send posts all unsent metrics to the collector, and marks them as
+// sent.
+func (ms *MetricSender) send(collectorURL string) error {
+	envUUID := ms.st.EnvironTag().Id()
+	for {
+		batches, err := ms.st.MetricsToSend(batchesPerPost)
+		if err != nil {
+			return errors.Annotate(err, "cannot get unsent metrics")
+		}
+		if len(batches) == 0 {
+			return nil
+		}
+		if err := post(collectorURL, envUUID, batches); err != nil {
+			return errors.Trace(err)
+		}
+		for _, batch := range batches {
+			if err := batch.SetSent(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		logger.Debugf("sent %d metric batches", len(batches))
+		if len(batches) < batchesPerPost {
+			return nil
+		}
+	}
+}
+
+// post posts the given metric batches to the collector.
+func post(collectorURL, envUUID string, batches []*state.MetricBatch) error {
+	posted := make([]MetricBatch, len(batches))
+	for i, batch := range batches {
+		metrics := batch.Metrics()
+		posted[i] = MetricBatch{
+			UUID:     batch.UUID(),
+			EnvUUID:  envUUID,
+			Unit:     batch.Unit(),
+			CharmURL: batch.CharmURL(),
+			Created:  batch.Created(),
+			Metrics:  make([]Metric, len(metrics)),
+		}
+		for j, metric := range metrics {
+			posted[i].Metrics[j] = Metric{
+				Key:   metric.Key(),
+				Value: metric.Value(),
+				Time:  metric.Time(),
+			}
+		}
+	}
+	data, err := json.Marshal(posted)
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal metrics")
+	}
+	client := &http.Client{Timeout: postTimeout}
+	resp, err := client.Post(collectorURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return errors.Annotate(err, "cannot post metrics")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("metrics collector returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker/metricsender"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type MetricSenderSuite struct {
+	testing.JujuConnSuite
+	collector *collector
+	server    *httptest.Server
+	factory   *factory.Factory
+	unit      *state.Unit
+}
+
+var _ = gc.Suite(&MetricSenderSuite{})
+
+func (s *MetricSenderSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.PatchValue(metricsender.SendInterval, 10*time.Millisecond)
+	s.PatchValue(metricsender.MaxRetryDelay, 50*time.Millisecond)
+	s.collector = &collector{
+		status: http.StatusOK,
+		posted: make(chan []metricsender.MetricBatch, 100),
+	}
+	s.server = httptest.NewServer(s.collector)
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+	s.factory = factory.NewFactory(s.State)
+	s.unit = s.factory.MakeUnit(c, nil)
+}
+
+// collector is a stand-in for the metrics collector.
+type collector struct {
+	mu       sync.Mutex
+	status   int
+	requests int
+	posted   chan []metricsender.MetricBatch
+}
+
+func (col *collector) setStatus(status int) {
+	col.mu.Lock()
+	defer col.mu.Unlock()
+	col.status = status
+}
+
+func (col *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	col.mu.Lock()
+	status := col.status
+	col.requests++
+	col.mu.Unlock()
+	if r.Method != "POST" || r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	var batches []metricsender.MetricBatch
+	if err := json.NewDecoder(r.Body).Decode(&batches); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(status)
+	if status == http.StatusOK {
+		col.posted <- batches
+	}
+}
+
+func (s *MetricSenderSuite) setCollectorURL(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"metrics-collector-url": s.server.URL,
+	}, nil, nil)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *MetricSenderSuite) waitPosted(c *gc.C) []metricsender.MetricBatch {
+	select {
+	case batches := <-s.collector.posted:
+		return batches
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for metrics to be posted")
+	}
+	panic("unreachable")
+}
+
+func (s *MetricSenderSuite) waitSent(c *gc.C, batch *state.MetricBatch) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		saved, err := s.State.MetricBatch(batch.UUID())
+		c.Assert(err, gc.IsNil)
+		if saved.Sent() {
+			return
+		}
+	}
+	c.Fatalf("metric batch %q not marked as sent", batch.UUID())
+}
+
+func (s *MetricSenderSuite) TestSendsMetrics(c *gc.C) {
+	s.setCollectorURL(c)
+	now := time.Now().Round(time.Second).UTC()
+	batch := s.factory.MakeMetric(c, &factory.MetricParams{
+		Unit:    s.unit,
+		Metrics: []*state.Metric{state.NewMetric("pings", "5", now, nil)},
+	})
+	s.factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Sent: true})
+
+	ms := metricsender.NewMetricSender(s.State)
+	defer func() { c.Assert(ms.Stop(), gc.IsNil) }()
+
+	posted := s.waitPosted(c)
+	c.Assert(posted, gc.HasLen, 1)
+	c.Check(posted[0].UUID, gc.Equals, batch.UUID())
+	c.Check(posted[0].EnvUUID, gc.Equals, s.State.EnvironTag().Id())
+	c.Check(posted[0].Unit, gc.Equals, s.unit.Name())
+	c.Check(posted[0].CharmURL, gc.Equals, batch.CharmURL())
+	c.Check(posted[0].Created.Equal(batch.Created()), jc.IsTrue)
+	c.Assert(posted[0].Metrics, gc.HasLen, 1)
+	c.Check(posted[0].Metrics[0].Key, gc.Equals, "pings")
+	c.Check(posted[0].Metrics[0].Value, gc.Equals, "5")
+	c.Check(posted[0].Metrics[0].Time.Equal(now), jc.IsTrue)
+	s.waitSent(c, batch)
+}
+
+func (s *MetricSenderSuite) TestSendsInSeveralPosts(c *gc.C) {
+	s.PatchValue(metricsender.BatchesPerPost, 2)
+	s.setCollectorURL(c)
+	for i := 0; i < 3; i++ {
+		s.factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit})
+	}
+
+	ms := metricsender.NewMetricSender(s.State)
+	defer func() { c.Assert(ms.Stop(), gc.IsNil) }()
+
+	c.Check(s.waitPosted(c), gc.HasLen, 2)
+	c.Check(s.waitPosted(c), gc.HasLen, 1)
+}
+
+func (s *MetricSenderSuite) TestNoCollector(c *gc.C) {
+	batch := s.factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit})
+
+	ms := metricsender.NewMetricSender(s.State)
+	defer func() { c.Assert(ms.Stop(), gc.IsNil) }()
+
+	time.Sleep(coretesting.ShortWait)
+	saved, err := s.State.MetricBatch(batch.UUID())
+	c.Assert(err, gc.IsNil)
+	c.Assert(saved.Sent(), jc.IsFalse)
+}
+
+func (s *MetricSenderSuite) TestRetriesOnFailure(c *gc.C) {
+	s.collector.setStatus(http.StatusInternalServerError)
+	s.setCollectorURL(c)
+	batch := s.factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit})
+
+	ms := metricsender.NewMetricSender(s.State)
+	defer func() { c.Assert(ms.Stop(), gc.IsNil) }()
+
+	// Wait for a few failed attempts.
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		s.collector.mu.Lock()
+		requests := s.collector.requests
+		s.collector.mu.Unlock()
+		if requests >= 2 {
+			break
+		}
+	}
+	saved, err := s.State.MetricBatch(batch.UUID())
+	c.Assert(err, gc.IsNil)
+	c.Assert(saved.Sent(), jc.IsFalse)
+
+	s.collector.setStatus(http.StatusOK)
+	posted := s.waitPosted(c)
+	c.Assert(posted, gc.HasLen, 1)
+	c.Check(posted[0].UUID, gc.Equals, batch.UUID())
+	s.waitSent(c, batch)
+}
+
+func (s *MetricSenderSuite) TestAbandonsStalledPosts(c *gc.C) {
+	s.PatchValue(metricsender.PostTimeout, 50*time.Millisecond)
+	stalled := make(chan struct{})
+	requests := make(chan struct{}, 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- struct{}{}
+		<-stalled
+	}))
+	defer server.Close()
+	defer close(stalled)
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"metrics-collector-url": server.URL,
+	}, nil, nil)
+	c.Assert(err, gc.IsNil)
+	s.factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit})
+
+	ms := metricsender.NewMetricSender(s.State)
+	defer func() { c.Assert(ms.Stop(), gc.IsNil) }()
+
+	// The stalled post is abandoned, and the metrics are sent again.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-requests:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for post %d", i)
+		}
+	}
+}
+
+func (s *MetricSenderSuite) TestCleansUpSentMetrics(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"metrics-retention": "1ns",
+	}, nil, nil)
+	c.Assert(err, gc.IsNil)
+	sent := s.factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Sent: true})
+	unsent := s.factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit})
+
+	ms := metricsender.NewMetricSender(s.State)
+	defer func() { c.Assert(ms.Stop(), gc.IsNil) }()
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		batches, err := s.State.MetricBatches(state.MetricFilter{Units: []string{s.unit.Name()}})
+		c.Assert(err, gc.IsNil)
+		if len(batches) == 1 {
+			c.Assert(batches[0].UUID(), gc.Equals, unsent.UUID())
+			return
+		}
+	}
+	c.Fatalf("metric batch %q not removed", sent.UUID())
+}
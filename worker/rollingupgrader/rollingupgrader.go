@@ -0,0 +1,134 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The rollingupgrader package implements the state server worker that
+// advances rolling upgrades of services' charms, releasing their units
+// to upgrade a batch at a time.
+package rollingupgrader
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.rollingupgrader")
+
+// DefaultInterval is the amount of time between checks of the
+// progress of rolling upgrades.
+const DefaultInterval = 10 * time.Second
+
+// New returns a worker that checks the progress of the rolling upgrades
+// in state every interval. Once every unit of a rolling upgrade's
+// current batch has upgraded, the next batch is released; when a unit
+// fails to upgrade, the rolling upgrade is paused.
+func New(st *state.State, interval time.Duration) worker.Worker {
+	check := func(stop <-chan struct{}) error {
+		upgrades, err := st.AllRollingUpgrades()
+		if err != nil {
+			return err
+		}
+		for _, ru := range upgrades {
+			if err := advance(st, ru, time.Now()); err != nil {
+				return errors.Annotatef(err, "cannot advance rolling upgrade of service %q", ru.Service())
+			}
+		}
+		return nil
+	}
+	return worker.NewPeriodicWorker(check, interval)
+}
+
+// advance releases the next batch of units of the rolling upgrade if
+// the current batch has upgraded, finishes it if no units are left,
+// and pauses it if a unit of the current batch failed.
+func advance(st *state.State, ru *state.RollingUpgrade, now time.Time) error {
+	if paused, _ := ru.Paused(); paused {
+		return nil
+	}
+	batch, started := ru.Batch()
+	for _, name := range batch {
+		done, reason, err := checkUnit(st, ru, name, started)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			logger.Warningf("pausing rolling upgrade of service %q: %s", ru.Service(), reason)
+			return ru.Pause(reason)
+		}
+		if !done {
+			return nil
+		}
+	}
+	held, err := ru.HeldUnits()
+	if err != nil {
+		return err
+	}
+	if len(held) == 0 {
+		logger.Infof("rolling upgrade of service %q to charm %q finished", ru.Service(), ru.ToCharmURL())
+		return ru.Finish()
+	}
+	if len(held) > ru.BatchSize() {
+		held = held[:ru.BatchSize()]
+	}
+	logger.Infof("upgrading units %s of service %q", strings.Join(held, ", "), ru.Service())
+	return ru.ReleaseBatch(held, now)
+}
+
+// checkUnit returns whether the named unit, released to upgrade at the
+// given time, has finished upgrading, or why the rolling upgrade must
+// be paused. Units that were removed count as upgraded.
+func checkUnit(st *state.State, ru *state.RollingUpgrade, name string, released time.Time) (done bool, reason string, err error) {
+	unit, err := st.Unit(name)
+	if errors.IsNotFound(err) {
+		return true, "", nil
+	} else if err != nil {
+		return false, "", err
+	}
+	if unit.Life() != state.Alive {
+		return true, "", nil
+	}
+	status, info, _, err := unit.Status()
+	if err != nil {
+		return false, "", err
+	}
+	if status == params.StatusError {
+		return false, fmt.Sprintf("unit %s: %s", name, info), nil
+	}
+	if curl, _ := unit.CharmURL(); curl == nil || *curl != *ru.ToCharmURL() {
+		return false, "", nil
+	}
+	// The unit is started again once its upgrade-charm hook has run,
+	// which is the only way to tell it apart from a unit that was
+	// started before, and is yet to run the hook.
+	if status != params.StatusStarted {
+		return false, "", nil
+	}
+	history, err := unit.StatusHistory(1)
+	if err != nil {
+		return false, "", err
+	}
+	if len(history) == 0 || history[0].Since.Before(released) {
+		return false, "", nil
+	}
+	if !ru.WaitActive() {
+		return true, "", nil
+	}
+	workload, info, err := unit.WorkloadStatus()
+	if err != nil {
+		return false, "", err
+	}
+	switch workload {
+	case params.WorkloadActive:
+		return true, "", nil
+	case params.WorkloadBlocked:
+		return false, fmt.Sprintf("unit %s: workload blocked: %s", name, info), nil
+	}
+	return false, "", nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rollingupgrader_test
+
+import (
+	stdtesting "testing"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/rollingupgrader"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type RollingUpgraderSuite struct {
+	testing.JujuConnSuite
+	newCharm *state.Charm
+	units    []*state.Unit
+}
+
+var _ = gc.Suite(&RollingUpgraderSuite{})
+
+func (s *RollingUpgraderSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	oldCharm := s.AddTestingCharm(c, "dummy")
+	s.newCharm = s.AddTestingCharm(c, "wordpress")
+	service := s.AddTestingService(c, "service", oldCharm)
+	s.units = nil
+	for i := 0; i < 3; i++ {
+		unit, err := service.AddUnit()
+		c.Assert(err, gc.IsNil)
+		err = unit.SetCharmURL(oldCharm.URL())
+		c.Assert(err, gc.IsNil)
+		err = unit.SetStatus(params.StatusStarted, "", nil)
+		c.Assert(err, gc.IsNil)
+		s.units = append(s.units, unit)
+	}
+	_, err := service.StartRollingUpgrade(state.RollingUpgradeParams{
+		Charm:     s.newCharm,
+		BatchSize: 2,
+	})
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *RollingUpgraderSuite) startWorker(c *gc.C) worker.Worker {
+	return rollingupgrader.New(s.State, coretesting.ShortWait)
+}
+
+func stopWorker(c *gc.C, w worker.Worker) {
+	w.Kill()
+	c.Assert(w.Wait(), gc.IsNil)
+}
+
+// waitReleased waits until exactly the given units are no longer held
+// back from upgrading.
+func (s *RollingUpgraderSuite) waitReleased(c *gc.C, released ...bool) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		matched := true
+		for i, unit := range s.units {
+			err := unit.Refresh()
+			c.Assert(err, gc.IsNil)
+			if unit.CharmUpgradeHeld() == released[i] {
+				matched = false
+			}
+		}
+		if matched {
+			return
+		}
+	}
+	c.Fatalf("units not released as expected")
+}
+
+// upgrade does what the uniter does when the unit upgrades.
+func (s *RollingUpgraderSuite) upgrade(c *gc.C, unit *state.Unit) {
+	err := unit.SetCharmURL(s.newCharm.URL())
+	c.Assert(err, gc.IsNil)
+	err = unit.SetStatus(params.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *RollingUpgraderSuite) TestUpgradesInBatches(c *gc.C) {
+	w := s.startWorker(c)
+	defer stopWorker(c, w)
+
+	s.waitReleased(c, true, true, false)
+	s.upgrade(c, s.units[0])
+	s.upgrade(c, s.units[1])
+	s.waitReleased(c, true, true, true)
+	s.upgrade(c, s.units[2])
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		_, err := s.State.RollingUpgrade("service")
+		if errors.IsNotFound(err) {
+			return
+		}
+		c.Assert(err, gc.IsNil)
+	}
+	c.Fatalf("rolling upgrade not finished")
+}
+
+func (s *RollingUpgraderSuite) TestPausesOnError(c *gc.C) {
+	w := s.startWorker(c)
+	defer stopWorker(c, w)
+
+	s.waitReleased(c, true, true, false)
+	err := s.units[0].SetStatus(params.StatusError, `hook failed: "upgrade-charm"`, nil)
+	c.Assert(err, gc.IsNil)
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		ru, err := s.State.RollingUpgrade("service")
+		c.Assert(err, gc.IsNil)
+		if paused, reason := ru.Paused(); paused {
+			c.Assert(reason, gc.Equals, `unit service/0: hook failed: "upgrade-charm"`)
+			// No further units are released.
+			s.upgrade(c, s.units[1])
+			err := s.units[2].Refresh()
+			c.Assert(err, gc.IsNil)
+			c.Assert(s.units[2].CharmUpgradeHeld(), jc.IsTrue)
+			return
+		}
+	}
+	c.Fatalf("rolling upgrade not paused")
+}
@@ -0,0 +1,49 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The statushistorypruner package implements the state server worker
+// that keeps the status history of units and machines from growing
+// without bound.
+package statushistorypruner
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+// HistoryPrunerParams holds the limits the status history is pruned
+// to, and how often it is pruned.
+type HistoryPrunerParams struct {
+	// MaxAge is the amount of time statuses are kept for.
+	MaxAge time.Duration
+
+	// MaxPerEntity is the number of statuses kept for each unit and
+	// machine.
+	MaxPerEntity int
+
+	// PruneInterval is the amount of time between prunings.
+	PruneInterval time.Duration
+}
+
+// NewHistoryPrunerParams returns the default pruning parameters.
+func NewHistoryPrunerParams() *HistoryPrunerParams {
+	return &HistoryPrunerParams{
+		MaxAge:        7 * 24 * time.Hour,
+		MaxPerEntity:  100,
+		PruneInterval: 5 * time.Minute,
+	}
+}
+
+// New returns a worker that periodically prunes the status history in
+// state to the limits given in params.
+func New(st *state.State, params *HistoryPrunerParams) worker.Worker {
+	prune := func(stop <-chan struct{}) error {
+		err := st.PruneStatusHistory(params.MaxAge, params.MaxPerEntity)
+		return errors.Annotate(err, "cannot prune status history")
+	}
+	return worker.NewPeriodicWorker(prune, params.PruneInterval)
+}
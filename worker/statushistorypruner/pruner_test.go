@@ -0,0 +1,62 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package statushistorypruner_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state/api/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker/statushistorypruner"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type PrunerSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&PrunerSuite{})
+
+func (s *PrunerSuite) TestPrunesToMaxPerEntity(c *gc.C) {
+	unit := factory.NewFactory(s.State).MakeUnit(c, nil)
+	for _, info := range []string{"first", "second", "third"} {
+		err := unit.SetStatus(params.StatusError, info, nil)
+		c.Assert(err, gc.IsNil)
+	}
+
+	pruner := statushistorypruner.New(s.State, &statushistorypruner.HistoryPrunerParams{
+		MaxAge:        time.Hour,
+		MaxPerEntity:  1,
+		PruneInterval: coretesting.ShortWait,
+	})
+	defer func() {
+		pruner.Kill()
+		c.Assert(pruner.Wait(), gc.IsNil)
+	}()
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		history, err := unit.StatusHistory(10)
+		c.Assert(err, gc.IsNil)
+		if len(history) == 1 {
+			c.Assert(history[0].Message, gc.Equals, "third")
+			return
+		}
+	}
+	c.Fatalf("status history not pruned")
+}
+
+func (s *PrunerSuite) TestDefaultParams(c *gc.C) {
+	p := statushistorypruner.NewHistoryPrunerParams()
+	c.Assert(p.MaxAge, gc.Equals, 7*24*time.Hour)
+	c.Assert(p.MaxPerEntity, gc.Equals, 100)
+	c.Assert(p.PruneInterval, gc.Equals, 5*time.Minute)
+}
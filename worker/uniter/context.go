@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/juju/loggo"
+	"github.com/juju/names"
 	utilexec "github.com/juju/utils/exec"
 	"github.com/juju/utils/proxy"
 	"gopkg.in/juju/charm.v3"
@@ -38,8 +39,6 @@ type missingHookError struct {
 	hookName string
 }
 
-type actionParams map[string]interface{}
-
 func (e *missingHookError) Error() string {
 	return e.hookName + " does not exist"
 }
@@ -49,6 +48,25 @@ func IsMissingHookError(err error) bool {
 	return ok
 }
 
+// ActionData contains the tag, parameters, and results of an Action.
+type ActionData struct {
+	ActionTag      names.ActionTag
+	ActionParams   map[string]interface{}
+	ActionFailed   bool
+	ResultsMessage string
+	ResultsMap     map[string]interface{}
+}
+
+// NewActionData builds a suitable ActionData struct with no nil members.
+// this should only be called in the event that an Action hook is being requested.
+func NewActionData(tag names.ActionTag, params map[string]interface{}) *ActionData {
+	return &ActionData{
+		ActionTag:    tag,
+		ActionParams: params,
+		ResultsMap:   map[string]interface{}{},
+	}
+}
+
 // HookContext is the implementation of jujuc.Context.
 type HookContext struct {
 	unit *uniter.Unit
@@ -67,8 +85,9 @@ type HookContext struct {
 	// id identifies the context.
 	id string
 
-	// actionParams holds the set of arguments passed with the action.
-	actionParams map[string]interface{}
+	// actionData contains the values relevant to the run of an Action:
+	// its tag, its parameters, and its results.
+	actionData *ActionData
 
 	// uuid is the universally unique identifier of the environment.
 	uuid string
@@ -98,6 +117,13 @@ type HookContext struct {
 
 	// proxySettings are the current proxy settings that the uniter knows about
 	proxySettings proxy.Settings
+
+	// canAddMetrics specifies whether the hook may record metrics.
+	canAddMetrics bool
+
+	// metrics holds the metrics recorded by the hook, which are sent
+	// when the hook completes successfully.
+	metrics []jujuc.Metric
 }
 
 func NewHookContext(
@@ -111,7 +137,8 @@ func NewHookContext(
 	apiAddrs []string,
 	serviceOwner string,
 	proxySettings proxy.Settings,
-	actionParams map[string]interface{},
+	actionData *ActionData,
+	canAddMetrics bool,
 ) (*HookContext, error) {
 	ctx := &HookContext{
 		unit:           unit,
@@ -124,7 +151,8 @@ func NewHookContext(
 		apiAddrs:       apiAddrs,
 		serviceOwner:   serviceOwner,
 		proxySettings:  proxySettings,
-		actionParams:   actionParams,
+		actionData:     actionData,
+		canAddMetrics:  canAddMetrics,
 	}
 	// Get and cache the addresses.
 	var err error
@@ -179,7 +207,86 @@ func (ctx *HookContext) ConfigSettings() (charm.Settings, error) {
 }
 
 func (ctx *HookContext) ActionParams() map[string]interface{} {
-	return ctx.actionParams
+	if ctx.actionData == nil {
+		return nil
+	}
+	return ctx.actionData.ActionParams
+}
+
+// SetActionFailed marks the running Action as failed and records the
+// reason given.
+func (ctx *HookContext) SetActionFailed(message string) error {
+	if ctx.actionData == nil {
+		return fmt.Errorf("not running an action")
+	}
+	ctx.actionData.ActionFailed = true
+	ctx.actionData.ResultsMessage = message
+	return nil
+}
+
+// UpdateActionResults inserts the given value into the results map of
+// the running Action, creating nested maps along the path given by keys
+// as needed. Values at the same path, or at a prefix of it, are replaced.
+func (ctx *HookContext) UpdateActionResults(keys []string, value string) error {
+	if ctx.actionData == nil {
+		return fmt.Errorf("not running an action")
+	}
+	addValueToMap(keys, value, ctx.actionData.ResultsMap)
+	return nil
+}
+
+// addValueToMap adds the given value to the map on which the method is run.
+// This allows us to merge maps such as {foo: {bar: baz}} and {foo: {baz: faz}}
+// into {foo: {bar: baz, baz: faz}}.
+func addValueToMap(keys []string, value string, target map[string]interface{}) {
+	next := target
+	for i := range keys {
+		// if we are on last key set the value.
+		// shouldn't be a problem.  overwrites existing vals.
+		if i == len(keys)-1 {
+			next[keys[i]] = value
+			break
+		}
+		if iface, ok := next[keys[i]]; ok {
+			switch typed := iface.(type) {
+			case map[string]interface{}:
+				// If we already had a map inside, keep
+				// stepping through.
+				next = typed
+			default:
+				// If we didn't, then overwrite value
+				// with a map and iterate with that.
+				m := map[string]interface{}{}
+				next[keys[i]] = m
+				next = m
+			}
+			continue
+		}
+		// Otherwise, it wasn't present, so make it and step
+		// into.
+		m := map[string]interface{}{}
+		next[keys[i]] = m
+		next = m
+	}
+}
+
+// AddMetric records a metric to be sent when the hook completes.
+func (ctx *HookContext) AddMetric(key, value string, created time.Time) error {
+	if !ctx.canAddMetrics {
+		return fmt.Errorf("metrics disabled")
+	}
+	ctx.metrics = append(ctx.metrics, jujuc.Metric{Key: key, Value: value, Time: created})
+	return nil
+}
+
+// SetWorkloadStatus sets the status of the unit's workload.
+func (ctx *HookContext) SetWorkloadStatus(status params.WorkloadStatus, info string) error {
+	return ctx.unit.SetWorkloadStatus(status, info)
+}
+
+// WorkloadStatus returns the status of the unit's workload.
+func (ctx *HookContext) WorkloadStatus() (params.WorkloadStatus, string, error) {
+	return ctx.unit.WorkloadStatus()
 }
 
 func (ctx *HookContext) HookRelation() (jujuc.ContextRelation, bool) {
@@ -306,9 +413,32 @@ func (ctx *HookContext) finalizeContext(process string, err error) error {
 		}
 		rctx.ClearCache()
 	}
+	if writeChanges && len(ctx.metrics) > 0 {
+		if e := ctx.sendMetrics(); e != nil {
+			e = fmt.Errorf("could not send metrics from %q: %v", process, e)
+			logger.Errorf("%v", e)
+			if err == nil {
+				err = e
+			}
+		}
+	}
+	ctx.metrics = nil
 	return err
 }
 
+// sendMetrics sends the metrics recorded by the hook as a single batch.
+func (ctx *HookContext) sendMetrics() error {
+	metrics := make([]params.Metric, len(ctx.metrics))
+	for i, metric := range ctx.metrics {
+		metrics[i] = params.Metric{
+			Key:   metric.Key,
+			Value: metric.Value,
+			Time:  metric.Time,
+		}
+	}
+	return ctx.unit.AddMetrics(metrics)
+}
+
 // RunCommands executes the commands in an environment which allows it to to
 // call back into the hook context to execute jujuc tools.
 func (ctx *HookContext) RunCommands(commands, charmDir, toolsDir, socketPath string) (*utilexec.ExecResponse, error) {
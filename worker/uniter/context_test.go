@@ -287,6 +287,56 @@ func (s *RunHookSuite) TestRunHook(c *gc.C) {
 	}
 }
 
+func (s *RunHookSuite) TestRunHookMetricsFlushing(c *gc.C) {
+	uuid, err := utils.NewUUID()
+	c.Assert(err, gc.IsNil)
+	ctx := s.getHookContext(c, uuid.String(), -1, "", noProxies)
+	charmDir, _ := makeCharm(c, hookSpec{
+		name: "something-happened",
+		perm: 0700,
+		code: 123,
+	})
+
+	// Metrics recorded by a failing hook are discarded.
+	now := time.Now().Round(time.Second).UTC()
+	err = ctx.AddMetric("pings", "5", now)
+	c.Assert(err, gc.IsNil)
+	err = ctx.RunHook("something-happened", charmDir, c.MkDir(), "/path/to/socket")
+	c.Assert(err, gc.ErrorMatches, "exit status 123")
+	batches, err := s.State.MetricBatches(state.MetricFilter{Units: []string{s.unit.Name()}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(batches, gc.HasLen, 0)
+
+	// Metrics recorded by a successful hook are sent as one batch.
+	charmDir, _ = makeCharm(c, hookSpec{
+		name: "something-happened",
+		perm: 0700,
+	})
+	err = ctx.AddMetric("pings", "6", now)
+	c.Assert(err, gc.IsNil)
+	err = ctx.AddMetric("users", "2", now)
+	c.Assert(err, gc.IsNil)
+	err = ctx.RunHook("something-happened", charmDir, c.MkDir(), "/path/to/socket")
+	c.Assert(err, gc.IsNil)
+	batches, err = s.State.MetricBatches(state.MetricFilter{Units: []string{s.unit.Name()}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(batches, gc.HasLen, 1)
+	metrics := batches[0].Metrics()
+	c.Assert(metrics, gc.HasLen, 2)
+	c.Assert(metrics[0].Key(), gc.Equals, "pings")
+	c.Assert(metrics[0].Value(), gc.Equals, "6")
+	c.Assert(metrics[0].Time().Equal(now), jc.IsTrue)
+	c.Assert(metrics[1].Key(), gc.Equals, "users")
+	c.Assert(metrics[1].Value(), gc.Equals, "2")
+
+	// Sent metrics are not sent again.
+	err = ctx.RunHook("something-happened", charmDir, c.MkDir(), "/path/to/socket")
+	c.Assert(err, gc.IsNil)
+	batches, err = s.State.MetricBatches(state.MetricFilter{Units: []string{s.unit.Name()}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(batches, gc.HasLen, 1)
+}
+
 // split the line into buffer-sized lengths.
 func splitLine(s string) []string {
 	var ss []string
@@ -670,6 +720,43 @@ func (s *InterfaceSuite) TestConfigCaching(c *gc.C) {
 	c.Assert(settings, gc.DeepEquals, charm.Settings{"blog-title": "My Title"})
 }
 
+func (s *InterfaceSuite) TestWorkloadStatus(c *gc.C) {
+	ctx := s.GetContext(c, -1, "")
+	status, info, err := ctx.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.WorkloadUnknown)
+	c.Assert(info, gc.Equals, "")
+
+	// The status is set immediately, not when the hook completes.
+	err = ctx.SetWorkloadStatus(params.WorkloadWaiting, "waiting for database relation")
+	c.Assert(err, gc.IsNil)
+	status, info, err = s.unit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.WorkloadWaiting)
+	c.Assert(info, gc.Equals, "waiting for database relation")
+
+	status, info, err = ctx.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.WorkloadWaiting)
+	c.Assert(info, gc.Equals, "waiting for database relation")
+}
+
+func (s *InterfaceSuite) TestAddMetricDisabled(c *gc.C) {
+	context, err := uniter.NewHookContext(s.apiUnit, "TestCtx", "uuid",
+		"test-env-name", -1, "", s.relctxs, apiAddrs, "test-owner",
+		noProxies, nil, false)
+	c.Assert(err, gc.IsNil)
+	err = context.AddMetric("pings", "5", time.Now())
+	c.Assert(err, gc.ErrorMatches, "metrics disabled")
+}
+
+func (s *InterfaceSuite) TestCanAddMetrics(c *gc.C) {
+	c.Assert(uniter.CanAddMetrics(nil, "config-changed"), jc.IsTrue)
+	metricsHooks := []string{"collect-metrics"}
+	c.Assert(uniter.CanAddMetrics(metricsHooks, "collect-metrics"), jc.IsTrue)
+	c.Assert(uniter.CanAddMetrics(metricsHooks, "config-changed"), jc.IsFalse)
+}
+
 type HookContextSuite struct {
 	testing.JujuConnSuite
 	service  *state.Service
@@ -753,7 +840,7 @@ func (s *HookContextSuite) getHookContext(c *gc.C, uuid string, relid int,
 	}
 	context, err := uniter.NewHookContext(s.apiUnit, "TestCtx", uuid,
 		"test-env-name", relid, remote, s.relctxs, apiAddrs, "test-owner",
-		proxies, map[string]interface{}(nil))
+		proxies, nil, true)
 	c.Assert(err, gc.IsNil)
 	return context
 }
@@ -24,3 +24,5 @@ var SearchHook = searchHook
 var HookCommand = hookCommand
 
 var LookPath = lookPath
+
+var CanAddMetrics = canAddMetrics
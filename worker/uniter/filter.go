@@ -88,6 +88,7 @@ type filter struct {
 	service          *uniter.Service
 	upgradeFrom      serviceCharm
 	upgradeAvailable serviceCharm
+	upgradeHeld      bool
 	upgrade          *charm.URL
 	relations        []int
 	actionsPending   []string
@@ -531,6 +532,10 @@ func (f *filter) unitChanged() error {
 			f.outResolved = f.outResolvedOn
 		}
 	}
+	if f.upgradeHeld {
+		// The unit may have been released to upgrade.
+		return f.upgradeChanged()
+	}
 	return nil
 }
 
@@ -572,6 +577,16 @@ func (f *filter) upgradeChanged() (err error) {
 	}
 	if *f.upgradeAvailable.url != *f.upgradeFrom.url {
 		if f.upgradeAvailable.force || !f.upgradeFrom.force {
+			// A rolling upgrade of the service holds the unit back
+			// until it is released to upgrade.
+			if f.upgradeHeld, err = f.unit.CharmUpgradeHeld(); err != nil {
+				return err
+			}
+			if f.upgradeHeld {
+				filterLogger.Debugf("upgrade held back by rolling upgrade")
+				f.outUpgrade = nil
+				return nil
+			}
 			filterLogger.Debugf("preparing new upgrade event")
 			if f.upgrade == nil || *f.upgrade != *f.upgradeAvailable.url {
 				f.upgrade = f.upgradeAvailable.url
@@ -40,7 +40,11 @@ var _ = gc.Suite(&FilterSuite{})
 
 func (s *FilterSuite) SetUpTest(c *gc.C) {
 	s.JujuConnSuite.SetUpTest(c)
-	s.wpcharm = s.AddTestingCharm(c, "wordpress")
+	s.wpcharm = s.AddActionsCharm(c, "wordpress", `
+actions:
+   snapshot:
+      description: Take a snapshot of the database.
+`)
 	s.wordpress = s.AddTestingService(c, "wordpress", s.wpcharm)
 	var err error
 	s.unit, err = s.wordpress.AddUnit()
@@ -289,6 +293,48 @@ func (s *FilterSuite) TestCharmUpgradeEvents(c *gc.C) {
 	assertNoChange()
 }
 
+func (s *FilterSuite) TestCharmUpgradeHeld(c *gc.C) {
+	oldCharm := s.AddTestingCharm(c, "upgrade1")
+	svc := s.AddTestingService(c, "upgradetest", oldCharm)
+	unit, err := svc.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.AssignToNewMachine()
+	c.Assert(err, gc.IsNil)
+
+	s.APILogin(c, unit)
+
+	f, err := newFilter(s.uniter, unit.Tag().String())
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertStop(c, f)
+	err = f.SetCharm(oldCharm.URL())
+	c.Assert(err, gc.IsNil)
+
+	// A rolling upgrade holds the unit back; no event.
+	newCharm := s.AddTestingCharm(c, "upgrade2")
+	ru, err := svc.StartRollingUpgrade(state.RollingUpgradeParams{
+		Charm:     newCharm,
+		BatchSize: 1,
+	})
+	c.Assert(err, gc.IsNil)
+	s.BackingState.StartSync()
+	select {
+	case sch := <-f.UpgradeEvents():
+		c.Fatalf("unexpected %#v", sch)
+	case <-time.After(coretesting.ShortWait):
+	}
+
+	// Releasing the unit generates the event.
+	err = ru.ReleaseBatch([]string{unit.Name()}, time.Now())
+	c.Assert(err, gc.IsNil)
+	s.BackingState.StartSync()
+	select {
+	case upgradeCharm := <-f.UpgradeEvents():
+		c.Assert(upgradeCharm, gc.DeepEquals, newCharm.URL())
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out")
+	}
+}
+
 func (s *FilterSuite) TestConfigEvents(c *gc.C) {
 	f, err := newFilter(s.uniter, s.unit.Tag().String())
 	c.Assert(err, gc.IsNil)
@@ -531,7 +577,7 @@ func (s *FilterSuite) TestActionEvents(c *gc.C) {
 	// Make sure bundled events arrive properly.
 	testIds := make([]string, 5)
 	for i := 0; i < 5; i++ {
-		testIds[i] = addAction("name" + string(i))
+		testIds[i] = addAction("snapshot")
 	}
 
 	assertChange(testIds)
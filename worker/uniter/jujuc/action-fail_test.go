@@ -0,0 +1,66 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type ActionFailSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&ActionFailSuite{})
+
+func (s *ActionFailSuite) TestActionFail(c *gc.C) {
+	var actionFailTests = []struct {
+		summary string
+		args    []string
+		code    int
+		message string
+	}{{
+		summary: "no parameters sets a default message",
+		message: "action failed without reason given, check action for errors",
+	}, {
+		summary: "a message sent is set as the failure reason",
+		args:    []string{"a message"},
+		message: "a message",
+	}, {
+		summary: "extra arguments are an error",
+		args:    []string{"a message", "something else"},
+		code:    2,
+	}}
+
+	for i, t := range actionFailTests {
+		c.Logf("test %d: %s\n args: %#v", i, t.summary, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.actionResults = map[string]interface{}{}
+		com, err := jujuc.NewCommand(hctx, "action-fail")
+		c.Assert(err, gc.IsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, t.code)
+		if code == 0 {
+			c.Check(hctx.actionFailed, gc.Equals, true)
+			c.Check(hctx.actionMessage, gc.Equals, t.message)
+		} else {
+			c.Check(bufferString(ctx.Stderr), gc.Matches, `(.|\n)*error: unrecognized args: \["something else"\]\n`)
+			c.Check(hctx.actionFailed, gc.Equals, false)
+		}
+	}
+}
+
+func (s *ActionFailSuite) TestNotRunningAction(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, "action-fail")
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"oops"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: not running an action\n")
+}
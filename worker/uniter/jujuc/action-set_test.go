@@ -0,0 +1,86 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type ActionSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&ActionSetSuite{})
+
+func (s *ActionSetSuite) TestActionSet(c *gc.C) {
+	var actionSetTests = []struct {
+		summary string
+		args    []string
+		code    int
+		errMsg  string
+		expect  map[string]interface{}
+	}{{
+		summary: "bare value(s) are an Init error",
+		args:    []string{"result"},
+		code:    2,
+		errMsg:  `argument "result" must be of the form key...=value`,
+	}, {
+		summary: "invalid keys are an error",
+		args:    []string{"Result=5"},
+		code:    2,
+		errMsg:  `key "Result" must start and end with lowercase alphanumeric, and contain only lowercase alphanumeric and hyphens`,
+	}, {
+		summary: "empty array is not an error",
+		expect:  map[string]interface{}{},
+	}, {
+		summary: "a single bare value is set",
+		args:    []string{"result=5"},
+		expect:  map[string]interface{}{"result": "5"},
+	}, {
+		summary: "nested values are built up and later values win",
+		args:    []string{"foo.bar=2", "foo.baz.val=3", "foo.bar.zab=4", "foo.baz=1", "outfile.size=10G"},
+		expect: map[string]interface{}{
+			"foo": map[string]interface{}{
+				"bar": map[string]interface{}{"zab": "4"},
+				"baz": "1",
+			},
+			"outfile": map[string]interface{}{"size": "10G"},
+		},
+	}}
+
+	for i, t := range actionSetTests {
+		c.Logf("test %d: %s\n args: %#v", i, t.summary, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.actionResults = map[string]interface{}{}
+		com, err := jujuc.NewCommand(hctx, "action-set")
+		c.Assert(err, gc.IsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, t.code)
+		if code == 0 {
+			c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+			c.Check(hctx.actionResults, jc.DeepEquals, t.expect)
+		} else {
+			expect := fmt.Sprintf(`(.|\n)*error: %s\n`, t.errMsg)
+			c.Check(bufferString(ctx.Stderr), gc.Matches, expect)
+		}
+	}
+}
+
+func (s *ActionSetSuite) TestNotRunningAction(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, "action-set")
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"result=5"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: not running an action\n")
+}
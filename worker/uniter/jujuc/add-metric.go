@@ -0,0 +1,74 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+)
+
+// AddMetricCommand implements the add-metric command.
+type AddMetricCommand struct {
+	cmd.CommandBase
+	ctx     Context
+	metrics []Metric
+}
+
+// NewAddMetricCommand returns an AddMetricCommand for use with the given
+// context.
+func NewAddMetricCommand(ctx Context) cmd.Command {
+	return &AddMetricCommand{ctx: ctx}
+}
+
+// Info returns the content for --help.
+func (c *AddMetricCommand) Info() *cmd.Info {
+	doc := `
+add-metric records the given metrics for the unit.  The metrics are sent to
+the state server when the hook completes successfully, and discarded if it
+fails.  Keys must start and end with lowercase alphanumeric, and contain only
+lowercase alphanumeric and hyphens.  If the metrics-hooks environment
+setting names any hooks, metrics may only be recorded in those hooks.
+
+Example usage:
+ add-metric requests=42 active-users=7
+`
+	return &cmd.Info{
+		Name:    "add-metric",
+		Args:    "<key>=<value> [<key>=<value> ...]",
+		Purpose: "send metrics",
+		Doc:     doc,
+	}
+}
+
+// Init checks that the key=value arguments are well formed.
+func (c *AddMetricCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no metrics specified")
+	}
+	now := time.Now()
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return fmt.Errorf("expected key=value, got %q", arg)
+		}
+		if !keyRule.MatchString(kv[0]) {
+			return fmt.Errorf("key %q must start and end with lowercase alphanumeric, and contain only lowercase alphanumeric and hyphens", kv[0])
+		}
+		c.metrics = append(c.metrics, Metric{Key: kv[0], Value: kv[1], Time: now})
+	}
+	return nil
+}
+
+// Run adds the metrics to the hook context.
+func (c *AddMetricCommand) Run(ctx *cmd.Context) error {
+	for _, metric := range c.metrics {
+		if err := c.ctx.AddMetric(metric.Key, metric.Value, metric.Time); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type AddMetricSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&AddMetricSuite{})
+
+func (s *AddMetricSuite) TestHelp(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, "add-metric")
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--help"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), gc.Matches, `usage: add-metric <key>=<value> \[<key>=<value> ...\]
+purpose: send metrics
+(.|\n)*`)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+}
+
+func (s *AddMetricSuite) TestAddMetric(c *gc.C) {
+	var addMetricTests = []struct {
+		summary string
+		args    []string
+		code    int
+		err     string
+		metrics map[string]string
+	}{{
+		summary: "no metrics",
+		code:    2,
+		err:     "error: no metrics specified\n",
+	}, {
+		summary: "a single metric",
+		args:    []string{"pings=5"},
+		metrics: map[string]string{"pings": "5"},
+	}, {
+		summary: "several metrics",
+		args:    []string{"pings=5", "active-users=2"},
+		metrics: map[string]string{"pings": "5", "active-users": "2"},
+	}, {
+		summary: "missing value",
+		args:    []string{"pings="},
+		code:    2,
+		err:     "error: expected key=value, got \"pings=\"\n",
+	}, {
+		summary: "missing separator",
+		args:    []string{"pings"},
+		code:    2,
+		err:     "error: expected key=value, got \"pings\"\n",
+	}, {
+		summary: "invalid key",
+		args:    []string{"Pings=5"},
+		code:    2,
+		err:     "error: key \"Pings\" must start and end with lowercase alphanumeric, and contain only lowercase alphanumeric and hyphens\n",
+	}}
+
+	for i, t := range addMetricTests {
+		c.Logf("test %d: %s\n args: %#v", i, t.summary, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.canAddMetrics = true
+		com, err := jujuc.NewCommand(hctx, "add-metric")
+		c.Assert(err, gc.IsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, t.code)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, t.err)
+		metrics := make(map[string]string)
+		for _, metric := range hctx.metrics {
+			metrics[metric.Key] = metric.Value
+			c.Check(metric.Time.IsZero(), gc.Equals, false)
+		}
+		if t.metrics == nil {
+			c.Check(metrics, gc.HasLen, 0)
+		} else {
+			c.Check(metrics, gc.DeepEquals, t.metrics)
+		}
+	}
+}
+
+func (s *AddMetricSuite) TestMetricsDisabled(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, "add-metric")
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"pings=5"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: metrics disabled\n")
+	c.Check(hctx.metrics, gc.HasLen, 0)
+}
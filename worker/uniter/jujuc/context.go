@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/juju/charm.v3"
 
@@ -41,6 +42,29 @@ type Context interface {
 	// ActionParams returns the map of params passed with an Action.
 	ActionParams() map[string]interface{}
 
+	// UpdateActionResults sets the value at the given path in the results
+	// of the running Action. It returns an error if the context is not
+	// running an Action.
+	UpdateActionResults(keys []string, value string) error
+
+	// SetActionFailed marks the running Action as failed, recording the
+	// given message. It returns an error if the context is not running
+	// an Action.
+	SetActionFailed(message string) error
+
+	// AddMetric records a metric to be sent to the state server when
+	// the hook completes. It returns an error if metrics may not be
+	// added in the executing hook.
+	AddMetric(key, value string, created time.Time) error
+
+	// SetWorkloadStatus sets the status of the executing unit's workload,
+	// along with a message for the operator.
+	SetWorkloadStatus(status params.WorkloadStatus, info string) error
+
+	// WorkloadStatus returns the status of the executing unit's workload
+	// and the accompanying message.
+	WorkloadStatus() (params.WorkloadStatus, string, error)
+
 	// HookRelation returns the ContextRelation associated with the executing
 	// hook if it was found, and whether it was found.
 	HookRelation() (ContextRelation, bool)
@@ -94,6 +118,13 @@ type Settings interface {
 	Delete(string)
 }
 
+// Metric represents a single metric set by the charm.
+type Metric struct {
+	Key   string
+	Value string
+	Time  time.Time
+}
+
 // newRelationIdValue returns a gnuflag.Value for convenient parsing of relation
 // ids in ctx.
 func newRelationIdValue(ctx Context, result *int) *relationIdValue {
@@ -26,15 +26,20 @@ var logger = loggo.GetLogger("worker.uniter.jujuc")
 
 // newCommands maps Command names to initializers.
 var newCommands = map[string]func(Context) cmd.Command{
+	"add-metric" + cmdSuffix:    NewAddMetricCommand,
 	"close-port" + cmdSuffix:    NewClosePortCommand,
 	"config-get" + cmdSuffix:    NewConfigGetCommand,
 	"juju-log" + cmdSuffix:      NewJujuLogCommand,
 	"open-port" + cmdSuffix:     NewOpenPortCommand,
 	"relation-get" + cmdSuffix:  NewRelationGetCommand,
 	"action-get" + cmdSuffix:    NewActionGetCommand,
+	"action-set" + cmdSuffix:    NewActionSetCommand,
+	"action-fail" + cmdSuffix:   NewActionFailCommand,
 	"relation-ids" + cmdSuffix:  NewRelationIdsCommand,
 	"relation-list" + cmdSuffix: NewRelationListCommand,
 	"relation-set" + cmdSuffix:  NewRelationSetCommand,
+	"status-get" + cmdSuffix:    NewStatusGetCommand,
+	"status-set" + cmdSuffix:    NewStatusSetCommand,
 	"unit-get" + cmdSuffix:      NewUnitGetCommand,
 	"owner-get" + cmdSuffix:     NewOwnerGetCommand,
 }
@@ -199,6 +199,8 @@ var newCommandTests = []struct {
 	{"relation-ids", ""},
 	{"relation-list", ""},
 	{"relation-set", ""},
+	{"status-get", ""},
+	{"status-set", ""},
 	{"unit-get", ""},
 	{"random", "unknown command: random"},
 }
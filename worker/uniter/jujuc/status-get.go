@@ -0,0 +1,60 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+)
+
+// StatusGetCommand implements the status-get command.
+type StatusGetCommand struct {
+	cmd.CommandBase
+	ctx            Context
+	includeMessage bool
+	out            cmd.Output
+}
+
+// NewStatusGetCommand returns a StatusGetCommand for use with the given
+// context.
+func NewStatusGetCommand(ctx Context) cmd.Command {
+	return &StatusGetCommand{ctx: ctx}
+}
+
+// Info returns the content for --help.
+func (c *StatusGetCommand) Info() *cmd.Info {
+	doc := `
+status-get prints the workload status of the unit, as last set with
+status-set.  It is "unknown" until the charm sets it.  With
+--include-message, the status is printed together with its message.
+`
+	return &cmd.Info{
+		Name:    "status-get",
+		Purpose: "print the workload status of the unit",
+		Doc:     doc,
+	}
+}
+
+func (c *StatusGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.BoolVar(&c.includeMessage, "include-message", false, "print the status message as well as the status")
+}
+
+func (c *StatusGetCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *StatusGetCommand) Run(ctx *cmd.Context) error {
+	status, message, err := c.ctx.WorkloadStatus()
+	if err != nil {
+		return err
+	}
+	if !c.includeMessage {
+		return c.out.Write(ctx, string(status))
+	}
+	return c.out.Write(ctx, map[string]interface{}{
+		"status":  string(status),
+		"message": message,
+	})
+}
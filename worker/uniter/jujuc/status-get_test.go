@@ -0,0 +1,63 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type StatusGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&StatusGetSuite{})
+
+var statusGetTests = []struct {
+	args []string
+	out  string
+}{
+	{nil, "waiting\n"},
+	{[]string{"--format", "json"}, `"waiting"` + "\n"},
+	{[]string{"--include-message"}, "message: waiting for database relation\nstatus: waiting\n"},
+	{[]string{"--include-message", "--format", "json"}, `{"message":"waiting for database relation","status":"waiting"}` + "\n"},
+}
+
+func (s *StatusGetSuite) TestOutputFormat(c *gc.C) {
+	for i, t := range statusGetTests {
+		c.Logf("test %d: %#v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.status = params.WorkloadWaiting
+		hctx.statusInfo = "waiting for database relation"
+		com, err := jujuc.NewCommand(hctx, "status-get")
+		c.Assert(err, gc.IsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Assert(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *StatusGetSuite) TestUnknown(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, "status-get")
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, "unknown\n")
+}
+
+func (s *StatusGetSuite) TestUnknownArg(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, "status-get")
+	c.Assert(err, gc.IsNil)
+	err = testing.InitCommand(com, []string{"blah"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["blah"\]`)
+}
@@ -0,0 +1,72 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/state/api/params"
+)
+
+// StatusSetCommand implements the status-set command.
+type StatusSetCommand struct {
+	cmd.CommandBase
+	ctx     Context
+	status  params.WorkloadStatus
+	message string
+}
+
+// NewStatusSetCommand returns a StatusSetCommand for use with the given
+// context.
+func NewStatusSetCommand(ctx Context) cmd.Command {
+	return &StatusSetCommand{ctx: ctx}
+}
+
+// Info returns the content for --help.
+func (c *StatusSetCommand) Info() *cmd.Info {
+	doc := `
+status-set reports the state of the unit's workload to the operator, who
+sees it in juju status.  It is independent of the status of the unit agent.
+The status must be one of:
+
+    maintenance  the unit is preparing to provide service
+    blocked      the unit needs operator intervention to continue
+    waiting      the unit is waiting for something outside its control,
+                 such as a relation
+    active       the unit is providing service
+
+Example usage:
+ status-set waiting "waiting for database relation"
+`
+	return &cmd.Info{
+		Name:    "status-set",
+		Args:    "<maintenance | blocked | waiting | active> [message]",
+		Purpose: "set the workload status of the unit",
+		Doc:     doc,
+	}
+}
+
+// Init checks the status and reads the optional message.
+func (c *StatusSetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no status specified")
+	}
+	c.status = params.WorkloadStatus(args[0])
+	if !c.status.Valid() {
+		return fmt.Errorf("invalid status %q, expected one of maintenance, blocked, waiting, active", args[0])
+	}
+	args = args[1:]
+	if len(args) > 0 {
+		c.message = args[0]
+		args = args[1:]
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run sets the workload status of the unit.
+func (c *StatusSetCommand) Run(ctx *cmd.Context) error {
+	return c.ctx.SetWorkloadStatus(c.status, c.message)
+}
@@ -0,0 +1,84 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type StatusSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&StatusSetSuite{})
+
+func (s *StatusSetSuite) TestHelp(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, "status-set")
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--help"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), gc.Matches, `usage: status-set <maintenance \| blocked \| waiting \| active> \[message\]
+purpose: set the workload status of the unit
+(.|\n)*`)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+}
+
+func (s *StatusSetSuite) TestStatusSet(c *gc.C) {
+	var statusSetTests = []struct {
+		summary string
+		args    []string
+		code    int
+		err     string
+		status  params.WorkloadStatus
+		message string
+	}{{
+		summary: "no status",
+		code:    2,
+		err:     "error: no status specified\n",
+	}, {
+		summary: "invalid status",
+		args:    []string{"sleeping"},
+		code:    2,
+		err:     "error: invalid status \"sleeping\", expected one of maintenance, blocked, waiting, active\n",
+	}, {
+		summary: "unknown may not be set",
+		args:    []string{"unknown"},
+		code:    2,
+		err:     "error: invalid status \"unknown\", expected one of maintenance, blocked, waiting, active\n",
+	}, {
+		summary: "status only",
+		args:    []string{"active"},
+		status:  params.WorkloadActive,
+	}, {
+		summary: "status and message",
+		args:    []string{"waiting", "waiting for database relation"},
+		status:  params.WorkloadWaiting,
+		message: "waiting for database relation",
+	}, {
+		summary: "extra arguments",
+		args:    []string{"blocked", "needs", "a licence key"},
+		code:    2,
+		err:     "error: unrecognized args: [\"a licence key\"]\n",
+	}}
+
+	for i, t := range statusSetTests {
+		c.Logf("test %d: %s\n args: %#v", i, t.summary, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		com, err := jujuc.NewCommand(hctx, "status-set")
+		c.Assert(err, gc.IsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, t.code)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, t.err)
+		c.Check(hctx.status, gc.Equals, t.status)
+		c.Check(hctx.statusInfo, gc.Equals, t.message)
+	}
+}
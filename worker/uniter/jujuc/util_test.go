@@ -9,6 +9,7 @@ import (
 	"io"
 	"sort"
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v3"
@@ -79,11 +80,18 @@ func setSettings(c *gc.C, ru *state.RelationUnit, settings map[string]interface{
 }
 
 type Context struct {
-	actionParams map[string]interface{}
-	ports        set.Strings
-	relid        int
-	remote       string
-	rels         map[int]*ContextRelation
+	actionParams  map[string]interface{}
+	actionResults map[string]interface{}
+	actionFailed  bool
+	actionMessage string
+	canAddMetrics bool
+	metrics       []jujuc.Metric
+	status        params.WorkloadStatus
+	statusInfo    string
+	ports         set.Strings
+	relid         int
+	remote        string
+	rels          map[int]*ContextRelation
 }
 
 func (c *Context) UnitName() string {
@@ -122,6 +130,53 @@ func (c *Context) ActionParams() map[string]interface{} {
 	return c.actionParams
 }
 
+func (c *Context) UpdateActionResults(keys []string, value string) error {
+	if c.actionResults == nil {
+		return fmt.Errorf("not running an action")
+	}
+	target := c.actionResults
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := target[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			target[key] = next
+		}
+		target = next
+	}
+	target[keys[len(keys)-1]] = value
+	return nil
+}
+
+func (c *Context) SetActionFailed(message string) error {
+	if c.actionResults == nil {
+		return fmt.Errorf("not running an action")
+	}
+	c.actionFailed = true
+	c.actionMessage = message
+	return nil
+}
+
+func (c *Context) AddMetric(key, value string, created time.Time) error {
+	if !c.canAddMetrics {
+		return fmt.Errorf("metrics disabled")
+	}
+	c.metrics = append(c.metrics, jujuc.Metric{Key: key, Value: value, Time: created})
+	return nil
+}
+
+func (c *Context) SetWorkloadStatus(status params.WorkloadStatus, info string) error {
+	c.status = status
+	c.statusInfo = info
+	return nil
+}
+
+func (c *Context) WorkloadStatus() (params.WorkloadStatus, string, error) {
+	if c.status == "" {
+		return params.WorkloadUnknown, "", nil
+	}
+	return c.status, c.statusInfo, nil
+}
+
 func (c *Context) HookRelation() (jujuc.ContextRelation, bool) {
 	return c.Relation(c.relid)
 }
@@ -47,6 +47,22 @@ const (
 	RunListenerFile = "run.socket"
 )
 
+// canAddMetrics returns whether metrics may be recorded in the named
+// hook, given the names of the hooks in which they may be recorded as
+// set by the metrics-hooks environment setting. When none are set,
+// metrics may be recorded in any hook.
+func canAddMetrics(metricsHooks []string, hookName string) bool {
+	if len(metricsHooks) == 0 {
+		return true
+	}
+	for _, name := range metricsHooks {
+		if name == hookName {
+			return true
+		}
+	}
+	return false
+}
+
 // A UniterExecutionObserver gets the appropriate methods called when a hook
 // is executed and either succeeds or fails.  Missing hooks don't get reported
 // in this way.
@@ -85,6 +101,9 @@ type Uniter struct {
 	proxy      proxyutils.Settings
 	proxyMutex sync.Mutex
 
+	metricsHooks      []string
+	metricsHooksMutex sync.Mutex
+
 	ranConfigChanged bool
 	// The execution observer is only used in tests at this stage. Should this
 	// need to be extended, perhaps a list of observers would be needed.
@@ -122,7 +141,13 @@ func (u *Uniter) loop(unitTag string) (err error) {
 		return err
 	}
 	defer watcher.Stop(environWatcher, &u.tomb)
-	u.watchForProxyChanges(environWatcher)
+	// The metrics hooks are needed before the first hook runs.
+	environConfig, err := u.st.EnvironConfig()
+	if err != nil {
+		return err
+	}
+	u.updateMetricsHooks(environConfig)
+	u.watchForEnvironChanges(environWatcher)
 
 	// Start filtering state change events for consumption by modes.
 	u.f, err = newFilter(u.st, unitTag)
@@ -354,7 +379,7 @@ func (u *Uniter) deploy(curl *corecharm.URL, reason Op) error {
 // operation is not affected by the error.
 var errHookFailed = stderrors.New("hook execution failed")
 
-func (u *Uniter) getHookContext(hctxId string, relationId int, remoteUnitName string, actionParams map[string]interface{}) (context *HookContext, err error) {
+func (u *Uniter) getHookContext(hctxId string, relationId int, remoteUnitName string, actionData *ActionData, canAddMetrics bool) (context *HookContext, err error) {
 
 	apiAddrs, err := u.st.APIAddresses()
 	if err != nil {
@@ -376,7 +401,7 @@ func (u *Uniter) getHookContext(hctxId string, relationId int, remoteUnitName st
 	proxySettings := u.proxy
 	return NewHookContext(u.unit, hctxId, u.uuid, u.envName, relationId,
 		remoteUnitName, ctxRelations, apiAddrs, ownerTag, proxySettings,
-		actionParams)
+		actionData, canAddMetrics)
 }
 
 func (u *Uniter) acquireHookLock(message string) (err error) {
@@ -426,7 +451,7 @@ func (u *Uniter) RunCommands(commands string) (results *exec.ExecResponse, err e
 	}
 	defer u.hookLock.Unlock()
 
-	hctx, err := u.getHookContext(hctxId, -1, "", map[string]interface{}(nil))
+	hctx, err := u.getHookContext(hctxId, -1, "", nil, false)
 	if err != nil {
 		return nil, err
 	}
@@ -494,7 +519,7 @@ func (u *Uniter) runHook(hi hook.Info) (err error) {
 	}
 
 	hookName := string(hi.Kind)
-	actionParams := map[string]interface{}(nil)
+	var actionData *ActionData
 
 	// This value is needed to pass results of Action param validation
 	// in case of error or invalidation.  This is probably bad form; it
@@ -509,13 +534,19 @@ func (u *Uniter) runHook(hi hook.Info) (err error) {
 			return err
 		}
 	} else if hi.Kind == hooks.ActionRequested {
-		action, err := u.st.Action(names.NewActionTag(hi.ActionId))
-		if err != nil {
+		actionTag := names.NewActionTag(hi.ActionId)
+		action, err := u.st.Action(actionTag)
+		if params.IsCodeNotFound(err) {
+			// The action's result was recorded before the hook
+			// could be committed; there is nothing left to run.
+			logger.Infof("action %q already finished", hi.ActionId)
+			return u.commitHook(hi)
+		} else if err != nil {
 			return err
 		}
-		actionParams = action.Params()
+		actionData = NewActionData(actionTag, action.Params())
 		hookName = action.Name()
-		_, actionParamsErr = u.validateAction(hookName, actionParams)
+		_, actionParamsErr = u.validateAction(hookName, actionData.ActionParams)
 	}
 	hctxId := fmt.Sprintf("%s:%s:%d", u.unit.Name(), hookName, u.rand.Int63())
 
@@ -525,7 +556,7 @@ func (u *Uniter) runHook(hi hook.Info) (err error) {
 	}
 	defer u.hookLock.Unlock()
 
-	hctx, err := u.getHookContext(hctxId, relationId, hi.RemoteUnit, actionParams)
+	hctx, err := u.getHookContext(hctxId, relationId, hi.RemoteUnit, actionData, u.canAddMetrics(hookName))
 	if err != nil {
 		return err
 	}
@@ -551,9 +582,25 @@ func (u *Uniter) runHook(hi hook.Info) (err error) {
 		if actionParamsErr != nil {
 			logger.Errorf("action %q param validation failed: %s", hookName, actionParamsErr.Error())
 			u.notifyHookFailed(hookName, hctx)
+			if err := hctx.SetActionFailed(fmt.Sprintf("param validation failed: %s", actionParamsErr.Error())); err != nil {
+				return err
+			}
+			if err := u.finishAction(hctx, nil); err != nil {
+				return err
+			}
 			return u.commitHook(hi)
 		}
 		err = hctx.RunAction(hookName, u.charmPath, u.toolsDir, socketPath)
+		if finishErr := u.finishAction(hctx, err); finishErr != nil {
+			return finishErr
+		}
+		if err != nil && !IsMissingHookError(err) {
+			// The failure is recorded in the action's results, so
+			// the unit carries on rather than waiting to be resolved.
+			logger.Errorf("action %q failed: %s", hookName, err)
+			u.notifyHookFailed(hookName, hctx)
+			return u.commitHook(hi)
+		}
 	} else {
 		err = hctx.RunHook(hookName, u.charmPath, u.toolsDir, socketPath)
 	}
@@ -579,6 +626,22 @@ func (u *Uniter) runHook(hi hook.Info) (err error) {
 	return u.commitHook(hi)
 }
 
+// finishAction records the outcome of the Action run in the given
+// context: its results, and whether it failed, either because the hook
+// reported failure with action-fail or because it could not be run.
+func (u *Uniter) finishAction(hctx *HookContext, runErr error) error {
+	data := hctx.actionData
+	switch {
+	case IsMissingHookError(runErr):
+		return u.st.ActionFail(data.ActionTag, "action not implemented on unit", data.ResultsMap)
+	case runErr != nil:
+		return u.st.ActionFail(data.ActionTag, runErr.Error(), data.ResultsMap)
+	case data.ActionFailed:
+		return u.st.ActionFail(data.ActionTag, data.ResultsMessage, data.ResultsMap)
+	}
+	return u.st.ActionComplete(data.ActionTag, "", data.ResultsMap)
+}
+
 // commitHook ensures that state is consistent with the supplied hook, and
 // that the fact of the hook's completion is persisted.
 func (u *Uniter) commitHook(hi hook.Info) error {
@@ -827,9 +890,25 @@ func (u *Uniter) updatePackageProxy(cfg *config.Config) {
 	}
 }
 
-// watchForProxyChanges kicks off a go routine to listen to the watcher and
-// update the proxy settings.
-func (u *Uniter) watchForProxyChanges(environWatcher apiwatcher.NotifyWatcher) {
+// updateMetricsHooks updates the names of the hooks in which charms may
+// record metrics from the environment.
+func (u *Uniter) updateMetricsHooks(cfg *config.Config) {
+	u.metricsHooksMutex.Lock()
+	defer u.metricsHooksMutex.Unlock()
+	u.metricsHooks = cfg.MetricsHooks()
+}
+
+// canAddMetrics returns whether the charm may record metrics in the
+// named hook.
+func (u *Uniter) canAddMetrics(hookName string) bool {
+	u.metricsHooksMutex.Lock()
+	defer u.metricsHooksMutex.Unlock()
+	return canAddMetrics(u.metricsHooks, hookName)
+}
+
+// watchForEnvironChanges kicks off a go routine to listen to the watcher and
+// update the proxy settings and metrics hooks.
+func (u *Uniter) watchForEnvironChanges(environWatcher apiwatcher.NotifyWatcher) {
 	go func() {
 		for {
 			select {
@@ -845,6 +924,7 @@ func (u *Uniter) watchForProxyChanges(environWatcher apiwatcher.NotifyWatcher) {
 					logger.Errorf("cannot load environment configuration: %v", err)
 				} else {
 					u.updatePackageProxy(environConfig)
+					u.updateMetricsHooks(environConfig)
 				}
 			}
 		}
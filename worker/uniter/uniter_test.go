@@ -217,6 +217,12 @@ juju-log $JUJU_ENV_UUID %s $JUJU_REMOTE_UNIT
 #!/bin/bash --norc
 juju-log $JUJU_ENV_UUID fail-%s $JUJU_REMOTE_UNIT
 exit 1
+`[1:],
+	"action-reboot": `
+#!/bin/bash --norc
+juju-log $JUJU_ENV_UUID %s $JUJU_REMOTE_UNIT
+action-set outfile.name="foo.bar" outfile.size=10G
+action-fail "reboot required"
 `[1:],
 }
 
@@ -243,6 +249,10 @@ actions:
 	"action-log-fail": `
    action-log-fail:
       params:
+`[1:],
+	"action-reboot": `
+   action-reboot:
+      params:
 `[1:],
 }
 
@@ -1318,6 +1328,65 @@ var actionEventTests = []uniterTest{
 		verifyCharm{},
 		addAction{"action-log", nil},
 		waitHooks{"action-log"},
+		verifyActionResults{[]actionResult{{
+			name:   "action-log",
+			status: state.ActionCompleted,
+		}}},
+	), ut(
+		"action-set and action-fail results are recorded",
+		createCharm{
+			customize: func(c *gc.C, ctx *context, path string) {
+				ctx.writeAction(c, path, "action-reboot")
+				ctx.writeActionsYaml(c, path, []string{"action-reboot"})
+			},
+		},
+		serveCharm{},
+		ensureStateWorker{},
+		createServiceAndUnit{},
+		startUniter{},
+		waitAddresses{},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start"},
+		verifyCharm{},
+		addAction{"action-reboot", nil},
+		waitHooks{"action-reboot"},
+		verifyActionResults{[]actionResult{{
+			name: "action-reboot",
+			results: map[string]interface{}{
+				"outfile": map[string]interface{}{
+					"name": "foo.bar",
+					"size": "10G",
+				},
+			},
+			status: state.ActionFailed,
+			output: "reboot required",
+		}}},
+	), ut(
+		"failed actions are recorded and do not block the unit",
+		createCharm{
+			customize: func(c *gc.C, ctx *context, path string) {
+				ctx.writeAction(c, path, "action-log-fail")
+				ctx.writeActionsYaml(c, path, []string{"action-log-fail"})
+			},
+		},
+		serveCharm{},
+		ensureStateWorker{},
+		createServiceAndUnit{},
+		startUniter{},
+		waitAddresses{},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start"},
+		verifyCharm{},
+		addAction{"action-log-fail", nil},
+		waitHooks{"fail-action-log-fail"},
+		verifyActionResults{[]actionResult{{
+			name:   "action-log-fail",
+			status: state.ActionFailed,
+			output: "exit status 1",
+		}}},
+		waitUnit{status: params.StatusStarted},
+		addAction{"action-log-fail", nil},
+		waitHooks{"fail-action-log-fail"},
 	), ut(
 		"actions with correct params passed are not an error",
 		createCharm{
@@ -1340,7 +1409,7 @@ var actionEventTests = []uniterTest{
 		},
 		waitHooks{"snapshot"},
 	), ut(
-		"actions with incorrect params passed are not an error but fail",
+		"actions with incorrect params are rejected when queued",
 		createCharm{
 			customize: func(c *gc.C, ctx *context, path string) {
 				ctx.writeAction(c, path, "snapshot")
@@ -1355,13 +1424,14 @@ var actionEventTests = []uniterTest{
 		waitUnit{status: params.StatusStarted},
 		waitHooks{"install", "config-changed", "start"},
 		verifyCharm{},
-		addAction{
+		addInvalidAction{
 			name:   "snapshot",
 			params: map[string]interface{}{"outfile": 2},
+			err:    `invalid action "snapshot": .*`,
 		},
-		waitHooks{"fail-snapshot"},
+		waitHooks{},
 	), ut(
-		"actions not defined in actions.yaml fail without an error",
+		"actions not defined in actions.yaml are rejected when queued",
 		createCharm{
 			customize: func(c *gc.C, ctx *context, path string) {
 				ctx.writeAction(c, path, "snapshot")
@@ -1375,8 +1445,12 @@ var actionEventTests = []uniterTest{
 		waitUnit{status: params.StatusStarted},
 		waitHooks{"install", "config-changed", "start"},
 		verifyCharm{},
-		addAction{"snapshot", map[string]interface{}{"outfile": "foo.bar"}},
-		waitHooks{"fail-snapshot"},
+		addInvalidAction{
+			name:   "snapshot",
+			params: map[string]interface{}{"outfile": "foo.bar"},
+			err:    `invalid action "snapshot": not defined by charm .*`,
+		},
+		waitHooks{},
 	), ut(
 		"pending actions get consumed",
 		createCharm{
@@ -2014,6 +2088,57 @@ func (s addAction) step(c *gc.C, ctx *context) {
 	c.Assert(err, gc.IsNil)
 }
 
+type addInvalidAction struct {
+	name   string
+	params map[string]interface{}
+	err    string
+}
+
+func (s addInvalidAction) step(c *gc.C, ctx *context) {
+	_, err := ctx.unit.AddAction(s.name, s.params)
+	c.Assert(err, gc.ErrorMatches, s.err)
+	c.Assert(err, jc.Satisfies, state.IsActionValidationError)
+}
+
+type actionResult struct {
+	name    string
+	results map[string]interface{}
+	status  state.ActionStatus
+	output  string
+}
+
+type verifyActionResults struct {
+	expectedResults []actionResult
+}
+
+func (s verifyActionResults) step(c *gc.C, ctx *context) {
+	timeout := time.After(worstCase)
+	for {
+		ctx.s.BackingState.StartSync()
+		results, err := ctx.unit.ActionResults()
+		c.Assert(err, gc.IsNil)
+		if len(results) == len(s.expectedResults) {
+			for i, result := range results {
+				expected := s.expectedResults[i]
+				c.Check(result.ActionName(), gc.Equals, expected.name)
+				c.Check(result.Status(), gc.Equals, expected.status)
+				c.Check(result.Output(), gc.Equals, expected.output)
+				if expected.results == nil {
+					c.Check(result.Results(), gc.HasLen, 0)
+				} else {
+					c.Check(result.Results(), jc.DeepEquals, expected.results)
+				}
+			}
+			return
+		}
+		select {
+		case <-time.After(coretesting.ShortWait):
+		case <-timeout:
+			c.Fatalf("never got expected action results")
+		}
+	}
+}
+
 type upgradeCharm struct {
 	revision int
 	forced   bool
@@ -124,6 +124,7 @@ func (u *Upgrader) loop() error {
 		dying                <-chan struct{}
 		wantTools            *coretools.Tools
 		wantVersion          version.Number
+		rollBack             bool
 		hostnameVerification utils.SSLHostnameVerification
 	)
 	for {
@@ -132,7 +133,7 @@ func (u *Upgrader) loop() error {
 			if !ok {
 				return watcher.MustErr(versionWatcher)
 			}
-			wantVersion, err = u.st.DesiredVersion(u.tag.String())
+			wantVersion, rollBack, err = u.st.DesiredVersionWithRollBack(u.tag.String())
 			if err != nil {
 				return err
 			}
@@ -144,6 +145,11 @@ func (u *Upgrader) loop() error {
 		}
 		if wantVersion == currentTools.Version.Number {
 			continue
+		} else if rollBack {
+			// A staged upgrade was rolled back before this
+			// agent ran any upgrade steps, so it is safe to
+			// return to the previous version.
+			logger.Infof("upgrade rolled back, returning to %s", wantVersion)
 		} else if !allowedTargetVersion(u.origAgentVersion, version.Current.Number,
 			u.isUpgradeRunning(), wantVersion) {
 			// See also bug #1299802 where when upgrading from
@@ -356,6 +356,38 @@ func (s *UpgraderSuite) TestUpgraderRefusesDowngradeToOrigVersionIfUpgradeNotInP
 	c.Check(err, gc.IsNil)
 }
 
+func (s *UpgraderSuite) TestUpgraderDowngradesMinorVersionIfUpgradeRolledBack(c *gc.C) {
+	downgradeVersion := version.MustParseBinary("5.3.0-precise-amd64")
+	s.upgradeRunning = false
+
+	stor := s.Environ.Storage()
+	origTools := envtesting.PrimeTools(c, stor, s.DataDir(), version.MustParseBinary("5.4.3-precise-amd64"))
+	s.PatchValue(&version.Current, origTools.Version)
+	downgradeTools := envtesting.AssertUploadFakeToolsVersions(c, stor, downgradeVersion)[0]
+	err := statetesting.SetAgentVersion(s.State, downgradeVersion.Number)
+	c.Assert(err, gc.IsNil)
+	err = s.machine.SetAgentVersion(downgradeVersion)
+	c.Assert(err, gc.IsNil)
+	au, err := s.State.StartAgentUpgrade(origTools.Version.Number, []string{s.machine.Id()})
+	c.Assert(err, gc.IsNil)
+	err = au.RollBack()
+	c.Assert(err, gc.IsNil)
+
+	dummy.SetStorageDelay(coretesting.ShortWait)
+
+	u := s.makeUpgrader()
+	err = u.Stop()
+	envtesting.CheckUpgraderReadyError(c, err, &upgrader.UpgradeReadyError{
+		AgentName: s.machine.Tag().String(),
+		OldTools:  origTools.Version,
+		NewTools:  downgradeVersion,
+		DataDir:   s.DataDir(),
+	})
+	foundTools, err := agenttools.ReadTools(s.DataDir(), downgradeTools.Version)
+	c.Assert(err, gc.IsNil)
+	envtesting.CheckTools(c, foundTools, downgradeTools)
+}
+
 type allowedTest struct {
 	original       string
 	current        string